@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+const (
+	defaultServer  = "http://localhost:8080"
+	requestTimeout = 5 * time.Minute
+)
+
+// apiClient — HTTP-клиент к API сервиса
+type apiClient struct {
+	server string
+	// apiKey — общий ключ или персональный ключ оператора; по персональному ключу
+	// сервис определяет автора изменений для истории версий
+	apiKey string
+	http   *http.Client
+}
+
+// apiError — ответ сервиса с кодом ошибки. Тело сохраняется для команд, которые получают
+// отчет вместе с ошибкой (например, отклоненный импорт).
+type apiError struct {
+	status  int
+	body    []byte
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+// registerClientFlags добавляет общие флаги подключения к сервису
+func registerClientFlags(fs *flag.FlagSet) *apiClient {
+	c := &apiClient{http: &http.Client{Timeout: requestTimeout}}
+
+	server := os.Getenv("GEOCTL_SERVER")
+	if server == "" {
+		server = defaultServer
+	}
+
+	fs.StringVar(&c.server, "server", server, "адрес сервиса")
+	fs.StringVar(&c.apiKey, "api-key", os.Getenv("API_KEY"), "ключ API")
+
+	return c
+}
+
+// do выполняет запрос к /api/v1 и декодирует JSON-ответ в out
+func (c *apiClient) do(method, path, contentType string, body []byte, out any) error {
+	url := strings.TrimRight(c.server, "/") + "/api/v1" + path
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-API-Key", c.apiKey)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("запрос к сервису: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("чтение ответа: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{
+			status:  resp.StatusCode,
+			body:    data,
+			message: fmt.Sprintf("сервис вернул %s: %s", resp.Status, strings.TrimSpace(string(data))),
+		}
+
+		var errResp entity.ErrorResponse
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+			apiErr.message = fmt.Sprintf("%s (%d): %s", errResp.Error, resp.StatusCode, errResp.Details)
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+func runExposure(args []string) error {
+	fs := flag.NewFlagSet("exposure", flag.ExitOnError)
+	client := registerClientFlags(fs)
+	format := fs.String("format", "", "формат трека: gpx или geojson (по умолчанию — по расширению файла)")
+	asJSON := fs.Bool("json", false, "вывести отчет в JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: geoctl exposure [флаги] <файл трека>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("не указан файл трека")
+	}
+
+	path := fs.Arg(0)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".gpx":
+			*format = entity.TrackFormatGPX
+		case ".json", ".geojson":
+			*format = entity.TrackFormatGeoJSON
+		}
+	}
+
+	endpoint := "/tracks/exposure"
+	if *format != "" {
+		endpoint += "?format=" + url.QueryEscape(*format)
+	}
+
+	var report entity.ExposureReport
+	if err := client.do(http.MethodPost, endpoint, "", data, &report); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printExposureReport(&report)
+	return nil
+}
+
+func printExposureReport(report *entity.ExposureReport) {
+	name := report.TrackName
+	if name == "" {
+		name = "(без названия)"
+	}
+
+	fmt.Printf("Трек: %s, точек: %d\n", name, report.PointCount)
+	if report.StartedAt != nil && report.FinishedAt != nil {
+		fmt.Printf("Период: %s — %s\n", formatTime(report.StartedAt), formatTime(report.FinishedAt))
+	}
+
+	if !report.IsExposed {
+		fmt.Println("Трек не проходил через зоны инцидентов")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ЗОНА\tТИП\tВХОД\tВЫХОД\tДЛИТЕЛЬНОСТЬ\tТОЧЕК")
+	for _, e := range report.Exposures {
+		kind := "внутри"
+		if e.Transit {
+			kind = "пересечение"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n",
+			e.Name, kind, formatTime(e.EnteredAt), formatTime(e.ExitedAt),
+			time.Duration(e.DurationSec*float64(time.Second)).Round(time.Second), e.PointsInside)
+	}
+	w.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	client := registerClientFlags(fs)
+	reason := fs.String("reason", "", "причина изменения для истории версий")
+	nameField := fs.String("name-field", "", "свойство с названием (по умолчанию name)")
+	descriptionField := fs.String("description-field", "", "свойство с описанием (по умолчанию description)")
+	severityField := fs.String("severity-field", "", "свойство с серьезностью (по умолчанию severity)")
+	externalIDField := fs.String("external-id-field", "", "свойство с внешним идентификатором (по умолчанию external_id, затем id объекта)")
+	format := fs.String("format", "", "формат файла: geojson, kml или shapefile (по умолчанию по расширению)")
+	dryRun := fs.Bool("dry-run", false, "только проверить объекты и показать проблемы геометрии, ничего не сохраняя")
+	asJSON := fs.Bool("json", false, "вывести отчет в JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: geoctl import [флаги] <файл GeoJSON, KML или ZIP-архив Shapefile>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("не указан файл для импорта")
+	}
+
+	path := fs.Arg(0)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".kml":
+			*format = entity.ImportFormatKML
+		case ".zip":
+			*format = entity.ImportFormatShapefile
+		case ".json", ".geojson":
+			*format = entity.ImportFormatGeoJSON
+		}
+	}
+
+	query := url.Values{}
+	if *dryRun {
+		query.Set("dry_run", "true")
+	}
+	for key, value := range map[string]string{
+		"name_field":        *nameField,
+		"description_field": *descriptionField,
+		"severity_field":    *severityField,
+		"external_id_field": *externalIDField,
+		"reason":            *reason,
+		"format":            *format,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	endpoint := "/incidents/import"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var report entity.ImportIncidentsResponse
+	err = client.do(http.MethodPost, endpoint, importContentType(*format), data, &report)
+
+	// Отклоненный импорт возвращает отчет по объектам с кодом 422
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status == http.StatusUnprocessableEntity {
+		if json.Unmarshal(apiErr.body, &report) != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printImportReport(&report)
+	}
+
+	if report.DryRun {
+		if report.Failed > 0 {
+			return fmt.Errorf("проверка не пройдена: ошибок в объектах — %d", report.Failed)
+		}
+		return nil
+	}
+	if !report.Committed {
+		return fmt.Errorf("импорт отклонен: ошибок в объектах — %d, ничего не сохранено", report.Failed)
+	}
+	return nil
+}
+
+func importContentType(format string) string {
+	switch format {
+	case entity.ImportFormatKML:
+		return "application/vnd.google-earth.kml+xml"
+	case entity.ImportFormatShapefile:
+		return "application/zip"
+	default:
+		return "application/json"
+	}
+}
+
+func printImportReport(report *entity.ImportIncidentsResponse) {
+	switch {
+	case report.DryRun:
+		fmt.Printf("Пробный импорт: объектов %d, с ошибками %d, ничего не сохранено\n", report.Total, report.Failed)
+	case report.Committed:
+		fmt.Printf("Импортировано объектов: %d (создано %d, обновлено %d)\n", report.Total, report.Created, report.Updated)
+	default:
+		fmt.Printf("Объектов: %d, с ошибками: %d\n", report.Total, report.Failed)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tEXTERNAL ID\tНАЗВАНИЕ\tРЕЗУЛЬТАТ\tИНЦИДЕНТ")
+	for _, f := range report.Features {
+		if !report.Committed && f.Action != entity.ImportActionFailed && len(f.Issues) == 0 {
+			continue
+		}
+
+		messages := append([]string{}, f.Errors...)
+		for _, issue := range f.Issues {
+			messages = append(messages, issue.Message)
+		}
+
+		result := f.Action
+		if len(messages) > 0 {
+			result = strings.Join(messages, "; ")
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", f.Index, orDash(f.ExternalID), orDash(f.Name), result, orDash(f.ID))
+	}
+	w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `geoctl — утилита администрирования Geo Incident Service
+
+Использование:
+  geoctl <команда> [флаги]
+
+Команды:
+  exposure   Ретроспективный анализ трека (GPX или GeoJSON)
+  import     Импорт зон инцидентов из GeoJSON, KML или Shapefile (ZIP)
+  recheck    Пересчет сохраненных проверок локаций после правки зон
+
+Общие флаги:
+  -server    Адрес сервиса (по умолчанию $GEOCTL_SERVER или http://localhost:8080)
+  -api-key   Ключ API (по умолчанию $API_KEY)
+
+Справка по команде: geoctl <команда> -h
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "exposure":
+		err = runExposure(os.Args[2:])
+	case "recheck":
+		err = runRecheck(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "неизвестная команда: %s\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ошибка:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+func runRecheck(args []string) error {
+	fs := flag.NewFlagSet("recheck", flag.ExitOnError)
+	client := registerClientFlags(fs)
+	from := fs.String("from", "", "начало периода (RFC3339 или YYYY-MM-DD), обязательно")
+	to := fs.String("to", "", "конец периода, не включительно (по умолчанию — текущее время)")
+	geometry := fs.String("geometry", entity.RecheckGeometryCurrent, "геометрия зон: current или historical")
+	incident := fs.String("incident", "", "ограничить отчет проверками, связанными с инцидентом")
+	apply := fs.Bool("apply", false, "записать пересчитанные значения (без флага — только отчет)")
+	limit := fs.Int("limit", 0, "максимальное число изменений (по умолчанию — ограничение сервиса)")
+	asJSON := fs.Bool("json", false, "вывести отчет в JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Использование: geoctl recheck -from <время> [флаги]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *from == "" {
+		fs.Usage()
+		return errors.New("не указано начало периода")
+	}
+
+	req := entity.RecheckRequest{
+		Geometry: *geometry,
+		Apply:    *apply,
+		Limit:    *limit,
+		To:       time.Now(),
+	}
+
+	var err error
+	if req.From, err = parseTimeFlag(*from); err != nil {
+		return fmt.Errorf("-from: %w", err)
+	}
+	if *to != "" {
+		if req.To, err = parseTimeFlag(*to); err != nil {
+			return fmt.Errorf("-to: %w", err)
+		}
+	}
+	if *incident != "" {
+		id, err := uuid.Parse(*incident)
+		if err != nil {
+			return fmt.Errorf("-incident: %w", err)
+		}
+		req.IncidentID = &id
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	var report entity.RecheckReport
+	if err := client.do(http.MethodPost, "/location/recheck", "application/json", body, &report); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printRecheckReport(&report)
+	return nil
+}
+
+// parseTimeFlag принимает время в RFC3339 или дату YYYY-MM-DD в локальном часовом поясе
+func parseTimeFlag(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, time.Local)
+}
+
+func printRecheckReport(report *entity.RecheckReport) {
+	fmt.Printf("Период: %s — %s, геометрия: %s\n",
+		formatTime(&report.From), formatTime(&report.To), report.Geometry)
+	fmt.Printf("Проверено: %d, изменилось: %d (стали опасными: %d, стали безопасными: %d)\n",
+		report.Scanned, report.Changed, report.BecameDanger, report.BecameSafe)
+	if report.Skipped > 0 {
+		fmt.Printf("Пропущено проверок с точностью координат: %d\n", report.Skipped)
+	}
+
+	if report.Truncated {
+		fmt.Println("Отчет обрезан по лимиту, повторите запуск для оставшихся проверок")
+	}
+
+	if report.Changed == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ПРОВЕРКА\tПОЛЬЗОВАТЕЛЬ\tВРЕМЯ\tБЫЛО\tСТАЛО")
+	for _, ch := range report.Changes {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			ch.CheckID, ch.UserID, formatTime(&ch.CreatedAt),
+			formatMatch(ch.OldIsDanger, ch.OldIncidentID), formatMatch(ch.NewIsDanger, ch.NewIncidentID))
+	}
+	w.Flush()
+
+	if report.Applied {
+		fmt.Println("Изменения записаны")
+	} else {
+		fmt.Println("Изменения не записаны, для записи запустите с флагом -apply")
+	}
+}
+
+func formatMatch(isDanger bool, incidentID *uuid.UUID) string {
+	if !isDanger || incidentID == nil {
+		return "безопасно"
+	}
+	return "опасно " + incidentID.String()
+}
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"net/netip"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -11,10 +14,13 @@ import (
 
 type Config struct {
 	HTTPServer  HTTPServerConfig
+	GRPCServer  GRPCServerConfig
 	Postgres    PostgresConfig
 	Redis       RedisConfig
 	RetryClient RetryClient
 	Worker      Worker
+	Location    LocationConfig
+	Incident    IncidentConfig
 }
 
 type HTTPServerConfig struct {
@@ -23,8 +29,22 @@ type HTTPServerConfig struct {
 	HTTPServerWriteTimeout time.Duration
 	HTTPServerIdleTimeout  time.Duration
 	APIKey                 string
+	// OperatorAPIKeys сопоставляет персональный ключ оператора с его именем
+	OperatorAPIKeys map[string]string
 
 	StatsWindowMinutes int
+
+	DeviceAuthMode         string
+	DeviceSignatureMaxSkew time.Duration
+
+	// PublicBaseURL — внешний адрес сервиса для абсолютных ссылок в публичных документах (лента CAP)
+	PublicBaseURL string
+	// TrustedProxies — адреса и подсети прокси, которым разрешено передавать X-Forwarded-*
+	TrustedProxies []string
+}
+
+type GRPCServerConfig struct {
+	GRPCServerPort string
 }
 
 type PostgresConfig struct {
@@ -49,6 +69,23 @@ type RedisConfig struct {
 	RedisMaxRetries   int
 }
 
+type LocationConfig struct {
+	TrajectoryMaxGap time.Duration
+	InsideOverlap    float64
+	UncertainPolicy  string
+	SpoofMaxSpeedKmh float64
+	SpoofPolicy      string
+	MaxBackdate      time.Duration
+}
+
+type IncidentConfig struct {
+	TwoPersonSeverity  string
+	UpdateNotifyWindow time.Duration
+	OverlapMaxPercent  float64
+	PolygonValidation  string
+	CapSender          string
+}
+
 type Worker struct {
 	WebhookURL string
 	MaxRetries int
@@ -88,6 +125,21 @@ func Load() (*Config, error) {
 		}
 	}
 
+	operatorKeys, err := parseOperatorKeys(viper.GetString("OPERATOR_API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	publicBaseURL, err := parsePublicBaseURL(viper.GetString("PUBLIC_BASE_URL"))
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := parseTrustedProxies(viper.GetString("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		HTTPServer: HTTPServerConfig{
 			HTTPServerPort:         mustLoad("HTTP_SERVER_PORT"),
@@ -95,7 +147,15 @@ func Load() (*Config, error) {
 			HTTPServerWriteTimeout: viper.GetDuration("HTTP_SERVER_WRITE_TIMEOUT"),
 			HTTPServerIdleTimeout:  viper.GetDuration("HTTP_SERVER_IDLE_TIMEOUT"),
 			APIKey:                 mustLoad("API_KEY"),
+			OperatorAPIKeys:        operatorKeys,
 			StatsWindowMinutes:     viper.GetInt("STATS_WINDOW_MINUTES"),
+			DeviceAuthMode:         viper.GetString("DEVICE_AUTH_MODE"),
+			DeviceSignatureMaxSkew: viper.GetDuration("DEVICE_SIGNATURE_MAX_SKEW"),
+			PublicBaseURL:          publicBaseURL,
+			TrustedProxies:         trustedProxies,
+		},
+		GRPCServer: GRPCServerConfig{
+			GRPCServerPort: viper.GetString("GRPC_SERVER_PORT"),
 		},
 		Postgres: PostgresConfig{
 			PostgresURL:                         mustLoad("DATABASE_URL"),
@@ -127,11 +187,88 @@ func Load() (*Config, error) {
 			RetryWaitMax: viper.GetDuration("RETRY_WAIT_MAX"),
 			Timeout:      viper.GetDuration("RETRY_TIMEOUT"),
 		},
+		Location: LocationConfig{
+			TrajectoryMaxGap: viper.GetDuration("LOCATION_TRAJECTORY_MAX_GAP"),
+			InsideOverlap:    viper.GetFloat64("LOCATION_INSIDE_OVERLAP"),
+			UncertainPolicy:  viper.GetString("LOCATION_UNCERTAIN_POLICY"),
+			SpoofMaxSpeedKmh: viper.GetFloat64("LOCATION_SPOOF_MAX_SPEED_KMH"),
+			SpoofPolicy:      viper.GetString("LOCATION_SPOOF_POLICY"),
+			MaxBackdate:      viper.GetDuration("LOCATION_MAX_BACKDATE"),
+		},
+		Incident: IncidentConfig{
+			TwoPersonSeverity:  viper.GetString("INCIDENT_TWO_PERSON_SEVERITY"),
+			UpdateNotifyWindow: viper.GetDuration("INCIDENT_UPDATE_NOTIFY_WINDOW"),
+			OverlapMaxPercent:  viper.GetFloat64("INCIDENT_OVERLAP_MAX_PERCENT"),
+			PolygonValidation:  viper.GetString("INCIDENT_POLYGON_VALIDATION"),
+			CapSender:          viper.GetString("INCIDENT_CAP_SENDER"),
+		},
 	}
 
 	return cfg, nil
 }
 
+// parseOperatorKeys разбирает список вида "alice:key-a,bob:key-b" в отображение ключ -> имя оператора
+func parseOperatorKeys(raw string) (map[string]string, error) {
+	keys := make(map[string]string)
+
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, key, ok := strings.Cut(pair, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("некорректная запись OPERATOR_API_KEYS: %q", pair)
+		}
+		if _, exists := keys[key]; exists {
+			return nil, fmt.Errorf("ключ оператора %q указан в OPERATOR_API_KEYS повторно", name)
+		}
+
+		keys[key] = name
+	}
+
+	return keys, nil
+}
+
+// parsePublicBaseURL проверяет, что PUBLIC_BASE_URL — абсолютный http(s) адрес, и убирает завершающий слеш
+func parsePublicBaseURL(raw string) (string, error) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	if raw == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("некорректный PUBLIC_BASE_URL: %q", raw)
+	}
+
+	return raw, nil
+}
+
+// parseTrustedProxies разбирает список IP-адресов и подсетей CIDR через запятую
+func parseTrustedProxies(raw string) ([]string, error) {
+	var proxies []string
+
+	for _, proxy := range strings.Split(raw, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				return nil, fmt.Errorf("некорректная запись TRUSTED_PROXIES: %q", proxy)
+			}
+		}
+
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies, nil
+}
+
 func mustLoad(name string) string {
 	value := viper.GetString(name)
 	if value == "" {
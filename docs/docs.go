@@ -15,40 +15,40 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/incidents": {
-            "get": {
+        "/cap/alerts": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Метод для получения пагенированного списка инцидентов. Поддерживает параметры limit и offset.",
+                "description": "Принимает оповещение в формате Common Alerting Protocol 1.2. Зона инцидента строится из элементов polygon и circle первого блока info с зоной (несколько фигур объединяются), название — из headline или event, серьезность — из severity (Extreme, Severe, Moderate, Minor → extreme, severe, moderate, minor; Unknown не задает серьезность). msgType Alert создает инцидент-черновик с external_id \"cap:\u003csender\u003e:\u003cidentifier\u003e\", который проходит обычное согласование. Update обновляет инцидент сообщения, указанного в references (если ссылка неизвестна — создает новый). Cancel завершает опубликованный инцидент или деактивирует черновик. Сообщения со status, отличным от Actual, msgType Ack и Error, а также истекшие по expires возвращаются с action=ignored; повторная доставка того же сообщения — с action=duplicate, а одновременная доставка сообщения, которое еще обрабатывается, — с 409.",
+                "consumes": [
+                    "text/xml"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "cap"
                 ],
-                "summary": "Получает список активных инцидентов",
+                "summary": "Принимает сообщение CAP 1.2",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Количество записей",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение (для пагинации)",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Сообщение CAP 1.2 (XML)",
+                        "name": "alert",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.GetIncidentsResponse"
+                            "$ref": "#/definitions/entity.CapIngestResponse"
                         }
                     },
                     "400": {
@@ -57,6 +57,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -64,47 +76,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Метод для создания инцидента. Создает инцидент с названием, описанием и гео-зоной (Polygon). Зона определяет опасную область для проверок локаций.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/cap/feed": {
+            "get": {
+                "description": "Возвращает ленту Atom (профиль CAP-over-Atom): для каждого опубликованного активного инцидента — запись со ссылкой на документ CAP и самим сообщением CAP 1.2 в content. Ответ содержит ETag и Last-Modified, вычисленные из updated_at опубликованных и завершенных инцидентов (правки черновиков и инцидентов на согласовании их не меняют); при совпадении If-None-Match или отсутствии изменений после If-Modified-Since возвращается 304 без тела. Завершение, деактивация или возврат опубликованного инцидента на согласование тоже меняет ленту.",
                 "produces": [
-                    "application/json"
+                    "application/atom+xml"
                 ],
                 "tags": [
-                    "incidents"
+                    "cap"
                 ],
-                "summary": "Создает новый инцидент",
+                "summary": "Лента Atom с сообщениями CAP об активных инцидентах",
                 "parameters": [
                     {
-                        "description": "Incident data",
-                        "name": "incident",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.CreateIncidentRequest"
-                        }
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Лента Atom",
                         "schema": {
-                            "$ref": "#/definitions/entity.IncidentResponse"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
+                    "304": {
+                        "description": "Лента не изменилась"
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -115,25 +121,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/incidents/stats": {
+        "/cap/incidents/{id}": {
             "get": {
-                "description": "Получает статистику инцидентов",
+                "description": "Возвращает сообщение CAP 1.2 (msgType Alert) об опубликованном активном инциденте: identifier составляется из id и updated_at, зона передается внешним контуром в polygon, серьезность — в severity. Для черновиков, завершенных и деактивированных инцидентов возвращается 404. Условные запросы обрабатываются так же, как для ленты.",
                 "produces": [
-                    "application/json"
+                    "application/cap+xml"
                 ],
                 "tags": [
-                    "incidents"
+                    "cap"
+                ],
+                "summary": "Сообщение CAP об инциденте",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag из предыдущего ответа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified из предыдущего ответа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Получает статистику инцидентов",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Сообщение CAP",
                         "schema": {
-                            "$ref": "#/definitions/entity.StatsResponse"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "304": {
+                        "description": "Инцидент не изменился"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -147,47 +177,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/incidents/{id}": {
+        "/devices": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Метод для получения инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути.",
+                "description": "Метод для получения пагинированного списка зарегистрированных устройств. Секреты не возвращаются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "devices"
                 ],
-                "summary": "Получает инцидент по ID",
+                "summary": "Получает список устройств",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Incident ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (для пагинации)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.GetIncidentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/entity.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.GetDeviceResponse"
+                            }
                         }
                     },
                     "500": {
@@ -198,13 +224,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Метод для обновления инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Можно обновить только название, описание и гео-зону (Polygon).",
+                "description": "Метод регистрирует мобильное устройство пользователя и возвращает его секрет для HMAC-подписи запросов. Секрет возвращается только один раз.",
                 "consumes": [
                     "application/json"
                 ],
@@ -212,32 +238,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "devices"
                 ],
-                "summary": "Обновляет инцидент",
+                "summary": "Регистрирует устройство",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Incident ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Incident",
-                        "name": "incident",
+                        "description": "Device data",
+                        "name": "device",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.UpdateIncidentRequest"
+                            "$ref": "#/definitions/entity.RegisterDeviceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/entity.IncidentResponse"
+                            "$ref": "#/definitions/entity.RegisterDeviceResponse"
                         }
                     },
                     "400": {
@@ -253,36 +272,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/devices/{id}": {
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Метод для деактивации инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути.",
+                "description": "Метод отзывает устройство по уникальному идентификатору (UUID). Подписанные им запросы перестают приниматься.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "incidents"
+                    "devices"
                 ],
-                "summary": "Деактивирует инцидент",
+                "summary": "Отзывает устройство",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Incident ID",
+                        "description": "Device ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.IncidentResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -290,6 +308,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -299,35 +329,41 @@ const docTemplate = `{
                 }
             }
         },
-        "/location/check": {
-            "post": {
-                "description": "Метод проверяет находится ли пользователь в опасной зоне. Принимает координаты пользователя и userID.",
-                "consumes": [
-                    "application/json"
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Открывает поток Server-Sent Events с доменными событиями всех реплик: incident.created, incident.updated, incident.deactivated, location.danger, webhook.failed, webhook.dlq. Каждое событие передается с полями id, event (тип) и data (JSON entity.Event). События можно отфильтровать по инциденту и по типам (через запятую). Пропущенные во время отключения события не досылаются.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "location"
+                    "events"
                 ],
-                "summary": "Проверяет локацию",
+                "summary": "Лента событий оператора (SSE)",
                 "parameters": [
                     {
-                        "description": "User data",
-                        "name": "location",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.CheckLocationRequest"
-                        }
+                        "type": "string",
+                        "description": "ID инцидента",
+                        "name": "incident_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "incident.created,location.danger",
+                        "description": "Типы событий через запятую",
+                        "name": "types",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.CheckLocationResponse"
+                            "$ref": "#/definitions/entity.Event"
                         }
                     },
                     "400": {
@@ -336,8 +372,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/entity.ErrorResponse"
                         }
@@ -345,26 +381,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/system/health": {
+        "/incidents": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Метод для проверки здоровья сервиса",
+                "description": "Метод для получения пагенированного списка инцидентов. Поддерживает параметры limit и offset.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "incidents"
+                ],
+                "summary": "Получает список активных инцидентов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (для пагинации)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент времени (RFC3339), на который нужно получить состояние инцидентов",
+                        "name": "as_of",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Проверяет здоровье сервиса",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.HealthResponse"
+                            "$ref": "#/definitions/entity.GetIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "500": {
@@ -374,204 +436,3613 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "entity.CheckLocationRequest": {
-            "type": "object",
-            "required": [
-                "user_id",
-                "user_location"
-            ],
-            "properties": {
-                "user_id": {
-                    "type": "string",
-                    "example": "123e4567-e89b-12d3-a456-426614174000"
-                },
-                "user_location": {
-                    "$ref": "#/definitions/entity.UserLocation"
-                }
-            }
-        },
-        "entity.CheckLocationResponse": {
-            "type": "object",
-            "properties": {
-                "incidents": {
-                    "type": "array",
-                    "items": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для создания инцидента. Создает инцидент с названием, описанием, серьезностью и гео-зоной (Polygon) в статусе draft. Зона начинает учитываться в проверках локаций только после публикации (POST /incidents/{id}/approve). parent_id делает инцидент частью более крупного события. В overlaps ответа перечисляются активные инциденты, с которыми пересекается зона (кроме предков по иерархии); при INCIDENT_OVERLAP_MAX_PERCENT \u003e 0 создание отклоняется с 409, если пересечение превышает порог.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Создает новый инцидент",
+                "parameters": [
+                    {
+                        "description": "Incident data",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Выгрузка зон для ГИС (QGIS, Google Earth). Формат задается параметром format (geojson, kml, csv) или заголовком Accept: application/geo+json, application/vnd.google-earth.kml+xml, text/csv; без них возвращается GeoJSON FeatureCollection. Фильтры те же, что у GET /incidents, но без limit выгружаются все инциденты. Строки читаются из базы и отправляются клиенту потоком; ошибка после начала выгрузки обрывает ответ.",
+                "produces": [
+                    "application/geo+json",
+                    "application/vnd.google-earth.kml+xml",
+                    "text/csv"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Выгружает инциденты в GeoJSON, KML или WKT/CSV",
+                "parameters": [
+                    {
+                        "enum": [
+                            "geojson",
+                            "kml",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей (по умолчанию все)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент времени (RFC3339), на который нужно получить состояние инцидентов",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Загружает набор зон (например, от ведомства-партнера) из GeoJSON FeatureCollection, KML или ZIP-архива Shapefile (.shp, .dbf, необязательные .prj и .cpg). Формат задается параметром format или определяется по Content-Type и содержимому; координаты Shapefile пересчитываются в EPSG:4326 по .prj. Свойства объектов (в KML — name, description и ExtendedData, в Shapefile — атрибуты .dbf) переносятся в поля инцидента: по умолчанию name, description, severity и external_id (если его нет — id объекта); имена свойств можно переопределить параметрами *_field. Каждый объект проверяется; при любой ошибке ничего не сохраняется и возвращается 422 с отчетом по объектам. Иначе все объекты сохраняются одной транзакцией: инцидент с тем же external_id обновляется, остальные создаются черновиками. Черновик остается черновиком, инцидент на согласовании или опубликованный снимается с публикации и возвращается на согласование (для серьезности от INCIDENT_TWO_PERSON_SEVERITY нужен персональный ключ оператора), завершенный инцидент не изменяется. Зоны проверяются на пересечения с активными инцидентами: пересечения попадают в отчет, а при превышении INCIDENT_OVERLAP_MAX_PERCENT объект отклоняется. С dry_run=true объекты только проверяются: ответ 200 содержит отчет с ошибками, пересечениями и топологическими проблемами полигонов.",
+                "consumes": [
+                    "application/json",
+                    "application/vnd.google-earth.kml+xml",
+                    "application/zip"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Импортирует зоны из GeoJSON, KML или Shapefile",
+                "parameters": [
+                    {
+                        "description": "FeatureCollection с полигонами, KML или ZIP-архив Shapefile",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.GeoJsonFeatureCollection"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "geojson",
+                            "kml",
+                            "shapefile"
+                        ],
+                        "type": "string",
+                        "description": "Формат файла",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свойство с названием (по умолчанию name)",
+                        "name": "name_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свойство с описанием (по умолчанию description)",
+                        "name": "description_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свойство с серьезностью (по умолчанию severity)",
+                        "name": "severity_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Свойство с внешним идентификатором (по умолчанию external_id)",
+                        "name": "external_id_field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить объекты, ничего не сохраняя",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина изменения для истории версий",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ImportIncidentsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для оценки последствий публикации зоны. Возвращает пользователей, достоверные проверки которых за окно window_minutes попали в полигон, разбивку по интервалам bucket_minutes и активные инциденты, с которыми полигон пересекается. Ничего не сохраняется, вебхуки не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Оценивает охват черновика зоны",
+                "parameters": [
+                    {
+                        "description": "Draft zone",
+                        "name": "preview",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PreviewIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PreviewIncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/stats": {
+            "get": {
+                "description": "Получает статистику инцидентов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Получает статистику инцидентов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/validate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Проверяет полигон до сохранения: помимо структуры ищет самопересечения, повторяющиеся соседние вершины, вырожденные кольца, пересечения колец, дыры вне внешнего кольца и превышение предела вершин. Для каждой проблемы возвращаются код, индекс кольца и вершины и координаты места. С repair=true некорректный полигон исправляется PostGIS ST_MakeValid; если результат не один полигон, причина возвращается в repair_error. Ничего не сохраняется.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Проверяет топологию полигона",
+                "parameters": [
+                    {
+                        "description": "Полигон",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ValidateAreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ValidateAreaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для получения инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. С include=tree в поле children возвращаются все потомки инцидента.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Получает инцидент по ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент времени (RFC3339), на который нужно получить состояние инцидента",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "tree"
+                        ],
+                        "type": "string",
+                        "description": "tree — вложить потомков инцидента",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetIncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для обновления инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Можно обновить название, описание, серьезность, гео-зону (Polygon) и родителя (пустой parent_id отвязывает инцидент). Родитель не может быть завершенным или потомком инцидента. При смене зоны в overlaps ответа перечисляются пересечения с другими активными инцидентами (кроме предков и потомков); в строгом режиме (INCIDENT_OVERLAP_MAX_PERCENT) превышение порога дает 409. Черновик остается черновиком; инцидент на согласовании или опубликованный после изменения возвращается на согласование (и перестает учитываться в проверках до одобрения), автором отправки становится автор изменения. Завершенный инцидент изменить нельзя (409). Для инцидентов, подпадающих под правило двух лиц, такое изменение требует персонального ключа оператора (иначе 403).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Обновляет инцидент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для деактивации инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Вместе с инцидентом завершаются все его потомки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Деактивирует инцидент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Причина деактивации",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит инцидент в статус published, после чего он учитывается в проверках локаций. Инциденты с серьезностью ниже INCIDENT_TWO_PERSON_SEVERITY можно опубликовать прямо из черновика. Начиная с этого уровня инцидент должен быть на согласовании, а одобряющий оператор должен отличаться от отправившего (операторы различаются по персональным API ключам), иначе возвращается 403.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Публикует инцидент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "transition",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentTransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/buffer": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Строит буфер зоны на distance_m метров (отрицательное значение сужает зону) с помощью PostGIS ST_Buffer. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление с записью версии и событием incident.updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Расширяет или сужает зону инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры буфера",
+                        "name": "operation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.BufferIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GeometryOperationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/diff": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает изменившиеся поля и изменение зоны между версиями from и to: добавленную и убранную области (GeoJSON) и их площади в квадратных метрах. По умолчанию to — последняя версия, from — предыдущая.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Сравнивает две версии инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Исходная версия",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Конечная версия",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/difference": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Вычитает из зоны зону другого инцидента (incident_id) или переданный полигон (area) с помощью PostGIS ST_Difference, например чтобы исключить больницу. Результат должен быть одним непустым полигоном, иначе 422. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Вырезает полигон из зоны инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Вычитаемый полигон",
+                        "name": "operation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CombineIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GeometryOperationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает версии инцидента от последней к первой. Версия создается при каждом создании, обновлении и деактивации и содержит состояние инцидента после изменения, автора (оператор, чей персональный API ключ использован), причину и период действия.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Получает историю изменений инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (для пагинации)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetIncidentHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Отклоняет инцидент, находящийся на согласовании, и возвращает его в статус draft. Причину отклонения можно передать в поле reason.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Возвращает инцидент в черновик",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "transition",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentTransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит опубликованный инцидент в статус resolved, после чего он перестает учитываться в проверках локаций.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Завершает инцидент",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "transition",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentTransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/simplify": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Сокращает число вершин зоны с допуском tolerance_m метров с помощью PostGIS ST_SimplifyPreserveTopology. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Упрощает границу зоны инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Параметры упрощения",
+                        "name": "operation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.SimplifyIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GeometryOperationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Переводит черновик в статус pending_review. Автор отправки (оператор по персональному API ключу) запоминается: для инцидентов с серьезностью не ниже INCIDENT_TWO_PERSON_SEVERITY он не сможет сам одобрить публикацию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Отправляет инцидент на согласование",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина",
+                        "name": "transition",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentTransitionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/union": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Объединяет зону с зоной другого инцидента (incident_id) или с переданным полигоном (area) с помощью PostGIS ST_Union. Результат должен быть одним полигоном: непересекающиеся зоны дают 422. Второй инцидент не изменяется. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Объединяет зону инцидента с другим полигоном",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Второй операнд",
+                        "name": "operation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CombineIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GeometryOperationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/updates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Возвращает сообщения по инциденту от последнего к первому. Поддерживает параметры limit и offset.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Получает сообщения о ходе инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (для пагинации)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetIncidentUpdatesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Добавляет к инциденту сообщение с отметкой времени, не меняя его описание и зону. С notify=true сообщение отправляется вебхуком пользователям, проверявшим локацию внутри зоны за INCIDENT_UPDATE_NOTIFY_WINDOW (только для опубликованных инцидентов). Автор — оператор, чей персональный API ключ использован.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Публикует сообщение о ходе инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update data",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateIncidentUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateIncidentUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/updates/{update_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Получает сообщение о ходе инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Update ID",
+                        "name": "update_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentUpdate"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Заменяет текст сообщения. Повторно вебхуки не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Исправляет сообщение о ходе инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Update ID",
+                        "name": "update_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update data",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateIncidentUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentUpdate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Удаляет сообщение о ходе инцидента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Update ID",
+                        "name": "update_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IncidentResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/check": {
+            "post": {
+                "description": "Метод проверяет находится ли пользователь в опасной зоне. Принимает координаты пользователя и userID. При check_trajectory=true дополнительно проверяется отрезок от предыдущей проверки пользователя до текущей, и пересеченные по пути зоны возвращаются в поле transit. Если передана точность user_location.accuracy_m, совпадения классифицируются как inside, possibly_inside или outside по доле круга точности внутри зоны.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Проверяет локацию",
+                "parameters": [
+                    {
+                        "description": "User data",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckLocationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID зарегистрированного устройства",
+                        "name": "X-Device-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unix-время подписи в секундах",
+                        "name": "X-Timestamp",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Одноразовое значение для защиты от повторов",
+                        "name": "X-Nonce",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HMAC-SHA256 подпись запроса (hex)",
+                        "name": "X-Signature",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckLocationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/flagged": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод возвращает проверки, помеченные детектором подмены координат (невозможная скорость перемещения от предыдущей проверки). Поддерживает фильтр по статусу рассмотрения и параметры limit и offset.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Получает подозрительные проверки локации",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "dismissed"
+                        ],
+                        "type": "string",
+                        "description": "Статус рассмотрения",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (для пагинации)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.GetFlaggedChecksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/flagged/{id}/review": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод фиксирует решение оператора по подозрительной проверке. confirm подтверждает подмену и исключает проверку из статистики, dismiss снимает подозрение и возвращает проверку в учет.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Рассматривает подозрительную проверку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Location check ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review decision",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReviewLocationCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/recheck": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Административное задание: пересчитывает совпадения проверок за период [from, to) по текущей (geometry=current) или действовавшей в момент проверки (geometry=historical) геометрии зон. Возвращает отчет об изменившихся проверках. При apply=true изменения записываются, и статистика начинает их учитывать. incident_id ограничивает отчет проверками, которые были или стали привязаны к этой зоне. Пересчитываются только точечные проверки: проверки, сохраненные с точностью координат (accuracy_m), пропускаются и учитываются в поле skipped, так как их статус зависит от доли круга точности внутри зоны.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Пересчитывает сохраненные проверки локаций",
+                "parameters": [
+                    {
+                        "description": "Recheck job",
+                        "name": "recheck",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RecheckReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/replay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод проверяет, находился ли пользователь в опасной зоне в момент at, по зонам в том виде, в котором они действовали тогда. Координаты передаются в user_location; если их нет, используется последняя достоверная проверка пользователя user_id не позже at. Результат не сохраняется, вебхуки и события не отправляются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Повторяет проверку локации на момент времени",
+                "parameters": [
+                    {
+                        "description": "Replay request",
+                        "name": "replay",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReplayLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReplayLocationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/location/ws": {
+            "get": {
+                "description": "Открывает WebSocket-соединение. Первым сообщением клиент отправляет {\"type\":\"auth\"} с подписью устройства (device_id, timestamp, nonce, signature от строки GET\\n/api/v1/location/ws\\nTIMESTAMP\\nNONCE\\nSHA256(\"\")). Если подпись не обязательна (DEVICE_AUTH_MODE не enforce), можно передать user_id. Затем клиент отправляет сообщения {\"type\":\"position\",\"user_location\":{...}}, а сервер присылает {\"type\":\"state\"} только при изменении состояния опасности.",
+                "tags": [
+                    "location"
+                ],
+                "summary": "Потоковый канал позиций (WebSocket)",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/osmand": {
+            "get": {
+                "description": "Совместимый с OsmAnd/Traccar эндпоинт для готовых трекеров. Параметры передаются в query-строке или form-данных. Идентификатор id должен совпадать с токеном трекера (tracker_token), выданным при регистрации устройства, проверка выполняется от имени пользователя устройства. Если DEVICE_AUTH_MODE не enforce, принимаются также ID устройства и UUID пользователя. Время timestamp принимается в unix-секундах, миллисекундах или RFC 3339; точки из будущего получают время запроса, а точки старше LOCATION_MAX_BACKDATE отклоняются с кодом 400, как и координаты вне допустимого диапазона. Ответ 200 без тела.",
+                "tags": [
+                    "location"
+                ],
+                "summary": "Прием координат по протоколу OsmAnd",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен трекера",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Широта",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Долгота",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Координаты строкой lat,lon (вместо lat/lon)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Время фиксации координат",
+                        "name": "timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Точность в метрах",
+                        "name": "accuracy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Совместимый с OsmAnd/Traccar эндпоинт для готовых трекеров. Параметры передаются в query-строке или form-данных. Идентификатор id должен совпадать с токеном трекера (tracker_token), выданным при регистрации устройства, проверка выполняется от имени пользователя устройства. Если DEVICE_AUTH_MODE не enforce, принимаются также ID устройства и UUID пользователя. Время timestamp принимается в unix-секундах, миллисекундах или RFC 3339; точки из будущего получают время запроса, а точки старше LOCATION_MAX_BACKDATE отклоняются с кодом 400, как и координаты вне допустимого диапазона. Ответ 200 без тела.",
+                "tags": [
+                    "location"
+                ],
+                "summary": "Прием координат по протоколу OsmAnd",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Токен трекера",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Широта",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Долгота",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Координаты строкой lat,lon (вместо lat/lon)",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Время фиксации координат",
+                        "name": "timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Точность в метрах",
+                        "name": "accuracy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/routes/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод принимает планируемый маршрут (GeoJSON LineString) и возвращает все активные инциденты, через которые он проходит. Для каждого инцидента указываются точки входа и выхода и длина участка маршрута внутри зоны в метрах.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "location"
+                ],
+                "summary": "Проверяет безопасность маршрута",
+                "parameters": [
+                    {
+                        "description": "Route",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckRouteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.CheckRouteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/health": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Метод для проверки здоровья сервиса",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Проверяет здоровье сервиса",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.HealthResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tracks/exposure": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Принимает записанный трек в формате GPX или GeoJSON (LineString/MultiLineString с properties.coordTimes или коллекция Point с properties.time) и возвращает отчет о зонах, в которых побывал трек, и о зонах, пересеченных между точками. Каждая точка сверяется с зонами, действовавшими в момент ее записи. Формат определяется по параметру format, заголовку Content-Type или содержимому. Проверки локаций не сохраняются.",
+                "consumes": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracks"
+                ],
+                "summary": "Ретроспективный анализ трека",
+                "parameters": [
+                    {
+                        "enum": [
+                            "gpx",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "description": "Формат трека",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Трек GPX или GeoJSON",
+                        "name": "track",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExposureReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.AffectedUser": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "entity.BufferIncidentRequest": {
+            "type": "object",
+            "required": [
+                "distance_m"
+            ],
+            "properties": {
+                "commit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "distance_m": {
+                    "type": "number",
+                    "maximum": 50000,
+                    "minimum": -50000,
+                    "example": 300
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Зона расширена на 300 м"
+                }
+            }
+        },
+        "entity.CapIngestResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "created"
+                },
+                "certainty": {
+                    "type": "string",
+                    "example": "Observed"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "Наводнение"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "identifier": {
+                    "type": "string",
+                    "example": "43b080713727"
+                },
+                "incident_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "msg_type": {
+                    "type": "string",
+                    "example": "Alert"
+                },
+                "onset": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "status Exercise: обрабатываются только сообщения Actual"
+                },
+                "sender": {
+                    "type": "string",
+                    "example": "hsas@dhs.gov"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "Severe"
+                },
+                "urgency": {
+                    "type": "string",
+                    "example": "Immediate"
+                }
+            }
+        },
+        "entity.CheckLocationRequest": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "user_location"
+            ],
+            "properties": {
+                "check_trajectory": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_location": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                }
+            }
+        },
+        "entity.CheckLocationResponse": {
+            "type": "object",
+            "properties": {
+                "incidents": {
+                    "type": "array",
+                    "items": {
                         "$ref": "#/definitions/entity.LocationCheckIncident"
                     }
                 },
-                "is_danger": {
+                "is_danger": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "type": "string",
+                    "example": "inside"
+                },
+                "transit": {
+                    "$ref": "#/definitions/entity.TransitEvent"
+                }
+            }
+        },
+        "entity.CheckRouteRequest": {
+            "type": "object",
+            "required": [
+                "route"
+            ],
+            "properties": {
+                "route": {
+                    "$ref": "#/definitions/entity.GeoJsonLineString"
+                }
+            }
+        },
+        "entity.CheckRouteResponse": {
+            "type": "object",
+            "properties": {
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RouteIncident"
+                    }
+                },
+                "is_safe": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "entity.CombineIncidentRequest": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "commit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "incident_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Зона расширена на 300 м"
+                }
+            }
+        },
+        "entity.CreateIncidentRequest": {
+            "type": "object",
+            "required": [
+                "area",
+                "name"
+            ],
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Описание наводнения"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "Наводнение"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Сообщение МЧС"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "minor",
+                        "moderate",
+                        "severe",
+                        "extreme"
+                    ],
+                    "example": "moderate"
+                }
+            }
+        },
+        "entity.CreateIncidentUpdateRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Пожар локализован на 40%"
+                },
+                "notify": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "entity.CreateIncidentUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "notified": {
+                    "description": "Число пользователей, которым поставлен в очередь вебхук",
+                    "type": "integer",
+                    "example": 12
+                },
+                "truncated": {
+                    "description": "Пользователей в зоне больше, чем допустимо уведомить за один раз",
+                    "type": "boolean",
+                    "example": false
+                },
+                "update": {
+                    "$ref": "#/definitions/entity.IncidentUpdate"
+                }
+            }
+        },
+        "entity.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "string",
+                    "example": "email is required"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "invalid input"
+                }
+            }
+        },
+        "entity.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "data": {},
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "incident_id": {
+                    "type": "string",
+                    "example": "8489c629-9e32-4d2d-9475-430349257bd7"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "incident.created"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user-123"
+                }
+            }
+        },
+        "entity.Exposure": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Описание наводнения"
+                },
+                "duration_sec": {
+                    "type": "number",
+                    "example": 900
+                },
+                "entered_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "entry_point": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                },
+                "exit_point": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                },
+                "exited_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:45:00Z"
+                },
+                "incident_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наводнение"
+                },
+                "points_inside": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "transit": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.ExposureReport": {
+            "type": "object",
+            "properties": {
+                "exposures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Exposure"
+                    }
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2026-01-18T19:00:00Z"
+                },
+                "is_exposed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "point_count": {
+                    "type": "integer",
+                    "example": 340
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:00:00Z"
+                },
+                "track_name": {
+                    "type": "string",
+                    "example": "Morning walk"
+                }
+            }
+        },
+        "entity.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "name"
+                },
+                "new": {},
+                "old": {}
+            }
+        },
+        "entity.FlaggedLocationCheck": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "flag_reason": {
+                    "type": "string",
+                    "example": "impossible_travel"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "implied_speed_kmh": {
+                    "type": "number",
+                    "example": 1840.5
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "is_danger": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "is_excluded": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "review_status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string",
+                    "example": "operator"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_location": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                }
+            }
+        },
+        "entity.GeoJsonFeature": {
+            "type": "object",
+            "properties": {
+                "geometry": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "zone-42"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Feature"
+                }
+            }
+        },
+        "entity.GeoJsonFeatureCollection": {
+            "type": "object",
+            "required": [
+                "features",
+                "type"
+            ],
+            "properties": {
+                "features": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/entity.GeoJsonFeature"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "FeatureCollection"
+                }
+            }
+        },
+        "entity.GeoJsonLineString": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number",
+                            "format": "float64"
+                        }
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "LineString"
+                }
+            }
+        },
+        "entity.GeoJsonPolygon": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "array",
+                            "items": {
+                                "type": "number",
+                                "format": "float64"
+                            }
+                        }
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Polygon"
+                }
+            }
+        },
+        "entity.GeometryDiff": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "object"
+                },
+                "added_area_m2": {
+                    "type": "number",
+                    "example": 60000
+                },
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "new_area_m2": {
+                    "type": "number",
+                    "example": 180000
+                },
+                "old_area_m2": {
+                    "type": "number",
+                    "example": 125000
+                },
+                "removed": {
+                    "type": "object"
+                },
+                "removed_area_m2": {
+                    "type": "number",
+                    "example": 5000
+                }
+            }
+        },
+        "entity.GeometryOperationResponse": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "area_change_m2": {
+                    "type": "number",
+                    "example": 270000.3
+                },
+                "area_m2": {
+                    "type": "number",
+                    "example": 1250000.5
+                },
+                "committed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "buffer"
+                },
+                "overlaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentOverlap"
+                    }
+                },
+                "previous_area_m2": {
+                    "type": "number",
+                    "example": 980000.2
+                }
+            }
+        },
+        "entity.GeometryResult": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "area_m2": {
+                    "type": "number",
+                    "example": 1250000.5
+                },
+                "previous_area_m2": {
+                    "type": "number",
+                    "example": 980000.2
+                }
+            }
+        },
+        "entity.GetDeviceResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "8489c629-9e32-4d2d-9475-430349257bd7"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Pixel 8"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                }
+            }
+        },
+        "entity.GetFlaggedChecksResponse": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FlaggedLocationCheck"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "entity.GetIncidentHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentVersion"
+                    }
+                }
+            }
+        },
+        "entity.GetIncidentResponse": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "children": {
+                    "description": "Дочерние инциденты, если запрошено дерево (include=tree)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.GetIncidentResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание наводнения"
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "zone-42"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "latest_update": {
+                    "description": "Последнее сообщение о ходе инцидента",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.IncidentUpdate"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наводнение"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
+                "submitted_by": {
+                    "type": "string",
+                    "example": "operator"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.GetIncidentUpdatesResponse": {
+            "type": "object",
+            "properties": {
+                "updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentUpdate"
+                    }
+                }
+            }
+        },
+        "entity.GetIncidentsResponse": {
+            "type": "object",
+            "properties": {
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.GetIncidentResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "entity.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ImpactBucket": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2026-01-18T18:15:00Z"
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "entity.ImportFeatureResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "created"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "zone-42"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "issues": {
+                    "description": "Issues — проблемы геометрии с местами, заполняются при пробном импорте",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PolygonIssue"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Зона подтопления"
+                },
+                "overlaps": {
+                    "description": "Overlaps — пересечения зоны с активными инцидентами",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentOverlap"
+                    }
+                }
+            }
+        },
+        "entity.ImportIncidentsResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ImportFeatureResult"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.IncidentDiffResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FieldChange"
+                    }
+                },
+                "from_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "geometry": {
+                    "$ref": "#/definitions/entity.GeometryDiff"
+                },
+                "incident_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "to_version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.IncidentOverlap": {
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string"
+                },
+                "incident_percent": {
+                    "type": "number",
+                    "example": 80.1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наводнение"
+                },
+                "overlap_area_m2": {
+                    "type": "number",
+                    "example": 125000.5
+                },
+                "overlap_percent": {
+                    "type": "number",
+                    "example": 35.2
+                }
+            }
+        },
+        "entity.IncidentResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "ошибка ввода: неверная область"
+                },
+                "overlaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentOverlap"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "успешно создано"
+                }
+            }
+        },
+        "entity.IncidentStats": {
+            "type": "object",
+            "properties": {
+                "incident_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "own_user_count": {
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "user_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.IncidentTransitionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Границы согласованы с МЧС"
+                }
+            }
+        },
+        "entity.IncidentUpdate": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "operator"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Пожар локализован на 40%"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                }
+            }
+        },
+        "entity.IncidentVersion": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "change_reason": {
+                    "type": "string",
+                    "example": "Уточнены границы зоны"
+                },
+                "changed_by": {
+                    "type": "string",
+                    "example": "operator"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Описание наводнения"
+                },
+                "is_active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наводнение"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "moderate"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "valid_to": {
+                    "type": "string",
+                    "example": "2026-01-18T19:30:00Z"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.LocationCheckIncident": {
+            "type": "object",
+            "properties": {
+                "Status": {
+                    "type": "string",
+                    "example": "inside"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PolygonIssue": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "self_intersection"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "segments 0 and 2 of ring 0 intersect"
+                },
+                "point": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    },
+                    "example": [
+                        37.615,
+                        55.755
+                    ]
+                },
+                "ring": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "vertex": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "entity.PreviewIncidentRequest": {
+            "type": "object",
+            "required": [
+                "area"
+            ],
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "bucket_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1,
+                    "example": 15
+                },
+                "window_minutes": {
+                    "type": "integer",
+                    "maximum": 10080,
+                    "minimum": 1,
+                    "example": 60
+                }
+            }
+        },
+        "entity.PreviewIncidentResponse": {
+            "type": "object",
+            "properties": {
+                "affected_users": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "bucket_minutes": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ImpactBucket"
+                    }
+                },
+                "overlaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.IncidentOverlap"
+                    }
+                },
+                "since": {
+                    "type": "string",
+                    "example": "2026-01-18T17:30:00Z"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.AffectedUser"
+                    }
+                },
+                "window_minutes": {
+                    "type": "integer",
+                    "example": 60
+                }
+            }
+        },
+        "entity.RecheckChange": {
+            "type": "object",
+            "properties": {
+                "check_id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "new_incident_id": {
+                    "type": "string"
+                },
+                "new_is_danger": {
                     "type": "boolean",
                     "example": true
+                },
+                "old_incident_id": {
+                    "type": "string"
+                },
+                "old_is_danger": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_location": {
+                    "$ref": "#/definitions/entity.UserLocation"
                 }
             }
         },
-        "entity.CreateIncidentRequest": {
+        "entity.RecheckReport": {
             "type": "object",
-            "required": [
-                "area",
-                "name"
-            ],
             "properties": {
-                "area": {
-                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                "applied": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "description": {
+                "became_danger": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "became_safe": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "changed": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RecheckChange"
+                    }
+                },
+                "from": {
                     "type": "string",
-                    "maxLength": 1000,
-                    "example": "Описание наводнения"
+                    "example": "2026-01-18T00:00:00Z"
                 },
-                "name": {
+                "geometry": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 1,
-                    "example": "Наводнение"
+                    "example": "current"
+                },
+                "scanned": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-01-19T00:00:00Z"
+                },
+                "truncated": {
+                    "type": "boolean",
+                    "example": false
                 }
             }
         },
-        "entity.ErrorResponse": {
+        "entity.RecheckRequest": {
             "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
             "properties": {
-                "details": {
+                "apply": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "from": {
                     "type": "string",
-                    "example": "email is required"
+                    "example": "2026-01-18T00:00:00Z"
                 },
-                "error": {
+                "geometry": {
                     "type": "string",
-                    "example": "invalid input"
+                    "enum": [
+                        "current",
+                        "historical"
+                    ],
+                    "example": "current"
+                },
+                "incident_id": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 1,
+                    "example": 10000
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2026-01-19T00:00:00Z"
                 }
             }
         },
-        "entity.GeoJsonPolygon": {
+        "entity.RegisterDeviceRequest": {
             "type": "object",
+            "required": [
+                "user_id"
+            ],
             "properties": {
-                "coordinates": {
-                    "type": "array",
-                    "items": {
-                        "type": "array",
-                        "items": {
-                            "type": "array",
-                            "items": {
-                                "type": "number",
-                                "format": "float64"
-                            }
-                        }
-                    }
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Pixel 8"
                 },
-                "type": {
+                "user_id": {
                     "type": "string",
-                    "example": "Polygon"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 }
             }
         },
-        "entity.GetIncidentResponse": {
+        "entity.RegisterDeviceResponse": {
             "type": "object",
             "properties": {
-                "area": {
-                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                "id": {
+                    "type": "string",
+                    "example": "8489c629-9e32-4d2d-9475-430349257bd7"
                 },
-                "created_at": {
+                "secret": {
                     "type": "string",
-                    "example": "2026-01-18T18:30:00Z"
+                    "example": "4f7a0c..."
                 },
-                "description": {
+                "tracker_token": {
                     "type": "string",
-                    "example": "Описание наводнения"
+                    "example": "9b1e5d..."
                 },
-                "id": {
+                "user_id": {
                     "type": "string",
                     "example": "123e4567-e89b-12d3-a456-426614174000"
-                },
-                "is_active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "name": {
+                }
+            }
+        },
+        "entity.ReplayLocationRequest": {
+            "type": "object",
+            "required": [
+                "at"
+            ],
+            "properties": {
+                "at": {
                     "type": "string",
-                    "example": "Наводнение"
+                    "example": "2026-01-18T14:32:00Z"
                 },
-                "updated_at": {
+                "user_id": {
                     "type": "string",
-                    "example": "2026-01-18T18:30:00Z"
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "user_location": {
+                    "$ref": "#/definitions/entity.UserLocation"
                 }
             }
         },
-        "entity.GetIncidentsResponse": {
+        "entity.ReplayLocationResponse": {
             "type": "object",
             "properties": {
+                "as_of": {
+                    "type": "string",
+                    "example": "2026-01-18T14:32:00Z"
+                },
                 "incidents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.GetIncidentResponse"
+                        "$ref": "#/definitions/entity.LocationCheckIncident"
                     }
                 },
-                "total": {
-                    "type": "integer",
-                    "example": 10
-                }
-            }
-        },
-        "entity.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "components": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "is_danger": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "location_time": {
+                    "type": "string",
+                    "example": "2026-01-18T14:30:12Z"
                 },
                 "status": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "inside"
                 },
-                "timestamp": {
-                    "type": "string"
+                "user_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
                 },
-                "uptime": {
-                    "type": "string"
+                "user_location": {
+                    "$ref": "#/definitions/entity.UserLocation"
                 }
             }
         },
-        "entity.IncidentResponse": {
+        "entity.ReviewLocationCheckRequest": {
             "type": "object",
+            "required": [
+                "decision"
+            ],
             "properties": {
-                "error": {
+                "decision": {
                     "type": "string",
-                    "example": "ошибка ввода: неверная область"
+                    "enum": [
+                        "confirm",
+                        "dismiss"
+                    ],
+                    "example": "confirm"
                 },
-                "status": {
+                "reviewer": {
                     "type": "string",
-                    "example": "успешно создано"
+                    "maxLength": 255,
+                    "example": "operator"
                 }
             }
         },
-        "entity.IncidentStats": {
+        "entity.RouteIncident": {
             "type": "object",
             "properties": {
-                "incident_id": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "example": "Описание наводнения"
                 },
-                "name": {
-                    "type": "string"
+                "entry_point": {
+                    "$ref": "#/definitions/entity.UserLocation"
                 },
-                "user_count": {
-                    "type": "integer"
+                "exit_point": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "length_m": {
+                    "type": "number",
+                    "example": 1250.5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Наводнение"
                 }
             }
         },
-        "entity.LocationCheckIncident": {
+        "entity.SimplifyIncidentRequest": {
             "type": "object",
+            "required": [
+                "tolerance_m"
+            ],
             "properties": {
-                "description": {
-                    "type": "string"
+                "commit": {
+                    "type": "boolean",
+                    "example": false
                 },
-                "id": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Зона расширена на 300 м"
                 },
-                "name": {
-                    "type": "string"
+                "tolerance_m": {
+                    "type": "number",
+                    "maximum": 10000,
+                    "example": 25
                 }
             }
         },
@@ -590,6 +4061,31 @@ const docTemplate = `{
                 }
             }
         },
+        "entity.TransitEvent": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                },
+                "from_time": {
+                    "type": "string",
+                    "example": "2026-01-18T18:30:00Z"
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.LocationCheckIncident"
+                    }
+                },
+                "to": {
+                    "$ref": "#/definitions/entity.UserLocation"
+                },
+                "to_time": {
+                    "type": "string",
+                    "example": "2026-01-18T18:31:00Z"
+                }
+            }
+        },
         "entity.UpdateIncidentRequest": {
             "type": "object",
             "properties": {
@@ -606,12 +4102,48 @@ const docTemplate = `{
                     "maxLength": 255,
                     "minLength": 1,
                     "example": "Наводнение"
+                },
+                "parent_id": {
+                    "type": "string",
+                    "example": "123e4567-e89b-12d3-a456-426614174000"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 1000,
+                    "example": "Уточнены границы зоны"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "minor",
+                        "moderate",
+                        "severe",
+                        "extreme"
+                    ],
+                    "example": "severe"
+                }
+            }
+        },
+        "entity.UpdateIncidentUpdateRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Пожар локализован на 60%"
                 }
             }
         },
         "entity.UserLocation": {
             "type": "object",
             "properties": {
+                "accuracy_m": {
+                    "type": "number",
+                    "example": 25
+                },
                 "lat": {
                     "type": "number"
                 },
@@ -619,6 +4151,43 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "entity.ValidateAreaRequest": {
+            "type": "object",
+            "required": [
+                "area"
+            ],
+            "properties": {
+                "area": {
+                    "$ref": "#/definitions/entity.GeoJsonPolygon"
+                },
+                "repair": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "entity.ValidateAreaResponse": {
+            "type": "object",
+            "properties": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PolygonIssue"
+                    }
+                },
+                "repair_error": {
+                    "type": "string",
+                    "example": "результат операции не является одним полигоном: получен MULTIPOLYGON"
+                },
+                "repaired": {
+                    "$ref": "#/definitions/entity.GeometryResult"
+                },
+                "valid": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
         }
     },
     "securityDefinitions": {
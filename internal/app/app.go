@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,13 +12,18 @@ import (
 
 	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/db"
+	myGrpc "github.com/levinOo/geo-incedent-service/internal/delivery/grpc"
 	myHttp "github.com/levinOo/geo-incedent-service/internal/delivery/http"
 	"github.com/levinOo/geo-incedent-service/internal/queue"
 	"github.com/levinOo/geo-incedent-service/internal/repo"
 	"github.com/levinOo/geo-incedent-service/internal/service"
 	"github.com/levinOo/geo-incedent-service/internal/worker"
+	"google.golang.org/grpc"
 )
 
+// shutdownStageTimeout ограничивает каждый этап остановки: потоковые соединения, HTTP и gRPC
+const shutdownStageTimeout = 5 * time.Second
+
 func Run() error {
 	// Context для graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -65,9 +71,21 @@ func Run() error {
 	repository := repo.NewRepo(postgres.Pool)
 	svc := service.NewService(repository, cfg, redisClient)
 
+	// Порт gRPC занимается до запуска воркера и HTTP сервера: при ошибке закрывать нужно только соединения с БД
+	var grpcLis net.Listener
+	if cfg.GRPCServer.GRPCServerPort != "" {
+		grpcLis, err = net.Listen("tcp", ":"+cfg.GRPCServer.GRPCServerPort)
+		if err != nil {
+			slog.Error("ошибка запуска grpc сервера", "error", err)
+			redisClient.Close()
+			postgres.Pool.Close()
+			return err
+		}
+	}
+
 	webhookSender := service.NewWebhookSender(cfg)
 	q := queue.NewQueue(redisClient.Client)
-	bgWorker := worker.NewWorker(q, webhookSender, cfg)
+	bgWorker := worker.NewWorker(q, webhookSender, svc.Events, cfg)
 
 	// Воркер
 	go func() {
@@ -75,7 +93,11 @@ func Run() error {
 		bgWorker.Run(ctx)
 	}()
 
-	router := myHttp.NewRouter(&cfg.HTTPServer, svc)
+	// Раздача событий ленты оператора подписчикам SSE этой реплики
+	go svc.Events.Run(ctx)
+
+	streamHub := myHttp.NewStreamHub()
+	router := myHttp.NewRouter(&cfg.HTTPServer, svc, streamHub)
 
 	// HTTP Server
 	srv := &http.Server{
@@ -96,15 +118,45 @@ func Run() error {
 
 	slog.Info("http сервер запущен", "port", cfg.HTTPServer.HTTPServerPort)
 
+	// gRPC Server для внутренних сервисов
+	var grpcServer *grpc.Server
+	if grpcLis != nil {
+		grpcServer = myGrpc.NewServer(&cfg.HTTPServer, svc)
+		go func() {
+			if err := grpcServer.Serve(grpcLis); err != nil {
+				slog.Error("ошибка работы grpc сервера", "error", err)
+			}
+		}()
+
+		slog.Info("grpc сервер запущен", "port", cfg.GRPCServer.GRPCServerPort)
+	} else {
+		slog.Info("grpc сервер отключен: GRPC_SERVER_PORT не задан")
+	}
+
 	// graceful shutdown
 	<-ctx.Done()
 	slog.Info("получен сигнал остановки")
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	// Потоковые соединения (WebSocket, SSE) не завершаются сами, и srv.Shutdown ждал бы их до
+	// истечения таймаута, поэтому они закрываются первыми. У каждого этапа свой таймаут, чтобы
+	// медленный этап не оставлял следующим уже истекший контекст.
+	hubCtx, cancelHub := context.WithTimeout(context.Background(), shutdownStageTimeout)
+	if err := streamHub.Shutdown(hubCtx); err != nil {
+		slog.Error("stream connections forced to close", "error", err)
+	}
+	cancelHub()
+
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), shutdownStageTimeout)
+	if err := srv.Shutdown(httpCtx); err != nil {
 		slog.Error("server forced to shutdown", "error", err)
 	}
+	cancelHTTP()
+
+	if grpcServer != nil {
+		grpcCtx, cancelGRPC := context.WithTimeout(context.Background(), shutdownStageTimeout)
+		stopGRPC(grpcCtx, grpcServer)
+		cancelGRPC()
+	}
 
 	slog.Info("closing database connections...")
 
@@ -117,3 +169,20 @@ func Run() error {
 	slog.Info("server exited properly")
 	return nil
 }
+
+// stopGRPC дожидается завершения активных вызовов, а по истечении ctx (например, при открытых
+// потоках позиций) останавливает сервер принудительно
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Error("grpc server forced to stop", "error", ctx.Err())
+		srv.Stop()
+	}
+}
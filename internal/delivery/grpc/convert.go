@@ -0,0 +1,108 @@
+package myGrpc
+
+import (
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	geoincidentv1 "github.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func polygonFromProto(p *geoincidentv1.Polygon) entity.GeoJsonPolygon {
+	rings := make([][][]float64, 0, len(p.GetRings()))
+	for _, ring := range p.GetRings() {
+		coords := make([][]float64, 0, len(ring.GetPositions()))
+		for _, pos := range ring.GetPositions() {
+			coords = append(coords, []float64{pos.GetLon(), pos.GetLat()})
+		}
+		rings = append(rings, coords)
+	}
+
+	return entity.GeoJsonPolygon{Type: "Polygon", Coordinates: rings}
+}
+
+func polygonToProto(p entity.GeoJsonPolygon) *geoincidentv1.Polygon {
+	rings := make([]*geoincidentv1.LinearRing, 0, len(p.Coordinates))
+	for _, ring := range p.Coordinates {
+		positions := make([]*geoincidentv1.Position, 0, len(ring))
+		for _, pos := range ring {
+			if len(pos) < 2 {
+				continue
+			}
+			positions = append(positions, &geoincidentv1.Position{Lon: pos[0], Lat: pos[1]})
+		}
+		rings = append(rings, &geoincidentv1.LinearRing{Positions: positions})
+	}
+
+	return &geoincidentv1.Polygon{Rings: rings}
+}
+
+func incidentToProto(inc *entity.GetIncidentResponse) *geoincidentv1.Incident {
+	children := make([]*geoincidentv1.Incident, 0, len(inc.Children))
+	for _, child := range inc.Children {
+		children = append(children, incidentToProto(child))
+	}
+
+	return &geoincidentv1.Incident{
+		Id:          inc.ID,
+		Name:        inc.Name,
+		Description: inc.Description,
+		Area:        polygonToProto(inc.Area),
+		IsActive:    inc.IsActive,
+		Status:      inc.Status,
+		Severity:    inc.Severity,
+		SubmittedBy: inc.SubmittedBy,
+		ParentId:    inc.ParentID,
+		Children:    children,
+		CreatedAt:   timestamppb.New(inc.CreatedAt),
+		UpdatedAt:   timestamppb.New(inc.UpdatedAt),
+	}
+}
+
+func locationFromProto(l *geoincidentv1.UserLocation) entity.UserLocation {
+	return entity.UserLocation{
+		Lat:       l.GetLat(),
+		Lon:       l.GetLon(),
+		AccuracyM: l.AccuracyM,
+	}
+}
+
+func locationToProto(l entity.UserLocation) *geoincidentv1.UserLocation {
+	return &geoincidentv1.UserLocation{
+		Lat:       l.Lat,
+		Lon:       l.Lon,
+		AccuracyM: l.AccuracyM,
+	}
+}
+
+func locationIncidentsToProto(incidents []*entity.LocationCheckIncident) []*geoincidentv1.LocationIncident {
+	result := make([]*geoincidentv1.LocationIncident, 0, len(incidents))
+	for _, inc := range incidents {
+		result = append(result, &geoincidentv1.LocationIncident{
+			Id:          inc.ID.String(),
+			Name:        inc.Name,
+			Description: inc.Description,
+			Status:      inc.Status,
+		})
+	}
+
+	return result
+}
+
+func checkLocationResponseToProto(resp *entity.CheckLocationResponse) *geoincidentv1.CheckLocationResponse {
+	result := &geoincidentv1.CheckLocationResponse{
+		IsDanger:  resp.IsDanger,
+		Status:    resp.Status,
+		Incidents: locationIncidentsToProto(resp.Incidents),
+	}
+
+	if resp.Transit != nil {
+		result.Transit = &geoincidentv1.TransitEvent{
+			From:      locationToProto(resp.Transit.From),
+			To:        locationToProto(resp.Transit.To),
+			FromTime:  timestamppb.New(resp.Transit.FromTime),
+			ToTime:    timestamppb.New(resp.Transit.ToTime),
+			Incidents: locationIncidentsToProto(resp.Transit.Incidents),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,198 @@
+package myGrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	geoincidentv1 "github.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultListLimit = 10
+
+type IncidentServer struct {
+	geoincidentv1.UnimplementedIncidentServiceServer
+	service *service.Service
+}
+
+func NewIncidentServer(service *service.Service) *IncidentServer {
+	return &IncidentServer{service: service}
+}
+
+func (s *IncidentServer) CreateIncident(ctx context.Context, req *geoincidentv1.CreateIncidentRequest) (*geoincidentv1.StatusResponse, error) {
+	if req.GetName() == "" || req.GetArea() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Некорректное тело запроса: name и area обязательны")
+	}
+
+	resp, err := s.service.Incident.Create(ctx, &entity.CreateIncidentRequest{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		Area:        polygonFromProto(req.GetArea()),
+		Severity:    req.GetSeverity(),
+		ParentID:    req.GetParentId(),
+		Reason:      req.GetReason(),
+		Actor:       operatorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, status.Errorf(incidentErrorCode(err), "Не удалось создать инцидент: %v", err)
+	}
+
+	return &geoincidentv1.StatusResponse{Status: resp.Status}, nil
+}
+
+func (s *IncidentServer) GetIncident(ctx context.Context, req *geoincidentv1.GetIncidentRequest) (*geoincidentv1.Incident, error) {
+	find := s.service.Incident.FindByID
+	if req.GetIncludeTree() {
+		find = s.service.Incident.FindTree
+	}
+
+	incident, err := find(ctx, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(incidentErrorCode(err), "Не удалось получить инцидент: %v", err)
+	}
+
+	return incidentToProto(incident), nil
+}
+
+func (s *IncidentServer) ListIncidents(ctx context.Context, req *geoincidentv1.ListIncidentsRequest) (*geoincidentv1.ListIncidentsResponse, error) {
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	offset := int(req.GetOffset())
+	if offset < 0 {
+		offset = 0
+	}
+
+	incidents, err := s.service.Incident.FindAll(ctx, limit, offset)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Не удалось получить список инцидентов: %v", err)
+	}
+
+	result := make([]*geoincidentv1.Incident, 0, len(incidents))
+	for _, inc := range incidents {
+		result = append(result, incidentToProto(inc))
+	}
+
+	return &geoincidentv1.ListIncidentsResponse{Incidents: result}, nil
+}
+
+func (s *IncidentServer) UpdateIncident(ctx context.Context, req *geoincidentv1.UpdateIncidentRequest) (*geoincidentv1.StatusResponse, error) {
+	update := &entity.UpdateIncidentRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Severity:    req.Severity,
+		ParentID:    req.ParentId,
+		Reason:      req.GetReason(),
+		Actor:       operatorFromContext(ctx),
+	}
+	if req.GetArea() != nil {
+		area := polygonFromProto(req.GetArea())
+		update.Area = &area
+	}
+
+	resp, err := s.service.Incident.Update(ctx, update, req.GetId())
+	if err != nil {
+		return nil, status.Errorf(incidentErrorCode(err), "Не удалось обновить инцидент: %v", err)
+	}
+
+	return &geoincidentv1.StatusResponse{Status: resp.Status}, nil
+}
+
+func (s *IncidentServer) DeleteIncident(ctx context.Context, req *geoincidentv1.DeleteIncidentRequest) (*geoincidentv1.StatusResponse, error) {
+	resp, err := s.service.Incident.Delete(ctx, req.GetId(), &entity.DeleteIncidentRequest{
+		Reason: req.GetReason(),
+		Actor:  operatorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, status.Errorf(incidentErrorCode(err), "Не удалось удалить инцидент: %v", err)
+	}
+
+	return &geoincidentv1.StatusResponse{Status: resp.Status}, nil
+}
+
+func (s *IncidentServer) GetStats(ctx context.Context, req *geoincidentv1.GetStatsRequest) (*geoincidentv1.GetStatsResponse, error) {
+	stats, err := s.service.Incident.GetStats(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Не удалось получить статистику: %v", err)
+	}
+
+	result := make([]*geoincidentv1.IncidentStats, 0, len(stats.Stats))
+	for _, st := range stats.Stats {
+		item := &geoincidentv1.IncidentStats{
+			IncidentId:   st.IncidentID.String(),
+			Name:         st.Name,
+			UserCount:    int32(st.UserCount),
+			OwnUserCount: int32(st.OwnUserCount),
+		}
+		if st.ParentID != nil {
+			item.ParentId = st.ParentID.String()
+		}
+		result = append(result, item)
+	}
+
+	return &geoincidentv1.GetStatsResponse{
+		Stats:         result,
+		WindowMinutes: int32(stats.WindowMinutes),
+	}, nil
+}
+
+func (s *IncidentServer) SubmitIncident(ctx context.Context, req *geoincidentv1.IncidentTransitionRequest) (*geoincidentv1.StatusResponse, error) {
+	return transition(ctx, req, s.service.Incident.Submit, "Не удалось отправить инцидент на согласование")
+}
+
+func (s *IncidentServer) ApproveIncident(ctx context.Context, req *geoincidentv1.IncidentTransitionRequest) (*geoincidentv1.StatusResponse, error) {
+	return transition(ctx, req, s.service.Incident.Approve, "Не удалось опубликовать инцидент")
+}
+
+func (s *IncidentServer) RejectIncident(ctx context.Context, req *geoincidentv1.IncidentTransitionRequest) (*geoincidentv1.StatusResponse, error) {
+	return transition(ctx, req, s.service.Incident.Reject, "Не удалось отклонить инцидент")
+}
+
+func (s *IncidentServer) ResolveIncident(ctx context.Context, req *geoincidentv1.IncidentTransitionRequest) (*geoincidentv1.StatusResponse, error) {
+	return transition(ctx, req, s.service.Incident.Resolve, "Не удалось завершить инцидент")
+}
+
+type transitionFunc func(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
+
+// transition выполняет переход статуса инцидента. Недопустимый переход возвращает
+// FailedPrecondition, нарушение правила двух лиц — PermissionDenied.
+func transition(ctx context.Context, req *geoincidentv1.IncidentTransitionRequest, fn transitionFunc, failure string) (*geoincidentv1.StatusResponse, error) {
+	resp, err := fn(ctx, req.GetId(), &entity.IncidentTransitionRequest{
+		Reason: req.GetReason(),
+		Actor:  operatorFromContext(ctx),
+	})
+	if err != nil {
+		return nil, status.Errorf(incidentErrorCode(err), "%s: %v", failure, err)
+	}
+
+	return &geoincidentv1.StatusResponse{Status: resp.Status}, nil
+}
+
+// incidentErrorCode возвращает InvalidArgument для ошибок в данных запроса, обнаруженных сервисом,
+// NotFound для неизвестного инцидента, AlreadyExists, если зона дублирует другой инцидент
+// в строгом режиме проверки пересечений, FailedPrecondition для недопустимого перехода
+// и PermissionDenied при нарушении правила двух лиц
+func incidentErrorCode(err error) codes.Code {
+	var topologyErr *validator.TopologyError
+
+	switch {
+	case errors.Is(err, service.ErrInvalidIncident), errors.Is(err, service.ErrInvalidIncidentID),
+		errors.Is(err, service.ErrInvalidParent), errors.As(err, &topologyErr):
+		return codes.InvalidArgument
+	case errors.Is(err, service.ErrIncidentNotFound):
+		return codes.NotFound
+	case errors.Is(err, service.ErrIncidentOverlap):
+		return codes.AlreadyExists
+	case errors.Is(err, service.ErrInvalidTransition):
+		return codes.FailedPrecondition
+	case errors.Is(err, service.ErrTwoPersonRule):
+		return codes.PermissionDenied
+	}
+	return codes.Internal
+}
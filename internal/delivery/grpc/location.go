@@ -0,0 +1,94 @@
+package myGrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	geoincidentv1 "github.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type LocationServer struct {
+	geoincidentv1.UnimplementedLocationServiceServer
+	service *service.Service
+}
+
+func NewLocationServer(service *service.Service) *LocationServer {
+	return &LocationServer{service: service}
+}
+
+func (s *LocationServer) CheckLocation(ctx context.Context, req *geoincidentv1.CheckLocationRequest) (*geoincidentv1.CheckLocationResponse, error) {
+	if req.GetUserId() == "" || req.GetUserLocation() == nil {
+		return nil, status.Error(codes.InvalidArgument, "Некорректное тело запроса: user_id и user_location обязательны")
+	}
+
+	resp, err := s.service.Location.CheckLocation(ctx, &entity.CheckLocationRequest{
+		UserID:          req.GetUserId(),
+		UserLocation:    locationFromProto(req.GetUserLocation()),
+		CheckTrajectory: req.GetCheckTrajectory(),
+	})
+	if err != nil {
+		code := codes.Internal
+		if errors.Is(err, service.ErrInvalidLocation) {
+			code = codes.InvalidArgument
+		}
+		return nil, status.Errorf(code, "Не удалось проверить локацию: %v", err)
+	}
+
+	return checkLocationResponseToProto(resp), nil
+}
+
+// StreamPositions проверяет каждую присланную позицию и отправляет состояние пользователя
+// только при изменении статуса или набора зон, а также при пересечении зон по пути.
+// Ошибка проверки одной позиции не разрывает поток.
+func (s *LocationServer) StreamPositions(stream grpc.BidiStreamingServer[geoincidentv1.PositionUpdate, geoincidentv1.LocationState]) error {
+	ctx := stream.Context()
+	lastStates := make(map[string]string)
+
+	for {
+		update, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if update.GetUserId() == "" || update.GetUserLocation() == nil {
+			slog.Warn("grpc: пропущена позиция без user_id или user_location")
+			continue
+		}
+
+		resp, err := s.service.Location.CheckLocation(ctx, &entity.CheckLocationRequest{
+			UserID:          update.GetUserId(),
+			UserLocation:    locationFromProto(update.GetUserLocation()),
+			CheckTrajectory: update.GetCheckTrajectory(),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			slog.Error("grpc: не удалось проверить позицию", "user_id", update.GetUserId(), "error", err)
+			continue
+		}
+
+		state := resp.StateKey()
+		if last, ok := lastStates[update.GetUserId()]; ok && last == state && resp.Transit == nil {
+			continue
+		}
+		lastStates[update.GetUserId()] = state
+
+		if err := stream.Send(&geoincidentv1.LocationState{
+			UserId: update.GetUserId(),
+			State:  checkLocationResponseToProto(resp),
+		}); err != nil {
+			return err
+		}
+	}
+}
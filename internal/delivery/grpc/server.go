@@ -0,0 +1,113 @@
+package myGrpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	geoincidentv1 "github.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyMetadata — ключ метаданных с API-ключом (аналог заголовка X-API-Key в HTTP)
+const apiKeyMetadata = "x-api-key"
+
+// operatorKey — ключ контекста с именем оператора, аутентифицированного персональным API ключом
+type operatorKey struct{}
+
+func NewServer(cfg *config.HTTPServerConfig, service *service.Service) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(LoggingUnaryInterceptor(), ApiKeyUnaryInterceptor(cfg)),
+		grpc.ChainStreamInterceptor(LoggingStreamInterceptor(), ApiKeyStreamInterceptor(cfg)),
+	)
+
+	geoincidentv1.RegisterIncidentServiceServer(srv, NewIncidentServer(service))
+	geoincidentv1.RegisterLocationServiceServer(srv, NewLocationServer(service))
+
+	return srv
+}
+
+// Interceptor для проверки API ключа в unary вызовах
+func ApiKeyUnaryInterceptor(cfg *config.HTTPServerConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		operator, err := checkApiKey(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, operatorKey{}, operator), req)
+	}
+}
+
+// Interceptor для проверки API ключа в потоковых вызовах
+func ApiKeyStreamInterceptor(cfg *config.HTTPServerConfig) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		operator, err := checkApiKey(ss.Context(), cfg)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &operatorStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), operatorKey{}, operator)})
+	}
+}
+
+// operatorStream подменяет контекст потока, чтобы обработчик видел аутентифицированного оператора
+type operatorStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *operatorStream) Context() context.Context {
+	return s.ctx
+}
+
+// checkApiKey проверяет API ключ и возвращает имя оператора для персонального ключа
+// или пустую строку для общего ключа
+func checkApiKey(ctx context.Context, cfg *config.HTTPServerConfig) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(apiKeyMetadata)
+
+	if len(keys) == 0 || keys[0] == "" {
+		slog.Error("missing API key metadata")
+		return "", status.Error(codes.Unauthenticated, "x-api-key metadata required")
+	}
+
+	if operator, ok := cfg.OperatorAPIKeys[keys[0]]; ok {
+		slog.Debug("operator API key validated", "operator", operator)
+		return operator, nil
+	}
+
+	if keys[0] != cfg.APIKey {
+		slog.Error("invalid API key")
+		return "", status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+
+	slog.Debug("API key validated")
+	return "", nil
+}
+
+// operatorFromContext возвращает имя оператора, сохраненное interceptor'ом API ключа
+func operatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
+
+// Interceptor для логирования unary вызовов
+func LoggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		slog.Info("grpc request", "method", info.FullMethod, "code", status.Code(err).String())
+		return resp, err
+	}
+}
+
+// Interceptor для логирования потоковых вызовов
+func LoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		slog.Info("grpc stream", "method", info.FullMethod, "code", status.Code(err).String())
+		return err
+	}
+}
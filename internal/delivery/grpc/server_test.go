@@ -0,0 +1,283 @@
+package myGrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	geoincidentv1 "github.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAPIKey = "key"
+
+type grpcTestEnv struct {
+	incidents    geoincidentv1.IncidentServiceClient
+	location     geoincidentv1.LocationServiceClient
+	incidentRepo *mocks.IncidentRepo
+	locationRepo *mocks.LocationRepo
+}
+
+func newGRPCTestEnv(t *testing.T) *grpcTestEnv {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	rdb := &db.Redis{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+
+	env := &grpcTestEnv{
+		incidentRepo: mocks.NewIncidentRepo(t),
+		locationRepo: mocks.NewLocationRepo(t),
+	}
+
+	cfg := &config.Config{HTTPServer: config.HTTPServerConfig{
+		APIKey:          testAPIKey,
+		OperatorAPIKeys: map[string]string{"alice-key": "alice"},
+	}}
+	svc := &service.Service{
+		Incident: service.NewIncidentService(env.incidentRepo, mocks.NewIncidentUpdateRepo(t), events.NewBus(rdb.Client), cfg),
+		Location: service.NewLocationService(env.locationRepo, env.incidentRepo, events.NewBus(rdb.Client), rdb, cfg),
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(&cfg.HTTPServer, svc)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	env.incidents = geoincidentv1.NewIncidentServiceClient(conn)
+	env.location = geoincidentv1.NewLocationServiceClient(conn)
+
+	return env
+}
+
+func authContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, testAPIKey)
+}
+
+func TestApiKeyInterceptor(t *testing.T) {
+	env := newGRPCTestEnv(t)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{
+			name:     "Missing API key",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "Invalid API key",
+			ctx:      metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "wrong"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "Valid API key",
+			ctx:      authContext(),
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Пустой запрос отклоняется обработчиком, поэтому до сервиса дело не доходит
+			_, err := env.incidents.CreateIncident(tt.ctx, &geoincidentv1.CreateIncidentRequest{})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestApiKeyInterceptor_Operator(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name      string
+		apiKey    string
+		wantActor string
+	}{
+		{name: "Operator key", apiKey: "alice-key", wantActor: "alice"},
+		{name: "Shared key", apiKey: testAPIKey, wantActor: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newGRPCTestEnv(t)
+			env.incidentRepo.On("Delete", mock.Anything, id, tt.wantActor, "").Return(nil, postgres.ErrIncidentNotFound)
+
+			// Метаданные x-actor больше не учитываются: автор берется только из ключа
+			ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, tt.apiKey, "x-actor", "mallory")
+			_, err := env.incidents.DeleteIncident(ctx, &geoincidentv1.DeleteIncidentRequest{Id: id.String()})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		})
+	}
+}
+
+func TestIncidentServer_ErrorCodes(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name     string
+		mock     func(r *mocks.IncidentRepo)
+		call     func(c geoincidentv1.IncidentServiceClient) error
+		wantCode codes.Code
+	}{
+		{
+			name: "Get with malformed id",
+			mock: func(r *mocks.IncidentRepo) {},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.GetIncident(authContext(), &geoincidentv1.GetIncidentRequest{Id: "not-a-uuid"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Get unknown incident",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindByID", mock.Anything, id).Return(nil, postgres.ErrIncidentNotFound)
+			},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.GetIncident(authContext(), &geoincidentv1.GetIncidentRequest{Id: id.String()})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Create with invalid polygon",
+			mock: func(r *mocks.IncidentRepo) {},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.CreateIncident(authContext(), &geoincidentv1.CreateIncidentRequest{
+					Name: "Zone",
+					Area: &geoincidentv1.Polygon{Rings: []*geoincidentv1.LinearRing{{
+						Positions: []*geoincidentv1.Position{{Lon: 0, Lat: 0}, {Lon: 1, Lat: 1}},
+					}}},
+				})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Update with unknown severity",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindByID", mock.Anything, id).Return(&entity.Incident{ID: id, Status: entity.IncidentStatusDraft}, nil)
+			},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				severity := "apocalyptic"
+				_, err := c.UpdateIncident(authContext(), &geoincidentv1.UpdateIncidentRequest{Id: id.String(), Severity: &severity})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Delete unknown incident",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("Delete", mock.Anything, id, "", "").Return(nil, postgres.ErrIncidentNotFound)
+			},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.DeleteIncident(authContext(), &geoincidentv1.DeleteIncidentRequest{Id: id.String()})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Submit unknown incident",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindByID", mock.Anything, id).Return(nil, postgres.ErrIncidentNotFound)
+			},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.SubmitIncident(authContext(), &geoincidentv1.IncidentTransitionRequest{Id: id.String()})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Approve resolved incident",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindByID", mock.Anything, id).Return(&entity.Incident{ID: id, Status: entity.IncidentStatusResolved}, nil)
+			},
+			call: func(c geoincidentv1.IncidentServiceClient) error {
+				_, err := c.ApproveIncident(authContext(), &geoincidentv1.IncidentTransitionRequest{Id: id.String()})
+				return err
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newGRPCTestEnv(t)
+			tt.mock(env.incidentRepo)
+
+			err := tt.call(env.incidents)
+			assert.Equal(t, tt.wantCode, status.Code(err), "error: %v", err)
+		})
+	}
+}
+
+func TestLocationServer_CheckLocation(t *testing.T) {
+	t.Run("Invalid coordinates", func(t *testing.T) {
+		env := newGRPCTestEnv(t)
+
+		_, err := env.location.CheckLocation(authContext(), &geoincidentv1.CheckLocationRequest{
+			UserId:       "user-1",
+			UserLocation: &geoincidentv1.UserLocation{Lat: 95, Lon: 10},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Missing user location", func(t *testing.T) {
+		env := newGRPCTestEnv(t)
+
+		_, err := env.location.CheckLocation(authContext(), &geoincidentv1.CheckLocationRequest{UserId: "user-1"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Inside zone", func(t *testing.T) {
+		env := newGRPCTestEnv(t)
+
+		zone := entity.Incident{
+			ID:       uuid.New(),
+			Name:     "Zone",
+			IsActive: true,
+			Area: entity.GeoJsonPolygon{
+				Type:        "Polygon",
+				Coordinates: [][][]float64{{{10, 10}, {11, 10}, {11, 11}, {10, 11}, {10, 10}}},
+			},
+		}
+		env.incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{zone}, nil)
+		env.locationRepo.On("SaveLocationCheck", mock.Anything, mock.Anything).Return(nil)
+
+		resp, err := env.location.CheckLocation(authContext(), &geoincidentv1.CheckLocationRequest{
+			UserId:       "user-1",
+			UserLocation: &geoincidentv1.UserLocation{Lat: 10.5, Lon: 10.5},
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.GetIsDanger())
+		require.Len(t, resp.GetIncidents(), 1)
+		assert.Equal(t, zone.ID.String(), resp.GetIncidents()[0].GetId())
+	})
+}
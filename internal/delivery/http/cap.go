@@ -0,0 +1,240 @@
+package myHttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/pkg/cap"
+)
+
+const maxCapMessageBytes = 4 << 20
+
+// Путь группы маршрутов CAP; от него строятся абсолютные ссылки в ленте и документах
+const capBasePath = "/api/v1/cap"
+
+type CapHandler interface {
+	IngestAlert(c *gin.Context)
+	GetFeed(c *gin.Context)
+	GetIncidentAlert(c *gin.Context)
+}
+
+type CapHandlerImpl struct {
+	service *service.Service
+	cfg     *config.HTTPServerConfig
+}
+
+func NewCapHandler(cfg *config.HTTPServerConfig, service *service.Service) CapHandler {
+	return &CapHandlerImpl{service: service, cfg: cfg}
+}
+
+// IngestAlert godoc
+// @Summary Принимает сообщение CAP 1.2
+// @Description Принимает оповещение в формате Common Alerting Protocol 1.2. Зона инцидента строится из элементов polygon и circle первого блока info с зоной (несколько фигур объединяются), название — из headline или event, серьезность — из severity (Extreme, Severe, Moderate, Minor → extreme, severe, moderate, minor; Unknown не задает серьезность). msgType Alert создает инцидент-черновик с external_id "cap:<sender>:<identifier>", который проходит обычное согласование. Update обновляет инцидент сообщения, указанного в references (если ссылка неизвестна — создает новый). Cancel завершает опубликованный инцидент или деактивирует черновик. Сообщения со status, отличным от Actual, msgType Ack и Error, а также истекшие по expires возвращаются с action=ignored; повторная доставка того же сообщения — с action=duplicate, а одновременная доставка сообщения, которое еще обрабатывается, — с 409.
+// @Tags cap
+// @Accept xml
+// @Produce json
+// @Security ApiKeyAuth
+// @Param alert body string true "Сообщение CAP 1.2 (XML)"
+// @Success 200 {object} entity.CapIngestResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /cap/alerts [post]
+func (h *CapHandlerImpl) IngestAlert(c *gin.Context) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCapMessageBytes))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	alert, err := cap.Parse(data)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное сообщение CAP",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Cap.Ingest(c, alert, operatorFromContext(c))
+	if err != nil {
+		code := incidentErrorStatus(err)
+		switch {
+		case errors.Is(err, service.ErrInvalidCapMessage):
+			code = http.StatusUnprocessableEntity
+		case errors.Is(err, service.ErrCapMessageInProgress):
+			code = http.StatusConflict
+		}
+		c.AbortWithStatusJSON(code, entity.ErrorResponse{
+			Error:   "Не удалось обработать сообщение CAP",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFeed godoc
+// @Summary Лента Atom с сообщениями CAP об активных инцидентах
+// @Description Возвращает ленту Atom (профиль CAP-over-Atom): для каждого опубликованного активного инцидента — запись со ссылкой на документ CAP и самим сообщением CAP 1.2 в content. Ответ содержит ETag и Last-Modified, вычисленные из updated_at опубликованных и завершенных инцидентов (правки черновиков и инцидентов на согласовании их не меняют); при совпадении If-None-Match или отсутствии изменений после If-Modified-Since возвращается 304 без тела. Завершение, деактивация или возврат опубликованного инцидента на согласование тоже меняет ленту.
+// @Tags cap
+// @Produce application/atom+xml
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param If-Modified-Since header string false "Last-Modified из предыдущего ответа"
+// @Success 200 {string} string "Лента Atom"
+// @Success 304 "Лента не изменилась"
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /cap/feed [get]
+func (h *CapHandlerImpl) GetFeed(c *gin.Context) {
+	doc, err := h.service.Cap.Feed(c, requestBaseURL(c, h.cfg)+capBasePath)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось сформировать ленту CAP",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	writeCapDocument(c, doc)
+}
+
+// GetIncidentAlert godoc
+// @Summary Сообщение CAP об инциденте
+// @Description Возвращает сообщение CAP 1.2 (msgType Alert) об опубликованном активном инциденте: identifier составляется из id и updated_at, зона передается внешним контуром в polygon, серьезность — в severity. Для черновиков, завершенных и деактивированных инцидентов возвращается 404. Условные запросы обрабатываются так же, как для ленты.
+// @Tags cap
+// @Produce application/cap+xml
+// @Param id path string true "Incident ID"
+// @Param If-None-Match header string false "ETag из предыдущего ответа"
+// @Param If-Modified-Since header string false "Last-Modified из предыдущего ответа"
+// @Success 200 {string} string "Сообщение CAP"
+// @Success 304 "Инцидент не изменился"
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /cap/incidents/{id} [get]
+func (h *CapHandlerImpl) GetIncidentAlert(c *gin.Context) {
+	doc, err := h.service.Cap.Document(c, c.Param("id"), requestBaseURL(c, h.cfg)+capBasePath)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCapDocumentNotFound) {
+			code = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(code, entity.ErrorResponse{
+			Error:   "Не удалось сформировать сообщение CAP",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	writeCapDocument(c, doc)
+}
+
+// writeCapDocument отдает документ с валидаторами или 304, если клиент уже получил эту версию
+func writeCapDocument(c *gin.Context, doc *entity.CapDocument) {
+	c.Header("ETag", doc.ETag)
+	if !doc.LastModified.IsZero() {
+		c.Header("Last-Modified", doc.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, doc.ETag, doc.LastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, doc.ContentType+"; charset=utf-8", doc.Body)
+}
+
+// notModified проверяет условные заголовки по RFC 9110: If-None-Match сравнивается с ETag
+// (слабое сравнение), а If-Modified-Since учитывается, только если If-None-Match не передан.
+// Last-Modified передается с точностью до секунды, поэтому время сравнивается с ней же.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		if err != nil {
+			return false
+		}
+		return !lastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// requestBaseURL возвращает адрес сервиса для абсолютных ссылок в ленте и документах CAP.
+// Эти маршруты публичны и кэшируются, поэтому адрес берется из PUBLIC_BASE_URL. Без него ссылки
+// строятся от Host запроса, а X-Forwarded-Proto и X-Forwarded-Host учитываются, только если запрос
+// пришел от доверенного прокси; ответ тогда помечается Vary, чтобы общий кэш не отдал чужой хост другим клиентам.
+func requestBaseURL(c *gin.Context, cfg *config.HTTPServerConfig) string {
+	if cfg.PublicBaseURL != "" {
+		return cfg.PublicBaseURL
+	}
+
+	c.Header("Vary", "Host, X-Forwarded-Proto, X-Forwarded-Host")
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+
+	if isTrustedProxy(c.RemoteIP(), cfg.TrustedProxies) {
+		if proto := firstForwarded(c.GetHeader("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwardedHost := firstForwarded(c.GetHeader("X-Forwarded-Host")); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+
+	return scheme + "://" + host
+}
+
+// firstForwarded возвращает первое значение списка из заголовка X-Forwarded-*
+func firstForwarded(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
+
+// isTrustedProxy проверяет, входит ли адрес соединения в TRUSTED_PROXIES
+func isTrustedProxy(remoteIP string, proxies []string) bool {
+	addr, err := netip.ParseAddr(remoteIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, proxy := range proxies {
+		if prefix, err := netip.ParsePrefix(proxy); err == nil {
+			if prefix.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if proxyAddr, err := netip.ParseAddr(proxy); err == nil && proxyAddr.Unmap() == addr {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package myHttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapRoutes_Auth(t *testing.T) {
+	env := newRouterTestEnv(t, "")
+	env.incidentRepo.On("FindPublished", mock.Anything).Return([]*entity.Incident{}, nil)
+	env.incidentRepo.On("LastModified", mock.Anything).Return(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), nil)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{
+			name:       "Feed is public",
+			method:     http.MethodGet,
+			path:       "/api/v1/cap/feed",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Document is public",
+			method:     http.MethodGet,
+			path:       "/api/v1/cap/incidents/not-a-uuid",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Ingest requires a key",
+			method:     http.MethodPost,
+			path:       "/api/v1/cap/alerts",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, env.server.URL+tt.path, strings.NewReader(""))
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestRequestBaseURL(t *testing.T) {
+	forged := map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.example"}
+
+	tests := []struct {
+		name       string
+		cfg        config.HTTPServerConfig
+		remoteAddr string
+		headers    map[string]string
+		want       string
+		wantVary   bool
+	}{
+		{
+			name:       "Public base URL ignores request headers",
+			cfg:        config.HTTPServerConfig{PublicBaseURL: "https://alerts.example.org"},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    forged,
+			want:       "https://alerts.example.org",
+		},
+		{
+			name:       "Forwarded headers from untrusted client are ignored",
+			remoteAddr: "203.0.113.5:1234",
+			headers:    forged,
+			want:       "http://geo.local",
+			wantVary:   true,
+		},
+		{
+			name:       "Forwarded headers from trusted proxy",
+			cfg:        config.HTTPServerConfig{TrustedProxies: []string{"10.0.0.0/8"}},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-Host": "alerts.example.org"},
+			want:       "https://alerts.example.org",
+			wantVary:   true,
+		},
+		{
+			name:       "Unknown forwarded scheme is ignored",
+			cfg:        config.HTTPServerConfig{TrustedProxies: []string{"10.0.0.1"}},
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-Proto": "javascript"},
+			want:       "http://geo.local",
+			wantVary:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "http://geo.local/api/v1/cap/feed", nil)
+			c.Request.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+
+			assert.Equal(t, tt.want, requestBaseURL(c, &tt.cfg))
+			assert.Equal(t, tt.wantVary, w.Header().Get("Vary") != "")
+		})
+	}
+}
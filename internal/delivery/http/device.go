@@ -0,0 +1,130 @@
+package myHttp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
+
+type DeviceHandler interface {
+	RegisterDevice(c *gin.Context)
+	GetDevices(c *gin.Context)
+	RevokeDevice(c *gin.Context)
+}
+
+type DeviceHandlerImpl struct {
+	service *service.Service
+}
+
+func NewDeviceHandler(service *service.Service) DeviceHandler {
+	return &DeviceHandlerImpl{service: service}
+}
+
+// RegisterDevice godoc
+// @Summary Регистрирует устройство
+// @Description Метод регистрирует мобильное устройство пользователя и возвращает его секрет для HMAC-подписи запросов. Секрет возвращается только один раз.
+// @Tags devices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param device body entity.RegisterDeviceRequest true "Device data"
+// @Success 201 {object} entity.RegisterDeviceResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /devices [post]
+func (h *DeviceHandlerImpl) RegisterDevice(c *gin.Context) {
+	var req entity.RegisterDeviceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Device.Register(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось зарегистрировать устройство",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetDevices godoc
+// @Summary Получает список устройств
+// @Description Метод для получения пагинированного списка зарегистрированных устройств. Секреты не возвращаются.
+// @Tags devices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "Количество записей"
+// @Param offset query int false "Смещение (для пагинации)"
+// @Success 200 {array} entity.GetDeviceResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /devices [get]
+func (h *DeviceHandlerImpl) GetDevices(c *gin.Context) {
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limitInt < 0 {
+		limitInt = 10
+	}
+
+	offsetInt, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offsetInt < 0 {
+		offsetInt = 0
+	}
+
+	resp, err := h.service.Device.FindAll(c, limitInt, offsetInt)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось получить список устройств",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeDevice godoc
+// @Summary Отзывает устройство
+// @Description Метод отзывает устройство по уникальному идентификатору (UUID). Подписанные им запросы перестают приниматься.
+// @Tags devices
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Device ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /devices/{id} [delete]
+func (h *DeviceHandlerImpl) RevokeDevice(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.Device.Revoke(c, id); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidDeviceID):
+			code = http.StatusBadRequest
+		case errors.Is(err, service.ErrDeviceNotFound):
+			code = http.StatusNotFound
+		case errors.Is(err, service.ErrDeviceAlreadyRevoked):
+			code = http.StatusConflict
+		}
+		c.AbortWithStatusJSON(code, entity.ErrorResponse{
+			Error:   "Не удалось отозвать устройство",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,131 @@
+package myHttp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
+
+// Интервал комментариев-пингов, которые не дают прокси закрыть простаивающее соединение
+const sseHeartbeatPeriod = 15 * time.Second
+
+type EventsHandler interface {
+	StreamEvents(c *gin.Context)
+}
+
+type EventsHandlerImpl struct {
+	service *service.Service
+	hub     *StreamHub
+}
+
+func NewEventsHandler(service *service.Service, hub *StreamHub) EventsHandler {
+	return &EventsHandlerImpl{service: service, hub: hub}
+}
+
+// StreamEvents godoc
+// @Summary Лента событий оператора (SSE)
+// @Description Открывает поток Server-Sent Events с доменными событиями всех реплик: incident.created, incident.updated, incident.deactivated, location.danger, webhook.failed, webhook.dlq. Каждое событие передается с полями id, event (тип) и data (JSON entity.Event). События можно отфильтровать по инциденту и по типам (через запятую). Пропущенные во время отключения события не досылаются.
+// @Tags events
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param incident_id query string false "ID инцидента"
+// @Param types query string false "Типы событий через запятую" example(incident.created,location.danger)
+// @Success 200 {object} entity.Event
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 503 {object} entity.ErrorResponse
+// @Router /events [get]
+func (h *EventsHandlerImpl) StreamEvents(c *gin.Context) {
+	filter, err := parseEventFilter(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры фильтра",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	untrack, err := h.hub.Track(cancel)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, entity.ErrorResponse{
+			Error:   "Сервер останавливается",
+			Details: err.Error(),
+		})
+		return
+	}
+	defer untrack()
+
+	sub := h.service.Events.Subscribe(filter)
+	defer h.service.Events.Unsubscribe(sub)
+
+	// Поток живет дольше, чем WriteTimeout http.Server
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		slog.Warn("sse: не удалось снять таймаут записи", "error", err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	slog.Info("sse: клиент подключен", "ip", c.ClientIP())
+	defer slog.Info("sse: клиент отключен", "ip", c.ClientIP())
+
+	heartbeat := time.NewTicker(sseHeartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case msg := <-sub.C:
+			if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", msg.Event.ID, msg.Event.Type, msg.Raw); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func parseEventFilter(c *gin.Context) (events.Filter, error) {
+	var filter events.Filter
+
+	if raw := c.Query("incident_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return filter, fmt.Errorf("некорректный incident_id: %w", err)
+		}
+		filter.IncidentID = &id
+	}
+
+	if raw := c.Query("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(entity.EventTypes, t) {
+				return filter, fmt.Errorf("неизвестный тип события: %s", t)
+			}
+			filter.Types = append(filter.Types, t)
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,80 @@
+package myHttp
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/pkg/track"
+)
+
+const maxTrackUploadBytes = 20 << 20
+
+type ExposureHandler interface {
+	AnalyzeTrack(c *gin.Context)
+}
+
+type ExposureHandlerImpl struct {
+	service *service.Service
+}
+
+func NewExposureHandler(service *service.Service) ExposureHandler {
+	return &ExposureHandlerImpl{service: service}
+}
+
+// AnalyzeTrack godoc
+// @Summary Ретроспективный анализ трека
+// @Description Принимает записанный трек в формате GPX или GeoJSON (LineString/MultiLineString с properties.coordTimes или коллекция Point с properties.time) и возвращает отчет о зонах, в которых побывал трек, и о зонах, пересеченных между точками. Каждая точка сверяется с зонами, действовавшими в момент ее записи. Формат определяется по параметру format, заголовку Content-Type или содержимому. Проверки локаций не сохраняются.
+// @Tags tracks
+// @Accept xml
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param format query string false "Формат трека" Enums(gpx, geojson)
+// @Param track body string true "Трек GPX или GeoJSON"
+// @Success 200 {object} entity.ExposureReport
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /tracks/exposure [post]
+func (h *ExposureHandlerImpl) AnalyzeTrack(c *gin.Context) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxTrackUploadBytes))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = track.DetectFormat(c.ContentType(), data)
+	}
+
+	t, err := track.Parse(format, data)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректный трек",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Exposure.AnalyzeTrack(c, t)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidTrack) {
+			code = http.StatusBadRequest
+		}
+		c.AbortWithStatusJSON(code, entity.ErrorResponse{
+			Error:   "Не удалось проанализировать трек",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,206 @@
+package myHttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
+
+type GeometryHandler interface {
+	Buffer(c *gin.Context)
+	Union(c *gin.Context)
+	Difference(c *gin.Context)
+	Simplify(c *gin.Context)
+	Validate(c *gin.Context)
+}
+
+type GeometryHandlerImpl struct {
+	service *service.Service
+}
+
+func NewGeometryHandler(service *service.Service) GeometryHandler {
+	return &GeometryHandlerImpl{service: service}
+}
+
+// Buffer godoc
+// @Summary Расширяет или сужает зону инцидента
+// @Description Строит буфер зоны на distance_m метров (отрицательное значение сужает зону) с помощью PostGIS ST_Buffer. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление с записью версии и событием incident.updated.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param operation body entity.BufferIncidentRequest true "Параметры буфера"
+// @Success 200 {object} entity.GeometryOperationResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/buffer [post]
+func (h *GeometryHandlerImpl) Buffer(c *gin.Context) {
+	var req entity.BufferIncidentRequest
+	if !bindGeometryRequest(c, &req, &req.GeometryCommit) {
+		return
+	}
+
+	resp, err := h.service.Geometry.Buffer(c, c.Param("id"), &req)
+	if err != nil {
+		abortGeometryError(c, "Не удалось построить буфер зоны", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Union godoc
+// @Summary Объединяет зону инцидента с другим полигоном
+// @Description Объединяет зону с зоной другого инцидента (incident_id) или с переданным полигоном (area) с помощью PostGIS ST_Union. Результат должен быть одним полигоном: непересекающиеся зоны дают 422. Второй инцидент не изменяется. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param operation body entity.CombineIncidentRequest true "Второй операнд"
+// @Success 200 {object} entity.GeometryOperationResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/union [post]
+func (h *GeometryHandlerImpl) Union(c *gin.Context) {
+	var req entity.CombineIncidentRequest
+	if !bindGeometryRequest(c, &req, &req.GeometryCommit) {
+		return
+	}
+
+	resp, err := h.service.Geometry.Union(c, c.Param("id"), &req)
+	if err != nil {
+		abortGeometryError(c, "Не удалось объединить зоны", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Difference godoc
+// @Summary Вырезает полигон из зоны инцидента
+// @Description Вычитает из зоны зону другого инцидента (incident_id) или переданный полигон (area) с помощью PostGIS ST_Difference, например чтобы исключить больницу. Результат должен быть одним непустым полигоном, иначе 422. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param operation body entity.CombineIncidentRequest true "Вычитаемый полигон"
+// @Success 200 {object} entity.GeometryOperationResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/difference [post]
+func (h *GeometryHandlerImpl) Difference(c *gin.Context) {
+	var req entity.CombineIncidentRequest
+	if !bindGeometryRequest(c, &req, &req.GeometryCommit) {
+		return
+	}
+
+	resp, err := h.service.Geometry.Difference(c, c.Param("id"), &req)
+	if err != nil {
+		abortGeometryError(c, "Не удалось вычесть полигон из зоны", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Simplify godoc
+// @Summary Упрощает границу зоны инцидента
+// @Description Сокращает число вершин зоны с допуском tolerance_m метров с помощью PostGIS ST_SimplifyPreserveTopology. Без commit результат только возвращается для просмотра; с commit=true зона заменяется через обычное обновление.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param operation body entity.SimplifyIncidentRequest true "Параметры упрощения"
+// @Success 200 {object} entity.GeometryOperationResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/simplify [post]
+func (h *GeometryHandlerImpl) Simplify(c *gin.Context) {
+	var req entity.SimplifyIncidentRequest
+	if !bindGeometryRequest(c, &req, &req.GeometryCommit) {
+		return
+	}
+
+	resp, err := h.service.Geometry.Simplify(c, c.Param("id"), &req)
+	if err != nil {
+		abortGeometryError(c, "Не удалось упростить зону", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Validate godoc
+// @Summary Проверяет топологию полигона
+// @Description Проверяет полигон до сохранения: помимо структуры ищет самопересечения, повторяющиеся соседние вершины, вырожденные кольца, пересечения колец, дыры вне внешнего кольца и превышение предела вершин. Для каждой проблемы возвращаются код, индекс кольца и вершины и координаты места. С repair=true некорректный полигон исправляется PostGIS ST_MakeValid; если результат не один полигон, причина возвращается в repair_error. Ничего не сохраняется.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param area body entity.ValidateAreaRequest true "Полигон"
+// @Success 200 {object} entity.ValidateAreaResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/validate [post]
+func (h *GeometryHandlerImpl) Validate(c *gin.Context) {
+	var req entity.ValidateAreaRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Geometry.Validate(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось проверить полигон",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func bindGeometryRequest(c *gin.Context, req any, commit *entity.GeometryCommit) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return false
+	}
+	commit.Actor = operatorFromContext(c)
+
+	return true
+}
+
+func abortGeometryError(c *gin.Context, failure string, err error) {
+	code := incidentErrorStatus(err)
+	if errors.Is(err, service.ErrInvalidGeometryOperation) {
+		code = http.StatusUnprocessableEntity
+	}
+
+	c.AbortWithStatusJSON(code, entity.ErrorResponse{
+		Error:   failure,
+		Details: err.Error(),
+	})
+}
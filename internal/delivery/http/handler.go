@@ -1,17 +1,36 @@
 package myHttp
 
-import "github.com/levinOo/geo-incedent-service/internal/service"
+import (
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
 
 type Handler struct {
 	Incident IncidentHandler
+	Updates  IncidentUpdateHandler
+	Geometry GeometryHandler
 	Location LocationHandler
 	Health   HealthHandler
+	Device   DeviceHandler
+	Stream   StreamHandler
+	Tracker  TrackerHandler
+	Exposure ExposureHandler
+	Events   EventsHandler
+	Cap      CapHandler
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(cfg *config.HTTPServerConfig, service *service.Service, hub *StreamHub) *Handler {
 	return &Handler{
 		Incident: NewIncidentHandler(service),
+		Updates:  NewIncidentUpdateHandler(service),
+		Geometry: NewGeometryHandler(service),
 		Location: NewLocationHandler(service),
 		Health:   NewHealthHandler(service),
+		Device:   NewDeviceHandler(service),
+		Stream:   NewStreamHandler(cfg, service, hub),
+		Tracker:  NewTrackerHandler(cfg, service),
+		Exposure: NewExposureHandler(service),
+		Events:   NewEventsHandler(service, hub),
+		Cap:      NewCapHandler(cfg, service),
 	}
 }
@@ -1,23 +1,48 @@
 package myHttp
 
 import (
+	"context"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
 	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/pkg/export"
+	"github.com/levinOo/geo-incedent-service/pkg/geoimport"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
 )
 
 type IncidentHandler interface {
 	CreateIncident(c *gin.Context)
 	GetIncidents(c *gin.Context)
+	ExportIncidents(c *gin.Context)
 	GetIncident(c *gin.Context)
 	UpdateIncident(c *gin.Context)
 	DeleteIncident(c *gin.Context)
 	GetStats(c *gin.Context)
+	GetHistory(c *gin.Context)
+	GetDiff(c *gin.Context)
+	PreviewIncident(c *gin.Context)
+	ImportIncidents(c *gin.Context)
+	SubmitIncident(c *gin.Context)
+	ApproveIncident(c *gin.Context)
+	RejectIncident(c *gin.Context)
+	ResolveIncident(c *gin.Context)
 }
 
+// Предел размера файла импорта; распакованные файлы Shapefile ограничиваются отдельно
+const maxImportUploadBytes = 64 << 20
+
+// Значение параметра include, при котором инцидент возвращается вместе с потомками
+const includeTree = "tree"
+
 type IncidentHandlerImpl struct {
 	service *service.Service
 }
@@ -28,7 +53,7 @@ func NewIncidentHandler(service *service.Service) IncidentHandler {
 
 // CreateIncident godoc
 // @Summary Создает новый инцидент
-// @Description Метод для создания инцидента. Создает инцидент с названием, описанием и гео-зоной (Polygon). Зона определяет опасную область для проверок локаций.
+// @Description Метод для создания инцидента. Создает инцидент с названием, описанием, серьезностью и гео-зоной (Polygon) в статусе draft. Зона начинает учитываться в проверках локаций только после публикации (POST /incidents/{id}/approve). parent_id делает инцидент частью более крупного события. В overlaps ответа перечисляются активные инциденты, с которыми пересекается зона (кроме предков по иерархии); при INCIDENT_OVERLAP_MAX_PERCENT > 0 создание отклоняется с 409, если пересечение превышает порог.
 // @Tags incidents
 // @Accept json
 // @Produce json
@@ -36,6 +61,7 @@ func NewIncidentHandler(service *service.Service) IncidentHandler {
 // @Param incident body entity.CreateIncidentRequest true "Incident data"
 // @Success 201 {object} entity.IncidentResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /incidents [post]
 func (h *IncidentHandlerImpl) CreateIncident(c *gin.Context) {
@@ -48,10 +74,11 @@ func (h *IncidentHandlerImpl) CreateIncident(c *gin.Context) {
 		})
 		return
 	}
+	req.Actor = operatorFromContext(c)
 
 	resp, err := h.service.Incident.Create(c, &req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
 			Error:   "Не удалось создать инцидент",
 			Details: err.Error(),
 		})
@@ -63,11 +90,13 @@ func (h *IncidentHandlerImpl) CreateIncident(c *gin.Context) {
 
 // GetIncident godoc
 // @Summary Получает инцидент по ID
-// @Description Метод для получения инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути.
+// @Description Метод для получения инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. С include=tree в поле children возвращаются все потомки инцидента.
 // @Tags incidents
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path string true "Incident ID"
+// @Param as_of query string false "Момент времени (RFC3339), на который нужно получить состояние инцидента"
+// @Param include query string false "tree — вложить потомков инцидента" Enums(tree)
 // @Success 200 {object} entity.GetIncidentResponse
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 404 {object} entity.ErrorResponse
@@ -76,9 +105,41 @@ func (h *IncidentHandlerImpl) CreateIncident(c *gin.Context) {
 func (h *IncidentHandlerImpl) GetIncident(c *gin.Context) {
 	id := c.Param("id")
 
-	resp, err := h.service.Incident.FindByID(c, id)
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	include := c.Query("include")
+	if include != "" && include != includeTree {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректный параметр include",
+			Details: "include=" + include,
+		})
+		return
+	}
+	if include == includeTree && asOf != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Дерево инцидентов доступно только для текущего состояния",
+			Details: "include=tree несовместим с as_of",
+		})
+		return
+	}
+
+	var (
+		resp *entity.GetIncidentResponse
+		err  error
+	)
+	switch {
+	case asOf != nil:
+		resp, err = h.service.Incident.FindByIDAsOf(c, id, *asOf)
+	case include == includeTree:
+		resp, err = h.service.Incident.FindTree(c, id)
+	default:
+		resp, err = h.service.Incident.FindByID(c, id)
+	}
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
 			Error:   "Не удалось получить инцидент",
 			Details: err.Error(),
 		})
@@ -96,6 +157,7 @@ func (h *IncidentHandlerImpl) GetIncident(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param limit query int false "Количество записей"
 // @Param offset query int false "Смещение (для пагинации)"
+// @Param as_of query string false "Момент времени (RFC3339), на который нужно получить состояние инцидентов"
 // @Success 200 {object} entity.GetIncidentsResponse
 // @Failure 400 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
@@ -114,7 +176,17 @@ func (h *IncidentHandlerImpl) GetIncidents(c *gin.Context) {
 		offsetInt = 0
 	}
 
-	resp, err := h.service.Incident.FindAll(c, limitInt, offsetInt)
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	var resp []*entity.GetIncidentResponse
+	if asOf != nil {
+		resp, err = h.service.Incident.FindAllAsOf(c, *asOf, limitInt, offsetInt)
+	} else {
+		resp, err = h.service.Incident.FindAll(c, limitInt, offsetInt)
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
 			Error:   "Не удалось получить список инцидентов",
@@ -126,9 +198,120 @@ func (h *IncidentHandlerImpl) GetIncidents(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// ExportIncidents godoc
+// @Summary Выгружает инциденты в GeoJSON, KML или WKT/CSV
+// @Description Выгрузка зон для ГИС (QGIS, Google Earth). Формат задается параметром format (geojson, kml, csv) или заголовком Accept: application/geo+json, application/vnd.google-earth.kml+xml, text/csv; без них возвращается GeoJSON FeatureCollection. Фильтры те же, что у GET /incidents, но без limit выгружаются все инциденты. Строки читаются из базы и отправляются клиенту потоком; ошибка после начала выгрузки обрывает ответ.
+// @Tags incidents
+// @Produce application/geo+json
+// @Produce application/vnd.google-earth.kml+xml
+// @Produce text/csv
+// @Security ApiKeyAuth
+// @Param format query string false "Формат выгрузки" Enums(geojson, kml, csv)
+// @Param limit query int false "Количество записей (по умолчанию все)"
+// @Param offset query int false "Смещение"
+// @Param as_of query string false "Момент времени (RFC3339), на который нужно получить состояние инцидентов"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 406 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/export [get]
+func (h *IncidentHandlerImpl) ExportIncidents(c *gin.Context) {
+	format, ok := negotiateExportFormat(c)
+	if !ok {
+		return
+	}
+
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limitInt < 0 {
+		limitInt = 0
+	}
+
+	offsetInt, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offsetInt < 0 {
+		offsetInt = 0
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", w.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="incidents.`+w.Extension()+`"`)
+
+	err = h.service.Incident.Export(c, entity.IncidentExportFilter{
+		Limit:  limitInt,
+		Offset: offsetInt,
+		AsOf:   asOf,
+	}, w)
+	if err != nil {
+		// Если данные уже ушли клиенту, статус поменять нельзя: ответ обрывается
+		if c.Writer.Written() {
+			c.Abort()
+			return
+		}
+
+		c.Writer.Header().Del("Content-Disposition")
+		c.Writer.Header().Del("Content-Type")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось выгрузить инциденты",
+			Details: err.Error(),
+		})
+	}
+}
+
+// negotiateExportFormat выбирает формат выгрузки: параметр format важнее заголовка Accept,
+// из Accept берется первый поддерживаемый тип, */* и пустой заголовок дают GeoJSON
+func negotiateExportFormat(c *gin.Context) (string, bool) {
+	if format := c.Query("format"); format != "" {
+		switch format {
+		case export.FormatGeoJSON, export.FormatKML, export.FormatCSV:
+			return format, true
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: "format: ожидается geojson, kml или csv",
+		})
+		return "", false
+	}
+
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		return export.FormatGeoJSON, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if mediaType == "*/*" {
+			return export.FormatGeoJSON, true
+		}
+		if format, ok := export.FormatForMediaType(mediaType); ok {
+			return format, true
+		}
+	}
+
+	c.AbortWithStatusJSON(http.StatusNotAcceptable, entity.ErrorResponse{
+		Error:   "Неподдерживаемый формат выгрузки",
+		Details: "Accept: поддерживаются application/geo+json, application/vnd.google-earth.kml+xml и text/csv",
+	})
+	return "", false
+}
+
 // UpdateIncident godoc
 // @Summary Обновляет инцидент
-// @Description Метод для обновления инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Можно обновить только название, описание и гео-зону (Polygon).
+// @Description Метод для обновления инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Можно обновить название, описание, серьезность, гео-зону (Polygon) и родителя (пустой parent_id отвязывает инцидент). Родитель не может быть завершенным или потомком инцидента. При смене зоны в overlaps ответа перечисляются пересечения с другими активными инцидентами (кроме предков и потомков); в строгом режиме (INCIDENT_OVERLAP_MAX_PERCENT) превышение порога дает 409. Черновик остается черновиком; инцидент на согласовании или опубликованный после изменения возвращается на согласование (и перестает учитываться в проверках до одобрения), автором отправки становится автор изменения. Завершенный инцидент изменить нельзя (409). Для инцидентов, подпадающих под правило двух лиц, такое изменение требует персонального ключа оператора (иначе 403).
 // @Tags incidents
 // @Accept json
 // @Produce json
@@ -137,6 +320,9 @@ func (h *IncidentHandlerImpl) GetIncidents(c *gin.Context) {
 // @Param incident body entity.UpdateIncidentRequest true "Incident"
 // @Success 200 {object} entity.IncidentResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /incidents/{id} [put]
 func (h *IncidentHandlerImpl) UpdateIncident(c *gin.Context) {
@@ -150,10 +336,11 @@ func (h *IncidentHandlerImpl) UpdateIncident(c *gin.Context) {
 		})
 		return
 	}
+	req.Actor = operatorFromContext(c)
 
 	resp, err := h.service.Incident.Update(c, &req, id)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
 			Error:   "Не удалось обновить инцидент",
 			Details: err.Error(),
 		})
@@ -165,21 +352,33 @@ func (h *IncidentHandlerImpl) UpdateIncident(c *gin.Context) {
 
 // DeleteIncident godoc
 // @Summary Деактивирует инцидент
-// @Description Метод для деактивации инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути.
+// @Description Метод для деактивации инцидента по уникальному идентификатору (UUID). ID передается в URL как параметр пути. Вместе с инцидентом завершаются все его потомки.
 // @Tags incidents
 // @Produce json
 // @Security ApiKeyAuth
 // @Param id path string true "Incident ID"
+// @Param reason query string false "Причина деактивации"
 // @Success 200 {object} entity.IncidentResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /incidents/{id} [delete]
 func (h *IncidentHandlerImpl) DeleteIncident(c *gin.Context) {
 	id := c.Param("id")
+	var req entity.DeleteIncidentRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.Actor = operatorFromContext(c)
 
-	resp, err := h.service.Incident.Delete(c, id)
+	resp, err := h.service.Incident.Delete(c, id, &req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
 			Error:   "Не удалось удалить инцидент",
 			Details: err.Error(),
 		})
@@ -210,3 +409,352 @@ func (h *IncidentHandlerImpl) GetStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// GetHistory godoc
+// @Summary Получает историю изменений инцидента
+// @Description Возвращает версии инцидента от последней к первой. Версия создается при каждом создании, обновлении и деактивации и содержит состояние инцидента после изменения, автора (оператор, чей персональный API ключ использован), причину и период действия.
+// @Tags incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param limit query int false "Количество записей"
+// @Param offset query int false "Смещение (для пагинации)"
+// @Success 200 {object} entity.GetIncidentHistoryResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/history [get]
+func (h *IncidentHandlerImpl) GetHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limitInt <= 0 {
+		limitInt = 50
+	}
+
+	offsetInt, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offsetInt < 0 {
+		offsetInt = 0
+	}
+
+	resp, err := h.service.Incident.History(c, id, limitInt, offsetInt)
+	if err != nil {
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
+			Error:   "Не удалось получить историю инцидента",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetDiff godoc
+// @Summary Сравнивает две версии инцидента
+// @Description Возвращает изменившиеся поля и изменение зоны между версиями from и to: добавленную и убранную области (GeoJSON) и их площади в квадратных метрах. По умолчанию to — последняя версия, from — предыдущая.
+// @Tags incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param from query int false "Исходная версия"
+// @Param to query int false "Конечная версия"
+// @Success 200 {object} entity.IncidentDiffResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/diff [get]
+func (h *IncidentHandlerImpl) GetDiff(c *gin.Context) {
+	id := c.Param("id")
+
+	var fromVersion, toVersion int
+	for param, target := range map[string]*int{"from": &fromVersion, "to": &toVersion} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+				Error:   "Некорректный номер версии",
+				Details: param + "=" + raw,
+			})
+			return
+		}
+		*target = v
+	}
+
+	resp, err := h.service.Incident.Diff(c, id, fromVersion, toVersion)
+	if err != nil {
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
+			Error:   "Не удалось сравнить версии инцидента",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PreviewIncident godoc
+// @Summary Оценивает охват черновика зоны
+// @Description Метод для оценки последствий публикации зоны. Возвращает пользователей, достоверные проверки которых за окно window_minutes попали в полигон, разбивку по интервалам bucket_minutes и активные инциденты, с которыми полигон пересекается. Ничего не сохраняется, вебхуки не отправляются.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param preview body entity.PreviewIncidentRequest true "Draft zone"
+// @Success 200 {object} entity.PreviewIncidentResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/preview [post]
+func (h *IncidentHandlerImpl) PreviewIncident(c *gin.Context) {
+	var req entity.PreviewIncidentRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Impact.Preview(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось оценить охват зоны",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ImportIncidents godoc
+// @Summary Импортирует зоны из GeoJSON, KML или Shapefile
+// @Description Загружает набор зон (например, от ведомства-партнера) из GeoJSON FeatureCollection, KML или ZIP-архива Shapefile (.shp, .dbf, необязательные .prj и .cpg). Формат задается параметром format или определяется по Content-Type и содержимому; координаты Shapefile пересчитываются в EPSG:4326 по .prj. Свойства объектов (в KML — name, description и ExtendedData, в Shapefile — атрибуты .dbf) переносятся в поля инцидента: по умолчанию name, description, severity и external_id (если его нет — id объекта); имена свойств можно переопределить параметрами *_field. Каждый объект проверяется; при любой ошибке ничего не сохраняется и возвращается 422 с отчетом по объектам. Иначе все объекты сохраняются одной транзакцией: инцидент с тем же external_id обновляется, остальные создаются черновиками. Черновик остается черновиком, инцидент на согласовании или опубликованный снимается с публикации и возвращается на согласование (для серьезности от INCIDENT_TWO_PERSON_SEVERITY нужен персональный ключ оператора), завершенный инцидент не изменяется. Зоны проверяются на пересечения с активными инцидентами: пересечения попадают в отчет, а при превышении INCIDENT_OVERLAP_MAX_PERCENT объект отклоняется. С dry_run=true объекты только проверяются: ответ 200 содержит отчет с ошибками, пересечениями и топологическими проблемами полигонов.
+// @Tags incidents
+// @Accept json
+// @Accept application/vnd.google-earth.kml+xml
+// @Accept application/zip
+// @Produce json
+// @Security ApiKeyAuth
+// @Param collection body entity.GeoJsonFeatureCollection true "FeatureCollection с полигонами, KML или ZIP-архив Shapefile"
+// @Param format query string false "Формат файла" Enums(geojson, kml, shapefile)
+// @Param name_field query string false "Свойство с названием (по умолчанию name)"
+// @Param description_field query string false "Свойство с описанием (по умолчанию description)"
+// @Param severity_field query string false "Свойство с серьезностью (по умолчанию severity)"
+// @Param external_id_field query string false "Свойство с внешним идентификатором (по умолчанию external_id)"
+// @Param dry_run query bool false "Только проверить объекты, ничего не сохраняя"
+// @Param reason query string false "Причина изменения для истории версий"
+// @Success 200 {object} entity.ImportIncidentsResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 422 {object} entity.ImportIncidentsResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/import [post]
+func (h *IncidentHandlerImpl) ImportIncidents(c *gin.Context) {
+	req := entity.ImportIncidentsRequest{
+		Mapping: entity.ImportMapping{
+			Name:        c.Query("name_field"),
+			Description: c.Query("description_field"),
+			Severity:    c.Query("severity_field"),
+			ExternalID:  c.Query("external_id_field"),
+		},
+		Reason: c.Query("reason"),
+		Actor:  operatorFromContext(c),
+		DryRun: c.Query("dry_run") == "true",
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportUploadBytes))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = geoimport.DetectFormat(c.ContentType(), data)
+	}
+
+	if format == entity.ImportFormatGeoJSON {
+		err = binding.JSON.BindBody(data, &req.Collection)
+	} else {
+		var collection *entity.GeoJsonFeatureCollection
+		if collection, err = geoimport.Parse(format, data); err == nil {
+			req.Collection = *collection
+		}
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Incident.Import(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось импортировать инциденты",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if !resp.Committed && !resp.DryRun {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, resp)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SubmitIncident godoc
+// @Summary Отправляет инцидент на согласование
+// @Description Переводит черновик в статус pending_review. Автор отправки (оператор по персональному API ключу) запоминается: для инцидентов с серьезностью не ниже INCIDENT_TWO_PERSON_SEVERITY он не сможет сам одобрить публикацию.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param transition body entity.IncidentTransitionRequest false "Причина"
+// @Success 200 {object} entity.IncidentResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/submit [post]
+func (h *IncidentHandlerImpl) SubmitIncident(c *gin.Context) {
+	h.transition(c, h.service.Incident.Submit, "Не удалось отправить инцидент на согласование")
+}
+
+// ApproveIncident godoc
+// @Summary Публикует инцидент
+// @Description Переводит инцидент в статус published, после чего он учитывается в проверках локаций. Инциденты с серьезностью ниже INCIDENT_TWO_PERSON_SEVERITY можно опубликовать прямо из черновика. Начиная с этого уровня инцидент должен быть на согласовании, а одобряющий оператор должен отличаться от отправившего (операторы различаются по персональным API ключам), иначе возвращается 403.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param transition body entity.IncidentTransitionRequest false "Причина"
+// @Success 200 {object} entity.IncidentResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 403 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/approve [post]
+func (h *IncidentHandlerImpl) ApproveIncident(c *gin.Context) {
+	h.transition(c, h.service.Incident.Approve, "Не удалось опубликовать инцидент")
+}
+
+// RejectIncident godoc
+// @Summary Возвращает инцидент в черновик
+// @Description Отклоняет инцидент, находящийся на согласовании, и возвращает его в статус draft. Причину отклонения можно передать в поле reason.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param transition body entity.IncidentTransitionRequest false "Причина"
+// @Success 200 {object} entity.IncidentResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/reject [post]
+func (h *IncidentHandlerImpl) RejectIncident(c *gin.Context) {
+	h.transition(c, h.service.Incident.Reject, "Не удалось отклонить инцидент")
+}
+
+// ResolveIncident godoc
+// @Summary Завершает инцидент
+// @Description Переводит опубликованный инцидент в статус resolved, после чего он перестает учитываться в проверках локаций.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param transition body entity.IncidentTransitionRequest false "Причина"
+// @Success 200 {object} entity.IncidentResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 409 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/resolve [post]
+func (h *IncidentHandlerImpl) ResolveIncident(c *gin.Context) {
+	h.transition(c, h.service.Incident.Resolve, "Не удалось завершить инцидент")
+}
+
+type transitionFunc func(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
+
+// transition выполняет переход статуса инцидента. Тело запроса необязательно.
+// Недопустимый переход возвращает 409, нарушение правила двух лиц — 403.
+func (h *IncidentHandlerImpl) transition(c *gin.Context, fn transitionFunc, failure string) {
+	var req entity.IncidentTransitionRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+				Error:   "Некорректное тело запроса",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+	req.Actor = operatorFromContext(c)
+
+	resp, err := fn(c, c.Param("id"), &req)
+	if err != nil {
+		c.AbortWithStatusJSON(incidentErrorStatus(err), entity.ErrorResponse{
+			Error:   failure,
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseAsOf разбирает параметр as_of. При некорректном значении отвечает 400 и возвращает ok = false.
+func parseAsOf(c *gin.Context) (*time.Time, bool) {
+	raw := c.Query("as_of")
+	if raw == "" {
+		return nil, true
+	}
+
+	asOf, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: "as_of: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	return &asOf, true
+}
+
+// incidentErrorStatus возвращает 400 для ошибок в данных запроса, обнаруженных сервисом,
+// 404 для неизвестного инцидента, 409 для недопустимого перехода или пересечения зон
+// и 403 при нарушении правила двух лиц
+func incidentErrorStatus(err error) int {
+	var topologyErr *validator.TopologyError
+
+	switch {
+	case errors.Is(err, service.ErrInvalidIncident), errors.Is(err, service.ErrInvalidIncidentID),
+		errors.Is(err, service.ErrInvalidParent), errors.Is(err, service.ErrInvalidVersion), errors.As(err, &topologyErr):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrIncidentNotFound), errors.Is(err, service.ErrIncidentVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrIncidentOverlap), errors.Is(err, service.ErrInvalidTransition):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrTwoPersonRule):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
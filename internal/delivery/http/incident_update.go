@@ -0,0 +1,189 @@
+package myHttp
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
+
+type IncidentUpdateHandler interface {
+	CreateUpdate(c *gin.Context)
+	GetUpdates(c *gin.Context)
+	GetUpdate(c *gin.Context)
+	EditUpdate(c *gin.Context)
+	DeleteUpdate(c *gin.Context)
+}
+
+type IncidentUpdateHandlerImpl struct {
+	service *service.Service
+}
+
+func NewIncidentUpdateHandler(service *service.Service) IncidentUpdateHandler {
+	return &IncidentUpdateHandlerImpl{service: service}
+}
+
+// CreateUpdate godoc
+// @Summary Публикует сообщение о ходе инцидента
+// @Description Добавляет к инциденту сообщение с отметкой времени, не меняя его описание и зону. С notify=true сообщение отправляется вебхуком пользователям, проверявшим локацию внутри зоны за INCIDENT_UPDATE_NOTIFY_WINDOW (только для опубликованных инцидентов). Автор — оператор, чей персональный API ключ использован.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param update body entity.CreateIncidentUpdateRequest true "Update data"
+// @Success 201 {object} entity.CreateIncidentUpdateResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/updates [post]
+func (h *IncidentUpdateHandlerImpl) CreateUpdate(c *gin.Context) {
+	var req entity.CreateIncidentUpdateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.Actor = operatorFromContext(c)
+
+	resp, err := h.service.Updates.Create(c, c.Param("id"), &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось опубликовать сообщение по инциденту",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetUpdates godoc
+// @Summary Получает сообщения о ходе инцидента
+// @Description Возвращает сообщения по инциденту от последнего к первому. Поддерживает параметры limit и offset.
+// @Tags incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param limit query int false "Количество записей"
+// @Param offset query int false "Смещение (для пагинации)"
+// @Success 200 {object} entity.GetIncidentUpdatesResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/updates [get]
+func (h *IncidentUpdateHandlerImpl) GetUpdates(c *gin.Context) {
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limitInt <= 0 {
+		limitInt = 50
+	}
+
+	offsetInt, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offsetInt < 0 {
+		offsetInt = 0
+	}
+
+	resp, err := h.service.Updates.FindAll(c, c.Param("id"), limitInt, offsetInt)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось получить сообщения по инциденту",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUpdate godoc
+// @Summary Получает сообщение о ходе инцидента
+// @Tags incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param update_id path string true "Update ID"
+// @Success 200 {object} entity.IncidentUpdate
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/updates/{update_id} [get]
+func (h *IncidentUpdateHandlerImpl) GetUpdate(c *gin.Context) {
+	resp, err := h.service.Updates.FindByID(c, c.Param("id"), c.Param("update_id"))
+	if err != nil {
+		abortUpdateError(c, "Не удалось получить сообщение по инциденту", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// EditUpdate godoc
+// @Summary Исправляет сообщение о ходе инцидента
+// @Description Заменяет текст сообщения. Повторно вебхуки не отправляются.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param update_id path string true "Update ID"
+// @Param update body entity.UpdateIncidentUpdateRequest true "Update data"
+// @Success 200 {object} entity.IncidentUpdate
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/updates/{update_id} [put]
+func (h *IncidentUpdateHandlerImpl) EditUpdate(c *gin.Context) {
+	var req entity.UpdateIncidentUpdateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Updates.Update(c, c.Param("id"), c.Param("update_id"), &req)
+	if err != nil {
+		abortUpdateError(c, "Не удалось обновить сообщение по инциденту", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteUpdate godoc
+// @Summary Удаляет сообщение о ходе инцидента
+// @Tags incidents
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Incident ID"
+// @Param update_id path string true "Update ID"
+// @Success 200 {object} entity.IncidentResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /incidents/{id}/updates/{update_id} [delete]
+func (h *IncidentUpdateHandlerImpl) DeleteUpdate(c *gin.Context) {
+	resp, err := h.service.Updates.Delete(c, c.Param("id"), c.Param("update_id"))
+	if err != nil {
+		abortUpdateError(c, "Не удалось удалить сообщение по инциденту", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func abortUpdateError(c *gin.Context, failure string, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, service.ErrIncidentUpdateNotFound) {
+		code = http.StatusNotFound
+	}
+
+	c.AbortWithStatusJSON(code, entity.ErrorResponse{
+		Error:   failure,
+		Details: err.Error(),
+	})
+}
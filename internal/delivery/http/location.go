@@ -1,7 +1,9 @@
 package myHttp
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
@@ -10,6 +12,11 @@ import (
 
 type LocationHandler interface {
 	CheckLocation(c *gin.Context)
+	CheckRoute(c *gin.Context)
+	GetFlaggedChecks(c *gin.Context)
+	ReviewFlaggedCheck(c *gin.Context)
+	ReplayLocation(c *gin.Context)
+	Recheck(c *gin.Context)
 }
 
 type LocationHandlerImpl struct {
@@ -22,13 +29,19 @@ func NewLocationHandler(service *service.Service) LocationHandler {
 
 // CheckLocation godoc
 // @Summary Проверяет локацию
-// @Description Метод проверяет находится ли пользователь в опасной зоне. Принимает координаты пользователя и userID.
+// @Description Метод проверяет находится ли пользователь в опасной зоне. Принимает координаты пользователя и userID. При check_trajectory=true дополнительно проверяется отрезок от предыдущей проверки пользователя до текущей, и пересеченные по пути зоны возвращаются в поле transit. Если передана точность user_location.accuracy_m, совпадения классифицируются как inside, possibly_inside или outside по доле круга точности внутри зоны.
 // @Tags location
 // @Accept json
 // @Produce json
 // @Param location body entity.CheckLocationRequest true "User data"
+// @Param X-Device-ID header string false "ID зарегистрированного устройства"
+// @Param X-Timestamp header string false "Unix-время подписи в секундах"
+// @Param X-Nonce header string false "Одноразовое значение для защиты от повторов"
+// @Param X-Signature header string false "HMAC-SHA256 подпись запроса (hex)"
 // @Success 200 {object} entity.CheckLocationResponse
 // @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 413 {object} entity.ErrorResponse
 // @Failure 500 {object} entity.ErrorResponse
 // @Router /location/check [post]
 func (h *LocationHandlerImpl) CheckLocation(c *gin.Context) {
@@ -44,7 +57,7 @@ func (h *LocationHandlerImpl) CheckLocation(c *gin.Context) {
 
 	resp, err := h.service.Location.CheckLocation(c, &req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+		c.AbortWithStatusJSON(locationErrorStatus(err), entity.ErrorResponse{
 			Error:   "Не удалось проверить локацию",
 			Details: err.Error(),
 		})
@@ -54,3 +67,217 @@ func (h *LocationHandlerImpl) CheckLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 
 }
+
+// CheckRoute godoc
+// @Summary Проверяет безопасность маршрута
+// @Description Метод принимает планируемый маршрут (GeoJSON LineString) и возвращает все активные инциденты, через которые он проходит. Для каждого инцидента указываются точки входа и выхода и длина участка маршрута внутри зоны в метрах.
+// @Tags location
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param route body entity.CheckRouteRequest true "Route"
+// @Success 200 {object} entity.CheckRouteResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /routes/check [post]
+func (h *LocationHandlerImpl) CheckRoute(c *gin.Context) {
+	var req entity.CheckRouteRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.Location.CheckRoute(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(locationErrorStatus(err), entity.ErrorResponse{
+			Error:   "Не удалось проверить маршрут",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFlaggedChecks godoc
+// @Summary Получает подозрительные проверки локации
+// @Description Метод возвращает проверки, помеченные детектором подмены координат (невозможная скорость перемещения от предыдущей проверки). Поддерживает фильтр по статусу рассмотрения и параметры limit и offset.
+// @Tags location
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Статус рассмотрения" Enums(pending, confirmed, dismissed)
+// @Param limit query int false "Количество записей"
+// @Param offset query int false "Смещение (для пагинации)"
+// @Success 200 {object} entity.GetFlaggedChecksResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /location/flagged [get]
+func (h *LocationHandlerImpl) GetFlaggedChecks(c *gin.Context) {
+	limitInt, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limitInt < 0 {
+		limitInt = 10
+	}
+
+	offsetInt, err := strconv.Atoi(c.Query("offset"))
+	if err != nil || offsetInt < 0 {
+		offsetInt = 0
+	}
+
+	resp, err := h.service.Location.FindFlaggedChecks(c, c.Query("status"), limitInt, offsetInt)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось получить подозрительные проверки",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ReviewFlaggedCheck godoc
+// @Summary Рассматривает подозрительную проверку
+// @Description Метод фиксирует решение оператора по подозрительной проверке. confirm подтверждает подмену и исключает проверку из статистики, dismiss снимает подозрение и возвращает проверку в учет.
+// @Tags location
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Location check ID"
+// @Param review body entity.ReviewLocationCheckRequest true "Review decision"
+// @Success 204 "No Content"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /location/flagged/{id}/review [post]
+func (h *LocationHandlerImpl) ReviewFlaggedCheck(c *gin.Context) {
+	id := c.Param("id")
+	var req entity.ReviewLocationCheckRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.Location.ReviewFlaggedCheck(c, id, &req); err != nil {
+		code := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidCheckID):
+			code = http.StatusBadRequest
+		case errors.Is(err, service.ErrFlaggedCheckNotFound):
+			code = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(code, entity.ErrorResponse{
+			Error:   "Не удалось сохранить решение по проверке",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReplayLocation godoc
+// @Summary Повторяет проверку локации на момент времени
+// @Description Метод проверяет, находился ли пользователь в опасной зоне в момент at, по зонам в том виде, в котором они действовали тогда. Координаты передаются в user_location; если их нет, используется последняя достоверная проверка пользователя user_id не позже at. Результат не сохраняется, вебхуки и события не отправляются.
+// @Tags location
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param replay body entity.ReplayLocationRequest true "Replay request"
+// @Success 200 {object} entity.ReplayLocationResponse
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 404 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /location/replay [post]
+func (h *LocationHandlerImpl) ReplayLocation(c *gin.Context) {
+	var req entity.ReplayLocationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if req.UserLocation == nil && req.UserID == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: "нужно указать user_location или user_id",
+		})
+		return
+	}
+
+	resp, err := h.service.Location.ReplayLocation(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(locationErrorStatus(err), entity.ErrorResponse{
+			Error:   "Не удалось повторить проверку локации",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Recheck godoc
+// @Summary Пересчитывает сохраненные проверки локаций
+// @Description Административное задание: пересчитывает совпадения проверок за период [from, to) по текущей (geometry=current) или действовавшей в момент проверки (geometry=historical) геометрии зон. Возвращает отчет об изменившихся проверках. При apply=true изменения записываются, и статистика начинает их учитывать. incident_id ограничивает отчет проверками, которые были или стали привязаны к этой зоне. Пересчитываются только точечные проверки: проверки, сохраненные с точностью координат (accuracy_m), пропускаются и учитываются в поле skipped, так как их статус зависит от доли круга точности внутри зоны.
+// @Tags location
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param recheck body entity.RecheckRequest true "Recheck job"
+// @Success 200 {object} entity.RecheckReport
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /location/recheck [post]
+func (h *LocationHandlerImpl) Recheck(c *gin.Context) {
+	var req entity.RecheckRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if !req.From.Before(req.To) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректное тело запроса",
+			Details: "from должен быть раньше to",
+		})
+		return
+	}
+
+	resp, err := h.service.Location.Recheck(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, entity.ErrorResponse{
+			Error:   "Не удалось пересчитать проверки",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// locationErrorStatus возвращает 400 для некорректных координат или маршрута
+// и 404, если у пользователя нет проверок для повторной проверки
+func locationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidLocation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNoLocationChecks):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -1,14 +1,37 @@
 package myHttp
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/pkg/signature"
 )
 
-// Middleware для проверки API ключа
+// Режимы проверки подписи запросов устройств
+const (
+	DeviceAuthOff        = "off"
+	DeviceAuthPermissive = "permissive"
+	DeviceAuthEnforce    = "enforce"
+
+	deviceContextKey = "device"
+	// operatorContextKey — имя оператора, определенное по персональному API ключу
+	operatorContextKey = "operator"
+
+	// Предел размера тела подписанного запроса: тело читается целиком для проверки подписи
+	maxSignedBodyBytes = 1 << 20
+)
+
+// Middleware для проверки API ключа. Персональный ключ оператора из OperatorAPIKeys
+// дополнительно определяет автора изменений; общий ключ API_KEY автора не задает.
 func ApiKeyMiddleware(cfg *config.HTTPServerConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
@@ -20,6 +43,13 @@ func ApiKeyMiddleware(cfg *config.HTTPServerConfig) gin.HandlerFunc {
 			return
 		}
 
+		if operator, ok := cfg.OperatorAPIKeys[apiKey]; ok {
+			slog.Debug("operator API key validated", "operator", operator)
+			c.Set(operatorContextKey, operator)
+			c.Next()
+			return
+		}
+
 		if apiKey != cfg.APIKey {
 			slog.Error("invalid API key")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
@@ -32,6 +62,94 @@ func ApiKeyMiddleware(cfg *config.HTTPServerConfig) gin.HandlerFunc {
 	}
 }
 
+// operatorFromContext возвращает имя оператора, аутентифицированного ApiKeyMiddleware,
+// или пустую строку для запросов с общим ключом
+func operatorFromContext(c *gin.Context) string {
+	return c.GetString(operatorContextKey)
+}
+
+// Middleware для проверки HMAC-подписи запросов устройств. В режиме permissive ошибки
+// проверки только логируются, в режиме enforce запрос отклоняется.
+func DeviceSignatureMiddleware(cfg *config.HTTPServerConfig, service *service.Service) gin.HandlerFunc {
+	mode := cfg.DeviceAuthMode
+	if mode == "" {
+		mode = DeviceAuthPermissive
+	}
+
+	return func(c *gin.Context) {
+		if mode == DeviceAuthOff {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSignedBodyBytes))
+		if err != nil {
+			slog.Error("failed to read request body", "error", err)
+
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+			} else {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		device, err := service.Device.VerifyRequest(c, &entity.SignedRequest{
+			DeviceID:  c.GetHeader(signature.HeaderDeviceID),
+			Timestamp: c.GetHeader(signature.HeaderTimestamp),
+			Nonce:     c.GetHeader(signature.HeaderNonce),
+			Signature: c.GetHeader(signature.HeaderSignature),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.RequestURI(),
+			Body:      body,
+		})
+		if err == nil {
+			err = checkDeviceUser(device, body)
+		}
+
+		if err != nil {
+			if mode != DeviceAuthEnforce {
+				slog.Warn("device signature check failed (permissive mode)",
+					slog.String("path", c.Request.URL.Path),
+					slog.String("device_id", c.GetHeader(signature.HeaderDeviceID)),
+					slog.String("error", err.Error()),
+				)
+				c.Next()
+				return
+			}
+
+			slog.Error("device signature check failed", "error", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		slog.Debug("device signature validated", "device_id", device.ID)
+		c.Set(deviceContextKey, device)
+		c.Next()
+	}
+}
+
+// checkDeviceUser запрещает устройству отправлять запросы от имени чужого user_id
+func checkDeviceUser(device *entity.Device, body []byte) error {
+	var payload struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.Unmarshal(body, &payload); err != nil || payload.UserID == "" {
+		return nil
+	}
+
+	if !strings.EqualFold(payload.UserID, device.UserID) {
+		return service.ErrDeviceUserMismatch
+	}
+
+	return nil
+}
+
 // Middleware для логирования запросов
 func LoggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,70 @@
+package myHttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeyMiddleware_Operator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.HTTPServerConfig{
+		APIKey:          "key",
+		OperatorAPIKeys: map[string]string{"alice-key": "alice"},
+	}
+
+	tests := []struct {
+		name         string
+		apiKey       string
+		wantStatus   int
+		wantOperator string
+	}{
+		{
+			name:         "Operator key",
+			apiKey:       "alice-key",
+			wantStatus:   http.StatusOK,
+			wantOperator: "alice",
+		},
+		{
+			name:       "Shared key has no operator",
+			apiKey:     "key",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Operator name is not a key",
+			apiKey:     "alice",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Missing key",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operator string
+
+			router := gin.New()
+			router.GET("/", ApiKeyMiddleware(cfg), func(c *gin.Context) {
+				operator = operatorFromContext(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantOperator, operator)
+		})
+	}
+}
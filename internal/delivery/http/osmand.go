@@ -0,0 +1,192 @@
+package myHttp
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/pkg/track"
+)
+
+// errInvalidTrackerID возвращается, если незарегистрированный id трекера нельзя использовать как user_id
+var errInvalidTrackerID = errors.New("id трекера не является токеном, ID устройства или UUID пользователя")
+
+type TrackerHandler interface {
+	OsmAnd(c *gin.Context)
+}
+
+type TrackerHandlerImpl struct {
+	service *service.Service
+	cfg     *config.HTTPServerConfig
+}
+
+func NewTrackerHandler(cfg *config.HTTPServerConfig, service *service.Service) TrackerHandler {
+	return &TrackerHandlerImpl{service: service, cfg: cfg}
+}
+
+// OsmAnd godoc
+// @Summary Прием координат по протоколу OsmAnd
+// @Description Совместимый с OsmAnd/Traccar эндпоинт для готовых трекеров. Параметры передаются в query-строке или form-данных. Идентификатор id должен совпадать с токеном трекера (tracker_token), выданным при регистрации устройства, проверка выполняется от имени пользователя устройства. Если DEVICE_AUTH_MODE не enforce, принимаются также ID устройства и UUID пользователя. Время timestamp принимается в unix-секундах, миллисекундах или RFC 3339; точки из будущего получают время запроса, а точки старше LOCATION_MAX_BACKDATE отклоняются с кодом 400, как и координаты вне допустимого диапазона. Ответ 200 без тела.
+// @Tags location
+// @Param id query string true "Токен трекера"
+// @Param lat query number false "Широта"
+// @Param lon query number false "Долгота"
+// @Param location query string false "Координаты строкой lat,lon (вместо lat/lon)"
+// @Param timestamp query string false "Время фиксации координат"
+// @Param accuracy query number false "Точность в метрах"
+// @Success 200 "OK"
+// @Failure 400 {object} entity.ErrorResponse
+// @Failure 401 {object} entity.ErrorResponse
+// @Failure 500 {object} entity.ErrorResponse
+// @Router /osmand [get]
+// @Router /osmand [post]
+func (h *TrackerHandlerImpl) OsmAnd(c *gin.Context) {
+	var req entity.OsmAndRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	checkReq, err := osmAndToCheckRequest(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+			Error:   "Некорректные параметры запроса",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	userID, err := h.resolveUser(c, checkReq.UserID)
+	if err != nil {
+		if errors.Is(err, errInvalidTrackerID) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, entity.ErrorResponse{
+				Error:   "Некорректные параметры запроса",
+				Details: err.Error(),
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, entity.ErrorResponse{
+			Error:   "Устройство не зарегистрировано",
+			Details: err.Error(),
+		})
+		return
+	}
+	checkReq.UserID = userID
+
+	// Ответ 400 сообщает трекеру, что повторять отправку точки бесполезно
+	if _, err := h.service.Location.CheckLocation(c, checkReq); err != nil {
+		c.AbortWithStatusJSON(locationErrorStatus(err), entity.ErrorResponse{
+			Error:   "Не удалось проверить локацию",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// resolveUser определяет пользователя по идентификатору трекера. Трекеры не умеют подписывать
+// запросы, поэтому идентификатором служит секретный токен трекера, выданный при регистрации устройства.
+// ID устройства публичен (GET /devices, X-Device-ID), поэтому в режиме enforce он не принимается.
+func (h *TrackerHandlerImpl) resolveUser(c *gin.Context, trackerID string) (string, error) {
+	device, err := h.service.Device.FindByTrackerToken(c, trackerID)
+	if err == nil {
+		return device.UserID, nil
+	}
+
+	if h.cfg.DeviceAuthMode == DeviceAuthEnforce {
+		return "", err
+	}
+
+	// Трекеры, настроенные до появления токенов, передают ID устройства или сам user_id
+	if device, err := h.service.Device.FindActive(c, trackerID); err == nil {
+		slog.Warn("osmand: трекер передал ID устройства вместо токена", "device_id", device.ID)
+		return device.UserID, nil
+	}
+
+	if _, err := uuid.Parse(trackerID); err != nil {
+		return "", fmt.Errorf("%w: %s", errInvalidTrackerID, trackerID)
+	}
+
+	slog.Warn("osmand: устройство не зарегистрировано, id используется как user_id", "id", trackerID, "error", err)
+	return trackerID, nil
+}
+
+func osmAndToCheckRequest(req *entity.OsmAndRequest) (*entity.CheckLocationRequest, error) {
+	trackerID := req.ID
+	if trackerID == "" {
+		trackerID = req.DeviceID
+	}
+	if trackerID == "" {
+		return nil, errors.New("не указан id устройства")
+	}
+
+	var lat, lon float64
+	switch {
+	case req.Lat != nil && req.Lon != nil:
+		lat, lon = *req.Lat, *req.Lon
+	case req.Location != "":
+		parts := strings.Split(req.Location, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("некорректный параметр location: %s", req.Location)
+		}
+
+		var err error
+		if lat, err = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64); err != nil {
+			return nil, fmt.Errorf("некорректная широта: %w", err)
+		}
+		if lon, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+			return nil, fmt.Errorf("некорректная долгота: %w", err)
+		}
+	default:
+		return nil, errors.New("не указаны координаты")
+	}
+
+	checkReq := &entity.CheckLocationRequest{
+		UserID: trackerID,
+		UserLocation: entity.UserLocation{
+			Lat:       lat,
+			Lon:       lon,
+			AccuracyM: req.Accuracy,
+		},
+	}
+
+	if req.Timestamp != "" {
+		ts, err := parseOsmAndTimestamp(req.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		checkReq.Timestamp = &ts
+	}
+
+	return checkReq, nil
+}
+
+// parseOsmAndTimestamp разбирает время в форматах, которые отправляют трекеры:
+// unix-секунды, unix-миллисекунды или RFC 3339
+func parseOsmAndTimestamp(value string) (time.Time, error) {
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		return track.UnixTime(n), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if ts, err := time.Parse(layout, value); err == nil {
+			return ts, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("некорректный параметр timestamp: %s", value)
+}
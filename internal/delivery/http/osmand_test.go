@@ -0,0 +1,144 @@
+package myHttp
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOsmAnd_TrackerAuth(t *testing.T) {
+	device := &entity.Device{
+		ID:       uuid.New(),
+		UserID:   uuid.NewString(),
+		IsActive: true,
+	}
+	userID := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		mode       string
+		id         string
+		mock       func(r *mocks.DeviceRepo)
+		wantStatus int
+		wantUserID string
+	}{
+		{
+			name: "Tracker token",
+			mode: DeviceAuthEnforce,
+			id:   "token",
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(device, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantUserID: device.UserID,
+		},
+		{
+			name: "Device ID in enforce mode",
+			mode: DeviceAuthEnforce,
+			id:   device.ID.String(),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Device ID in permissive mode",
+			mode: DeviceAuthPermissive,
+			id:   device.ID.String(),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+				r.On("FindByID", mock.Anything, device.ID).Return(device, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantUserID: device.UserID,
+		},
+		{
+			name: "User ID in permissive mode",
+			mode: DeviceAuthPermissive,
+			id:   userID,
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+				r.On("FindByID", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+			},
+			wantStatus: http.StatusOK,
+			wantUserID: userID,
+		},
+		{
+			name: "Numeric tracker ID in permissive mode",
+			mode: DeviceAuthPermissive,
+			id:   "123456",
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newRouterTestEnv(t, tt.mode)
+			tt.mock(env.deviceRepo)
+
+			if tt.wantUserID != "" {
+				env.incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{}, nil)
+				env.locationRepo.On("SaveLocationCheck", mock.Anything, mock.MatchedBy(func(c *entity.LocationCheck) bool {
+					return c.UserID == tt.wantUserID
+				})).Return(nil)
+			}
+
+			query := url.Values{"id": {tt.id}, "lat": {"55.75"}, "lon": {"37.65"}}
+			resp, err := http.Get(env.server.URL + "/api/v1/osmand?" + query.Encode())
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestOsmAnd_InvalidLocation(t *testing.T) {
+	device := &entity.Device{
+		ID:       uuid.New(),
+		UserID:   uuid.NewString(),
+		IsActive: true,
+	}
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{
+			name:  "Latitude out of range",
+			query: url.Values{"id": {"token"}, "lat": {"95"}, "lon": {"37.65"}},
+		},
+		{
+			name:  "Longitude out of range",
+			query: url.Values{"id": {"token"}, "location": {"55.75,190"}},
+		},
+		{
+			name:  "Zero timestamp",
+			query: url.Values{"id": {"token"}, "lat": {"55.75"}, "lon": {"37.65"}, "timestamp": {"0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newRouterTestEnv(t, DeviceAuthEnforce)
+			env.deviceRepo.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(device, nil)
+
+			resp, err := http.Get(env.server.URL + "/api/v1/osmand?" + tt.query.Encode())
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package myHttp
 
 import (
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
 	"github.com/levinOo/geo-incedent-service/config"
 	_ "github.com/levinOo/geo-incedent-service/docs"
@@ -9,14 +11,20 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(cfg *config.HTTPServerConfig, service *service.Service) *gin.Engine {
+func NewRouter(cfg *config.HTTPServerConfig, service *service.Service, hub *StreamHub) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
+	// По умолчанию gin доверяет X-Forwarded-For от любого клиента. Заголовки прокси учитываются
+	// только от адресов из TRUSTED_PROXIES, остальные клиенты определяются по адресу соединения.
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("некорректный список доверенных прокси", "error", err)
+	}
+
 	r.Use(gin.Recovery())
 	r.Use(LoggingMiddleware())
 
-	h := NewHandler(service)
+	h := NewHandler(cfg, service, hub)
 
 	// Swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -30,16 +38,84 @@ func NewRouter(cfg *config.HTTPServerConfig, service *service.Service) *gin.Engi
 		{
 			incidents.GET("/stats", h.Incident.GetStats)
 			incidents.POST("", h.Incident.CreateIncident)
+			incidents.POST("/preview", h.Incident.PreviewIncident)
+			incidents.POST("/validate", h.Geometry.Validate)
+			incidents.POST("/import", h.Incident.ImportIncidents)
 			incidents.GET("", h.Incident.GetIncidents)
+			incidents.GET("/export", h.Incident.ExportIncidents)
 			incidents.GET("/:id", h.Incident.GetIncident)
+			incidents.GET("/:id/history", h.Incident.GetHistory)
+			incidents.GET("/:id/diff", h.Incident.GetDiff)
+			incidents.POST("/:id/submit", h.Incident.SubmitIncident)
+			incidents.POST("/:id/approve", h.Incident.ApproveIncident)
+			incidents.POST("/:id/reject", h.Incident.RejectIncident)
+			incidents.POST("/:id/resolve", h.Incident.ResolveIncident)
+			incidents.POST("/:id/updates", h.Updates.CreateUpdate)
+			incidents.GET("/:id/updates", h.Updates.GetUpdates)
+			incidents.GET("/:id/updates/:update_id", h.Updates.GetUpdate)
+			incidents.PUT("/:id/updates/:update_id", h.Updates.EditUpdate)
+			incidents.DELETE("/:id/updates/:update_id", h.Updates.DeleteUpdate)
+			incidents.POST("/:id/buffer", h.Geometry.Buffer)
+			incidents.POST("/:id/union", h.Geometry.Union)
+			incidents.POST("/:id/difference", h.Geometry.Difference)
+			incidents.POST("/:id/simplify", h.Geometry.Simplify)
 			incidents.PUT("/:id", h.Incident.UpdateIncident)
 			incidents.DELETE("/:id", h.Incident.DeleteIncident)
 		}
 
 		location := api.Group("/location")
+		location.Use(DeviceSignatureMiddleware(cfg, service))
 		{
 			location.POST("/check", h.Location.CheckLocation)
 		}
+
+		// WebSocket аутентифицируется первым сообщением, а не заголовками запроса
+		api.GET("/location/ws", h.Stream.PositionStream)
+
+		// Трекеры OsmAnd/Traccar не передают заголовки, устройство определяется по токену в параметре id
+		api.GET("/osmand", h.Tracker.OsmAnd)
+		api.POST("/osmand", h.Tracker.OsmAnd)
+
+		flagged := api.Group("/location/flagged")
+		flagged.Use(ApiKeyMiddleware(cfg))
+		{
+			flagged.GET("", h.Location.GetFlaggedChecks)
+			flagged.POST("/:id/review", h.Location.ReviewFlaggedCheck)
+		}
+
+		api.POST("/location/replay", ApiKeyMiddleware(cfg), h.Location.ReplayLocation)
+		api.POST("/location/recheck", ApiKeyMiddleware(cfg), h.Location.Recheck)
+
+		devices := api.Group("/devices")
+		devices.Use(ApiKeyMiddleware(cfg))
+		{
+			devices.POST("", h.Device.RegisterDevice)
+			devices.GET("", h.Device.GetDevices)
+			devices.DELETE("/:id", h.Device.RevokeDevice)
+		}
+
+		routes := api.Group("/routes")
+		routes.Use(ApiKeyMiddleware(cfg))
+		{
+			routes.POST("/check", h.Location.CheckRoute)
+		}
+
+		api.GET("/events", ApiKeyMiddleware(cfg), h.Events.StreamEvents)
+
+		tracks := api.Group("/tracks")
+		tracks.Use(ApiKeyMiddleware(cfg))
+		{
+			tracks.POST("/exposure", h.Exposure.AnalyzeTrack)
+		}
+
+		capAlerts := api.Group("/cap")
+		{
+			capAlerts.POST("/alerts", ApiKeyMiddleware(cfg), h.Cap.IngestAlert)
+			// Лента и документы CAP публичны: в них только опубликованные инциденты, а читают их
+			// агрегаторы оповещений, которым нельзя выдавать ключ оператора
+			capAlerts.GET("/feed", h.Cap.GetFeed)
+			capAlerts.GET("/incidents/:id", h.Cap.GetIncidentAlert)
+		}
 	}
 
 	return r
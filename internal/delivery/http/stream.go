@@ -0,0 +1,77 @@
+package myHttp
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrStreamHubClosed = errors.New("сервер останавливается, новые потоковые соединения не принимаются")
+
+// StreamHub отслеживает долгоживущие соединения (WebSocket, SSE), которые не закрываются
+// http.Server.Shutdown, и закрывает их при остановке приложения.
+type StreamHub struct {
+	mu      sync.Mutex
+	closers map[uint64]func()
+	nextID  uint64
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func NewStreamHub() *StreamHub {
+	return &StreamHub{closers: make(map[uint64]func())}
+}
+
+// Track регистрирует соединение. closer вызывается при остановке и должен прервать обработку соединения.
+// Возвращаемую функцию нужно вызвать после завершения обработки.
+func (h *StreamHub) Track(closer func()) (func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrStreamHubClosed
+	}
+
+	id := h.nextID
+	h.nextID++
+	h.closers[id] = closer
+	h.wg.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.closers, id)
+			h.mu.Unlock()
+			h.wg.Done()
+		})
+	}, nil
+}
+
+// Shutdown закрывает все соединения и ждет завершения их обработчиков
+func (h *StreamHub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	closers := make([]func(), 0, len(h.closers))
+	for _, closer := range h.closers {
+		closers = append(closers, closer)
+	}
+	h.mu.Unlock()
+
+	for _, closer := range closers {
+		closer()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
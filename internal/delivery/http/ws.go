@@ -0,0 +1,256 @@
+package myHttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+)
+
+const (
+	wsAuthTimeout    = 10 * time.Second
+	wsPongWait       = 60 * time.Second
+	wsPingPeriod     = 30 * time.Second
+	wsWriteWait      = 5 * time.Second
+	wsCheckTimeout   = 5 * time.Second
+	wsMaxMessageSize = 4096
+)
+
+type StreamHandler interface {
+	PositionStream(c *gin.Context)
+}
+
+type StreamHandlerImpl struct {
+	service  *service.Service
+	cfg      *config.HTTPServerConfig
+	hub      *StreamHub
+	upgrader websocket.Upgrader
+}
+
+func NewStreamHandler(cfg *config.HTTPServerConfig, service *service.Service, hub *StreamHub) StreamHandler {
+	return &StreamHandlerImpl{
+		service: service,
+		cfg:     cfg,
+		hub:     hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// Клиенты — мобильные приложения без cookie-сессий, проверка Origin не нужна
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// PositionStream godoc
+// @Summary Потоковый канал позиций (WebSocket)
+// @Description Открывает WebSocket-соединение. Первым сообщением клиент отправляет {"type":"auth"} с подписью устройства (device_id, timestamp, nonce, signature от строки GET\n/api/v1/location/ws\nTIMESTAMP\nNONCE\nSHA256("")). Если подпись не обязательна (DEVICE_AUTH_MODE не enforce), можно передать user_id. Затем клиент отправляет сообщения {"type":"position","user_location":{...}}, а сервер присылает {"type":"state"} только при изменении состояния опасности.
+// @Tags location
+// @Success 101 "Switching Protocols"
+// @Failure 400 {object} entity.ErrorResponse
+// @Router /location/ws [get]
+func (h *StreamHandlerImpl) PositionStream(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		slog.Error("не удалось открыть websocket", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &positionSession{
+		handler: h,
+		conn:    conn,
+		ctx:     ctx,
+		cancel:  cancel,
+		path:    c.Request.URL.Path,
+	}
+
+	untrack, err := h.hub.Track(session.shutdown)
+	if err != nil {
+		session.writeClose(websocket.CloseGoingAway, err.Error())
+		conn.Close()
+		cancel()
+		return
+	}
+	defer untrack()
+
+	session.run()
+}
+
+// positionSession обслуживает одно WebSocket-соединение
+type positionSession struct {
+	handler *StreamHandlerImpl
+	conn    *websocket.Conn
+	ctx     context.Context
+	cancel  context.CancelFunc
+	path    string
+
+	writeMu   sync.Mutex
+	userID    string
+	lastState string
+}
+
+func (s *positionSession) run() {
+	defer s.conn.Close()
+	defer s.cancel()
+
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	go s.pingLoop()
+
+	if err := s.authenticate(); err != nil {
+		slog.Warn("websocket: ошибка аутентификации", "error", err)
+		s.writeEvent(&entity.StreamEvent{Type: entity.StreamEventError, Error: err.Error()})
+		s.writeClose(websocket.ClosePolicyViolation, "authentication failed")
+		return
+	}
+
+	slog.Info("websocket: клиент подключен", "user_id", s.userID)
+	defer slog.Info("websocket: клиент отключен", "user_id", s.userID)
+
+	for {
+		if err := s.conn.SetReadDeadline(time.Now().Add(wsPongWait)); err != nil {
+			return
+		}
+
+		var msg entity.StreamMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) &&
+				!errors.Is(err, context.Canceled) && s.ctx.Err() == nil {
+				slog.Debug("websocket: чтение прервано", "error", err)
+			}
+			return
+		}
+
+		if msg.Type != entity.StreamMessagePosition || msg.UserLocation == nil {
+			s.writeEvent(&entity.StreamEvent{Type: entity.StreamEventError, Error: "ожидается сообщение position с user_location"})
+			continue
+		}
+
+		s.handlePosition(&msg)
+	}
+}
+
+// authenticate ждет первое сообщение auth и определяет пользователя соединения
+func (s *positionSession) authenticate() error {
+	if err := s.conn.SetReadDeadline(time.Now().Add(wsAuthTimeout)); err != nil {
+		return err
+	}
+
+	var msg entity.StreamMessage
+	if err := s.conn.ReadJSON(&msg); err != nil {
+		return err
+	}
+
+	if msg.Type != entity.StreamMessageAuth {
+		return errors.New("первым сообщением должно быть auth")
+	}
+
+	device, err := s.handler.service.Device.VerifyRequest(s.ctx, &entity.SignedRequest{
+		DeviceID:  msg.DeviceID,
+		Timestamp: msg.Timestamp,
+		Nonce:     msg.Nonce,
+		Signature: msg.Signature,
+		Method:    http.MethodGet,
+		Path:      s.path,
+	})
+
+	switch {
+	case err == nil:
+		s.userID = device.UserID
+	case s.handler.cfg.DeviceAuthMode != DeviceAuthEnforce && msg.UserID != "":
+		slog.Warn("websocket: подпись устройства не прошла проверку (permissive mode)", "error", err)
+		s.userID = msg.UserID
+	default:
+		return err
+	}
+
+	s.writeEvent(&entity.StreamEvent{Type: entity.StreamEventAuthOK, UserID: s.userID})
+	return nil
+}
+
+func (s *positionSession) handlePosition(msg *entity.StreamMessage) {
+	ctx, cancel := context.WithTimeout(s.ctx, wsCheckTimeout)
+	defer cancel()
+
+	resp, err := s.handler.service.Location.CheckLocation(ctx, &entity.CheckLocationRequest{
+		UserID:          s.userID,
+		UserLocation:    *msg.UserLocation,
+		CheckTrajectory: msg.CheckTrajectory,
+	})
+	if err != nil {
+		s.writeEvent(&entity.StreamEvent{Type: entity.StreamEventError, Error: err.Error()})
+		return
+	}
+
+	// Клиенту отправляются только изменения состояния опасности и пересечения зон по пути
+	state := resp.StateKey()
+	if state == s.lastState && resp.Transit == nil {
+		return
+	}
+	s.lastState = state
+
+	s.writeEvent(&entity.StreamEvent{Type: entity.StreamEventState, State: resp})
+}
+
+func (s *positionSession) pingLoop() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *positionSession) writeEvent(event *entity.StreamEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("websocket: ошибка сериализации события", "error", err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return
+	}
+	if err := s.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		slog.Debug("websocket: ошибка отправки", "error", err)
+	}
+}
+
+func (s *positionSession) writeClose(code int, reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteWait))
+}
+
+// shutdown вызывается StreamHub при остановке сервера
+func (s *positionSession) shutdown() {
+	s.writeClose(websocket.CloseGoingAway, "server shutdown")
+	s.cancel()
+	s.conn.Close()
+}
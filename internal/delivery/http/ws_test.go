@@ -0,0 +1,296 @@
+package myHttp
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/signature"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const wsPath = "/api/v1/location/ws"
+
+type routerTestEnv struct {
+	server       *httptest.Server
+	hub          *StreamHub
+	deviceRepo   *mocks.DeviceRepo
+	locationRepo *mocks.LocationRepo
+	incidentRepo *mocks.IncidentRepo
+}
+
+func newRouterTestEnv(t *testing.T, mode string) *routerTestEnv {
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	rdb := &db.Redis{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+
+	env := &routerTestEnv{
+		hub:          NewStreamHub(),
+		deviceRepo:   mocks.NewDeviceRepo(t),
+		locationRepo: mocks.NewLocationRepo(t),
+		incidentRepo: mocks.NewIncidentRepo(t),
+	}
+
+	cfg := &config.Config{HTTPServer: config.HTTPServerConfig{APIKey: "key", DeviceAuthMode: mode}}
+	svc := &service.Service{
+		Device:   service.NewDeviceService(env.deviceRepo, rdb, cfg),
+		Location: service.NewLocationService(env.locationRepo, env.incidentRepo, events.NewBus(rdb.Client), rdb, cfg),
+		Cap:      service.NewCapService(mocks.NewCapRepo(t), env.incidentRepo, mocks.NewGeometryRepo(t), nil, cfg),
+	}
+
+	env.server = httptest.NewServer(NewRouter(&cfg.HTTPServer, svc, env.hub))
+	t.Cleanup(env.server.Close)
+
+	return env
+}
+
+func (e *routerTestEnv) dial(t *testing.T) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(e.server.URL, "http") + wsPath
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) *entity.StreamEvent {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	var event entity.StreamEvent
+	require.NoError(t, conn.ReadJSON(&event))
+
+	return &event
+}
+
+func requireClose(t *testing.T, conn *websocket.Conn, code int) {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	_, _, err := conn.ReadMessage()
+	require.Error(t, err)
+	assert.True(t, websocket.IsCloseError(err, code), "ожидался код закрытия %d, получено %v", code, err)
+}
+
+func TestPositionStream_Auth(t *testing.T) {
+	device := &entity.Device{
+		ID:       uuid.New(),
+		UserID:   uuid.NewString(),
+		Secret:   "secret",
+		IsActive: true,
+	}
+
+	signedAuth := func(secret string) *entity.StreamMessage {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := uuid.NewString()
+
+		return &entity.StreamMessage{
+			Type:      entity.StreamMessageAuth,
+			DeviceID:  device.ID.String(),
+			Timestamp: ts,
+			Nonce:     nonce,
+			Signature: signature.Sign(secret, "GET", wsPath, ts, nonce, nil),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		mode       string
+		msg        *entity.StreamMessage
+		mock       func(r *mocks.DeviceRepo)
+		wantUserID string
+	}{
+		{
+			name:       "Valid device signature",
+			mode:       DeviceAuthEnforce,
+			msg:        signedAuth(device.Secret),
+			mock:       func(r *mocks.DeviceRepo) { r.On("FindByID", mock.Anything, device.ID).Return(device, nil) },
+			wantUserID: device.UserID,
+		},
+		{
+			name: "Invalid signature in enforce mode",
+			mode: DeviceAuthEnforce,
+			msg:  signedAuth("wrong"),
+			mock: func(r *mocks.DeviceRepo) { r.On("FindByID", mock.Anything, device.ID).Return(device, nil) },
+		},
+		{
+			name: "Unknown device in enforce mode",
+			mode: DeviceAuthEnforce,
+			msg:  signedAuth(device.Secret),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByID", mock.Anything, device.ID).Return(nil, postgres.ErrDeviceNotFound)
+			},
+		},
+		{
+			name: "User ID without signature in enforce mode",
+			mode: DeviceAuthEnforce,
+			msg:  &entity.StreamMessage{Type: entity.StreamMessageAuth, UserID: "user-1"},
+			mock: func(r *mocks.DeviceRepo) {},
+		},
+		{
+			name:       "User ID without signature in permissive mode",
+			mode:       DeviceAuthPermissive,
+			msg:        &entity.StreamMessage{Type: entity.StreamMessageAuth, UserID: "user-1"},
+			mock:       func(r *mocks.DeviceRepo) {},
+			wantUserID: "user-1",
+		},
+		{
+			name: "First message is not auth",
+			mode: DeviceAuthPermissive,
+			msg:  &entity.StreamMessage{Type: entity.StreamMessagePosition, UserID: "user-1"},
+			mock: func(r *mocks.DeviceRepo) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newRouterTestEnv(t, tt.mode)
+			tt.mock(env.deviceRepo)
+
+			conn := env.dial(t)
+			require.NoError(t, conn.WriteJSON(tt.msg))
+
+			event := readEvent(t, conn)
+
+			if tt.wantUserID == "" {
+				assert.Equal(t, entity.StreamEventError, event.Type)
+				requireClose(t, conn, websocket.ClosePolicyViolation)
+				return
+			}
+
+			assert.Equal(t, entity.StreamEventAuthOK, event.Type)
+			assert.Equal(t, tt.wantUserID, event.UserID)
+		})
+	}
+}
+
+func TestPositionStream_StateChange(t *testing.T) {
+	env := newRouterTestEnv(t, DeviceAuthPermissive)
+
+	zone := entity.Incident{
+		ID:       uuid.New(),
+		Name:     "Zone",
+		IsActive: true,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{10, 10}, {11, 10}, {11, 11}, {10, 11}, {10, 10}}},
+		},
+	}
+	env.incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{zone}, nil)
+	env.locationRepo.On("SaveLocationCheck", mock.Anything, mock.Anything).Return(nil)
+
+	conn := env.dial(t)
+	require.NoError(t, conn.WriteJSON(&entity.StreamMessage{Type: entity.StreamMessageAuth, UserID: "user-1"}))
+	require.Equal(t, entity.StreamEventAuthOK, readEvent(t, conn).Type)
+
+	sendPosition := func(lat, lon float64) {
+		require.NoError(t, conn.WriteJSON(&entity.StreamMessage{
+			Type:         entity.StreamMessagePosition,
+			UserLocation: &entity.UserLocation{Lat: lat, Lon: lon},
+		}))
+	}
+
+	// Первая позиция всегда приходит клиенту: состояние еще не отправлялось
+	sendPosition(10.5, 10.5)
+	event := readEvent(t, conn)
+	require.Equal(t, entity.StreamEventState, event.Type)
+	require.NotNil(t, event.State)
+	assert.True(t, event.State.IsDanger)
+	require.Len(t, event.State.Incidents, 1)
+	assert.Equal(t, zone.ID, event.State.Incidents[0].ID)
+
+	// Повтор того же состояния не отправляется: следующим событием будет выход из зоны
+	sendPosition(10.6, 10.6)
+	sendPosition(20, 20)
+
+	event = readEvent(t, conn)
+	require.Equal(t, entity.StreamEventState, event.Type)
+	assert.False(t, event.State.IsDanger)
+	assert.Empty(t, event.State.Incidents)
+
+	env.locationRepo.AssertNumberOfCalls(t, "SaveLocationCheck", 3)
+}
+
+func TestPositionStream_HubShutdown(t *testing.T) {
+	env := newRouterTestEnv(t, DeviceAuthPermissive)
+
+	conn := env.dial(t)
+	require.NoError(t, conn.WriteJSON(&entity.StreamMessage{Type: entity.StreamMessageAuth, UserID: "user-1"}))
+	require.Equal(t, entity.StreamEventAuthOK, readEvent(t, conn).Type)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	require.NoError(t, env.hub.Shutdown(ctx))
+	requireClose(t, conn, websocket.CloseGoingAway)
+
+	// После остановки новые соединения сразу закрываются
+	requireClose(t, env.dial(t), websocket.CloseGoingAway)
+}
+
+func TestStreamHub_Shutdown(t *testing.T) {
+	t.Run("Closes tracked connections and waits for handlers", func(t *testing.T) {
+		hub := NewStreamHub()
+
+		done := make(chan struct{})
+		untrack, err := hub.Track(func() { close(done) })
+		require.NoError(t, err)
+
+		// Обработчик завершается только после вызова closer
+		go func() {
+			<-done
+			untrack()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		require.NoError(t, hub.Shutdown(ctx))
+
+		_, err = hub.Track(func() {})
+		assert.ErrorIs(t, err, ErrStreamHubClosed)
+	})
+
+	t.Run("Returns context error when handler does not finish", func(t *testing.T) {
+		hub := NewStreamHub()
+
+		_, err := hub.Track(func() {})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, hub.Shutdown(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("Untracked connection is not closed", func(t *testing.T) {
+		hub := NewStreamHub()
+
+		called := false
+		untrack, err := hub.Track(func() { called = true })
+		require.NoError(t, err)
+		untrack()
+		untrack()
+
+		require.NoError(t, hub.Shutdown(context.Background()))
+		assert.False(t, called)
+	})
+}
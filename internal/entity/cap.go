@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CapMessage — принятое сообщение CAP и инцидент, который оно создало, изменило или отменило
+type CapMessage struct {
+	Sender     string
+	Identifier string
+	Sent       time.Time
+	MsgType    string
+	IncidentID uuid.UUID
+	Event      string
+	Urgency    string
+	Severity   string
+	Certainty  string
+	Onset      *time.Time
+	Expires    *time.Time
+	ReceivedAt time.Time
+}
+
+const (
+	CapActionCreated   = "created"
+	CapActionUpdated   = "updated"
+	CapActionCancelled = "cancelled"
+	CapActionIgnored   = "ignored"
+	CapActionDuplicate = "duplicate"
+)
+
+// CapIngestResponse — результат обработки сообщения CAP. Reason поясняет action=ignored.
+type CapIngestResponse struct {
+	Identifier string     `json:"identifier" example:"43b080713727"`
+	Sender     string     `json:"sender" example:"hsas@dhs.gov"`
+	MsgType    string     `json:"msg_type" example:"Alert"`
+	Action     string     `json:"action" example:"created"`
+	Reason     string     `json:"reason,omitempty" example:"status Exercise: обрабатываются только сообщения Actual"`
+	IncidentID string     `json:"incident_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Event      string     `json:"event,omitempty" example:"Наводнение"`
+	Urgency    string     `json:"urgency,omitempty" example:"Immediate"`
+	Severity   string     `json:"severity,omitempty" example:"Severe"`
+	Certainty  string     `json:"certainty,omitempty" example:"Observed"`
+	Onset      *time.Time `json:"onset,omitempty"`
+	Expires    *time.Time `json:"expires,omitempty"`
+}
+
+// CapDocument — сформированная лента Atom или сообщение CAP с валидаторами для условных запросов.
+// ETag и LastModified вычисляются из updated_at инцидентов.
+type CapDocument struct {
+	Body         []byte
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
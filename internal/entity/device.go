@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Device struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID string    `json:"user_id" db:"user_id"`
+	Name   string    `json:"name,omitempty" db:"name"`
+	Secret string    `json:"-" db:"secret"`
+	// TrackerTokenHash — SHA-256 токена трекера в hex; сам токен не хранится
+	TrackerTokenHash string     `json:"-" db:"tracker_token_hash"`
+	IsActive         bool       `json:"is_active" db:"is_active"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+type RegisterDeviceRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name   string `json:"name" binding:"omitempty,max=255" example:"Pixel 8"`
+}
+
+// RegisterDeviceResponse содержит секрет устройства и токен трекера OsmAnd/Traccar.
+// Оба значения возвращаются только один раз при регистрации.
+type RegisterDeviceResponse struct {
+	ID           string `json:"id" example:"8489c629-9e32-4d2d-9475-430349257bd7"`
+	UserID       string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Secret       string `json:"secret" example:"4f7a0c..."`
+	TrackerToken string `json:"tracker_token" example:"9b1e5d..."`
+}
+
+type GetDeviceResponse struct {
+	ID        string     `json:"id" example:"8489c629-9e32-4d2d-9475-430349257bd7"`
+	UserID    string     `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string     `json:"name,omitempty" example:"Pixel 8"`
+	IsActive  bool       `json:"is_active" example:"true"`
+	CreatedAt time.Time  `json:"created_at" example:"2026-01-18T18:30:00Z"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// SignedRequest — параметры подписанного запроса устройства
+type SignedRequest struct {
+	DeviceID  string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
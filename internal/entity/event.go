@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы доменных событий ленты оператора
+const (
+	EventIncidentCreated     = "incident.created"
+	EventIncidentUpdated     = "incident.updated"
+	EventIncidentDeactivated = "incident.deactivated"
+	// Смена статуса в процессе согласования: отправка, одобрение, возврат, завершение
+	EventIncidentStatusChanged = "incident.status_changed"
+	EventLocationDanger        = "location.danger"
+	EventWebhookFailed         = "webhook.failed"
+	EventWebhookDLQ            = "webhook.dlq"
+)
+
+// EventTypes — все поддерживаемые типы событий
+var EventTypes = []string{
+	EventIncidentCreated,
+	EventIncidentUpdated,
+	EventIncidentDeactivated,
+	EventIncidentStatusChanged,
+	EventLocationDanger,
+	EventWebhookFailed,
+	EventWebhookDLQ,
+}
+
+type Event struct {
+	ID         uuid.UUID  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Type       string     `json:"type" example:"incident.created"`
+	IncidentID *uuid.UUID `json:"incident_id,omitempty" example:"8489c629-9e32-4d2d-9475-430349257bd7"`
+	UserID     string     `json:"user_id,omitempty" example:"user-123"`
+	Data       any        `json:"data,omitempty"`
+	CreatedAt  time.Time  `json:"created_at" example:"2026-01-18T18:30:00Z"`
+}
+
+// DangerEventData — данные события location.danger
+type DangerEventData struct {
+	Status       string       `json:"status" example:"inside"`
+	UserLocation UserLocation `json:"user_location"`
+	IsFlagged    bool         `json:"is_flagged" example:"false"`
+}
+
+// WebhookEventData — данные событий webhook.failed и webhook.dlq
+type WebhookEventData struct {
+	TaskID     uuid.UUID `json:"task_id"`
+	RetryCount int       `json:"retry_count" example:"3"`
+	Error      string    `json:"error"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// IncidentExportFilter — фильтры выгрузки, совпадающие с GET /incidents.
+// Limit = 0 выгружает все инциденты, AsOf задает состояние на момент времени.
+type IncidentExportFilter struct {
+	Limit  int
+	Offset int
+	AsOf   *time.Time
+}
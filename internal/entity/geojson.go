@@ -4,13 +4,22 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"math"
 )
 
+// metersPerDegree — длина одного градуса широты в метрах (приближение для локальных расчетов)
+const metersPerDegree = 111320.0
+
 type GeoJsonPolygon struct {
 	Type        string        `json:"type" example:"Polygon"`
 	Coordinates [][][]float64 `json:"coordinates"`
 }
 
+type GeoJsonLineString struct {
+	Type        string      `json:"type" example:"LineString"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
 func (g GeoJsonPolygon) Value() (driver.Value, error) {
 	return json.Marshal(g)
 }
@@ -51,3 +60,130 @@ func (g *GeoJsonPolygon) Contains(lat, lon float64) bool {
 
 	return inside
 }
+
+// IntersectsSegment проверяет, пересекает ли отрезок между двумя точками полигон:
+// хотя бы один из концов лежит внутри или отрезок пересекает внешнюю границу.
+func (g *GeoJsonPolygon) IntersectsSegment(fromLat, fromLon, toLat, toLon float64) bool {
+	if len(g.Coordinates) == 0 {
+		return false
+	}
+
+	if g.Contains(fromLat, fromLon) || g.Contains(toLat, toLon) {
+		return true
+	}
+
+	polygon := g.Coordinates[0]
+	for i := 0; i+1 < len(polygon); i++ {
+		if segmentsIntersect(
+			fromLon, fromLat, toLon, toLat,
+			polygon[i][0], polygon[i][1], polygon[i+1][0], polygon[i+1][1],
+		) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func segmentsIntersect(ax, ay, bx, by, cx, cy, dx, dy float64) bool {
+	d1 := orientation(cx, cy, dx, dy, ax, ay)
+	d2 := orientation(cx, cy, dx, dy, bx, by)
+	d3 := orientation(ax, ay, bx, by, cx, cy)
+	d4 := orientation(ax, ay, bx, by, dx, dy)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	switch {
+	case d1 == 0 && onSegment(cx, cy, dx, dy, ax, ay):
+		return true
+	case d2 == 0 && onSegment(cx, cy, dx, dy, bx, by):
+		return true
+	case d3 == 0 && onSegment(ax, ay, bx, by, cx, cy):
+		return true
+	case d4 == 0 && onSegment(ax, ay, bx, by, dx, dy):
+		return true
+	}
+
+	return false
+}
+
+// orientation возвращает знак векторного произведения (b - a) x (c - a)
+func orientation(ax, ay, bx, by, cx, cy float64) float64 {
+	return (bx-ax)*(cy-ay) - (by-ay)*(cx-ax)
+}
+
+// onSegment проверяет, лежит ли коллинеарная точка c внутри отрезка ab
+func onSegment(ax, ay, bx, by, cx, cy float64) bool {
+	return cx >= min(ax, bx) && cx <= max(ax, bx) &&
+		cy >= min(ay, by) && cy <= max(ay, by)
+}
+
+// OverlapRatio возвращает долю площади круга радиусом radiusM метров с центром в точке,
+// приходящуюся на полигон: 1 — круг целиком внутри, 0 — целиком снаружи.
+func (g *GeoJsonPolygon) OverlapRatio(lat, lon, radiusM float64) float64 {
+	inside := g.Contains(lat, lon)
+
+	if radiusM <= 0 || g.DistanceToBoundary(lat, lon) >= radiusM {
+		if inside {
+			return 1
+		}
+		return 0
+	}
+
+	// Круг пересекает границу: считаем долю равновеликих ячеек полярной сетки, центры которых внутри полигона
+	const rings, sectors = 10, 36
+	cosLat := math.Cos(lat * math.Pi / 180)
+
+	hits := 0
+	for i := 0; i < rings; i++ {
+		r := radiusM * math.Sqrt((float64(i)+0.5)/rings)
+		for j := 0; j < sectors; j++ {
+			angle := 2 * math.Pi * (float64(j) + 0.5) / sectors
+			pointLat := lat + r*math.Sin(angle)/metersPerDegree
+			pointLon := lon + r*math.Cos(angle)/(metersPerDegree*cosLat)
+			if g.Contains(pointLat, pointLon) {
+				hits++
+			}
+		}
+	}
+
+	return float64(hits) / float64(rings*sectors)
+}
+
+// DistanceToBoundary возвращает приблизительное расстояние в метрах от точки до внешней границы полигона
+func (g *GeoJsonPolygon) DistanceToBoundary(lat, lon float64) float64 {
+	if len(g.Coordinates) == 0 {
+		return math.Inf(1)
+	}
+
+	cosLat := math.Cos(lat * math.Pi / 180)
+	toLocal := func(p []float64) (float64, float64) {
+		return (p[0] - lon) * metersPerDegree * cosLat, (p[1] - lat) * metersPerDegree
+	}
+
+	polygon := g.Coordinates[0]
+	distance := math.Inf(1)
+	for i := 0; i+1 < len(polygon); i++ {
+		ax, ay := toLocal(polygon[i])
+		bx, by := toLocal(polygon[i+1])
+		distance = math.Min(distance, distanceToSegment(0, 0, ax, ay, bx, by))
+	}
+
+	return distance
+}
+
+func distanceToSegment(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+
+	t := ((px-ax)*dx + (py-ay)*dy) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
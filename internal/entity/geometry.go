@@ -0,0 +1,52 @@
+package entity
+
+// GeometryCommit — общие поля операций над зоной. Без commit=true результат только
+// возвращается для просмотра; с commit=true зона инцидента заменяется результатом
+// через обычное обновление с записью версии.
+type GeometryCommit struct {
+	Commit bool   `json:"commit" example:"false"`
+	Reason string `json:"reason" binding:"omitempty,max=1000" example:"Зона расширена на 300 м"`
+	Actor  string `json:"-"`
+}
+
+// BufferIncidentRequest — расширение зоны на DistanceM метров (отрицательное значение сужает зону)
+type BufferIncidentRequest struct {
+	DistanceM float64 `json:"distance_m" binding:"required,min=-50000,max=50000" example:"300"`
+	GeometryCommit
+}
+
+// SimplifyIncidentRequest — упрощение границы зоны с допуском ToleranceM метров без нарушения топологии
+type SimplifyIncidentRequest struct {
+	ToleranceM float64 `json:"tolerance_m" binding:"required,gt=0,max=10000" example:"25"`
+	GeometryCommit
+}
+
+// CombineIncidentRequest — второй операнд объединения или вычитания: другой инцидент
+// или произвольный полигон. Должно быть указано ровно одно из полей.
+type CombineIncidentRequest struct {
+	IncidentID string          `json:"incident_id" binding:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Area       *GeoJsonPolygon `json:"area"`
+	GeometryCommit
+}
+
+// GeometryResult — результат операции над полигоном с площадями до и после в квадратных метрах
+type GeometryResult struct {
+	Area           GeoJsonPolygon `json:"area"`
+	AreaM2         float64        `json:"area_m2" example:"1250000.5"`
+	PreviousAreaM2 float64        `json:"previous_area_m2" example:"980000.2"`
+}
+
+type GeometryOperationResponse struct {
+	Operation string `json:"operation" example:"buffer"`
+	GeometryResult
+	AreaChangeM2 float64            `json:"area_change_m2" example:"270000.3"`
+	Committed    bool               `json:"committed" example:"false"`
+	Overlaps     []*IncidentOverlap `json:"overlaps,omitempty"`
+}
+
+const (
+	GeometryOperationBuffer     = "buffer"
+	GeometryOperationUnion      = "union"
+	GeometryOperationDifference = "difference"
+	GeometryOperationSimplify   = "simplify"
+)
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PreviewIncidentRequest — черновик зоны, для которого оценивается охват пользователей.
+// Окно и шаг разбивки задаются в минутах; по умолчанию окно совпадает с окном статистики.
+type PreviewIncidentRequest struct {
+	Area          GeoJsonPolygon `json:"area" binding:"required"`
+	WindowMinutes int            `json:"window_minutes" binding:"omitempty,min=1,max=10080" example:"60"`
+	BucketMinutes int            `json:"bucket_minutes" binding:"omitempty,min=1,max=1440" example:"15"`
+}
+
+// AffectedUser — пользователь, проверки которого за окно попали в черновик зоны
+type AffectedUser struct {
+	UserID     string    `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Checks     int       `json:"checks" example:"4"`
+	LastSeenAt time.Time `json:"last_seen_at" example:"2026-01-18T18:30:00Z"`
+}
+
+// ImpactBucket — число пользователей и проверок внутри зоны за интервал [Start, Start+шаг)
+type ImpactBucket struct {
+	Start  time.Time `json:"start" example:"2026-01-18T18:15:00Z"`
+	Users  int       `json:"users" example:"12"`
+	Checks int       `json:"checks" example:"40"`
+}
+
+// IncidentOverlap — пересечение полигона с существующим активным инцидентом.
+// Доли считаются от площади полигона и от площади инцидента соответственно.
+type IncidentOverlap struct {
+	IncidentID      uuid.UUID `json:"incident_id"`
+	Name            string    `json:"name" example:"Наводнение"`
+	OverlapAreaM2   float64   `json:"overlap_area_m2" example:"125000.5"`
+	OverlapPercent  float64   `json:"overlap_percent" example:"35.2"`
+	IncidentPercent float64   `json:"incident_percent" example:"80.1"`
+}
+
+type PreviewIncidentResponse struct {
+	WindowMinutes int                `json:"window_minutes" example:"60"`
+	BucketMinutes int                `json:"bucket_minutes" example:"15"`
+	Since         time.Time          `json:"since" example:"2026-01-18T17:30:00Z"`
+	AffectedUsers int                `json:"affected_users" example:"42"`
+	Users         []*AffectedUser    `json:"users"`
+	Buckets       []*ImpactBucket    `json:"buckets"`
+	Overlaps      []*IncidentOverlap `json:"overlaps"`
+}
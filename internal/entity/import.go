@@ -0,0 +1,86 @@
+package entity
+
+import "encoding/json"
+
+// Форматы файлов импорта. KML и Shapefile преобразуются в FeatureCollection и проходят
+// ту же проверку, что и GeoJSON.
+const (
+	ImportFormatGeoJSON   = "geojson"
+	ImportFormatKML       = "kml"
+	ImportFormatShapefile = "shapefile"
+)
+
+// GeoJsonFeatureCollection — набор зон для импорта. Геометрия каждого объекта разбирается
+// отдельно, чтобы ошибка в одном объекте попала в отчет, а не отклонила весь запрос.
+type GeoJsonFeatureCollection struct {
+	Type     string           `json:"type" binding:"required,eq=FeatureCollection" example:"FeatureCollection"`
+	Features []GeoJsonFeature `json:"features" binding:"required,min=1"`
+}
+
+type GeoJsonFeature struct {
+	Type       string          `json:"type" example:"Feature"`
+	ID         any             `json:"id,omitempty" swaggertype:"string" example:"zone-42"`
+	Geometry   json.RawMessage `json:"geometry" swaggertype:"object"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// ImportMapping — имена свойств объекта, из которых берутся поля инцидента.
+// Если свойство external_id не задано, используется id объекта.
+type ImportMapping struct {
+	Name        string
+	Description string
+	Severity    string
+	ExternalID  string
+}
+
+// DefaultImportMapping — соответствие свойств по умолчанию
+var DefaultImportMapping = ImportMapping{
+	Name:        "name",
+	Description: "description",
+	Severity:    "severity",
+	ExternalID:  "external_id",
+}
+
+type ImportIncidentsRequest struct {
+	Collection GeoJsonFeatureCollection
+	Mapping    ImportMapping
+	Reason     string
+	Actor      string
+	// DryRun только проверяет объекты и возвращает отчет с проблемами геометрии, ничего не сохраняя
+	DryRun bool
+}
+
+const (
+	ImportActionCreated = "created"
+	ImportActionUpdated = "updated"
+	ImportActionValid   = "valid"
+	ImportActionFailed  = "failed"
+)
+
+// ImportFeatureResult — итог по одному объекту. Index — позиция объекта в features.
+// При отклоненном импорте корректные объекты получают action=valid.
+type ImportFeatureResult struct {
+	Index      int      `json:"index" example:"0"`
+	ExternalID string   `json:"external_id,omitempty" example:"zone-42"`
+	ID         string   `json:"id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name       string   `json:"name,omitempty" example:"Зона подтопления"`
+	Action     string   `json:"action" example:"created"`
+	Errors     []string `json:"errors,omitempty"`
+	// Issues — проблемы геометрии с местами, заполняются при пробном импорте
+	Issues []PolygonIssue `json:"issues,omitempty"`
+	// Overlaps — пересечения зоны с активными инцидентами
+	Overlaps []*IncidentOverlap `json:"overlaps,omitempty"`
+}
+
+// ImportIncidentsResponse — отчет об импорте. Импорт выполняется целиком или не выполняется:
+// при любой ошибке committed=false и ни один инцидент не сохраняется. При пробном импорте
+// dry_run=true, committed=false, а корректные объекты получают action=valid.
+type ImportIncidentsResponse struct {
+	Committed bool                  `json:"committed" example:"true"`
+	DryRun    bool                  `json:"dry_run,omitempty" example:"false"`
+	Total     int                   `json:"total" example:"12"`
+	Created   int                   `json:"created" example:"10"`
+	Updated   int                   `json:"updated" example:"2"`
+	Failed    int                   `json:"failed" example:"0"`
+	Features  []ImportFeatureResult `json:"features"`
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,25 +13,104 @@ type Incident struct {
 	Description string         `json:"description,omitempty" db:"description"`
 	Area        GeoJsonPolygon `json:"area" db:"area"`
 	IsActive    bool           `json:"is_active" db:"is_active"`
+	Status      string         `json:"status" db:"status"`
+	Severity    string         `json:"severity" db:"severity"`
+	SubmittedBy string         `json:"submitted_by,omitempty" db:"submitted_by"`
+	ParentID    *uuid.UUID     `json:"parent_id,omitempty" db:"parent_id"`
+	ExternalID  string         `json:"external_id,omitempty" db:"external_id"`
 	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Автор и причина изменения, записываются в историю версий
+	ChangedBy    string `json:"-" db:"-"`
+	ChangeReason string `json:"-" db:"-"`
+	// Номер версии, если инцидент получен на момент времени из истории
+	Version int `json:"-" db:"-"`
+}
+
+const (
+	IncidentOperationCreate  = "create"
+	IncidentOperationUpdate  = "update"
+	IncidentOperationDelete  = "delete"
+	IncidentOperationSubmit  = "submit"
+	IncidentOperationApprove = "approve"
+	IncidentOperationReject  = "reject"
+	IncidentOperationResolve = "resolve"
+)
+
+// Статусы инцидента. Проверки локаций учитывают только опубликованные инциденты,
+// is_active хранится синхронно со статусом published.
+const (
+	IncidentStatusDraft         = "draft"
+	IncidentStatusPendingReview = "pending_review"
+	IncidentStatusPublished     = "published"
+	IncidentStatusResolved      = "resolved"
+)
+
+const (
+	IncidentSeverityMinor    = "minor"
+	IncidentSeverityModerate = "moderate"
+	IncidentSeveritySevere   = "severe"
+	IncidentSeverityExtreme  = "extreme"
+)
+
+// IncidentSeverities — уровни серьезности по возрастанию
+var IncidentSeverities = []string{
+	IncidentSeverityMinor,
+	IncidentSeverityModerate,
+	IncidentSeveritySevere,
+	IncidentSeverityExtreme,
+}
+
+// SeverityRank возвращает порядковый номер уровня серьезности начиная с 1 или 0 для неизвестного уровня
+func SeverityRank(severity string) int {
+	for i, s := range IncidentSeverities {
+		if s == severity {
+			return i + 1
+		}
+	}
+	return 0
 }
 
 type CreateIncidentRequest struct {
 	Name        string         `json:"name" binding:"required,min=1,max=255" example:"Наводнение"`
 	Description string         `json:"description" binding:"omitempty,max=1000" example:"Описание наводнения"`
 	Area        GeoJsonPolygon `json:"area" binding:"required"`
+	Severity    string         `json:"severity" binding:"omitempty,oneof=minor moderate severe extreme" example:"moderate"`
+	ParentID    string         `json:"parent_id" binding:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Reason      string         `json:"reason" binding:"omitempty,max=1000" example:"Сообщение МЧС"`
+	Actor       string         `json:"-"`
 }
 
+// UpdateIncidentRequest — частичное обновление инцидента. Пустой parent_id отвязывает
+// инцидент от родителя.
 type UpdateIncidentRequest struct {
 	Name        *string         `json:"name" binding:"omitempty,min=1,max=255" example:"Наводнение"`
 	Description *string         `json:"description" binding:"omitempty,max=1000" example:"Описание наводнения"`
 	Area        *GeoJsonPolygon `json:"area" binding:"omitempty"`
+	Severity    *string         `json:"severity" binding:"omitempty,oneof=minor moderate severe extreme" example:"severe"`
+	ParentID    *string         `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Reason      string          `json:"reason" binding:"omitempty,max=1000" example:"Уточнены границы зоны"`
+	Actor       string          `json:"-"`
+}
+
+type DeleteIncidentRequest struct {
+	Reason string `form:"reason" binding:"omitempty,max=1000"`
+	Actor  string `form:"-"`
 }
 
+// IncidentTransitionRequest — перевод инцидента в следующий статус процесса согласования
+type IncidentTransitionRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000" example:"Границы согласованы с МЧС"`
+	Actor  string `json:"-"`
+}
+
+// IncidentResponse — результат изменения инцидента. Overlaps при создании и смене зоны
+// предупреждает о пересечениях с другими активными инцидентами (возможные дубли).
 type IncidentResponse struct {
-	Status string `json:"status" example:"успешно создано"`
-	Error  string `json:"error,omitempty" example:"ошибка ввода: неверная область"`
+	Status   string             `json:"status" example:"успешно создано"`
+	Error    string             `json:"error,omitempty" example:"ошибка ввода: неверная область"`
+	Overlaps []*IncidentOverlap `json:"overlaps,omitempty"`
 }
 
 type GetIncidentResponse struct {
@@ -39,11 +119,69 @@ type GetIncidentResponse struct {
 	Description string         `json:"description" example:"Описание наводнения"`
 	Area        GeoJsonPolygon `json:"area"`
 	IsActive    bool           `json:"is_active" example:"true"`
+	Status      string         `json:"status" example:"published"`
+	Severity    string         `json:"severity" example:"moderate"`
+	SubmittedBy string         `json:"submitted_by,omitempty" example:"operator"`
+	ParentID    string         `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ExternalID  string         `json:"external_id,omitempty" example:"zone-42"`
 	CreatedAt   time.Time      `json:"created_at" example:"2026-01-18T18:30:00Z"`
 	UpdatedAt   time.Time      `json:"updated_at" example:"2026-01-18T18:30:00Z"`
+	Version     int            `json:"version,omitempty" example:"2"`
+	// Последнее сообщение о ходе инцидента
+	LatestUpdate *IncidentUpdate `json:"latest_update,omitempty"`
+	// Дочерние инциденты, если запрошено дерево (include=tree)
+	Children []*GetIncidentResponse `json:"children,omitempty"`
 }
 
 type GetIncidentsResponse struct {
 	Incidents []GetIncidentResponse `json:"incidents"`
 	Total     int                   `json:"total" example:"10"`
 }
+
+// IncidentVersion — состояние инцидента после очередного изменения
+type IncidentVersion struct {
+	IncidentID   uuid.UUID      `json:"-"`
+	Version      int            `json:"version" example:"2"`
+	Operation    string         `json:"operation" example:"update"`
+	Name         string         `json:"name" example:"Наводнение"`
+	Description  string         `json:"description" example:"Описание наводнения"`
+	Area         GeoJsonPolygon `json:"area"`
+	IsActive     bool           `json:"is_active" example:"true"`
+	Status       string         `json:"status" example:"published"`
+	Severity     string         `json:"severity" example:"moderate"`
+	ChangedBy    string         `json:"changed_by,omitempty" example:"operator"`
+	ChangeReason string         `json:"change_reason,omitempty" example:"Уточнены границы зоны"`
+	ValidFrom    time.Time      `json:"valid_from" example:"2026-01-18T18:30:00Z"`
+	ValidTo      *time.Time     `json:"valid_to,omitempty" example:"2026-01-18T19:30:00Z"`
+}
+
+type GetIncidentHistoryResponse struct {
+	IncidentID string             `json:"incident_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Versions   []*IncidentVersion `json:"versions"`
+	Total      int                `json:"total" example:"3"`
+}
+
+type FieldChange struct {
+	Field string `json:"field" example:"name"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// GeometryDiff описывает изменение зоны: добавленную и убранную области и их площади
+type GeometryDiff struct {
+	Changed       bool            `json:"changed" example:"true"`
+	OldAreaM2     float64         `json:"old_area_m2" example:"125000"`
+	NewAreaM2     float64         `json:"new_area_m2" example:"180000"`
+	AddedAreaM2   float64         `json:"added_area_m2" example:"60000"`
+	RemovedAreaM2 float64         `json:"removed_area_m2" example:"5000"`
+	Added         json.RawMessage `json:"added,omitempty" swaggertype:"object"`
+	Removed       json.RawMessage `json:"removed,omitempty" swaggertype:"object"`
+}
+
+type IncidentDiffResponse struct {
+	IncidentID  string         `json:"incident_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	FromVersion int            `json:"from_version" example:"1"`
+	ToVersion   int            `json:"to_version" example:"2"`
+	Changes     []*FieldChange `json:"changes"`
+	Geometry    *GeometryDiff  `json:"geometry"`
+}
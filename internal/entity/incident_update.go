@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncidentUpdate — сообщение оператора о ходе инцидента («пожар локализован на 40%»).
+// Не меняет описание и зону инцидента и не создает новую версию.
+type IncidentUpdate struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	IncidentID uuid.UUID `json:"incident_id" db:"incident_id"`
+	Message    string    `json:"message" db:"message" example:"Пожар локализован на 40%"`
+	Author     string    `json:"author,omitempty" db:"author" example:"operator"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2026-01-18T18:30:00Z"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at" example:"2026-01-18T18:30:00Z"`
+}
+
+// CreateIncidentUpdateRequest — новое сообщение по инциденту. С notify = true сообщение
+// отправляется вебхуком пользователям, недавно проверявшим локацию внутри зоны.
+type CreateIncidentUpdateRequest struct {
+	Message string `json:"message" binding:"required,max=2000" example:"Пожар локализован на 40%"`
+	Notify  bool   `json:"notify" example:"true"`
+	Actor   string `json:"-"`
+}
+
+type UpdateIncidentUpdateRequest struct {
+	Message string `json:"message" binding:"required,max=2000" example:"Пожар локализован на 60%"`
+}
+
+type CreateIncidentUpdateResponse struct {
+	Update *IncidentUpdate `json:"update"`
+	// Число пользователей, которым поставлен в очередь вебхук
+	Notified int `json:"notified" example:"12"`
+	// Пользователей в зоне больше, чем допустимо уведомить за один раз
+	Truncated bool `json:"truncated,omitempty" example:"false"`
+}
+
+type GetIncidentUpdatesResponse struct {
+	Updates []*IncidentUpdate `json:"updates"`
+}
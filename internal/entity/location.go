@@ -1,37 +1,148 @@
 package entity
 
 import (
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	ReviewStatusPending   = "pending"
+	ReviewStatusConfirmed = "confirmed"
+	ReviewStatusDismissed = "dismissed"
+)
+
+const (
+	LocationStatusInside         = "inside"
+	LocationStatusPossiblyInside = "possibly_inside"
+	LocationStatusOutside        = "outside"
+)
+
 type UserLocation struct {
-	Lat float64 `json:"lat"`
-	Lon float64 `json:"lon"`
+	Lat       float64  `json:"lat"`
+	Lon       float64  `json:"lon"`
+	AccuracyM *float64 `json:"accuracy_m,omitempty" example:"25"`
+}
+
+// DistanceTo возвращает расстояние по большому кругу до другой точки в метрах
+func (l UserLocation) DistanceTo(other UserLocation) float64 {
+	const earthRadiusM = 6371000.0
+
+	lat1 := l.Lat * math.Pi / 180
+	lat2 := other.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Lon - l.Lon) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusM * math.Asin(math.Min(1, math.Sqrt(a)))
 }
 
 type LocationCheck struct {
-	ID           uuid.UUID    `db:"id"`
-	UserID       string       `db:"user_id"`
-	UserLocation UserLocation `db:"-"`
-	IsDanger     bool         `db:"is_danger"`
-	IncidentID   *uuid.UUID   `db:"incident_id"`
-	CreatedAt    time.Time    `db:"created_at"`
+	ID              int64        `db:"id"`
+	UserID          string       `db:"user_id"`
+	UserLocation    UserLocation `db:"-"`
+	IsDanger        bool         `db:"is_danger"`
+	IncidentID      *uuid.UUID   `db:"incident_id"`
+	MatchStatus     string       `db:"match_status"`
+	IsFlagged       bool         `db:"is_flagged"`
+	FlagReason      string       `db:"flag_reason"`
+	ImpliedSpeedKmh *float64     `db:"implied_speed_kmh"`
+	IsExcluded      bool         `db:"is_excluded"`
+	CreatedAt       time.Time    `db:"created_at"`
+}
+
+// FlaggedLocationCheck — проверка локации, помеченная детектором подмены координат
+type FlaggedLocationCheck struct {
+	ID              int64        `json:"id" example:"42"`
+	UserID          string       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserLocation    UserLocation `json:"user_location"`
+	IsDanger        bool         `json:"is_danger" example:"true"`
+	IncidentID      *uuid.UUID   `json:"incident_id,omitempty"`
+	FlagReason      string       `json:"flag_reason" example:"impossible_travel"`
+	ImpliedSpeedKmh *float64     `json:"implied_speed_kmh,omitempty" example:"1840.5"`
+	IsExcluded      bool         `json:"is_excluded" example:"true"`
+	ReviewStatus    string       `json:"review_status" example:"pending"`
+	ReviewedBy      string       `json:"reviewed_by,omitempty" example:"operator"`
+	ReviewedAt      *time.Time   `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time    `json:"created_at" example:"2026-01-18T18:30:00Z"`
+}
+
+type GetFlaggedChecksResponse struct {
+	Checks []*FlaggedLocationCheck `json:"checks"`
+	Total  int                     `json:"total" example:"10"`
+}
+
+type ReviewLocationCheckRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=confirm dismiss" example:"confirm"`
+	Reviewer string `json:"reviewer" binding:"omitempty,max=255" example:"operator"`
 }
 
+// LocationCheckIncident сериализуется с ключами ID, Name и Description, как в первой версии API:
+// от них зависят существующие клиенты /location/check. Status добавлен позже в том же регистре.
 type LocationCheckIncident struct {
 	ID          uuid.UUID `db:"id"`
 	Name        string    `db:"name"`
 	Description string    `db:"description"`
+	Status      string    `json:"Status,omitempty" db:"-" example:"inside"`
 }
 
 type CheckLocationRequest struct {
-	UserID       string       `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
-	UserLocation UserLocation `json:"user_location" binding:"required"`
+	UserID          string       `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserLocation    UserLocation `json:"user_location" binding:"required"`
+	CheckTrajectory bool         `json:"check_trajectory" example:"true"`
+	// Timestamp — время фиксации координат устройством. Заполняется транспортами трекеров
+	// (OsmAnd), которые могут присылать накопленные офлайн точки; по умолчанию — время запроса.
+	Timestamp *time.Time `json:"-"`
 }
 
 type CheckLocationResponse struct {
 	IsDanger  bool                     `json:"is_danger" example:"true"`
+	Status    string                   `json:"status" example:"inside"`
 	Incidents []*LocationCheckIncident `json:"incidents,omitempty"`
+	Transit   *TransitEvent            `json:"transit,omitempty"`
+}
+
+// StateKey описывает состояние опасности: статус и отсортированный список зон.
+// Потоковые каналы отправляют клиенту событие только при изменении этого ключа.
+func (r *CheckLocationResponse) StateKey() string {
+	ids := make([]string, 0, len(r.Incidents))
+	for _, inc := range r.Incidents {
+		ids = append(ids, inc.ID.String()+":"+inc.Status)
+	}
+	sort.Strings(ids)
+
+	return r.Status + "|" + strings.Join(ids, ",")
+}
+
+// TransitEvent описывает зоны, которые пользователь пересек между предыдущей и текущей проверкой,
+// не оказавшись внутри них ни в одной из точек.
+type TransitEvent struct {
+	From      UserLocation             `json:"from"`
+	To        UserLocation             `json:"to"`
+	FromTime  time.Time                `json:"from_time" example:"2026-01-18T18:30:00Z"`
+	ToTime    time.Time                `json:"to_time" example:"2026-01-18T18:31:00Z"`
+	Incidents []*LocationCheckIncident `json:"incidents"`
+}
+
+// ReplayLocationRequest — запрос повторной проверки локации по зонам, действовавшим в момент At.
+// Если UserLocation не указан, используется последняя проверка пользователя UserID не позже At.
+type ReplayLocationRequest struct {
+	UserID       string        `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserLocation *UserLocation `json:"user_location,omitempty"`
+	At           time.Time     `json:"at" binding:"required" example:"2026-01-18T14:32:00Z"`
+}
+
+type ReplayLocationResponse struct {
+	AsOf         time.Time                `json:"as_of" example:"2026-01-18T14:32:00Z"`
+	UserID       string                   `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserLocation UserLocation             `json:"user_location"`
+	LocationTime *time.Time               `json:"location_time,omitempty" example:"2026-01-18T14:30:12Z"`
+	IsDanger     bool                     `json:"is_danger" example:"true"`
+	Status       string                   `json:"status" example:"inside"`
+	Incidents    []*LocationCheckIncident `json:"incidents,omitempty"`
 }
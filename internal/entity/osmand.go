@@ -0,0 +1,14 @@
+package entity
+
+// OsmAndRequest — параметры протокола OsmAnd (Traccar Client, OsmAnd и совместимые трекеры).
+// Параметры передаются в query-строке или form-данных; координаты могут прийти как lat/lon
+// или одной строкой location="lat,lon".
+type OsmAndRequest struct {
+	ID        string   `form:"id"`
+	DeviceID  string   `form:"deviceid"`
+	Lat       *float64 `form:"lat"`
+	Lon       *float64 `form:"lon"`
+	Location  string   `form:"location"`
+	Timestamp string   `form:"timestamp"`
+	Accuracy  *float64 `form:"accuracy"`
+}
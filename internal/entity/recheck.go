@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Геометрия, по которой пересчитываются проверки: текущие зоны или версии,
+// действовавшие в момент каждой проверки
+const (
+	RecheckGeometryCurrent    = "current"
+	RecheckGeometryHistorical = "historical"
+)
+
+// RecheckRequest — задание на пересчет сохраненных проверок локаций за период [From, To).
+// Без Apply строится только отчет, с Apply измененные проверки обновляются в той же транзакции.
+type RecheckRequest struct {
+	From       time.Time  `json:"from" binding:"required" example:"2026-01-18T00:00:00Z"`
+	To         time.Time  `json:"to" binding:"required" example:"2026-01-19T00:00:00Z"`
+	Geometry   string     `json:"geometry" binding:"omitempty,oneof=current historical" example:"current"`
+	IncidentID *uuid.UUID `json:"incident_id,omitempty"`
+	Apply      bool       `json:"apply" example:"false"`
+	Limit      int        `json:"limit" binding:"omitempty,min=1,max=100000" example:"10000"`
+}
+
+// RecheckChange — проверка локации, результат которой изменился после пересчета
+type RecheckChange struct {
+	CheckID       int64        `json:"check_id" example:"42"`
+	UserID        string       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserLocation  UserLocation `json:"user_location"`
+	CreatedAt     time.Time    `json:"created_at" example:"2026-01-18T18:30:00Z"`
+	OldIsDanger   bool         `json:"old_is_danger" example:"false"`
+	NewIsDanger   bool         `json:"new_is_danger" example:"true"`
+	OldIncidentID *uuid.UUID   `json:"old_incident_id,omitempty"`
+	NewIncidentID *uuid.UUID   `json:"new_incident_id,omitempty"`
+}
+
+type RecheckReport struct {
+	From         time.Time        `json:"from" example:"2026-01-18T00:00:00Z"`
+	To           time.Time        `json:"to" example:"2026-01-19T00:00:00Z"`
+	Geometry     string           `json:"geometry" example:"current"`
+	Applied      bool             `json:"applied" example:"false"`
+	Scanned      int              `json:"scanned" example:"1200"`
+	Skipped      int              `json:"skipped" example:"40"`
+	Changed      int              `json:"changed" example:"3"`
+	BecameDanger int              `json:"became_danger" example:"2"`
+	BecameSafe   int              `json:"became_safe" example:"1"`
+	Truncated    bool             `json:"truncated" example:"false"`
+	Changes      []*RecheckChange `json:"changes"`
+}
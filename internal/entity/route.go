@@ -0,0 +1,23 @@
+package entity
+
+import "github.com/google/uuid"
+
+type CheckRouteRequest struct {
+	Route GeoJsonLineString `json:"route" binding:"required"`
+}
+
+// RouteIncident описывает участок маршрута, проходящий через зону инцидента.
+// Точки входа и выхода отсутствуют, если маршрут только касается границы зоны.
+type RouteIncident struct {
+	ID          uuid.UUID     `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name        string        `json:"name" example:"Наводнение"`
+	Description string        `json:"description" example:"Описание наводнения"`
+	EntryPoint  *UserLocation `json:"entry_point,omitempty"`
+	ExitPoint   *UserLocation `json:"exit_point,omitempty"`
+	LengthM     float64       `json:"length_m" example:"1250.5"`
+}
+
+type CheckRouteResponse struct {
+	IsSafe    bool             `json:"is_safe" example:"false"`
+	Incidents []*RouteIncident `json:"incidents,omitempty"`
+}
@@ -2,10 +2,14 @@ package entity
 
 import "github.com/google/uuid"
 
+// IncidentStats — число пользователей в зоне за окно. UserCount учитывает и всех потомков
+// инцидента (пользователь считается один раз), OwnUserCount — только собственную зону.
 type IncidentStats struct {
-	IncidentID uuid.UUID `json:"incident_id"`
-	Name       string    `json:"name"`
-	UserCount  int       `json:"user_count"`
+	IncidentID   uuid.UUID  `json:"incident_id"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	Name         string     `json:"name"`
+	UserCount    int        `json:"user_count"`
+	OwnUserCount int        `json:"own_user_count"`
 }
 
 type StatsResponse struct {
@@ -0,0 +1,35 @@
+package entity
+
+// Типы сообщений потокового канала позиций
+const (
+	StreamMessageAuth     = "auth"
+	StreamMessagePosition = "position"
+
+	StreamEventAuthOK = "auth_ok"
+	StreamEventState  = "state"
+	StreamEventError  = "error"
+)
+
+// StreamMessage — сообщение клиента в потоковом канале позиций.
+// auth передает подпись устройства (или user_id, если подпись не обязательна),
+// position — очередную координату пользователя.
+type StreamMessage struct {
+	Type string `json:"type" example:"position"`
+
+	DeviceID  string `json:"device_id,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Nonce     string `json:"nonce,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+
+	UserLocation    *UserLocation `json:"user_location,omitempty"`
+	CheckTrajectory bool          `json:"check_trajectory,omitempty"`
+}
+
+// StreamEvent — сообщение сервера в потоковом канале позиций
+type StreamEvent struct {
+	Type   string                 `json:"type" example:"state"`
+	UserID string                 `json:"user_id,omitempty"`
+	State  *CheckLocationResponse `json:"state,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+const (
+	TrackFormatGPX     = "gpx"
+	TrackFormatGeoJSON = "geojson"
+)
+
+// TrackPoint — точка записанного трека. Time может отсутствовать, если формат трека не содержит времени.
+type TrackPoint struct {
+	Lat  float64    `json:"lat"`
+	Lon  float64    `json:"lon"`
+	Time *time.Time `json:"time,omitempty"`
+}
+
+type Track struct {
+	Name   string
+	Points []TrackPoint
+}
+
+// Exposure — интервал, в течение которого трек находился в зоне инцидента.
+// Transit=true означает, что зона пересечена между двумя соседними точками трека,
+// ни одна из которых не попала внутрь.
+type Exposure struct {
+	IncidentID   string       `json:"incident_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Version      int          `json:"version,omitempty" example:"2"`
+	Name         string       `json:"name" example:"Наводнение"`
+	Description  string       `json:"description,omitempty" example:"Описание наводнения"`
+	Transit      bool         `json:"transit" example:"false"`
+	EnteredAt    *time.Time   `json:"entered_at,omitempty" example:"2026-01-18T18:30:00Z"`
+	ExitedAt     *time.Time   `json:"exited_at,omitempty" example:"2026-01-18T18:45:00Z"`
+	DurationSec  float64      `json:"duration_sec" example:"900"`
+	EntryPoint   UserLocation `json:"entry_point"`
+	ExitPoint    UserLocation `json:"exit_point"`
+	PointsInside int          `json:"points_inside" example:"12"`
+}
+
+type ExposureReport struct {
+	TrackName  string      `json:"track_name,omitempty" example:"Morning walk"`
+	PointCount int         `json:"point_count" example:"340"`
+	StartedAt  *time.Time  `json:"started_at,omitempty" example:"2026-01-18T18:00:00Z"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty" example:"2026-01-18T19:00:00Z"`
+	IsExposed  bool        `json:"is_exposed" example:"true"`
+	Exposures  []*Exposure `json:"exposures"`
+}
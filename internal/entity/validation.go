@@ -0,0 +1,40 @@
+package entity
+
+// Коды проблем топологической проверки полигона
+const (
+	PolygonIssueInvalidType       = "invalid_type"
+	PolygonIssueInvalidCoordinate = "invalid_coordinate"
+	PolygonIssueTooFewPoints      = "too_few_points"
+	PolygonIssueNotClosed         = "not_closed"
+	PolygonIssueTooManyVertices   = "too_many_vertices"
+	PolygonIssueDuplicateVertex   = "duplicate_vertex"
+	PolygonIssueZeroAreaRing      = "zero_area_ring"
+	PolygonIssueSelfIntersection  = "self_intersection"
+	PolygonIssueRingIntersection  = "ring_intersection"
+	PolygonIssueHoleOutsideShell  = "hole_outside_shell"
+)
+
+// PolygonIssue — проблема полигона с местом, где она найдена: Ring — индекс кольца (0 — внешнее),
+// Vertex — индекс вершины или начала отрезка в кольце, Point — координаты [lon, lat].
+// Для проблем, не привязанных к кольцу или вершине, соответствующий индекс равен -1.
+type PolygonIssue struct {
+	Code    string    `json:"code" example:"self_intersection"`
+	Message string    `json:"message" example:"segments 0 and 2 of ring 0 intersect"`
+	Ring    int       `json:"ring" example:"0"`
+	Vertex  int       `json:"vertex" example:"2"`
+	Point   []float64 `json:"point,omitempty" example:"37.615,55.755"`
+}
+
+// ValidateAreaRequest — проверка полигона с топологией. С repair=true дополнительно
+// возвращается полигон, исправленный PostGIS ST_MakeValid.
+type ValidateAreaRequest struct {
+	Area   GeoJsonPolygon `json:"area" binding:"required"`
+	Repair bool           `json:"repair" example:"true"`
+}
+
+type ValidateAreaResponse struct {
+	Valid       bool            `json:"valid" example:"false"`
+	Issues      []PolygonIssue  `json:"issues"`
+	Repaired    *GeometryResult `json:"repaired,omitempty"`
+	RepairError string          `json:"repair_error,omitempty" example:"результат операции не является одним полигоном: получен MULTIPOLYGON"`
+}
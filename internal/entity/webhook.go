@@ -13,11 +13,16 @@ type WebhookTask struct {
 	IncidentID uuid.UUID `db:"incident_id"`
 	CreatedAt  time.Time `db:"created_at"`
 	RetryCount int       `db:"retry_count"`
+	// Сообщение о ходе инцидента, если вебхук отправлен по нему, а не по проверке локации
+	UpdateID *uuid.UUID `db:"update_id"`
+	Message  string     `db:"message"`
 }
 
 type WebhookPayload struct {
-	Name       string    `json:"name"`
-	IncidentID uuid.UUID `json:"incident_id"`
-	UserID     string    `json:"user_id"`
-	Timestamp  time.Time `json:"timestamp"`
+	Name       string     `json:"name"`
+	IncidentID uuid.UUID  `json:"incident_id"`
+	UserID     string     `json:"user_id"`
+	Timestamp  time.Time  `json:"timestamp"`
+	UpdateID   *uuid.UUID `json:"update_id,omitempty"`
+	Message    string     `json:"message,omitempty"`
 }
@@ -0,0 +1,176 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	channel = "events"
+
+	// Размер буфера подписчика. Если подписчик не успевает читать, события для него отбрасываются,
+	// чтобы медленный клиент не задерживал остальных.
+	subscriberBuffer = 64
+
+	resubscribeDelay = time.Second
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event *entity.Event) error
+}
+
+// Message — событие и его исходное JSON-представление, которое отправляется клиентам без повторной сериализации
+type Message struct {
+	Event *entity.Event
+	Raw   []byte
+}
+
+// Filter отбирает события по инциденту и типу. Пустые поля не ограничивают выборку.
+type Filter struct {
+	IncidentID *uuid.UUID
+	Types      []string
+}
+
+func (f Filter) Match(event *entity.Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+
+	if f.IncidentID != nil && (event.IncidentID == nil || *event.IncidentID != *f.IncidentID) {
+		return false
+	}
+
+	return true
+}
+
+type Subscription struct {
+	C      <-chan *Message
+	ch     chan *Message
+	filter Filter
+}
+
+// Bus публикует доменные события в Redis pub/sub, чтобы их получали клиенты всех реплик.
+// Каждая реплика держит одну подписку на Redis и раздает события локальным подписчикам.
+type Bus struct {
+	client *redis.Client
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func NewBus(client *redis.Client) *Bus {
+	return &Bus{client: client, subs: make(map[*Subscription]struct{})}
+}
+
+func (b *Bus) Publish(ctx context.Context, event *entity.Event) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("не удалось сериализовать событие", "error", err)
+		return fmt.Errorf("не удалось сериализовать событие: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, channel, data).Err(); err != nil {
+		slog.Error("не удалось опубликовать событие", "type", event.Type, "error", err)
+		return fmt.Errorf("не удалось опубликовать событие: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe регистрирует локального подписчика. После использования подписку нужно закрыть через Unsubscribe.
+func (b *Bus) Subscribe(filter Filter) *Subscription {
+	ch := make(chan *Message, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, filter: filter}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subs, sub)
+	b.mu.Unlock()
+}
+
+// Run слушает канал событий в Redis и раздает их подписчикам до отмены ctx.
+// При обрыве соединения подписка восстанавливается; события за время обрыва теряются.
+func (b *Bus) Run(ctx context.Context) {
+	for {
+		b.listen(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+func (b *Bus) listen(ctx context.Context) {
+	pubsub := b.client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() == nil {
+			slog.Error("не удалось подписаться на события", "error", err)
+		}
+		return
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			b.dispatch([]byte(msg.Payload))
+		}
+	}
+}
+
+func (b *Bus) dispatch(raw []byte) {
+	var event entity.Event
+	if err := json.Unmarshal(raw, &event); err != nil {
+		slog.Error("не удалось десериализовать событие", "error", err)
+		return
+	}
+
+	message := &Message{Event: &event, Raw: raw}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Match(&event) {
+			continue
+		}
+
+		select {
+		case sub.ch <- message:
+		default:
+			slog.Warn("подписчик не успевает читать события, событие отброшено", "type", event.Type)
+		}
+	}
+}
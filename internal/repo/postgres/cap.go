@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// ErrCapMessageNotFound возвращается, если сообщение CAP с таким отправителем и идентификатором не принималось
+var ErrCapMessageNotFound = errors.New("сообщение CAP не найдено")
+
+// capClaimTimeout — через сколько незавершенную заявку на сообщение может перехватить повторная
+// доставка: обработка, прерванная падением процесса, не блокирует сообщение навсегда
+const capClaimTimeout = 5 * time.Minute
+
+type CapRepo interface {
+	ClaimMessage(ctx context.Context, m *entity.CapMessage) (bool, error)
+	SaveMessage(ctx context.Context, m *entity.CapMessage) error
+	ReleaseMessage(ctx context.Context, sender, identifier string) error
+	FindMessage(ctx context.Context, sender, identifier string) (*entity.CapMessage, error)
+}
+
+type CapRepoImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewCapRepo(pool *pgxpool.Pool) CapRepo {
+	return &CapRepoImpl{pool: pool}
+}
+
+// ClaimMessage занимает (sender, identifier) до обработки сообщения: запись без инцидента вставляется
+// уникальной вставкой, и из одновременных доставок продолжает только одна. Возвращает false, если
+// сообщение уже принято или его обрабатывает другой запрос. Заявку старше capClaimTimeout можно
+// перехватить.
+func (r *CapRepoImpl) ClaimMessage(ctx context.Context, m *entity.CapMessage) (bool, error) {
+	query := `
+		INSERT INTO cap_messages (sender, identifier, sent, msg_type)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sender, identifier) DO UPDATE
+		SET
+			sent = EXCLUDED.sent,
+			msg_type = EXCLUDED.msg_type,
+			received_at = NOW()
+		WHERE cap_messages.incident_id IS NULL
+		AND cap_messages.received_at < NOW() - make_interval(secs => $5)
+		RETURNING received_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, m.Sender, m.Identifier, m.Sent, m.MsgType, capClaimTimeout.Seconds()).Scan(&m.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ошибка заявки на сообщение CAP %s: %w", m.Identifier, err)
+	}
+
+	return true, nil
+}
+
+// SaveMessage завершает заявку ClaimMessage: связывает сообщение с инцидентом и сохраняет его атрибуты
+func (r *CapRepoImpl) SaveMessage(ctx context.Context, m *entity.CapMessage) error {
+	query := `
+		UPDATE cap_messages
+		SET
+			incident_id = $3,
+			event = NULLIF($4, ''),
+			urgency = NULLIF($5, ''),
+			severity = NULLIF($6, ''),
+			certainty = NULLIF($7, ''),
+			onset = $8,
+			expires = $9
+		WHERE sender = $1 AND identifier = $2 AND incident_id IS NULL
+		RETURNING received_at
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		m.Sender,
+		m.Identifier,
+		m.IncidentID,
+		m.Event,
+		m.Urgency,
+		m.Severity,
+		m.Certainty,
+		m.Onset,
+		m.Expires,
+	).Scan(&m.ReceivedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("ошибка сохранения сообщения CAP %s: заявка на сообщение не найдена", m.Identifier)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения сообщения CAP %s: %w", m.Identifier, err)
+	}
+
+	return nil
+}
+
+// ReleaseMessage снимает незавершенную заявку, чтобы сообщение можно было доставить повторно
+func (r *CapRepoImpl) ReleaseMessage(ctx context.Context, sender, identifier string) error {
+	query := `
+		DELETE FROM cap_messages
+		WHERE sender = $1 AND identifier = $2 AND incident_id IS NULL
+	`
+
+	if _, err := r.pool.Exec(ctx, query, sender, identifier); err != nil {
+		return fmt.Errorf("ошибка снятия заявки на сообщение CAP %s: %w", identifier, err)
+	}
+
+	return nil
+}
+
+// FindMessage возвращает принятое сообщение; незавершенные заявки не учитываются
+func (r *CapRepoImpl) FindMessage(ctx context.Context, sender, identifier string) (*entity.CapMessage, error) {
+	query := `
+		SELECT
+			sender,
+			identifier,
+			sent,
+			msg_type,
+			incident_id,
+			COALESCE(event, ''),
+			COALESCE(urgency, ''),
+			COALESCE(severity, ''),
+			COALESCE(certainty, ''),
+			onset,
+			expires,
+			received_at
+		FROM cap_messages
+		WHERE sender = $1 AND identifier = $2 AND incident_id IS NOT NULL
+	`
+
+	var m entity.CapMessage
+	err := r.pool.QueryRow(ctx, query, sender, identifier).Scan(
+		&m.Sender,
+		&m.Identifier,
+		&m.Sent,
+		&m.MsgType,
+		&m.IncidentID,
+		&m.Event,
+		&m.Urgency,
+		&m.Severity,
+		&m.Certainty,
+		&m.Onset,
+		&m.Expires,
+		&m.ReceivedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCapMessageNotFound
+		}
+		return nil, fmt.Errorf("ошибка поиска сообщения CAP %s: %w", identifier, err)
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+var (
+	ErrDeviceNotFound       = errors.New("устройство не найдено")
+	ErrDeviceAlreadyRevoked = errors.New("устройство уже отозвано")
+)
+
+type DeviceRepo interface {
+	Create(ctx context.Context, d *entity.Device) error
+	FindByID(ctx context.Context, id uuid.UUID) (*entity.Device, error)
+	FindByTrackerToken(ctx context.Context, tokenHash string) (*entity.Device, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*entity.Device, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
+
+type DeviceRepoImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewDeviceRepo(pool *pgxpool.Pool) DeviceRepo {
+	return &DeviceRepoImpl{pool: pool}
+}
+
+func (r *DeviceRepoImpl) Create(ctx context.Context, d *entity.Device) error {
+	query := `
+		INSERT INTO devices (user_id, name, secret, tracker_token_hash, is_active)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id, created_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, d.UserID, d.Name, d.Secret, d.TrackerTokenHash, d.IsActive).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка регистрации устройства: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DeviceRepoImpl) FindByID(ctx context.Context, id uuid.UUID) (*entity.Device, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			COALESCE(name, ''),
+			secret,
+			is_active,
+			created_at,
+			revoked_at
+		FROM devices
+		WHERE id = $1
+	`
+
+	var d entity.Device
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Name,
+		&d.Secret,
+		&d.IsActive,
+		&d.CreatedAt,
+		&d.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("ошибка поиска устройства по id %s: %w", id, err)
+	}
+
+	return &d, nil
+}
+
+// FindByTrackerToken ищет устройство по хешу токена трекера
+func (r *DeviceRepoImpl) FindByTrackerToken(ctx context.Context, tokenHash string) (*entity.Device, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			COALESCE(name, ''),
+			secret,
+			is_active,
+			created_at,
+			revoked_at
+		FROM devices
+		WHERE tracker_token_hash = $1
+	`
+
+	var d entity.Device
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.Name,
+		&d.Secret,
+		&d.IsActive,
+		&d.CreatedAt,
+		&d.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("ошибка поиска устройства по токену трекера: %w", err)
+	}
+	d.TrackerTokenHash = tokenHash
+
+	return &d, nil
+}
+
+func (r *DeviceRepoImpl) FindAll(ctx context.Context, limit, offset int) ([]*entity.Device, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			COALESCE(name, ''),
+			is_active,
+			created_at,
+			revoked_at
+		FROM devices
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска устройств: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*entity.Device
+	for rows.Next() {
+		var d entity.Device
+		if err := rows.Scan(
+			&d.ID,
+			&d.UserID,
+			&d.Name,
+			&d.IsActive,
+			&d.CreatedAt,
+			&d.RevokedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования устройства: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return devices, nil
+}
+
+func (r *DeviceRepoImpl) Revoke(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE devices
+		SET
+			is_active = false,
+			revoked_at = NOW()
+		WHERE id = $1 AND is_active = true
+	`
+
+	result, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("ошибка отзыва устройства %s: %w", id, err)
+	}
+
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	// Устройство не изменилось: отличаем отсутствующее от уже отозванного
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM devices WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return fmt.Errorf("ошибка поиска устройства %s: %w", id, err)
+	}
+	if !exists {
+		return ErrDeviceNotFound
+	}
+
+	return ErrDeviceAlreadyRevoked
+}
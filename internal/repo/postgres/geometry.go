@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+type GeometryRepo interface {
+	Buffer(ctx context.Context, area entity.GeoJsonPolygon, distanceM float64) (*entity.GeometryResult, error)
+	Union(ctx context.Context, area, other entity.GeoJsonPolygon) (*entity.GeometryResult, error)
+	Difference(ctx context.Context, area, other entity.GeoJsonPolygon) (*entity.GeometryResult, error)
+	Simplify(ctx context.Context, area entity.GeoJsonPolygon, toleranceM float64) (*entity.GeometryResult, error)
+	MakeValid(ctx context.Context, area entity.GeoJsonPolygon) (*entity.GeometryResult, error)
+}
+
+var (
+	ErrGeometryEmpty      = errors.New("результат операции пуст")
+	ErrGeometryNotPolygon = errors.New("результат операции не является одним полигоном")
+)
+
+type GeometryRepoImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewGeometryRepo(pool *pgxpool.Pool) GeometryRepo {
+	return &GeometryRepoImpl{pool: pool}
+}
+
+// geometryQuery применяет выражение над src.g (исходный полигон, SRID 4326) и возвращает тип,
+// GeoJSON и площади результата и исходного полигона. Коллекция из одного полигона
+// разворачивается, чтобы объединение касающихся зон оставалось Polygon.
+const geometryQuery = `
+	WITH src AS (
+		SELECT ST_SetSRID(ST_GeomFromGeoJSON($1), 4326) AS g
+	), raw AS (
+		SELECT %s AS g FROM src
+	), res AS (
+		SELECT CASE WHEN ST_NumGeometries(g) = 1 THEN ST_GeometryN(g, 1) ELSE g END AS g FROM raw
+	)
+	SELECT
+		GeometryType(res.g),
+		ST_IsEmpty(res.g),
+		ST_AsGeoJSON(res.g),
+		ST_Area(res.g::geography),
+		ST_Area(src.g::geography)
+	FROM src, res
+`
+
+// Buffer расширяет полигон на distanceM метров по геодезическому расстоянию; отрицательное значение сужает его
+func (r *GeometryRepoImpl) Buffer(ctx context.Context, area entity.GeoJsonPolygon, distanceM float64) (*entity.GeometryResult, error) {
+	return r.apply(ctx, "ST_Buffer(src.g::geography, $2::float8)::geometry", area, distanceM)
+}
+
+func (r *GeometryRepoImpl) Union(ctx context.Context, area, other entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	otherJSON, err := json.Marshal(other)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	return r.apply(ctx, "ST_Union(src.g, ST_SetSRID(ST_GeomFromGeoJSON($2), 4326))", area, string(otherJSON))
+}
+
+func (r *GeometryRepoImpl) Difference(ctx context.Context, area, other entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	otherJSON, err := json.Marshal(other)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	return r.apply(ctx, "ST_Difference(src.g, ST_SetSRID(ST_GeomFromGeoJSON($2), 4326))", area, string(otherJSON))
+}
+
+// Simplify упрощает границу в проекции Web Mercator. Допуск в метрах делится на косинус широты
+// центроида, чтобы компенсировать растяжение проекции.
+func (r *GeometryRepoImpl) Simplify(ctx context.Context, area entity.GeoJsonPolygon, toleranceM float64) (*entity.GeometryResult, error) {
+	expr := `ST_Transform(ST_SimplifyPreserveTopology(
+		ST_Transform(src.g, 3857),
+		$2::float8 / cos(radians(ST_Y(ST_Centroid(src.g))))
+	), 4326)`
+
+	return r.apply(ctx, expr, area, toleranceM)
+}
+
+// MakeValid исправляет топологию полигона: убирает повторяющиеся вершины, применяет ST_MakeValid
+// и оставляет только полигональные части (вырожденные кольца превращаются в линии и отбрасываются)
+func (r *GeometryRepoImpl) MakeValid(ctx context.Context, area entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	return r.apply(ctx, "ST_CollectionExtract(ST_MakeValid(ST_RemoveRepeatedPoints(src.g)), 3)", area)
+}
+
+func (r *GeometryRepoImpl) apply(ctx context.Context, expr string, area entity.GeoJsonPolygon, args ...any) (*entity.GeometryResult, error) {
+	areaJSON, err := json.Marshal(area)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	var (
+		geomType   string
+		empty      bool
+		resultJSON string
+		result     entity.GeometryResult
+	)
+
+	err = r.pool.QueryRow(ctx, fmt.Sprintf(geometryQuery, expr), append([]any{string(areaJSON)}, args...)...).Scan(
+		&geomType,
+		&empty,
+		&resultJSON,
+		&result.AreaM2,
+		&result.PreviousAreaM2,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения операции над полигоном: %w", err)
+	}
+
+	if empty {
+		return nil, ErrGeometryEmpty
+	}
+
+	if geomType != "POLYGON" {
+		return nil, fmt.Errorf("%w: получен %s", ErrGeometryNotPolygon, geomType)
+	}
+
+	if err := json.Unmarshal([]byte(resultJSON), &result.Area); err != nil {
+		return nil, fmt.Errorf("ошибка анмаршалинга area: %w", err)
+	}
+
+	return &result, nil
+}
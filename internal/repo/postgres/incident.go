@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -13,12 +14,36 @@ import (
 	"github.com/levinOo/geo-incedent-service/internal/entity"
 )
 
+// ErrStatusConflict возвращается, если статус инцидента изменился между чтением и переходом
+var ErrStatusConflict = errors.New("статус инцидента изменился")
+
+// ErrIncidentNotFound возвращается, если инцидента с таким id нет
+var ErrIncidentNotFound = errors.New("инцидент не найден")
+
+// ErrIncidentVersionNotFound возвращается, если у инцидента нет версии с таким номером
+var ErrIncidentVersionNotFound = errors.New("версия инцидента не найдена")
+
 type IncidentRepo interface {
 	Create(ctx context.Context, i *entity.Incident) error
+	UpsertMany(ctx context.Context, incidents []*entity.Incident) ([]bool, error)
+	FindByExternalIDs(ctx context.Context, externalIDs []string) (map[string]*entity.Incident, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.Incident, error)
 	FindAll(ctx context.Context, limit, offset int) ([]entity.Incident, error)
-	Update(ctx context.Context, i *entity.Incident) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	StreamAll(ctx context.Context, filter entity.IncidentExportFilter, fn func(*entity.Incident) error) error
+	Update(ctx context.Context, i *entity.Incident, fromStatus string) error
+	Delete(ctx context.Context, id uuid.UUID, changedBy, reason string) ([]uuid.UUID, error)
+	UpdateStatus(ctx context.Context, current *entity.Incident, toStatus, operation, changedBy, reason string) ([]uuid.UUID, error)
+	FindDescendants(ctx context.Context, id uuid.UUID) ([]entity.Incident, error)
+	FindVersions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entity.IncidentVersion, error)
+	CountVersions(ctx context.Context, id uuid.UUID) (int, error)
+	FindVersion(ctx context.Context, id uuid.UUID, version int) (*entity.IncidentVersion, error)
+	DiffGeometry(ctx context.Context, id uuid.UUID, fromVersion, toVersion int) (*entity.GeometryDiff, error)
+	FindAllAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]entity.Incident, error)
+	FindByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*entity.Incident, error)
+	FindVersionsInRange(ctx context.Context, from, to time.Time) ([]*entity.IncidentVersion, error)
+	FindOverlaps(ctx context.Context, area entity.GeoJsonPolygon, excludeID *uuid.UUID) ([]*entity.IncidentOverlap, error)
+	FindPublished(ctx context.Context) ([]*entity.Incident, error)
+	LastModified(ctx context.Context) (time.Time, error)
 	GetStats(ctx context.Context, minutes int) ([]*entity.IncidentStats, error)
 	Ping(ctx context.Context) error
 }
@@ -38,18 +63,21 @@ func (r *IncidentRepoImpl) Create(ctx context.Context, i *entity.Incident) error
 	}
 
 	query := `
-		INSERT INTO incidents (name, description, area, is_active)
-		VALUES ($1, $2, ST_GeomFromGeoJSON($3)::geography, $4)
+		INSERT INTO incidents (name, description, area, is_active, status, severity, parent_id)
+		VALUES ($1, $2, ST_GeomFromGeoJSON($3)::geography, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
 	`
 
-	_, err = r.pool.Exec(ctx, query,
-		i.Name, i.Description, string(areaJSON), i.IsActive,
-	)
-	if err != nil {
-		return fmt.Errorf("ошибка создания инцидента: %w", err)
-	}
+	return withTx(ctx, r.pool, func(q querier) error {
+		err := q.QueryRow(ctx, query,
+			i.Name, i.Description, string(areaJSON), i.IsActive, i.Status, i.Severity, i.ParentID,
+		).Scan(&i.ID, &i.CreatedAt, &i.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("ошибка создания инцидента: %w", err)
+		}
 
-	return nil
+		return insertVersion(ctx, q, i.ID, entity.IncidentOperationCreate, i.ChangedBy, i.ChangeReason)
+	})
 }
 
 func (r *IncidentRepoImpl) FindByID(ctx context.Context, id uuid.UUID) (*entity.Incident, error) {
@@ -62,6 +90,11 @@ func (r *IncidentRepoImpl) FindByID(ctx context.Context, id uuid.UUID) (*entity.
 			description,
 			ST_AsGeoJSON(area) AS area_json,
 			is_active,
+			status,
+			severity,
+			COALESCE(submitted_by, ''),
+			parent_id,
+			COALESCE(external_id, ''),
 			created_at,
 			updated_at
 		FROM incidents
@@ -75,13 +108,18 @@ func (r *IncidentRepoImpl) FindByID(ctx context.Context, id uuid.UUID) (*entity.
 		&i.Description,
 		&areaJSONStr,
 		&i.IsActive,
+		&i.Status,
+		&i.Severity,
+		&i.SubmittedBy,
+		&i.ParentID,
+		&i.ExternalID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("инцидент не найден")
+			return nil, ErrIncidentNotFound
 		}
 		return nil, fmt.Errorf("ошибка поиска инцидента по id %s: %w", id, err)
 	}
@@ -101,6 +139,11 @@ func (r *IncidentRepoImpl) FindAll(ctx context.Context, limit, offset int) ([]en
 			description,
 			ST_AsGeoJSON(area) AS area_json,
 			is_active,
+			status,
+			severity,
+			COALESCE(submitted_by, ''),
+			parent_id,
+			COALESCE(external_id, ''),
 			created_at,
 			updated_at
 		FROM incidents
@@ -126,6 +169,11 @@ func (r *IncidentRepoImpl) FindAll(ctx context.Context, limit, offset int) ([]en
 			&i.Description,
 			&areaJSONStr,
 			&i.IsActive,
+			&i.Status,
+			&i.Severity,
+			&i.SubmittedBy,
+			&i.ParentID,
+			&i.ExternalID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		)
@@ -147,7 +195,10 @@ func (r *IncidentRepoImpl) FindAll(ctx context.Context, limit, offset int) ([]en
 	return incidents, nil
 }
 
-func (r *IncidentRepoImpl) Update(ctx context.Context, i *entity.Incident) error {
+// Update сохраняет поля инцидента и его статус i.Status, если текущий статус по-прежнему
+// fromStatus; иначе возвращает ErrStatusConflict. Для инцидента на согласовании автором
+// отправки становится автор изменения i.ChangedBy.
+func (r *IncidentRepoImpl) Update(ctx context.Context, i *entity.Incident, fromStatus string) error {
 	areaJSON, err := json.Marshal(i.Area)
 	if err != nil {
 		return fmt.Errorf("ошибка маршалинга area: %w", err)
@@ -159,61 +210,751 @@ func (r *IncidentRepoImpl) Update(ctx context.Context, i *entity.Incident) error
 			name = $1,
 			description = $2,
 			area = ST_GeomFromGeoJSON($3)::geography, 
+			severity = $5,
+			parent_id = $6,
+			status = $7,
+			is_active = ($7 = 'published'),
+			submitted_by = CASE
+				WHEN $7 = 'pending_review' THEN NULLIF($9, '')
+				ELSE submitted_by
+			END,
 			updated_at = NOW()
 		WHERE id = $4
+		AND status = $8
 	`
 
-	_, err = r.pool.Exec(ctx, query,
-		i.Name,
-		i.Description,
-		string(areaJSON),
-		i.ID,
-	)
+	return withTx(ctx, r.pool, func(q querier) error {
+		result, err := q.Exec(ctx, query,
+			i.Name,
+			i.Description,
+			string(areaJSON),
+			i.ID,
+			i.Severity,
+			i.ParentID,
+			i.Status,
+			fromStatus,
+			i.ChangedBy,
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка обновления инцидента: %w", err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return ErrStatusConflict
+		}
 
+		return insertVersion(ctx, q, i.ID, entity.IncidentOperationUpdate, i.ChangedBy, i.ChangeReason)
+	})
+}
+
+// Delete деактивирует инцидент вместе со всеми его потомками и возвращает идентификаторы
+// деактивированных потомков. Уже завершенные потомки не меняются.
+func (r *IncidentRepoImpl) Delete(ctx context.Context, id uuid.UUID, changedBy, reason string) ([]uuid.UUID, error) {
+	query := `
+		UPDATE incidents
+		SET
+			is_active = false,
+			status = 'resolved',
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	var children []uuid.UUID
+	err := withTx(ctx, r.pool, func(q querier) error {
+		result, err := q.Exec(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("ошибка деактивации инцидента %s: %w", id, err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return ErrIncidentNotFound
+		}
+
+		if err := insertVersion(ctx, q, id, entity.IncidentOperationDelete, changedBy, reason); err != nil {
+			return err
+		}
+
+		children, err = resolveDescendants(ctx, q, id, entity.IncidentOperationDelete, changedBy, reason)
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("инцидент не найден для обновления")
+		return nil, err
+	}
+
+	slog.Info("инцидент деактивирован", slog.String("id", id.String()), slog.Int("children", len(children)))
+	return children, nil
+}
+
+// UpdateStatus переводит инцидент из статуса current.Status в toStatus и записывает версию.
+// Переход выполняется, только если статус, серьезность и автор отправки на согласование не изменились
+// с момента загрузки current: по ним сервис проверял правило двух лиц. Иначе возвращается ErrStatusConflict.
+// is_active поддерживается равным признаку публикации, автор отправки на согласование
+// сохраняется для правила двух лиц. При завершении инцидента завершаются и все его потомки,
+// их идентификаторы возвращаются.
+func (r *IncidentRepoImpl) UpdateStatus(ctx context.Context, current *entity.Incident, toStatus, operation, changedBy, reason string) ([]uuid.UUID, error) {
+	query := `
+		UPDATE incidents
+		SET
+			status = $3,
+			is_active = ($3 = 'published'),
+			submitted_by = CASE
+				WHEN $3 = 'pending_review' THEN NULLIF($4, '')
+				WHEN $3 = 'draft' THEN NULL
+				ELSE submitted_by
+			END,
+			updated_at = NOW()
+		WHERE id = $1
+		AND status = $2
+		AND severity = $5
+		AND submitted_by IS NOT DISTINCT FROM NULLIF($6, '')
+	`
+
+	id := current.ID
+	var children []uuid.UUID
+	err := withTx(ctx, r.pool, func(q querier) error {
+		result, err := q.Exec(ctx, query, id, current.Status, toStatus, changedBy, current.Severity, current.SubmittedBy)
+		if err != nil {
+			return fmt.Errorf("ошибка смены статуса инцидента %s: %w", id, err)
+		}
+
+		if result.RowsAffected() == 0 {
+			return ErrStatusConflict
+		}
+
+		if err := insertVersion(ctx, q, id, operation, changedBy, reason); err != nil {
+			return err
+		}
+
+		if toStatus != entity.IncidentStatusResolved {
+			return nil
 		}
-		return fmt.Errorf("ошибка обновления инцидента: %w", err)
+
+		children, err = resolveDescendants(ctx, q, id, operation, changedBy, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return children, nil
 }
 
-func (r *IncidentRepoImpl) Delete(ctx context.Context, id uuid.UUID) error {
+// resolveDescendants завершает еще не завершенных потомков инцидента и записывает каждому
+// версию. Обход идет и через уже завершенных потомков, чтобы не оставить активными их детей.
+func resolveDescendants(ctx context.Context, q querier, id uuid.UUID, operation, changedBy, reason string) ([]uuid.UUID, error) {
 	query := `
+		WITH RECURSIVE tree AS (
+			SELECT c.id FROM incidents c WHERE c.parent_id = $1
+			UNION
+			SELECT c.id FROM incidents c JOIN tree t ON c.parent_id = t.id
+		)
 		UPDATE incidents
 		SET
 			is_active = false,
+			status = 'resolved',
 			updated_at = NOW()
-		WHERE id = $1
+		WHERE id IN (SELECT id FROM tree)
+		AND status <> 'resolved'
+		RETURNING id
 	`
 
-	result, err := r.pool.Exec(ctx, query, id)
+	rows, err := q.Query(ctx, query, id)
 	if err != nil {
-		return fmt.Errorf("ошибка деактивации инцидента %s: %w", id, err)
+		return nil, fmt.Errorf("ошибка деактивации дочерних инцидентов %s: %w", id, err)
+	}
+
+	var children []uuid.UUID
+	for rows.Next() {
+		var childID uuid.UUID
+		if err := rows.Scan(&childID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("ошибка сканирования дочернего инцидента: %w", err)
+		}
+		children = append(children, childID)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	// Следующие запросы в той же транзакции возможны только после закрытия rows
+	for _, childID := range children {
+		if err := insertVersion(ctx, q, childID, operation, changedBy, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	return children, nil
+}
+
+// insertVersion записывает текущее состояние инцидента новой версией и закрывает период
+// действия предыдущей. Вызывается в транзакции изменения, после того как строка инцидента
+// заблокирована, поэтому номера версий не конкурируют.
+func insertVersion(ctx context.Context, q querier, id uuid.UUID, operation, changedBy, reason string) error {
+	closeQuery := `
+		UPDATE incident_versions
+		SET valid_to = NOW()
+		WHERE incident_id = $1 AND valid_to IS NULL
+	`
+
+	if _, err := q.Exec(ctx, closeQuery, id); err != nil {
+		return fmt.Errorf("ошибка закрытия предыдущей версии инцидента: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return errors.New("инцидент не найден")
+	insertQuery := `
+		INSERT INTO incident_versions (
+			incident_id, version, operation, name, description, area, is_active,
+			status, severity, parent_id, changed_by, change_reason, valid_from
+		)
+		SELECT
+			i.id,
+			COALESCE((SELECT MAX(v.version) FROM incident_versions v WHERE v.incident_id = i.id), 0) + 1,
+			$2,
+			i.name,
+			i.description,
+			i.area,
+			COALESCE(i.is_active, TRUE),
+			i.status,
+			i.severity,
+			i.parent_id,
+			NULLIF($3, ''),
+			NULLIF($4, ''),
+			NOW()
+		FROM incidents i
+		WHERE i.id = $1
+	`
+
+	if _, err := q.Exec(ctx, insertQuery, id, operation, changedBy, reason); err != nil {
+		return fmt.Errorf("ошибка записи версии инцидента: %w", err)
 	}
 
-	slog.Info("инцидент деактивирован", slog.String("id", id.String()))
 	return nil
 }
 
+func (r *IncidentRepoImpl) FindVersions(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entity.IncidentVersion, error) {
+	query := `
+		SELECT
+			version,
+			operation,
+			name,
+			COALESCE(description, ''),
+			ST_AsGeoJSON(area) AS area_json,
+			is_active,
+			status,
+			severity,
+			COALESCE(changed_by, ''),
+			COALESCE(change_reason, ''),
+			valid_from,
+			valid_to
+		FROM incident_versions
+		WHERE incident_id = $1
+		ORDER BY version DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска версий инцидента: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*entity.IncidentVersion
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+// CountVersions возвращает общее число версий инцидента
+func (r *IncidentRepoImpl) CountVersions(ctx context.Context, id uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM incident_versions WHERE incident_id = $1`
+
+	var total int
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&total); err != nil {
+		return 0, fmt.Errorf("ошибка подсчета версий инцидента: %w", err)
+	}
+
+	return total, nil
+}
+
+// FindVersion возвращает версию инцидента по номеру; при version = 0 — последнюю версию
+func (r *IncidentRepoImpl) FindVersion(ctx context.Context, id uuid.UUID, version int) (*entity.IncidentVersion, error) {
+	query := `
+		SELECT
+			version,
+			operation,
+			name,
+			COALESCE(description, ''),
+			ST_AsGeoJSON(area) AS area_json,
+			is_active,
+			status,
+			severity,
+			COALESCE(changed_by, ''),
+			COALESCE(change_reason, ''),
+			valid_from,
+			valid_to
+		FROM incident_versions
+		WHERE incident_id = $1 AND ($2 = 0 OR version = $2)
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	v, err := scanVersion(r.pool.QueryRow(ctx, query, id, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncidentVersionNotFound
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// DiffGeometry сравнивает зоны двух версий инцидента. Разность считается в геометрии
+// (градусах), площади — на сфероиде.
+func (r *IncidentRepoImpl) DiffGeometry(ctx context.Context, id uuid.UUID, fromVersion, toVersion int) (*entity.GeometryDiff, error) {
+	query := `
+		WITH pair AS (
+			SELECT a.area::geometry AS old_geom, b.area::geometry AS new_geom
+			FROM incident_versions a
+			JOIN incident_versions b ON b.incident_id = a.incident_id AND b.version = $3
+			WHERE a.incident_id = $1 AND a.version = $2
+		), diff AS (
+			SELECT
+				old_geom,
+				new_geom,
+				ST_Difference(new_geom, old_geom) AS added,
+				ST_Difference(old_geom, new_geom) AS removed
+			FROM pair
+		)
+		SELECT
+			NOT ST_Equals(old_geom, new_geom),
+			ST_Area(old_geom::geography),
+			ST_Area(new_geom::geography),
+			COALESCE(ST_Area(added::geography), 0),
+			COALESCE(ST_Area(removed::geography), 0),
+			CASE WHEN ST_IsEmpty(added) THEN NULL ELSE ST_AsGeoJSON(added) END,
+			CASE WHEN ST_IsEmpty(removed) THEN NULL ELSE ST_AsGeoJSON(removed) END
+		FROM diff
+	`
+
+	var (
+		d              entity.GeometryDiff
+		added, removed *string
+	)
+
+	err := r.pool.QueryRow(ctx, query, id, fromVersion, toVersion).Scan(
+		&d.Changed,
+		&d.OldAreaM2,
+		&d.NewAreaM2,
+		&d.AddedAreaM2,
+		&d.RemovedAreaM2,
+		&added,
+		&removed,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncidentVersionNotFound
+		}
+		return nil, fmt.Errorf("ошибка сравнения геометрии версий: %w", err)
+	}
+
+	if added != nil {
+		d.Added = []byte(*added)
+	}
+	if removed != nil {
+		d.Removed = []byte(*removed)
+	}
+
+	return &d, nil
+}
+
+// FindAllAsOf возвращает инциденты в том состоянии, в котором они были в момент asOf.
+// Инциденты, созданные позже asOf, не возвращаются; UpdatedAt — начало действия версии.
+func (r *IncidentRepoImpl) FindAllAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]entity.Incident, error) {
+	query := `
+		SELECT
+			v.incident_id,
+			v.name,
+			COALESCE(v.description, ''),
+			ST_AsGeoJSON(v.area) AS area_json,
+			v.is_active,
+			v.status,
+			v.severity,
+			'' AS submitted_by,
+			v.parent_id,
+			COALESCE(i.external_id, ''),
+			i.created_at,
+			v.valid_from,
+			v.version
+		FROM incident_versions v
+		JOIN incidents i ON i.id = v.incident_id
+		WHERE v.valid_from <= $1
+		AND (v.valid_to IS NULL OR v.valid_to > $1)
+		ORDER BY i.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, asOf, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска инцидентов на момент времени: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []entity.Incident
+	for rows.Next() {
+		i, err := scanIncidentAsOf(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, *i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return incidents, nil
+}
+
+func (r *IncidentRepoImpl) FindByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*entity.Incident, error) {
+	query := `
+		SELECT
+			v.incident_id,
+			v.name,
+			COALESCE(v.description, ''),
+			ST_AsGeoJSON(v.area) AS area_json,
+			v.is_active,
+			v.status,
+			v.severity,
+			'' AS submitted_by,
+			v.parent_id,
+			COALESCE(i.external_id, ''),
+			i.created_at,
+			v.valid_from,
+			v.version
+		FROM incident_versions v
+		JOIN incidents i ON i.id = v.incident_id
+		WHERE v.incident_id = $1
+		AND v.valid_from <= $2
+		AND (v.valid_to IS NULL OR v.valid_to > $2)
+	`
+
+	i, err := scanIncidentAsOf(r.pool.QueryRow(ctx, query, id, asOf))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w на указанный момент времени", ErrIncidentNotFound)
+		}
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// FindVersionsInRange возвращает версии всех инцидентов, действовавшие хотя бы часть
+// периода [from, to]
+func (r *IncidentRepoImpl) FindVersionsInRange(ctx context.Context, from, to time.Time) ([]*entity.IncidentVersion, error) {
+	query := `
+		SELECT
+			incident_id,
+			version,
+			operation,
+			name,
+			COALESCE(description, ''),
+			ST_AsGeoJSON(area) AS area_json,
+			is_active,
+			status,
+			severity,
+			COALESCE(changed_by, ''),
+			COALESCE(change_reason, ''),
+			valid_from,
+			valid_to
+		FROM incident_versions
+		WHERE valid_from <= $2
+		AND (valid_to IS NULL OR valid_to > $1)
+		ORDER BY incident_id, version
+	`
+
+	rows, err := r.pool.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска версий инцидентов за период: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []*entity.IncidentVersion
+	for rows.Next() {
+		var (
+			v           entity.IncidentVersion
+			areaJSONStr string
+		)
+
+		err := rows.Scan(
+			&v.IncidentID,
+			&v.Version,
+			&v.Operation,
+			&v.Name,
+			&v.Description,
+			&areaJSONStr,
+			&v.IsActive,
+			&v.Status,
+			&v.Severity,
+			&v.ChangedBy,
+			&v.ChangeReason,
+			&v.ValidFrom,
+			&v.ValidTo,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования версии инцидента: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(areaJSONStr), &v.Area); err != nil {
+			return nil, fmt.Errorf("ошибка размаршалинга area: %w", err)
+		}
+
+		versions = append(versions, &v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+// FindDescendants возвращает всех потомков инцидента в любом статусе, от старых к новым
+func (r *IncidentRepoImpl) FindDescendants(ctx context.Context, id uuid.UUID) ([]entity.Incident, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT c.id FROM incidents c WHERE c.parent_id = $1
+			UNION
+			SELECT c.id FROM incidents c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT
+			i.id,
+			i.name,
+			i.description,
+			ST_AsGeoJSON(i.area) AS area_json,
+			i.is_active,
+			i.status,
+			i.severity,
+			COALESCE(i.submitted_by, ''),
+			i.parent_id,
+			COALESCE(i.external_id, ''),
+			i.created_at,
+			i.updated_at
+		FROM incidents i
+		JOIN tree t ON t.id = i.id
+		ORDER BY i.created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска дочерних инцидентов: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []entity.Incident
+	for rows.Next() {
+		var (
+			i           entity.Incident
+			areaJSONStr string
+		)
+
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&areaJSONStr,
+			&i.IsActive,
+			&i.Status,
+			&i.Severity,
+			&i.SubmittedBy,
+			&i.ParentID,
+			&i.ExternalID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования инцидента: %w", err)
+		}
+
+		if err := json.Unmarshal([]byte(areaJSONStr), &i.Area); err != nil {
+			return nil, fmt.Errorf("ошибка размаршалинга area: %w", err)
+		}
+
+		incidents = append(incidents, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// FindOverlaps возвращает активные инциденты, пересекающиеся с полигоном, с площадью пересечения.
+// excludeID исключает из поиска сам инцидент при проверке его новой геометрии.
+func (r *IncidentRepoImpl) FindOverlaps(ctx context.Context, area entity.GeoJsonPolygon, excludeID *uuid.UUID) ([]*entity.IncidentOverlap, error) {
+	areaJSON, err := json.Marshal(area)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	query := `
+		WITH draft AS (
+			SELECT ST_GeomFromGeoJSON($1)::geography AS area
+		)
+		SELECT
+			i.id,
+			i.name,
+			ST_Area(ST_Intersection(i.area, d.area)) AS overlap_m2,
+			ST_Area(d.area) AS draft_m2,
+			ST_Area(i.area) AS incident_m2
+		FROM incidents i
+		CROSS JOIN draft d
+		WHERE i.is_active = true
+		AND ST_Intersects(i.area, d.area)
+		AND ($2::uuid IS NULL OR i.id <> $2)
+		ORDER BY overlap_m2 DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, string(areaJSON), excludeID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска пересечений с инцидентами: %w", err)
+	}
+	defer rows.Close()
+
+	var overlaps []*entity.IncidentOverlap
+	for rows.Next() {
+		var (
+			o                   entity.IncidentOverlap
+			draftM2, incidentM2 float64
+		)
+
+		if err := rows.Scan(&o.IncidentID, &o.Name, &o.OverlapAreaM2, &draftM2, &incidentM2); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования пересечения: %w", err)
+		}
+
+		if draftM2 > 0 {
+			o.OverlapPercent = o.OverlapAreaM2 / draftM2 * 100
+		}
+		if incidentM2 > 0 {
+			o.IncidentPercent = o.OverlapAreaM2 / incidentM2 * 100
+		}
+
+		overlaps = append(overlaps, &o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return overlaps, nil
+}
+
+func scanIncidentAsOf(row pgx.Row) (*entity.Incident, error) {
+	var (
+		i           entity.Incident
+		areaJSONStr string
+	)
+
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&areaJSONStr,
+		&i.IsActive,
+		&i.Status,
+		&i.Severity,
+		&i.SubmittedBy,
+		&i.ParentID,
+		&i.ExternalID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка сканирования инцидента: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(areaJSONStr), &i.Area); err != nil {
+		return nil, fmt.Errorf("ошибка размаршалинга area: %w", err)
+	}
+
+	return &i, nil
+}
+
+func scanVersion(row pgx.Row) (*entity.IncidentVersion, error) {
+	var (
+		v           entity.IncidentVersion
+		areaJSONStr string
+	)
+
+	err := row.Scan(
+		&v.Version,
+		&v.Operation,
+		&v.Name,
+		&v.Description,
+		&areaJSONStr,
+		&v.IsActive,
+		&v.Status,
+		&v.Severity,
+		&v.ChangedBy,
+		&v.ChangeReason,
+		&v.ValidFrom,
+		&v.ValidTo,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("ошибка сканирования версии инцидента: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(areaJSONStr), &v.Area); err != nil {
+		return nil, fmt.Errorf("ошибка размаршалинга area: %w", err)
+	}
+
+	return &v, nil
+}
+
+// GetStats считает пользователей в активных зонах за окно. Пользователи активных потомков
+// учитываются и в счетчике родителя, каждый один раз.
 func (r *IncidentRepoImpl) GetStats(ctx context.Context, minutes int) ([]*entity.IncidentStats, error) {
 	query := `
-        SELECT 
+        WITH RECURSIVE tree AS (
+            SELECT id AS root_id, id FROM incidents WHERE is_active = true
+            UNION
+            SELECT t.root_id, c.id
+            FROM tree t
+            JOIN incidents c ON c.parent_id = t.id
+            WHERE c.is_active = true
+        )
+        SELECT
             i.id as incident_id,
+            i.parent_id,
             i.name,
-            COUNT(DISTINCT lc.user_id) as user_count
+            COUNT(DISTINCT lc.user_id) as user_count,
+            COUNT(DISTINCT lc.user_id) FILTER (WHERE lc.incident_id = i.id) as own_user_count
         FROM incidents i
-        LEFT JOIN location_checks lc ON lc.incident_id = i.id 
+        JOIN tree t ON t.root_id = i.id
+        LEFT JOIN location_checks lc ON lc.incident_id = t.id
             AND lc.is_danger = true
+            AND lc.is_excluded = false
             AND lc.created_at > NOW() - INTERVAL '1 minute' * $1
-        WHERE i.is_active = true
-        GROUP BY i.id, i.name
+        GROUP BY i.id, i.parent_id, i.name
         HAVING COUNT(DISTINCT lc.user_id) > 0
         ORDER BY user_count DESC
     `
@@ -227,7 +968,7 @@ func (r *IncidentRepoImpl) GetStats(ctx context.Context, minutes int) ([]*entity
 	var stats []*entity.IncidentStats
 	for rows.Next() {
 		var s entity.IncidentStats
-		if err := rows.Scan(&s.IncidentID, &s.Name, &s.UserCount); err != nil {
+		if err := rows.Scan(&s.IncidentID, &s.ParentID, &s.Name, &s.UserCount, &s.OwnUserCount); err != nil {
 			return nil, fmt.Errorf("ошибка сканирования статистики: %w", err)
 		}
 		stats = append(stats, &s)
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// StreamAll читает инциденты по фильтру и передает их fn по одному, не накапливая выборку
+// в памяти. Ошибка fn прерывает чтение и возвращается как есть. Нулевой Limit превращается
+// в LIMIT NULL, то есть выгрузку всех строк.
+func (r *IncidentRepoImpl) StreamAll(ctx context.Context, filter entity.IncidentExportFilter, fn func(*entity.Incident) error) error {
+	var (
+		rows pgx.Rows
+		err  error
+	)
+
+	if filter.AsOf != nil {
+		rows, err = r.pool.Query(ctx, `
+			SELECT
+				v.incident_id,
+				v.name,
+				COALESCE(v.description, ''),
+				ST_AsGeoJSON(v.area) AS area_json,
+				v.is_active,
+				v.status,
+				v.severity,
+				'' AS submitted_by,
+				v.parent_id,
+				COALESCE(i.external_id, ''),
+				i.created_at,
+				v.valid_from,
+				v.version
+			FROM incident_versions v
+			JOIN incidents i ON i.id = v.incident_id
+			WHERE v.valid_from <= $1
+			AND (v.valid_to IS NULL OR v.valid_to > $1)
+			ORDER BY i.created_at DESC
+			LIMIT NULLIF($2::int, 0) OFFSET $3
+		`, *filter.AsOf, filter.Limit, filter.Offset)
+	} else {
+		rows, err = r.pool.Query(ctx, `
+			SELECT
+				id,
+				name,
+				description,
+				ST_AsGeoJSON(area) AS area_json,
+				is_active,
+				status,
+				severity,
+				COALESCE(submitted_by, ''),
+				parent_id,
+				COALESCE(external_id, ''),
+				created_at,
+				updated_at,
+				0 AS version
+			FROM incidents
+			ORDER BY created_at DESC
+			LIMIT NULLIF($1::int, 0) OFFSET $2
+		`, filter.Limit, filter.Offset)
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка выгрузки инцидентов: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		i, err := scanIncidentAsOf(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// BatchItemError — ошибка пакетной операции с номером элемента, на котором она произошла
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("элемент %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// UpsertMany сохраняет инциденты в одной транзакции: при совпадении external_id существующий
+// инцидент обновляется (пустая серьезность сохраняет текущую), иначе создается черновик.
+// Черновик остается черновиком, а инцидент на согласовании или опубликованный возвращается на
+// согласование и снимается с публикации; автором отправки становится ChangedBy. Завершенный
+// инцидент не изменяется. Для каждого записывается версия. Возвращает признак создания для
+// каждого инцидента; при ошибке откатывается вся транзакция, а ошибка оборачивается в *BatchItemError.
+func (r *IncidentRepoImpl) UpsertMany(ctx context.Context, incidents []*entity.Incident) ([]bool, error) {
+	query := `
+		INSERT INTO incidents (name, description, area, is_active, status, severity, external_id)
+		VALUES ($1, $2, ST_GeomFromGeoJSON($3)::geography, false, $4, COALESCE(NULLIF($5, ''), $6), NULLIF($7, ''))
+		ON CONFLICT (external_id) WHERE external_id IS NOT NULL DO UPDATE
+		SET
+			name = EXCLUDED.name,
+			description = EXCLUDED.description,
+			area = EXCLUDED.area,
+			severity = COALESCE(NULLIF($5, ''), incidents.severity),
+			status = CASE WHEN incidents.status = $4 THEN $4 ELSE $8 END,
+			is_active = false,
+			submitted_by = CASE WHEN incidents.status = $4 THEN NULL ELSE NULLIF($9, '') END,
+			updated_at = NOW()
+		WHERE incidents.status <> $10
+		RETURNING id, status, severity, is_active, COALESCE(submitted_by, ''), created_at, updated_at, (xmax = 0) AS inserted
+	`
+
+	created := make([]bool, len(incidents))
+
+	err := withTx(ctx, r.pool, func(q querier) error {
+		for idx, i := range incidents {
+			areaJSON, err := json.Marshal(i.Area)
+			if err != nil {
+				return &BatchItemError{Index: idx, Err: fmt.Errorf("ошибка маршалинга area: %w", err)}
+			}
+
+			err = q.QueryRow(ctx, query,
+				i.Name, i.Description, string(areaJSON), entity.IncidentStatusDraft,
+				i.Severity, entity.IncidentSeverityModerate, i.ExternalID,
+				entity.IncidentStatusPendingReview, i.ChangedBy, entity.IncidentStatusResolved,
+			).Scan(&i.ID, &i.Status, &i.Severity, &i.IsActive, &i.SubmittedBy, &i.CreatedAt, &i.UpdatedAt, &created[idx])
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return &BatchItemError{Index: idx, Err: ErrStatusConflict}
+				}
+				return &BatchItemError{Index: idx, Err: fmt.Errorf("ошибка сохранения инцидента: %w", err)}
+			}
+
+			operation := entity.IncidentOperationUpdate
+			if created[idx] {
+				operation = entity.IncidentOperationCreate
+			}
+
+			if err := insertVersion(ctx, q, i.ID, operation, i.ChangedBy, i.ChangeReason); err != nil {
+				return &BatchItemError{Index: idx, Err: err}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// FindByExternalIDs возвращает существующие инциденты с указанными external_id без зоны:
+// импорт сверяет по ним статус, серьезность и родителя
+func (r *IncidentRepoImpl) FindByExternalIDs(ctx context.Context, externalIDs []string) (map[string]*entity.Incident, error) {
+	query := `
+		SELECT id, name, status, severity, COALESCE(submitted_by, ''), parent_id, external_id
+		FROM incidents
+		WHERE external_id = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска инцидентов по external_id: %w", err)
+	}
+	defer rows.Close()
+
+	found := make(map[string]*entity.Incident)
+	for rows.Next() {
+		var i entity.Incident
+		if err := rows.Scan(&i.ID, &i.Name, &i.Status, &i.Severity, &i.SubmittedBy, &i.ParentID, &i.ExternalID); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования инцидента: %w", err)
+		}
+		found[i.ExternalID] = &i
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения инцидентов: %w", err)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// FindPublished возвращает действующие опубликованные инциденты, последние измененные первыми
+func (r *IncidentRepoImpl) FindPublished(ctx context.Context) ([]*entity.Incident, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			id,
+			name,
+			description,
+			ST_AsGeoJSON(area) AS area_json,
+			is_active,
+			status,
+			severity,
+			COALESCE(submitted_by, ''),
+			parent_id,
+			COALESCE(external_id, ''),
+			created_at,
+			updated_at,
+			0 AS version
+		FROM incidents
+		WHERE status = 'published' AND is_active
+		ORDER BY updated_at DESC, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска опубликованных инцидентов: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []*entity.Incident
+	for rows.Next() {
+		i, err := scanIncidentAsOf(rows)
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// LastModified возвращает время последнего изменения ленты CAP: последнее изменение опубликованных
+// и завершенных инцидентов, а также момент, когда опубликованный инцидент вернулся на согласование
+// (valid_to его опубликованной версии). Правки черновиков и инцидентов на согласовании не учитываются,
+// чтобы валидаторы публичной ленты не менялись без изменения ее содержимого.
+// Если в ленте ничего не было, возвращается нулевое время.
+func (r *IncidentRepoImpl) LastModified(ctx context.Context) (time.Time, error) {
+	query := `
+	SELECT greatest(
+		(SELECT max(updated_at) FROM incidents WHERE status IN ('published', 'resolved')),
+		(SELECT max(valid_to) FROM incident_versions WHERE status = 'published')
+	)
+	`
+
+	var lastModified *time.Time
+	err := r.pool.QueryRow(ctx, query).Scan(&lastModified)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ошибка поиска времени изменения инцидентов: %w", err)
+	}
+
+	if lastModified == nil {
+		return time.Time{}, nil
+	}
+	return *lastModified, nil
+}
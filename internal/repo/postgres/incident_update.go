@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+type IncidentUpdateRepo interface {
+	Create(ctx context.Context, u *entity.IncidentUpdate) error
+	FindByID(ctx context.Context, incidentID, id uuid.UUID) (*entity.IncidentUpdate, error)
+	FindByIncident(ctx context.Context, incidentID uuid.UUID, limit, offset int) ([]*entity.IncidentUpdate, error)
+	FindLatest(ctx context.Context, incidentIDs []uuid.UUID, asOf *time.Time) (map[uuid.UUID]*entity.IncidentUpdate, error)
+	Update(ctx context.Context, u *entity.IncidentUpdate) error
+	Delete(ctx context.Context, incidentID, id uuid.UUID) error
+}
+
+var ErrIncidentUpdateNotFound = errors.New("сообщение по инциденту не найдено")
+
+type IncidentUpdateRepoImpl struct {
+	pool *pgxpool.Pool
+}
+
+func NewIncidentUpdateRepo(pool *pgxpool.Pool) IncidentUpdateRepo {
+	return &IncidentUpdateRepoImpl{pool: pool}
+}
+
+func (r *IncidentUpdateRepoImpl) Create(ctx context.Context, u *entity.IncidentUpdate) error {
+	query := `
+		INSERT INTO incident_updates (incident_id, message, author)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, u.IncidentID, u.Message, u.Author).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("ошибка создания сообщения по инциденту: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IncidentUpdateRepoImpl) FindByID(ctx context.Context, incidentID, id uuid.UUID) (*entity.IncidentUpdate, error) {
+	query := `
+		SELECT
+			id,
+			incident_id,
+			message,
+			COALESCE(author, ''),
+			created_at,
+			updated_at
+		FROM incident_updates
+		WHERE incident_id = $1 AND id = $2
+	`
+
+	u, err := scanIncidentUpdate(r.pool.QueryRow(ctx, query, incidentID, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIncidentUpdateNotFound
+		}
+		return nil, fmt.Errorf("ошибка поиска сообщения по инциденту %s: %w", id, err)
+	}
+
+	return u, nil
+}
+
+// FindByIncident возвращает сообщения по инциденту от последнего к первому
+func (r *IncidentUpdateRepoImpl) FindByIncident(ctx context.Context, incidentID uuid.UUID, limit, offset int) ([]*entity.IncidentUpdate, error) {
+	query := `
+		SELECT
+			id,
+			incident_id,
+			message,
+			COALESCE(author, ''),
+			created_at,
+			updated_at
+		FROM incident_updates
+		WHERE incident_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, incidentID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска сообщений по инциденту: %w", err)
+	}
+	defer rows.Close()
+
+	var updates []*entity.IncidentUpdate
+	for rows.Next() {
+		u, err := scanIncidentUpdate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования сообщения по инциденту: %w", err)
+		}
+		updates = append(updates, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return updates, nil
+}
+
+// FindLatest возвращает последнее сообщение по каждому из инцидентов. С asOf учитываются
+// только сообщения, созданные не позже этого момента.
+func (r *IncidentUpdateRepoImpl) FindLatest(ctx context.Context, incidentIDs []uuid.UUID, asOf *time.Time) (map[uuid.UUID]*entity.IncidentUpdate, error) {
+	latest := make(map[uuid.UUID]*entity.IncidentUpdate, len(incidentIDs))
+	if len(incidentIDs) == 0 {
+		return latest, nil
+	}
+
+	query := `
+		SELECT DISTINCT ON (incident_id)
+			id,
+			incident_id,
+			message,
+			COALESCE(author, ''),
+			created_at,
+			updated_at
+		FROM incident_updates
+		WHERE incident_id = ANY($1)
+		AND ($2::timestamptz IS NULL OR created_at <= $2)
+		ORDER BY incident_id, created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, incidentIDs, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска последних сообщений по инцидентам: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		u, err := scanIncidentUpdate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования сообщения по инциденту: %w", err)
+		}
+		latest[u.IncidentID] = u
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return latest, nil
+}
+
+func (r *IncidentUpdateRepoImpl) Update(ctx context.Context, u *entity.IncidentUpdate) error {
+	query := `
+		UPDATE incident_updates
+		SET message = $3, updated_at = NOW()
+		WHERE incident_id = $1 AND id = $2
+		RETURNING updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, u.IncidentID, u.ID, u.Message).Scan(&u.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIncidentUpdateNotFound
+		}
+		return fmt.Errorf("ошибка обновления сообщения по инциденту: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IncidentUpdateRepoImpl) Delete(ctx context.Context, incidentID, id uuid.UUID) error {
+	query := `DELETE FROM incident_updates WHERE incident_id = $1 AND id = $2`
+
+	tag, err := r.pool.Exec(ctx, query, incidentID, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления сообщения по инциденту: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIncidentUpdateNotFound
+	}
+
+	return nil
+}
+
+func scanIncidentUpdate(row pgx.Row) (*entity.IncidentUpdate, error) {
+	var u entity.IncidentUpdate
+	if err := row.Scan(
+		&u.ID,
+		&u.IncidentID,
+		&u.Message,
+		&u.Author,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
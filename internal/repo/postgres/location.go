@@ -2,15 +2,30 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
 )
 
+// ErrFlaggedCheckNotFound возвращается, если помеченной проверки с таким id нет
+var ErrFlaggedCheckNotFound = errors.New("помеченная проверка не найдена")
+
 type LocationRepo interface {
 	CheckLocation(ctx context.Context, location entity.UserLocation) ([]*entity.LocationCheckIncident, error)
 	SaveLocationCheck(ctx context.Context, location *entity.LocationCheck) error
+	FindAdjacentLocationChecks(ctx context.Context, userID string, at time.Time) (prev, next *entity.LocationCheck, err error)
+	FindLocationCheckAt(ctx context.Context, userID string, at time.Time) (*entity.LocationCheck, error)
+	CheckRoute(ctx context.Context, route entity.GeoJsonLineString) ([]*entity.RouteIncident, error)
+	FindFlaggedChecks(ctx context.Context, reviewStatus string, limit, offset int) ([]*entity.FlaggedLocationCheck, error)
+	ReviewLocationCheck(ctx context.Context, id int64, reviewStatus, reviewer string, excluded bool) error
+	RecheckLocationChecks(ctx context.Context, req *entity.RecheckRequest) ([]*entity.RecheckChange, int, int, error)
+	FindUsersInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, limit int) ([]*entity.AffectedUser, int, error)
+	CountChecksInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, bucket time.Duration) ([]*entity.ImpactBucket, error)
 }
 
 type LocationRepoImpl struct {
@@ -54,8 +69,16 @@ func (r *LocationRepoImpl) CheckLocation(ctx context.Context, location entity.Us
 
 func (r *LocationRepoImpl) SaveLocationCheck(ctx context.Context, location *entity.LocationCheck) error {
 	query := `
-	INSERT INTO location_checks ( user_id, user_location, is_danger, incident_id, created_at)
-	VALUES ($1, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4, $5, $6)
+	INSERT INTO location_checks (
+		user_id, user_location, is_danger, incident_id, created_at,
+		is_flagged, flag_reason, implied_speed_kmh, is_excluded, review_status,
+		accuracy_m, match_status
+	)
+	VALUES (
+		$1, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4, $5, $6,
+		$7, NULLIF($8, ''), $9, $10, CASE WHEN $7 THEN $11 END,
+		$12, NULLIF($13, '')
+	)
 	`
 
 	_, err := r.pool.Exec(ctx, query,
@@ -65,6 +88,13 @@ func (r *LocationRepoImpl) SaveLocationCheck(ctx context.Context, location *enti
 		location.IsDanger,
 		location.IncidentID,
 		location.CreatedAt,
+		location.IsFlagged,
+		location.FlagReason,
+		location.ImpliedSpeedKmh,
+		location.IsExcluded,
+		entity.ReviewStatusPending,
+		location.UserLocation.AccuracyM,
+		location.MatchStatus,
 	)
 
 	if err != nil {
@@ -73,3 +103,530 @@ func (r *LocationRepoImpl) SaveLocationCheck(ctx context.Context, location *enti
 
 	return nil
 }
+
+// FindAdjacentLocationChecks возвращает ближайшие проверки пользователя по обе стороны от at:
+// последнюю сделанную не позже at и первую сделанную позже. Отсутствующая сторона возвращается как nil.
+// Помеченные проверки не пропускаются: после реального дальнего перемещения следующая точка
+// сравнивается уже с новым местом, а не с устаревшей точкой до перемещения.
+func (r *LocationRepoImpl) FindAdjacentLocationChecks(ctx context.Context, userID string, at time.Time) (*entity.LocationCheck, *entity.LocationCheck, error) {
+	query := `
+	(
+		SELECT user_id, ST_Y(user_location::geometry), ST_X(user_location::geometry),
+			is_danger, incident_id, is_flagged, created_at
+		FROM location_checks
+		WHERE user_id = $1 AND created_at <= $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	)
+	UNION ALL
+	(
+		SELECT user_id, ST_Y(user_location::geometry), ST_X(user_location::geometry),
+			is_danger, incident_id, is_flagged, created_at
+		FROM location_checks
+		WHERE user_id = $1 AND created_at > $2
+		ORDER BY created_at ASC
+		LIMIT 1
+	)
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, at)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка поиска соседних проверок пользователя %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var prev, next *entity.LocationCheck
+	for rows.Next() {
+		var check entity.LocationCheck
+		if err := rows.Scan(
+			&check.UserID,
+			&check.UserLocation.Lat,
+			&check.UserLocation.Lon,
+			&check.IsDanger,
+			&check.IncidentID,
+			&check.IsFlagged,
+			&check.CreatedAt,
+		); err != nil {
+			return nil, nil, fmt.Errorf("ошибка чтения соседней проверки: %w", err)
+		}
+
+		if check.CreatedAt.After(at) {
+			next = &check
+		} else {
+			prev = &check
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("ошибка итерации соседних проверок: %w", err)
+	}
+
+	return prev, next, nil
+}
+
+// FindLocationCheckAt возвращает последнюю проверку пользователя, сделанную не позже at.
+// Пропускает проверки, помеченные детектором подмены координат и не отклоненные оператором.
+func (r *LocationRepoImpl) FindLocationCheckAt(ctx context.Context, userID string, at time.Time) (*entity.LocationCheck, error) {
+	query := `
+	SELECT
+		user_id,
+		ST_Y(user_location::geometry),
+		ST_X(user_location::geometry),
+		is_danger,
+		incident_id,
+		created_at
+	FROM location_checks
+	WHERE user_id = $1
+	AND created_at <= $2
+	AND (is_flagged = false OR review_status = 'dismissed')
+	ORDER BY created_at DESC
+	LIMIT 1
+	`
+
+	var check entity.LocationCheck
+	err := r.pool.QueryRow(ctx, query, userID, at).Scan(
+		&check.UserID,
+		&check.UserLocation.Lat,
+		&check.UserLocation.Lon,
+		&check.IsDanger,
+		&check.IncidentID,
+		&check.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка поиска проверки пользователя %s на момент %s: %w", userID, at, err)
+	}
+
+	return &check, nil
+}
+
+// CheckRoute находит активные инциденты, через которые проходит маршрут. Для каждого
+// пересечения вычисляются точки входа и выхода (первая и последняя по ходу маршрута)
+// и длина участка маршрута внутри зоны в метрах.
+func (r *LocationRepoImpl) CheckRoute(ctx context.Context, route entity.GeoJsonLineString) ([]*entity.RouteIncident, error) {
+	routeJSON, err := json.Marshal(route)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга маршрута: %w", err)
+	}
+
+	query := `
+	WITH route AS (
+		SELECT ST_SetSRID(ST_GeomFromGeoJSON($1), 4326) AS geom
+	),
+	hits AS (
+		SELECT
+			i.id,
+			i.name,
+			i.description,
+			ST_CollectionExtract(ST_Intersection(i.area, r.geom::geography)::geometry, 2) AS inside
+		FROM incidents i
+		CROSS JOIN route r
+		WHERE i.is_active = true
+		AND ST_Intersects(i.area, r.geom::geography)
+	)
+	SELECT
+		h.id,
+		h.name,
+		COALESCE(h.description, ''),
+		ST_Y(en.pt),
+		ST_X(en.pt),
+		ST_Y(ex.pt),
+		ST_X(ex.pt),
+		COALESCE(ST_Length(h.inside::geography), 0)
+	FROM hits h
+	CROSS JOIN route r
+	LEFT JOIN LATERAL (
+		SELECT p.pt FROM (
+			SELECT ST_StartPoint(d.geom) AS pt FROM ST_Dump(h.inside) d
+			UNION ALL
+			SELECT ST_EndPoint(d.geom) AS pt FROM ST_Dump(h.inside) d
+		) p
+		ORDER BY ST_LineLocatePoint(r.geom, p.pt) ASC
+		LIMIT 1
+	) en ON true
+	LEFT JOIN LATERAL (
+		SELECT p.pt FROM (
+			SELECT ST_StartPoint(d.geom) AS pt FROM ST_Dump(h.inside) d
+			UNION ALL
+			SELECT ST_EndPoint(d.geom) AS pt FROM ST_Dump(h.inside) d
+		) p
+		ORDER BY ST_LineLocatePoint(r.geom, p.pt) DESC
+		LIMIT 1
+	) ex ON true
+	ORDER BY ST_LineLocatePoint(r.geom, en.pt) NULLS LAST
+	`
+
+	rows, err := r.pool.Query(ctx, query, string(routeJSON))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки маршрута: %w", err)
+	}
+	defer rows.Close()
+
+	var incidents []*entity.RouteIncident
+	for rows.Next() {
+		var (
+			incident           entity.RouteIncident
+			entryLat, entryLon *float64
+			exitLat, exitLon   *float64
+		)
+
+		if err := rows.Scan(
+			&incident.ID,
+			&incident.Name,
+			&incident.Description,
+			&entryLat,
+			&entryLon,
+			&exitLat,
+			&exitLon,
+			&incident.LengthM,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования пересечения маршрута: %w", err)
+		}
+
+		if entryLat != nil && entryLon != nil {
+			incident.EntryPoint = &entity.UserLocation{Lat: *entryLat, Lon: *entryLon}
+		}
+		if exitLat != nil && exitLon != nil {
+			incident.ExitPoint = &entity.UserLocation{Lat: *exitLat, Lon: *exitLon}
+		}
+
+		incidents = append(incidents, &incident)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return incidents, nil
+}
+
+func (r *LocationRepoImpl) FindFlaggedChecks(ctx context.Context, reviewStatus string, limit, offset int) ([]*entity.FlaggedLocationCheck, error) {
+	query := `
+	SELECT
+		id,
+		user_id,
+		ST_Y(user_location::geometry),
+		ST_X(user_location::geometry),
+		is_danger,
+		incident_id,
+		COALESCE(flag_reason, ''),
+		implied_speed_kmh,
+		is_excluded,
+		COALESCE(review_status, ''),
+		COALESCE(reviewed_by, ''),
+		reviewed_at,
+		created_at
+	FROM location_checks
+	WHERE is_flagged = true
+	AND ($1 = '' OR review_status = $1)
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, reviewStatus, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска помеченных проверок: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []*entity.FlaggedLocationCheck
+	for rows.Next() {
+		var c entity.FlaggedLocationCheck
+		if err := rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.UserLocation.Lat,
+			&c.UserLocation.Lon,
+			&c.IsDanger,
+			&c.IncidentID,
+			&c.FlagReason,
+			&c.ImpliedSpeedKmh,
+			&c.IsExcluded,
+			&c.ReviewStatus,
+			&c.ReviewedBy,
+			&c.ReviewedAt,
+			&c.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования помеченной проверки: %w", err)
+		}
+		checks = append(checks, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return checks, nil
+}
+
+func (r *LocationRepoImpl) ReviewLocationCheck(ctx context.Context, id int64, reviewStatus, reviewer string, excluded bool) error {
+	query := `
+	UPDATE location_checks
+	SET
+		review_status = $1,
+		reviewed_by = NULLIF($2, ''),
+		reviewed_at = NOW(),
+		is_excluded = $3
+	WHERE id = $4
+	AND is_flagged = true
+	`
+
+	result, err := r.pool.Exec(ctx, query, reviewStatus, reviewer, excluded, id)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения решения по проверке %d: %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrFlaggedCheckNotFound
+	}
+
+	return nil
+}
+
+// Подзапросы, находящие зону для проверки c. Если сохраненная зона по-прежнему содержит точку,
+// она остается выбранной, иначе берется самый новый инцидент, как при обычной проверке.
+const (
+	recheckCurrentMatch = `
+		SELECT i.id AS incident_id
+		FROM incidents i
+		WHERE i.is_active = true
+		AND ST_Intersects(i.area, c.user_location)
+		ORDER BY (i.id IS NOT DISTINCT FROM c.incident_id) DESC, i.created_at DESC
+		LIMIT 1
+	`
+	recheckHistoricalMatch = `
+		SELECT v.incident_id
+		FROM incident_versions v
+		JOIN incidents i ON i.id = v.incident_id
+		WHERE v.is_active = true
+		AND v.valid_from <= c.created_at
+		AND (v.valid_to IS NULL OR v.valid_to > c.created_at)
+		AND ST_Intersects(v.area, c.user_location)
+		ORDER BY (v.incident_id IS NOT DISTINCT FROM c.incident_id) DESC, i.created_at DESC
+		LIMIT 1
+	`
+)
+
+// RecheckLocationChecks пересчитывает совпадения проверок за период [req.From, req.To) по текущей
+// или исторической геометрии зон и возвращает проверки, результат которых изменился, вместе с
+// числом просмотренных и пропущенных проверок. При req.Apply изменения записываются в той же транзакции.
+// Пересчитываются только точечные проверки: статус проверки с точностью координат зависит от доли
+// круга точности внутри зоны и политики possibly_inside, поэтому такие проверки пропускаются.
+func (r *LocationRepoImpl) RecheckLocationChecks(ctx context.Context, req *entity.RecheckRequest) ([]*entity.RecheckChange, int, int, error) {
+	match := recheckCurrentMatch
+	if req.Geometry == entity.RecheckGeometryHistorical {
+		match = recheckHistoricalMatch
+	}
+
+	changed := `
+	WITH recomputed AS (
+		SELECT
+			c.id,
+			c.user_id,
+			c.user_location,
+			c.created_at,
+			c.is_danger,
+			c.incident_id,
+			m.incident_id AS new_incident_id
+		FROM location_checks c
+		LEFT JOIN LATERAL (` + match + `) m ON true
+		WHERE c.created_at >= $1
+		AND c.created_at < $2
+		AND COALESCE(c.accuracy_m, 0) = 0
+	),
+	changed AS (
+		SELECT *
+		FROM recomputed
+		WHERE (is_danger <> (new_incident_id IS NOT NULL) OR incident_id IS DISTINCT FROM new_incident_id)
+		AND ($3::uuid IS NULL OR incident_id = $3 OR new_incident_id = $3)
+		ORDER BY created_at
+		LIMIT $4
+	)
+	`
+
+	query := changed + `
+	SELECT
+		ch.id,
+		ch.user_id,
+		ST_Y(ch.user_location::geometry),
+		ST_X(ch.user_location::geometry),
+		ch.created_at,
+		ch.is_danger,
+		ch.incident_id,
+		ch.new_incident_id
+	FROM changed ch
+	ORDER BY ch.created_at
+	`
+	if req.Apply {
+		query = changed + `
+		UPDATE location_checks c
+		SET
+			is_danger = ch.new_incident_id IS NOT NULL,
+			incident_id = ch.new_incident_id,
+			match_status = CASE WHEN ch.new_incident_id IS NOT NULL THEN $5 ELSE $6 END
+		FROM changed ch
+		WHERE c.id = ch.id
+		RETURNING
+			ch.id,
+			ch.user_id,
+			ST_Y(ch.user_location::geometry),
+			ST_X(ch.user_location::geometry),
+			ch.created_at,
+			ch.is_danger,
+			ch.incident_id,
+			ch.new_incident_id
+		`
+	}
+
+	countQuery := `
+	SELECT COUNT(*), COUNT(*) FILTER (WHERE COALESCE(accuracy_m, 0) <> 0)
+	FROM location_checks
+	WHERE created_at >= $1
+	AND created_at < $2
+	`
+
+	args := []any{req.From, req.To, req.IncidentID, req.Limit}
+	if req.Apply {
+		args = append(args, entity.LocationStatusInside, entity.LocationStatusOutside)
+	}
+
+	var (
+		changes []*entity.RecheckChange
+		scanned int
+		skipped int
+	)
+
+	err := withTx(ctx, r.pool, func(q querier) error {
+		if err := q.QueryRow(ctx, countQuery, req.From, req.To).Scan(&scanned, &skipped); err != nil {
+			return fmt.Errorf("ошибка подсчета проверок за период: %w", err)
+		}
+
+		rows, err := q.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("ошибка пересчета проверок: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var ch entity.RecheckChange
+			if err := rows.Scan(
+				&ch.CheckID,
+				&ch.UserID,
+				&ch.UserLocation.Lat,
+				&ch.UserLocation.Lon,
+				&ch.CreatedAt,
+				&ch.OldIsDanger,
+				&ch.OldIncidentID,
+				&ch.NewIncidentID,
+			); err != nil {
+				return fmt.Errorf("ошибка сканирования пересчитанной проверки: %w", err)
+			}
+			ch.NewIsDanger = ch.NewIncidentID != nil
+			changes = append(changes, &ch)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("ошибка rows: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return changes, scanned, skipped, nil
+}
+
+// FindUsersInArea возвращает пользователей, достоверные проверки которых после since попали
+// в полигон, начиная с последних увиденных, и общее число таких пользователей
+func (r *LocationRepoImpl) FindUsersInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, limit int) ([]*entity.AffectedUser, int, error) {
+	areaJSON, err := json.Marshal(area)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	query := `
+	SELECT
+		user_id,
+		COUNT(*) AS checks,
+		MAX(created_at) AS last_seen_at,
+		COUNT(*) OVER () AS total
+	FROM location_checks
+	WHERE created_at >= $2
+	AND is_excluded = false
+	AND ST_Intersects(user_location, ST_GeomFromGeoJSON($1)::geography)
+	GROUP BY user_id
+	ORDER BY last_seen_at DESC
+	LIMIT $3
+	`
+
+	rows, err := r.pool.Query(ctx, query, string(areaJSON), since, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("ошибка поиска пользователей в зоне: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		users []*entity.AffectedUser
+		total int
+	)
+	for rows.Next() {
+		var u entity.AffectedUser
+		if err := rows.Scan(&u.UserID, &u.Checks, &u.LastSeenAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("ошибка сканирования пользователя в зоне: %w", err)
+		}
+		users = append(users, &u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// CountChecksInArea считает пользователей и проверки внутри полигона по интервалам длиной bucket,
+// отсчитываемым от since. Пустые интервалы не возвращаются.
+func (r *LocationRepoImpl) CountChecksInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, bucket time.Duration) ([]*entity.ImpactBucket, error) {
+	areaJSON, err := json.Marshal(area)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка маршалинга area: %w", err)
+	}
+
+	query := `
+	SELECT
+		date_bin($3 * INTERVAL '1 second', created_at, $2) AS bucket,
+		COUNT(DISTINCT user_id),
+		COUNT(*)
+	FROM location_checks
+	WHERE created_at >= $2
+	AND is_excluded = false
+	AND ST_Intersects(user_location, ST_GeomFromGeoJSON($1)::geography)
+	GROUP BY bucket
+	ORDER BY bucket
+	`
+
+	rows, err := r.pool.Query(ctx, query, string(areaJSON), since, int64(bucket.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подсчета проверок в зоне: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []*entity.ImpactBucket
+	for rows.Next() {
+		var b entity.ImpactBucket
+		if err := rows.Scan(&b.Start, &b.Users, &b.Checks); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования интервала: %w", err)
+		}
+		buckets = append(buckets, &b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка rows: %w", err)
+	}
+
+	return buckets, nil
+}
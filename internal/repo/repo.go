@@ -6,15 +6,23 @@ import (
 )
 
 type Repo struct {
-	IncidentRepo postgres.IncidentRepo
-	LocationRepo postgres.LocationRepo
-	HealthRepo   postgres.HealthRepo
+	IncidentRepo       postgres.IncidentRepo
+	IncidentUpdateRepo postgres.IncidentUpdateRepo
+	LocationRepo       postgres.LocationRepo
+	HealthRepo         postgres.HealthRepo
+	DeviceRepo         postgres.DeviceRepo
+	GeometryRepo       postgres.GeometryRepo
+	CapRepo            postgres.CapRepo
 }
 
 func NewRepo(pool *pgxpool.Pool) *Repo {
 	return &Repo{
-		IncidentRepo: postgres.NewIncidentRepo(pool),
-		LocationRepo: postgres.NewLocationRepo(pool),
-		HealthRepo:   postgres.NewHealthRepoImpl(pool),
+		IncidentRepo:       postgres.NewIncidentRepo(pool),
+		IncidentUpdateRepo: postgres.NewIncidentUpdateRepo(pool),
+		LocationRepo:       postgres.NewLocationRepo(pool),
+		HealthRepo:         postgres.NewHealthRepoImpl(pool),
+		DeviceRepo:         postgres.NewDeviceRepo(pool),
+		GeometryRepo:       postgres.NewGeometryRepo(pool),
+		CapRepo:            postgres.NewCapRepo(pool),
 	}
 }
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/cap"
+)
+
+var (
+	ErrInvalidCapMessage    = errors.New("сообщение CAP не может быть применено")
+	ErrCapMessageInProgress = errors.New("сообщение CAP уже обрабатывается")
+	ErrCapDocumentNotFound  = errors.New("опубликованный инцидент не найден")
+)
+
+// Отправитель публикуемых сообщений CAP, если INCIDENT_CAP_SENDER не задан
+const defaultCapSender = "geo-incident-service"
+
+type CapService interface {
+	Ingest(ctx context.Context, alert *cap.Alert, actor string) (*entity.CapIngestResponse, error)
+	Feed(ctx context.Context, baseURL string) (*entity.CapDocument, error)
+	Document(ctx context.Context, id, baseURL string) (*entity.CapDocument, error)
+}
+
+type CapServiceImpl struct {
+	repo         postgres.CapRepo
+	incidentRepo postgres.IncidentRepo
+	geometryRepo postgres.GeometryRepo
+	incidents    IncidentService
+	cfg          *config.Config
+}
+
+// NewCapService принимает IncidentService, чтобы инциденты из CAP создавались и обновлялись тем же
+// путем, что и при импорте: с проверкой зоны, upsert по external_id, версиями и событиями.
+func NewCapService(repo postgres.CapRepo, incidentRepo postgres.IncidentRepo, geometryRepo postgres.GeometryRepo, incidents IncidentService, cfg *config.Config) CapService {
+	return &CapServiceImpl{repo: repo, incidentRepo: incidentRepo, geometryRepo: geometryRepo, incidents: incidents, cfg: cfg}
+}
+
+// Ingest применяет сообщение CAP. Alert создает инцидент-черновик с external_id "cap:<sender>:<identifier>",
+// Update меняет инцидент, на сообщение которого ссылается references (без известной ссылки создается
+// новый), Cancel завершает его. Сообщения со status не Actual, Ack, Error и истекшие пропускаются,
+// повторная доставка уже принятого сообщения ничего не меняет. Перед применением сообщение
+// занимается уникальной вставкой (ClaimMessage), поэтому одновременная доставка того же сообщения
+// получает ErrCapMessageInProgress; если применить сообщение не удалось, заявка снимается.
+func (s *CapServiceImpl) Ingest(ctx context.Context, alert *cap.Alert, actor string) (*entity.CapIngestResponse, error) {
+	resp := &entity.CapIngestResponse{
+		Identifier: alert.Identifier,
+		Sender:     alert.Sender,
+		MsgType:    alert.MsgType,
+	}
+
+	sent, err := cap.ParseTime(alert.Sent)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sent: %v", ErrInvalidCapMessage, err)
+	}
+
+	if alert.Status != cap.StatusActual {
+		return ignore(resp, fmt.Sprintf("status %s: обрабатываются только сообщения Actual", alert.Status)), nil
+	}
+
+	switch alert.MsgType {
+	case cap.MsgTypeAlert, cap.MsgTypeUpdate, cap.MsgTypeCancel:
+	case cap.MsgTypeAck, cap.MsgTypeError:
+		return ignore(resp, fmt.Sprintf("msgType %s не меняет инциденты", alert.MsgType)), nil
+	default:
+		return nil, fmt.Errorf("%w: неизвестный msgType %q", ErrInvalidCapMessage, alert.MsgType)
+	}
+
+	refs, err := alert.ParseReferences()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCapMessage, err)
+	}
+
+	message := &entity.CapMessage{
+		Sender:     alert.Sender,
+		Identifier: alert.Identifier,
+		Sent:       sent,
+		MsgType:    alert.MsgType,
+	}
+
+	info := pickInfo(alert)
+	if info != nil {
+		if err := describeInfo(resp, message, info); err != nil {
+			return nil, err
+		}
+	}
+
+	claimed, err := s.repo.ClaimMessage(ctx, message)
+	if err != nil {
+		slog.Error("не удалось занять сообщение CAP", "identifier", alert.Identifier, "error", err)
+		return nil, fmt.Errorf("не удалось занять сообщение CAP: %w", err)
+	}
+	if !claimed {
+		return s.duplicate(ctx, resp)
+	}
+
+	saved := false
+	defer func() {
+		if saved {
+			return
+		}
+		if err := s.repo.ReleaseMessage(context.WithoutCancel(ctx), alert.Sender, alert.Identifier); err != nil {
+			slog.Error("не удалось снять заявку на сообщение CAP", "identifier", alert.Identifier, "error", err)
+		}
+	}()
+
+	var referenced *entity.CapMessage
+	if alert.MsgType != cap.MsgTypeAlert {
+		if referenced, err = s.findReferenced(ctx, refs); err != nil {
+			return nil, err
+		}
+	}
+
+	reason := fmt.Sprintf("CAP %s %s", alert.MsgType, alert.Identifier)
+	if alert.MsgType == cap.MsgTypeCancel {
+		if referenced == nil {
+			return ignore(resp, "в references нет принятых сообщений"), nil
+		}
+		message.IncidentID = referenced.IncidentID
+		if err := s.cancel(ctx, resp, referenced.IncidentID, reason, actor); err != nil {
+			return nil, err
+		}
+	} else {
+		if info == nil {
+			return nil, fmt.Errorf("%w: нет элемента info", ErrInvalidCapMessage)
+		}
+		if message.Expires != nil && message.Expires.Before(time.Now()) {
+			return ignore(resp, "срок действия сообщения (expires) истек"), nil
+		}
+
+		externalID := capExternalID(alert.Sender, alert.Identifier)
+		if referenced != nil {
+			incident, err := s.incidentRepo.FindByID(ctx, referenced.IncidentID)
+			if err != nil {
+				slog.Error("не удалось найти инцидент сообщения CAP", "id", referenced.IncidentID, "error", err)
+				return nil, fmt.Errorf("не удалось найти инцидент сообщения CAP: %w", err)
+			}
+			if incident.ExternalID == "" {
+				return nil, fmt.Errorf("%w: инцидент %s не связан с CAP", ErrInvalidCapMessage, incident.ID)
+			}
+			externalID = incident.ExternalID
+		}
+
+		if err := s.upsert(ctx, resp, alert, info, externalID, reason, actor); err != nil {
+			return nil, err
+		}
+		message.IncidentID = uuid.MustParse(resp.IncidentID)
+	}
+
+	if err := s.repo.SaveMessage(ctx, message); err != nil {
+		slog.Error("не удалось сохранить сообщение CAP", "identifier", alert.Identifier, "error", err)
+		return nil, fmt.Errorf("не удалось сохранить сообщение CAP: %w", err)
+	}
+	saved = true
+
+	return resp, nil
+}
+
+// duplicate отвечает на доставку сообщения, которое уже принято или обрабатывается другим запросом
+func (s *CapServiceImpl) duplicate(ctx context.Context, resp *entity.CapIngestResponse) (*entity.CapIngestResponse, error) {
+	existing, err := s.repo.FindMessage(ctx, resp.Sender, resp.Identifier)
+	switch {
+	case errors.Is(err, postgres.ErrCapMessageNotFound):
+		slog.Error("сообщение CAP обрабатывается другим запросом", "identifier", resp.Identifier)
+		return nil, fmt.Errorf("%w: %s", ErrCapMessageInProgress, resp.Identifier)
+	case err != nil:
+		slog.Error("не удалось проверить сообщение CAP", "identifier", resp.Identifier, "error", err)
+		return nil, fmt.Errorf("не удалось проверить сообщение CAP: %w", err)
+	}
+
+	resp.Action = entity.CapActionDuplicate
+	resp.IncidentID = existing.IncidentID.String()
+	return resp, nil
+}
+
+// upsert сохраняет зону и описание сообщения через импорт одного объекта
+func (s *CapServiceImpl) upsert(ctx context.Context, resp *entity.CapIngestResponse, alert *cap.Alert, info *cap.Info, externalID, reason, actor string) error {
+	area, err := s.area(ctx, info)
+	if err != nil {
+		return err
+	}
+
+	geometry, err := json.Marshal(area)
+	if err != nil {
+		return fmt.Errorf("ошибка маршалинга зоны: %w", err)
+	}
+
+	result, err := s.incidents.Import(ctx, &entity.ImportIncidentsRequest{
+		Collection: entity.GeoJsonFeatureCollection{
+			Type: "FeatureCollection",
+			Features: []entity.GeoJsonFeature{{
+				Type:     "Feature",
+				Geometry: geometry,
+				Properties: map[string]any{
+					"name":        capName(alert, info),
+					"description": capDescription(info),
+					"severity":    cap.Severity(info.Severity),
+					"external_id": externalID,
+				},
+			}},
+		},
+		Reason: reason,
+		Actor:  actor,
+	})
+	if err != nil {
+		return err
+	}
+
+	feature := result.Features[0]
+	if !result.Committed {
+		return fmt.Errorf("%w: %s", ErrInvalidCapMessage, strings.Join(feature.Errors, "; "))
+	}
+
+	resp.IncidentID = feature.ID
+	resp.Action = entity.CapActionCreated
+	if feature.Action == entity.ImportActionUpdated {
+		resp.Action = entity.CapActionUpdated
+	}
+
+	return nil
+}
+
+// area объединяет все polygon и circle сообщения в одну зону
+func (s *CapServiceImpl) area(ctx context.Context, info *cap.Info) (entity.GeoJsonPolygon, error) {
+	shapes, err := info.Shapes()
+	if err != nil {
+		return entity.GeoJsonPolygon{}, fmt.Errorf("%w: %v", ErrInvalidCapMessage, err)
+	}
+	if len(shapes) == 0 {
+		return entity.GeoJsonPolygon{}, fmt.Errorf("%w: в area нет polygon или circle", ErrInvalidCapMessage)
+	}
+
+	area := shapes[0]
+	for _, shape := range shapes[1:] {
+		result, err := s.geometryRepo.Union(ctx, area, shape)
+		if err != nil {
+			slog.Error("не удалось объединить зоны сообщения CAP", "error", err)
+			if errors.Is(err, postgres.ErrGeometryNotPolygon) || errors.Is(err, postgres.ErrGeometryEmpty) {
+				return entity.GeoJsonPolygon{}, fmt.Errorf("%w: зоны area не образуют один полигон", ErrInvalidCapMessage)
+			}
+			return entity.GeoJsonPolygon{}, fmt.Errorf("не удалось объединить зоны сообщения CAP: %w", err)
+		}
+		area = result.Area
+	}
+
+	return area, nil
+}
+
+// cancel завершает опубликованный инцидент, а неопубликованный деактивирует
+func (s *CapServiceImpl) cancel(ctx context.Context, resp *entity.CapIngestResponse, incidentID uuid.UUID, reason, actor string) error {
+	incident, err := s.incidentRepo.FindByID(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось найти инцидент сообщения CAP", "id", incidentID, "error", err)
+		return fmt.Errorf("не удалось найти инцидент сообщения CAP: %w", err)
+	}
+
+	resp.IncidentID = incidentID.String()
+	resp.Action = entity.CapActionCancelled
+
+	switch incident.Status {
+	case entity.IncidentStatusResolved:
+		resp.Action = entity.CapActionIgnored
+		resp.Reason = "инцидент уже завершен"
+		return nil
+	case entity.IncidentStatusPublished:
+		_, err = s.incidents.Resolve(ctx, incidentID.String(), &entity.IncidentTransitionRequest{Reason: reason, Actor: actor})
+	default:
+		_, err = s.incidents.Delete(ctx, incidentID.String(), &entity.DeleteIncidentRequest{Reason: reason, Actor: actor})
+	}
+
+	return err
+}
+
+// findReferenced возвращает самое позднее из принятых сообщений, перечисленных в references
+func (s *CapServiceImpl) findReferenced(ctx context.Context, refs []cap.Reference) (*entity.CapMessage, error) {
+	var latest *entity.CapMessage
+	for _, ref := range refs {
+		m, err := s.repo.FindMessage(ctx, ref.Sender, ref.Identifier)
+		if errors.Is(err, postgres.ErrCapMessageNotFound) {
+			continue
+		}
+		if err != nil {
+			slog.Error("не удалось найти сообщение CAP из references", "identifier", ref.Identifier, "error", err)
+			return nil, fmt.Errorf("не удалось найти сообщение CAP из references: %w", err)
+		}
+		if latest == nil || m.Sent.After(latest.Sent) {
+			latest = m
+		}
+	}
+	return latest, nil
+}
+
+// Feed формирует ленту Atom с сообщением CAP для каждого опубликованного инцидента. baseURL —
+// абсолютный адрес группы маршрутов CAP, от него строятся ссылки ленты и документов.
+// LastModified ленты — последнее изменение любого инцидента, поэтому завершение или
+// деактивация инцидента тоже меняет ленту.
+func (s *CapServiceImpl) Feed(ctx context.Context, baseURL string) (*entity.CapDocument, error) {
+	incidents, err := s.incidentRepo.FindPublished(ctx)
+	if err != nil {
+		slog.Error("не удалось найти опубликованные инциденты", "error", err)
+		return nil, fmt.Errorf("не удалось найти опубликованные инциденты: %w", err)
+	}
+
+	lastModified, err := s.incidentRepo.LastModified(ctx)
+	if err != nil {
+		slog.Error("не удалось определить время изменения инцидентов", "error", err)
+		return nil, fmt.Errorf("не удалось определить время изменения инцидентов: %w", err)
+	}
+
+	sender := s.sender()
+	feedURL := baseURL + "/feed"
+	feed := cap.Feed{
+		Xmlns:   cap.AtomNamespace,
+		ID:      feedURL,
+		Title:   "Активные инциденты",
+		Updated: lastModified.UTC().Format(time.RFC3339),
+		Author:  cap.Author{Name: sender},
+		Links:   []cap.Link{{Rel: "self", Type: cap.AtomContentType, Href: feedURL}},
+		Entries: make([]cap.Entry, 0, len(incidents)),
+	}
+
+	tag := []string{baseURL, lastModified.UTC().Format(time.RFC3339Nano)}
+	for _, incident := range incidents {
+		docURL := baseURL + "/incidents/" + incident.ID.String()
+		feed.Entries = append(feed.Entries, cap.Entry{
+			ID:      "urn:uuid:" + incident.ID.String(),
+			Title:   incident.Name,
+			Updated: incident.UpdatedAt.UTC().Format(time.RFC3339),
+			Summary: incident.Description,
+			Links:   []cap.Link{{Rel: "alternate", Type: cap.ContentType, Href: docURL}},
+			Content: cap.Content{Type: cap.ContentType, Alert: cap.FromIncident(incident, sender, docURL)},
+		})
+		tag = append(tag, incident.ID.String(), strconv.FormatInt(incident.UpdatedAt.UnixMicro(), 10))
+	}
+
+	return renderCapDocument(feed, cap.AtomContentType, lastModified, tag)
+}
+
+// Document формирует сообщение CAP об опубликованном инциденте. Для черновиков, завершенных
+// и деактивированных инцидентов возвращается ErrCapDocumentNotFound.
+func (s *CapServiceImpl) Document(ctx context.Context, id, baseURL string) (*entity.CapDocument, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCapDocumentNotFound, id)
+	}
+
+	incident, err := s.incidentRepo.FindByID(ctx, incidentID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrIncidentNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrCapDocumentNotFound, id)
+		}
+		slog.Error("не удалось найти инцидент", "id", id, "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
+	}
+	if incident.Status != entity.IncidentStatusPublished || !incident.IsActive {
+		return nil, fmt.Errorf("%w: %s", ErrCapDocumentNotFound, id)
+	}
+
+	docURL := baseURL + "/incidents/" + incident.ID.String()
+	alert := cap.FromIncident(incident, s.sender(), docURL)
+
+	return renderCapDocument(alert, cap.ContentType, incident.UpdatedAt,
+		[]string{baseURL, incident.ID.String(), strconv.FormatInt(incident.UpdatedAt.UnixMicro(), 10)})
+}
+
+func (s *CapServiceImpl) sender() string {
+	if s.cfg.Incident.CapSender != "" {
+		return s.cfg.Incident.CapSender
+	}
+	return defaultCapSender
+}
+
+// renderCapDocument сериализует документ и вычисляет ETag из значений tag: адреса
+// и updated_at инцидентов. Тело определяется этими значениями, поэтому ETag строгий.
+func renderCapDocument(doc any, contentType string, lastModified time.Time, tag []string) (*entity.CapDocument, error) {
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		slog.Error("не удалось сформировать документ CAP", "error", err)
+		return nil, fmt.Errorf("не удалось сформировать документ CAP: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(tag, "\n")))
+
+	return &entity.CapDocument{
+		Body:         append([]byte(xml.Header), body...),
+		ContentType:  contentType,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastModified,
+	}, nil
+}
+
+// pickInfo выбирает первый блок info с зоной polygon или circle, иначе первый блок
+func pickInfo(alert *cap.Alert) *cap.Info {
+	for i := range alert.Infos {
+		for _, area := range alert.Infos[i].Areas {
+			if len(area.Polygons) > 0 || len(area.Circles) > 0 {
+				return &alert.Infos[i]
+			}
+		}
+	}
+	if len(alert.Infos) > 0 {
+		return &alert.Infos[0]
+	}
+	return nil
+}
+
+// describeInfo переносит метаданные info в ответ и сохраняемое сообщение
+func describeInfo(resp *entity.CapIngestResponse, message *entity.CapMessage, info *cap.Info) error {
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   **time.Time
+	}{
+		{"onset", info.Onset, &message.Onset},
+		{"expires", info.Expires, &message.Expires},
+	} {
+		if field.value == "" {
+			continue
+		}
+		t, err := cap.ParseTime(field.value)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidCapMessage, field.name, err)
+		}
+		*field.dst = &t
+	}
+
+	message.Event = info.Event
+	message.Urgency = info.Urgency
+	message.Severity = info.Severity
+	message.Certainty = info.Certainty
+
+	resp.Event = info.Event
+	resp.Urgency = info.Urgency
+	resp.Severity = info.Severity
+	resp.Certainty = info.Certainty
+	resp.Onset = message.Onset
+	resp.Expires = message.Expires
+
+	return nil
+}
+
+func ignore(resp *entity.CapIngestResponse, reason string) *entity.CapIngestResponse {
+	resp.Action = entity.CapActionIgnored
+	resp.Reason = reason
+	return resp
+}
+
+func capExternalID(sender, identifier string) string {
+	return "cap:" + sender + ":" + identifier
+}
+
+// capName берет headline, затем event; название обрезается до допустимой длины
+func capName(alert *cap.Alert, info *cap.Info) string {
+	name := strings.TrimSpace(info.Headline)
+	if name == "" {
+		name = strings.TrimSpace(info.Event)
+	}
+	if name == "" {
+		name = alert.Identifier
+	}
+	return truncateRunes(name, 255)
+}
+
+// capDescription объединяет description и instruction
+func capDescription(info *cap.Info) string {
+	var parts []string
+	for _, s := range []string{info.Description, info.Instruction} {
+		if s = strings.TrimSpace(s); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return truncateRunes(strings.Join(parts, "\n\n"), 1000)
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
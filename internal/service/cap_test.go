@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/cap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func capAlert(t *testing.T, identifier, status, msgType, references, areas string) *cap.Alert {
+	t.Helper()
+
+	data := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">
+  <identifier>%s</identifier>
+  <sender>mchs@example.org</sender>
+  <sent>2026-10-18T10:00:00+03:00</sent>
+  <status>%s</status>
+  <msgType>%s</msgType>
+  <scope>Public</scope>
+  <references>%s</references>
+  <info>
+    <category>Met</category>
+    <event>Наводнение</event>
+    <urgency>Immediate</urgency>
+    <severity>Severe</severity>
+    <certainty>Observed</certainty>
+    <expires>%s</expires>
+    <headline>Подтопление поймы</headline>
+    <description>Уровень воды растет</description>
+    <instruction>Покиньте зону</instruction>
+    <area><areaDesc>Пойма</areaDesc>%s</area>
+  </info>
+</alert>`, identifier, status, msgType, references, time.Now().Add(time.Hour).Format(time.RFC3339), areas)
+
+	alert, err := cap.Parse([]byte(data))
+	require.NoError(t, err)
+	return alert
+}
+
+// capMessage сопоставляет заявку на сообщение с указанным идентификатором
+func capMessage(identifier string) any {
+	return mock.MatchedBy(func(m *entity.CapMessage) bool {
+		return m.Sender == "mchs@example.org" && m.Identifier == identifier
+	})
+}
+
+func TestCapService_Ingest(t *testing.T) {
+	const polygon = `<polygon>55.0,37.0 55.0,37.1 55.1,37.1 55.1,37.0 55.0,37.0</polygon>`
+
+	incidentID := uuid.New()
+	linked := &entity.CapMessage{Sender: "mchs@example.org", Identifier: "a-1", MsgType: cap.MsgTypeAlert, IncidentID: incidentID}
+
+	tests := []struct {
+		name       string
+		alert      func(t *testing.T) *cap.Alert
+		setup      func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, geometryRepo *mocks.GeometryRepo)
+		wantAction string
+		wantSaved  bool
+		wantErr    error
+	}{
+		{
+			name: "Alert creates a draft",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-1", "Actual", "Alert", "", polygon)
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-1")).Return(true, nil)
+				incidentRepo.On("FindByExternalIDs", mock.Anything, []string{"cap:mchs@example.org:a-1"}).Return(map[string]*entity.Incident{}, nil)
+				incidentRepo.On("FindOverlaps", mock.Anything, mock.Anything, (*uuid.UUID)(nil)).Return(nil, nil)
+				incidentRepo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(incidents []*entity.Incident) bool {
+					i := incidents[0]
+					return i.ExternalID == "cap:mchs@example.org:a-1" && i.Name == "Подтопление поймы" &&
+						i.Severity == entity.IncidentSeveritySevere && i.Description == "Уровень воды растет\n\nПокиньте зону" &&
+						i.Area.Coordinates[0][0][0] == 37.0 && i.ChangeReason == "CAP Alert a-1"
+				})).Return(func(_ context.Context, incidents []*entity.Incident) ([]bool, error) {
+					incidents[0].ID = incidentID
+					return []bool{true}, nil
+				})
+			},
+			wantAction: entity.CapActionCreated,
+			wantSaved:  true,
+		},
+		{
+			name: "Polygon and circle are united",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-1", "Actual", "Alert", "", polygon+`<circle>55.1,37.1 2</circle>`)
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, geometryRepo *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-1")).Return(true, nil)
+				geometryRepo.On("Union", mock.Anything, mock.Anything, mock.Anything).Return(&entity.GeometryResult{
+					Area: entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{{{37, 55}, {37.2, 55}, {37.2, 55.2}, {37, 55}}}},
+				}, nil)
+				incidentRepo.On("FindByExternalIDs", mock.Anything, []string{"cap:mchs@example.org:a-1"}).Return(map[string]*entity.Incident{}, nil)
+				incidentRepo.On("FindOverlaps", mock.Anything, mock.Anything, (*uuid.UUID)(nil)).Return(nil, nil)
+				incidentRepo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(incidents []*entity.Incident) bool {
+					return incidents[0].Area.Coordinates[0][1][0] == 37.2
+				})).Return(func(_ context.Context, incidents []*entity.Incident) ([]bool, error) {
+					incidents[0].ID = incidentID
+					return []bool{true}, nil
+				})
+			},
+			wantAction: entity.CapActionCreated,
+			wantSaved:  true,
+		},
+		{
+			name: "Update changes the referenced incident",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-2", "Actual", "Update", "mchs@example.org,a-1,2026-10-18T09:00:00+03:00", polygon)
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-2")).Return(true, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(linked, nil)
+				incidentRepo.On("FindByID", mock.Anything, incidentID).Return(&entity.Incident{
+					ID: incidentID, ExternalID: "cap:mchs@example.org:a-1", Status: entity.IncidentStatusPublished,
+				}, nil)
+				incidentRepo.On("FindByExternalIDs", mock.Anything, []string{"cap:mchs@example.org:a-1"}).Return(map[string]*entity.Incident{
+					"cap:mchs@example.org:a-1": {ID: incidentID, ExternalID: "cap:mchs@example.org:a-1", Status: entity.IncidentStatusPublished},
+				}, nil)
+				incidentRepo.On("FindOverlaps", mock.Anything, mock.Anything, &incidentID).Return(nil, nil)
+				incidentRepo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(incidents []*entity.Incident) bool {
+					return incidents[0].ExternalID == "cap:mchs@example.org:a-1"
+				})).Return(func(_ context.Context, incidents []*entity.Incident) ([]bool, error) {
+					incidents[0].ID = incidentID
+					incidents[0].Status = entity.IncidentStatusPendingReview
+					return []bool{false}, nil
+				})
+			},
+			wantAction: entity.CapActionUpdated,
+			wantSaved:  true,
+		},
+		{
+			name: "Update of a resolved incident is rejected",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-2", "Actual", "Update", "mchs@example.org,a-1,2026-10-18T09:00:00+03:00", polygon)
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-2")).Return(true, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(linked, nil)
+				incidentRepo.On("FindByID", mock.Anything, incidentID).Return(&entity.Incident{
+					ID: incidentID, ExternalID: "cap:mchs@example.org:a-1", Status: entity.IncidentStatusPublished,
+				}, nil)
+				incidentRepo.On("FindByExternalIDs", mock.Anything, []string{"cap:mchs@example.org:a-1"}).Return(map[string]*entity.Incident{
+					"cap:mchs@example.org:a-1": {ID: incidentID, ExternalID: "cap:mchs@example.org:a-1", Status: entity.IncidentStatusResolved},
+				}, nil)
+				capRepo.On("ReleaseMessage", mock.Anything, "mchs@example.org", "a-2").Return(nil)
+			},
+			wantErr: ErrInvalidCapMessage,
+		},
+		{
+			name: "Cancel resolves a published incident",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-3", "Actual", "Cancel", "mchs@example.org,a-1,2026-10-18T09:00:00+03:00", "")
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-3")).Return(true, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(linked, nil)
+				incidentRepo.On("FindByID", mock.Anything, incidentID).Return(&entity.Incident{
+					ID: incidentID, Status: entity.IncidentStatusPublished,
+				}, nil)
+				incidentRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(i *entity.Incident) bool { return i.ID == incidentID }), entity.IncidentStatusResolved,
+					entity.IncidentOperationResolve, "operator", "CAP Cancel a-3").Return(nil, nil)
+			},
+			wantAction: entity.CapActionCancelled,
+			wantSaved:  true,
+		},
+		{
+			name: "Cancel deactivates a draft",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-3", "Actual", "Cancel", "mchs@example.org,a-1,2026-10-18T09:00:00+03:00", "")
+			},
+			setup: func(capRepo *mocks.CapRepo, incidentRepo *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-3")).Return(true, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(linked, nil)
+				incidentRepo.On("FindByID", mock.Anything, incidentID).Return(&entity.Incident{
+					ID: incidentID, Status: entity.IncidentStatusDraft,
+				}, nil)
+				incidentRepo.On("Delete", mock.Anything, incidentID, "operator", "CAP Cancel a-3").Return(nil, nil)
+			},
+			wantAction: entity.CapActionCancelled,
+			wantSaved:  true,
+		},
+		{
+			name: "Cancel without known references is ignored",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-3", "Actual", "Cancel", "mchs@example.org,a-0,2026-10-18T09:00:00+03:00", "")
+			},
+			setup: func(capRepo *mocks.CapRepo, _ *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, capMessage("a-3")).Return(true, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-0").Return(nil, postgres.ErrCapMessageNotFound)
+				capRepo.On("ReleaseMessage", mock.Anything, "mchs@example.org", "a-3").Return(nil)
+			},
+			wantAction: entity.CapActionIgnored,
+		},
+		{
+			name: "Exercise is ignored",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-1", "Exercise", "Alert", "", polygon)
+			},
+			wantAction: entity.CapActionIgnored,
+		},
+		{
+			name: "Redelivery is a duplicate",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-1", "Actual", "Alert", "", polygon)
+			},
+			setup: func(capRepo *mocks.CapRepo, _ *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, mock.Anything).Return(false, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(linked, nil)
+			},
+			wantAction: entity.CapActionDuplicate,
+		},
+		{
+			name: "Concurrent delivery is a conflict",
+			alert: func(t *testing.T) *cap.Alert {
+				return capAlert(t, "a-1", "Actual", "Alert", "", polygon)
+			},
+			setup: func(capRepo *mocks.CapRepo, _ *mocks.IncidentRepo, _ *mocks.GeometryRepo) {
+				capRepo.On("ClaimMessage", mock.Anything, mock.Anything).Return(false, nil)
+				capRepo.On("FindMessage", mock.Anything, "mchs@example.org", "a-1").Return(nil, postgres.ErrCapMessageNotFound)
+			},
+			wantErr: ErrCapMessageInProgress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capRepo := mocks.NewCapRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			geometryRepo := mocks.NewGeometryRepo(t)
+			if tt.setup != nil {
+				tt.setup(capRepo, incidentRepo, geometryRepo)
+			}
+			if tt.wantSaved {
+				capRepo.On("SaveMessage", mock.Anything, mock.MatchedBy(func(m *entity.CapMessage) bool {
+					return m.IncidentID == incidentID && m.Sender == "mchs@example.org"
+				})).Return(nil)
+			}
+
+			incidents := NewIncidentService(incidentRepo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+			s := NewCapService(capRepo, incidentRepo, geometryRepo, incidents, &config.Config{})
+
+			got, err := s.Ingest(context.Background(), tt.alert(t), "operator")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantAction, got.Action)
+			assert.Equal(t, tt.wantAction == entity.CapActionIgnored, got.Reason != "")
+			if tt.wantSaved || tt.wantAction == entity.CapActionDuplicate {
+				assert.Equal(t, incidentID.String(), got.IncidentID)
+			}
+		})
+	}
+}
+
+func TestCapService_IngestInvalid(t *testing.T) {
+	capRepo := mocks.NewCapRepo(t)
+	capRepo.On("ClaimMessage", mock.Anything, capMessage("a-1")).Return(true, nil)
+	capRepo.On("ReleaseMessage", mock.Anything, "mchs@example.org", "a-1").Return(nil)
+
+	incidentRepo := mocks.NewIncidentRepo(t)
+	incidents := NewIncidentService(incidentRepo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+	s := NewCapService(capRepo, incidentRepo, mocks.NewGeometryRepo(t), incidents, &config.Config{})
+
+	_, err := s.Ingest(context.Background(), capAlert(t, "a-1", "Actual", "Alert", "", ""), "operator")
+	assert.ErrorIs(t, err, ErrInvalidCapMessage)
+}
+
+func TestCapService_Feed(t *testing.T) {
+	updatedAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	incident := &entity.Incident{
+		ID:          uuid.New(),
+		Name:        "Пожар",
+		Description: "Горит склад",
+		Status:      entity.IncidentStatusPublished,
+		IsActive:    true,
+		Severity:    entity.IncidentSeveritySevere,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{37, 55}, {37.1, 55}, {37.1, 55.1}, {37, 55}}},
+		},
+		UpdatedAt: updatedAt,
+	}
+
+	feed := func(t *testing.T, incidents []*entity.Incident, lastModified time.Time) *entity.CapDocument {
+		repo := mocks.NewIncidentRepo(t)
+		repo.On("FindPublished", mock.Anything).Return(incidents, nil)
+		repo.On("LastModified", mock.Anything).Return(lastModified, nil)
+
+		s := NewCapService(mocks.NewCapRepo(t), repo, mocks.NewGeometryRepo(t), nil, &config.Config{})
+		doc, err := s.Feed(context.Background(), "https://geo.example.org/api/v1/cap")
+		require.NoError(t, err)
+		return doc
+	}
+
+	doc := feed(t, []*entity.Incident{incident}, updatedAt)
+	assert.Equal(t, cap.AtomContentType, doc.ContentType)
+	assert.Equal(t, updatedAt, doc.LastModified)
+
+	var parsed cap.Feed
+	require.NoError(t, xml.Unmarshal(doc.Body, &parsed))
+	require.Len(t, parsed.Entries, 1)
+
+	entry := parsed.Entries[0]
+	assert.Equal(t, "urn:uuid:"+incident.ID.String(), entry.ID)
+	assert.Equal(t, "https://geo.example.org/api/v1/cap/incidents/"+incident.ID.String(), entry.Links[0].Href)
+
+	alert := entry.Content.Alert
+	require.NotNil(t, alert)
+	assert.Equal(t, "geo-incident-service", alert.Sender)
+	assert.Equal(t, "2026-10-18T12:00:00+00:00", alert.Sent)
+	assert.Equal(t, "Severe", alert.Infos[0].Severity)
+
+	area, err := cap.ParsePolygon(alert.Infos[0].Areas[0].Polygons[0])
+	require.NoError(t, err)
+	assert.Equal(t, incident.Area, area)
+
+	// ETag повторяется для того же состояния и меняется вместе с составом ленты и updated_at
+	assert.Equal(t, doc.ETag, feed(t, []*entity.Incident{incident}, updatedAt).ETag)
+	assert.NotEqual(t, doc.ETag, feed(t, nil, updatedAt.Add(time.Minute)).ETag)
+
+	changed := *incident
+	changed.UpdatedAt = updatedAt.Add(time.Minute)
+	assert.NotEqual(t, doc.ETag, feed(t, []*entity.Incident{&changed}, changed.UpdatedAt).ETag)
+}
+
+func TestCapService_Document(t *testing.T) {
+	published := &entity.Incident{
+		ID:        uuid.New(),
+		Name:      "Пожар",
+		Status:    entity.IncidentStatusPublished,
+		IsActive:  true,
+		Area:      entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{{{37, 55}, {37.1, 55}, {37.1, 55.1}, {37, 55}}}},
+		UpdatedAt: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	}
+	draft := &entity.Incident{ID: uuid.New(), Status: entity.IncidentStatusDraft}
+	resolved := &entity.Incident{ID: uuid.New(), Status: entity.IncidentStatusResolved}
+	missing := uuid.New()
+
+	repo := mocks.NewIncidentRepo(t)
+	repo.On("FindByID", mock.Anything, published.ID).Return(published, nil)
+	repo.On("FindByID", mock.Anything, draft.ID).Return(draft, nil)
+	repo.On("FindByID", mock.Anything, resolved.ID).Return(resolved, nil)
+	repo.On("FindByID", mock.Anything, missing).Return(nil, postgres.ErrIncidentNotFound)
+
+	s := NewCapService(mocks.NewCapRepo(t), repo, mocks.NewGeometryRepo(t), nil, &config.Config{
+		Incident: config.IncidentConfig{CapSender: "alerts@geo.example.org"},
+	})
+
+	doc, err := s.Document(context.Background(), published.ID.String(), "https://geo.example.org/api/v1/cap")
+	require.NoError(t, err)
+	assert.Equal(t, cap.ContentType, doc.ContentType)
+	assert.Equal(t, published.UpdatedAt, doc.LastModified)
+	assert.NotEmpty(t, doc.ETag)
+
+	alert, err := cap.Parse(doc.Body)
+	require.NoError(t, err)
+	assert.Equal(t, cap.Namespace, alert.Xmlns)
+	assert.Equal(t, "alerts@geo.example.org", alert.Sender)
+	assert.Equal(t, cap.Identifier(published), alert.Identifier)
+	assert.Equal(t, "Unknown", alert.Infos[0].Severity)
+
+	for _, id := range []string{draft.ID.String(), resolved.ID.String(), missing.String(), "not-a-uuid"} {
+		_, err := s.Document(context.Background(), id, "https://geo.example.org/api/v1/cap")
+		assert.ErrorIs(t, err, ErrCapDocumentNotFound, id)
+	}
+}
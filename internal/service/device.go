@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/signature"
+)
+
+const (
+	deviceSecretBytes     = 32
+	defaultSignatureSkew  = 5 * time.Minute
+	deviceNonceKeyPattern = "device:nonce:%s:%s"
+)
+
+var (
+	ErrSignatureMissing   = errors.New("запрос не подписан")
+	ErrSignatureInvalid   = errors.New("некорректная подпись запроса")
+	ErrSignatureExpired   = errors.New("метка времени запроса вне допустимого окна")
+	ErrNonceReused        = errors.New("nonce уже использован")
+	ErrDeviceInactive     = errors.New("устройство не зарегистрировано или отозвано")
+	ErrDeviceUserMismatch = errors.New("устройство не может действовать от имени другого пользователя")
+
+	ErrInvalidDeviceID      = errors.New("некорректный id устройства")
+	ErrDeviceNotFound       = errors.New("устройство не найдено")
+	ErrDeviceAlreadyRevoked = errors.New("устройство уже отозвано")
+)
+
+type DeviceService interface {
+	Register(ctx context.Context, req *entity.RegisterDeviceRequest) (*entity.RegisterDeviceResponse, error)
+	FindAll(ctx context.Context, limit, offset int) ([]*entity.GetDeviceResponse, error)
+	Revoke(ctx context.Context, id string) error
+	VerifyRequest(ctx context.Context, req *entity.SignedRequest) (*entity.Device, error)
+	FindActive(ctx context.Context, id string) (*entity.Device, error)
+	FindByTrackerToken(ctx context.Context, token string) (*entity.Device, error)
+}
+
+type DeviceServiceImpl struct {
+	repo  postgres.DeviceRepo
+	redis *db.Redis
+	cfg   *config.Config
+}
+
+func NewDeviceService(repo postgres.DeviceRepo, redis *db.Redis, cfg *config.Config) DeviceService {
+	return &DeviceServiceImpl{repo: repo, redis: redis, cfg: cfg}
+}
+
+func (s *DeviceServiceImpl) Register(ctx context.Context, req *entity.RegisterDeviceRequest) (*entity.RegisterDeviceResponse, error) {
+	secretBytes := make([]byte, deviceSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		slog.Error("не удалось сгенерировать секрет устройства", "error", err)
+		return nil, fmt.Errorf("не удалось сгенерировать секрет устройства: %w", err)
+	}
+
+	tokenBytes := make([]byte, deviceSecretBytes)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		slog.Error("не удалось сгенерировать токен трекера", "error", err)
+		return nil, fmt.Errorf("не удалось сгенерировать токен трекера: %w", err)
+	}
+	trackerToken := hex.EncodeToString(tokenBytes)
+
+	device := &entity.Device{
+		UserID:           req.UserID,
+		Name:             req.Name,
+		Secret:           hex.EncodeToString(secretBytes),
+		TrackerTokenHash: hashTrackerToken(trackerToken),
+		IsActive:         true,
+	}
+
+	if err := s.repo.Create(ctx, device); err != nil {
+		slog.Error("не удалось зарегистрировать устройство", "error", err)
+		return nil, fmt.Errorf("не удалось зарегистрировать устройство: %w", err)
+	}
+
+	return &entity.RegisterDeviceResponse{
+		ID:           device.ID.String(),
+		UserID:       device.UserID,
+		Secret:       device.Secret,
+		TrackerToken: trackerToken,
+	}, nil
+}
+
+func (s *DeviceServiceImpl) FindAll(ctx context.Context, limit, offset int) ([]*entity.GetDeviceResponse, error) {
+	devices, err := s.repo.FindAll(ctx, limit, offset)
+	if err != nil {
+		slog.Error("не удалось найти устройства", "error", err)
+		return nil, fmt.Errorf("не удалось найти устройства: %w", err)
+	}
+
+	var resp []*entity.GetDeviceResponse
+	for _, d := range devices {
+		resp = append(resp, &entity.GetDeviceResponse{
+			ID:        d.ID.String(),
+			UserID:    d.UserID,
+			Name:      d.Name,
+			IsActive:  d.IsActive,
+			CreatedAt: d.CreatedAt,
+			RevokedAt: d.RevokedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *DeviceServiceImpl) Revoke(ctx context.Context, id string) error {
+	deviceID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return fmt.Errorf("%w: %w", ErrInvalidDeviceID, err)
+	}
+
+	if err := s.repo.Revoke(ctx, deviceID); err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrDeviceNotFound):
+			return fmt.Errorf("%w: %s", ErrDeviceNotFound, id)
+		case errors.Is(err, postgres.ErrDeviceAlreadyRevoked):
+			return fmt.Errorf("%w: %s", ErrDeviceAlreadyRevoked, id)
+		}
+		slog.Error("не удалось отозвать устройство", "error", err)
+		return fmt.Errorf("не удалось отозвать устройство: %w", err)
+	}
+
+	return nil
+}
+
+// FindActive возвращает активное устройство по идентификатору
+func (s *DeviceServiceImpl) FindActive(ctx context.Context, id string) (*entity.Device, error) {
+	deviceID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeviceInactive, err)
+	}
+
+	device, err := s.repo.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeviceInactive, err)
+	}
+
+	if !device.IsActive {
+		return nil, ErrDeviceInactive
+	}
+
+	return device, nil
+}
+
+// FindByTrackerToken возвращает активное устройство по токену трекера. Трекеры OsmAnd/Traccar
+// не умеют подписывать запросы, поэтому вместо подписи передают в id секретный токен.
+func (s *DeviceServiceImpl) FindByTrackerToken(ctx context.Context, token string) (*entity.Device, error) {
+	if token == "" {
+		return nil, ErrDeviceInactive
+	}
+
+	device, err := s.repo.FindByTrackerToken(ctx, hashTrackerToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeviceInactive, err)
+	}
+
+	if !device.IsActive {
+		return nil, ErrDeviceInactive
+	}
+
+	return device, nil
+}
+
+// VerifyRequest проверяет подпись запроса устройства: метку времени, подпись HMAC
+// и одноразовость nonce. Возвращает устройство, от имени которого подписан запрос.
+func (s *DeviceServiceImpl) VerifyRequest(ctx context.Context, req *entity.SignedRequest) (*entity.Device, error) {
+	if req.DeviceID == "" || req.Timestamp == "" || req.Nonce == "" || req.Signature == "" {
+		return nil, ErrSignatureMissing
+	}
+
+	deviceID, err := uuid.Parse(req.DeviceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeviceInactive, err)
+	}
+
+	unixTime, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSignatureExpired, err)
+	}
+
+	maxSkew := s.cfg.HTTPServer.DeviceSignatureMaxSkew
+	if maxSkew <= 0 {
+		maxSkew = defaultSignatureSkew
+	}
+
+	skew := time.Since(time.Unix(unixTime, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return nil, ErrSignatureExpired
+	}
+
+	device, err := s.repo.FindByID(ctx, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDeviceInactive, err)
+	}
+
+	if !device.IsActive {
+		return nil, ErrDeviceInactive
+	}
+
+	if !signature.Verify(device.Secret, req.Method, req.Path, req.Timestamp, req.Nonce, req.Body, req.Signature) {
+		return nil, ErrSignatureInvalid
+	}
+
+	// Nonce запоминается только после проверки подписи, чтобы чужие запросы не могли его занять
+	nonceKey := fmt.Sprintf(deviceNonceKeyPattern, device.ID, req.Nonce)
+	fresh, err := s.redis.Client.SetNX(ctx, nonceKey, 1, 2*maxSkew).Result()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки nonce: %w", err)
+	}
+
+	if !fresh {
+		return nil, ErrNonceReused
+	}
+
+	return device, nil
+}
+
+func hashTrackerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
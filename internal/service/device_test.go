@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceService_VerifyRequest(t *testing.T) {
+	device := &entity.Device{
+		ID:       uuid.New(),
+		UserID:   uuid.NewString(),
+		Secret:   "secret",
+		IsActive: true,
+	}
+	body := []byte(`{"user_id":"` + device.UserID + `"}`)
+
+	signed := func(secret string, ts time.Time, nonce string) *entity.SignedRequest {
+		timestamp := strconv.FormatInt(ts.Unix(), 10)
+		return &entity.SignedRequest{
+			DeviceID:  device.ID.String(),
+			Timestamp: timestamp,
+			Nonce:     nonce,
+			Signature: signature.Sign(secret, "POST", "/api/v1/location/check", timestamp, nonce, body),
+			Method:    "POST",
+			Path:      "/api/v1/location/check",
+			Body:      body,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     *entity.SignedRequest
+		mock    func(r *mocks.DeviceRepo)
+		replay  bool
+		wantErr error
+	}{
+		{
+			name: "Valid signature",
+			req:  signed("secret", time.Now(), "n1"),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByID", mock.Anything, device.ID).Return(device, nil)
+			},
+		},
+		{
+			name:    "Missing headers",
+			req:     &entity.SignedRequest{Method: "POST", Path: "/", Body: body},
+			mock:    func(r *mocks.DeviceRepo) {},
+			wantErr: ErrSignatureMissing,
+		},
+		{
+			name:    "Stale timestamp",
+			req:     signed("secret", time.Now().Add(-time.Hour), "n2"),
+			mock:    func(r *mocks.DeviceRepo) {},
+			wantErr: ErrSignatureExpired,
+		},
+		{
+			name: "Wrong secret",
+			req:  signed("other", time.Now(), "n3"),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByID", mock.Anything, device.ID).Return(device, nil)
+			},
+			wantErr: ErrSignatureInvalid,
+		},
+		{
+			name: "Replayed nonce",
+			req:  signed("secret", time.Now(), "n4"),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByID", mock.Anything, device.ID).Return(device, nil)
+			},
+			replay:  true,
+			wantErr: ErrNonceReused,
+		},
+		{
+			name: "Unknown device",
+			req:  signed("secret", time.Now(), "n5"),
+			mock: func(r *mocks.DeviceRepo) {
+				r.On("FindByID", mock.Anything, device.ID).Return(nil, errors.New("устройство не найдено"))
+			},
+			wantErr: ErrDeviceInactive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewDeviceRepo(t)
+			tt.mock(repo)
+
+			s := NewDeviceService(repo, newTestRedis(t), &config.Config{})
+
+			if tt.replay {
+				_, err := s.VerifyRequest(context.Background(), tt.req)
+				require.NoError(t, err)
+			}
+
+			got, err := s.VerifyRequest(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, device.ID, got.ID)
+		})
+	}
+}
+
+func TestDeviceService_Revoke(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		id      string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Revoked", id: id.String()},
+		{name: "Malformed id", id: "abc", wantErr: ErrInvalidDeviceID},
+		{name: "Unknown device", id: id.String(), repoErr: postgres.ErrDeviceNotFound, wantErr: ErrDeviceNotFound},
+		{name: "Already revoked", id: id.String(), repoErr: postgres.ErrDeviceAlreadyRevoked, wantErr: ErrDeviceAlreadyRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewDeviceRepo(t)
+			if tt.id == id.String() {
+				repo.On("Revoke", mock.Anything, id).Return(tt.repoErr)
+			}
+
+			s := NewDeviceService(repo, newTestRedis(t), &config.Config{})
+
+			err := s.Revoke(context.Background(), tt.id)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDeviceService_TrackerToken(t *testing.T) {
+	repo := mocks.NewDeviceRepo(t)
+	s := NewDeviceService(repo, newTestRedis(t), &config.Config{})
+
+	var stored *entity.Device
+	repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.Device)
+		stored.ID = uuid.New()
+	}).Return(nil)
+
+	resp, err := s.Register(context.Background(), &entity.RegisterDeviceRequest{UserID: uuid.NewString()})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.TrackerToken)
+
+	// Токен хранится только в виде хеша и не совпадает с секретом подписи
+	assert.NotEqual(t, resp.TrackerToken, stored.TrackerTokenHash)
+	assert.NotEqual(t, resp.Secret, resp.TrackerToken)
+
+	repo.On("FindByTrackerToken", mock.Anything, stored.TrackerTokenHash).Return(stored, nil)
+	repo.On("FindByTrackerToken", mock.Anything, mock.Anything).Return(nil, postgres.ErrDeviceNotFound)
+
+	device, err := s.FindByTrackerToken(context.Background(), resp.TrackerToken)
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, device.ID)
+
+	_, err = s.FindByTrackerToken(context.Background(), stored.ID.String())
+	assert.ErrorIs(t, err, ErrDeviceInactive)
+
+	_, err = s.FindByTrackerToken(context.Background(), "")
+	assert.ErrorIs(t, err, ErrDeviceInactive)
+
+	stored.IsActive = false
+	_, err = s.FindByTrackerToken(context.Background(), resp.TrackerToken)
+	assert.ErrorIs(t, err, ErrDeviceInactive)
+}
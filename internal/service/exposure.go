@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
+)
+
+var ErrInvalidTrack = errors.New("некорректный трек")
+
+type ExposureService interface {
+	AnalyzeTrack(ctx context.Context, track *entity.Track) (*entity.ExposureReport, error)
+}
+
+type ExposureServiceImpl struct {
+	incidentRepo postgres.IncidentRepo
+}
+
+func NewExposureService(incidentRepo postgres.IncidentRepo) ExposureService {
+	return &ExposureServiceImpl{incidentRepo: incidentRepo}
+}
+
+// AnalyzeTrack строит отчет о зонах, через которые прошел записанный трек. Каждая точка
+// сверяется с зонами, действовавшими в момент ее записи. Проверки локаций не сохраняются.
+func (s *ExposureServiceImpl) AnalyzeTrack(ctx context.Context, track *entity.Track) (*entity.ExposureReport, error) {
+	if err := validator.ValidateTrack(track); err != nil {
+		slog.Error("ошибка валидации трека", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTrack, err)
+	}
+
+	report := &entity.ExposureReport{
+		TrackName:  track.Name,
+		PointCount: len(track.Points),
+		Exposures:  []*entity.Exposure{},
+	}
+
+	points := sortTrackPoints(track.Points)
+
+	for _, p := range points {
+		if p.Time == nil {
+			continue
+		}
+		if report.StartedAt == nil || p.Time.Before(*report.StartedAt) {
+			report.StartedAt = p.Time
+		}
+		if report.FinishedAt == nil || p.Time.After(*report.FinishedAt) {
+			report.FinishedAt = p.Time
+		}
+	}
+
+	// Точки без времени сверяются с текущим состоянием зон, поэтому период расширяется до now
+	now := time.Now()
+	from, to := now, now
+	if report.StartedAt != nil {
+		from = *report.StartedAt
+	}
+	if report.FinishedAt != nil && !hasTimelessPoints(points) {
+		to = *report.FinishedAt
+	}
+
+	versions, err := s.incidentRepo.FindVersionsInRange(ctx, from, to)
+	if err != nil {
+		slog.Error("не удалось получить версии инцидентов", "error", err)
+		return nil, fmt.Errorf("не удалось получить версии инцидентов: %w", err)
+	}
+
+	for _, tl := range groupTimelines(versions) {
+		report.Exposures = append(report.Exposures, trackExposures(tl, points)...)
+	}
+
+	sort.SliceStable(report.Exposures, func(i, j int) bool {
+		a, b := report.Exposures[i].EnteredAt, report.Exposures[j].EnteredAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	report.IsExposed = len(report.Exposures) > 0
+
+	return report, nil
+}
+
+// incidentTimeline — версии одного инцидента, упорядоченные по номеру
+type incidentTimeline []*entity.IncidentVersion
+
+// at возвращает активную версию зоны, действовавшую в момент t, или nil. Для точек без
+// времени берется текущая версия.
+func (tl incidentTimeline) at(t *time.Time) *entity.IncidentVersion {
+	for _, v := range tl {
+		var valid bool
+		if t == nil {
+			valid = v.ValidTo == nil
+		} else {
+			valid = !t.Before(v.ValidFrom) && (v.ValidTo == nil || t.Before(*v.ValidTo))
+		}
+
+		if valid {
+			if !v.IsActive {
+				return nil
+			}
+			return v
+		}
+	}
+
+	return nil
+}
+
+func groupTimelines(versions []*entity.IncidentVersion) []incidentTimeline {
+	var (
+		timelines []incidentTimeline
+		index     = make(map[uuid.UUID]int)
+	)
+
+	for _, v := range versions {
+		i, ok := index[v.IncidentID]
+		if !ok {
+			i = len(timelines)
+			index[v.IncidentID] = i
+			timelines = append(timelines, nil)
+		}
+		timelines[i] = append(timelines[i], v)
+	}
+
+	for _, tl := range timelines {
+		sort.Slice(tl, func(i, j int) bool { return tl[i].Version < tl[j].Version })
+	}
+
+	return timelines
+}
+
+// trackExposures возвращает интервалы пребывания трека в зоне инцидента и пересечения зоны
+// между соседними точками. Каждая точка сверяется с версией зоны, действовавшей в момент ее записи.
+func trackExposures(tl incidentTimeline, points []entity.TrackPoint) []*entity.Exposure {
+	var (
+		result  []*entity.Exposure
+		current *entity.Exposure
+	)
+
+	for i, p := range points {
+		loc := entity.UserLocation{Lat: p.Lat, Lon: p.Lon}
+
+		if v := tl.at(p.Time); v != nil && v.Area.Contains(p.Lat, p.Lon) {
+			if current == nil {
+				current = newExposure(v, p.Time, loc)
+				result = append(result, current)
+			}
+			current.ExitedAt = p.Time
+			current.ExitPoint = loc
+			current.PointsInside++
+			current.DurationSec = durationBetween(current.EnteredAt, current.ExitedAt)
+			continue
+		}
+
+		current = nil
+		if i == 0 {
+			continue
+		}
+
+		prev := points[i-1]
+		v := tl.at(prev.Time)
+		if v == nil {
+			v = tl.at(p.Time)
+		}
+		if v == nil {
+			continue
+		}
+
+		if v.Area.Contains(prev.Lat, prev.Lon) || v.Area.Contains(p.Lat, p.Lon) {
+			continue
+		}
+
+		if v.Area.IntersectsSegment(prev.Lat, prev.Lon, p.Lat, p.Lon) {
+			transit := newExposure(v, prev.Time, entity.UserLocation{Lat: prev.Lat, Lon: prev.Lon})
+			transit.Transit = true
+			transit.ExitedAt = p.Time
+			transit.ExitPoint = loc
+			transit.DurationSec = durationBetween(transit.EnteredAt, transit.ExitedAt)
+			result = append(result, transit)
+		}
+	}
+
+	return result
+}
+
+func newExposure(v *entity.IncidentVersion, at *time.Time, loc entity.UserLocation) *entity.Exposure {
+	return &entity.Exposure{
+		IncidentID:  v.IncidentID.String(),
+		Version:     v.Version,
+		Name:        v.Name,
+		Description: v.Description,
+		EnteredAt:   at,
+		EntryPoint:  loc,
+	}
+}
+
+// sortTrackPoints возвращает копию точек, упорядоченную по времени записи: файлы треков и
+// выгрузки трекеров не гарантируют порядок, а интервалы в зоне строятся по соседним точкам.
+// Точки без времени сверяются с текущим состоянием зон, поэтому идут последними в исходном порядке.
+func sortTrackPoints(points []entity.TrackPoint) []entity.TrackPoint {
+	sorted := slices.Clone(points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Time, sorted[j].Time
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.Before(*b)
+	})
+	return sorted
+}
+
+func hasTimelessPoints(points []entity.TrackPoint) bool {
+	for _, p := range points {
+		if p.Time == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func durationBetween(from, to *time.Time) float64 {
+	if from == nil || to == nil {
+		return 0
+	}
+	return to.Sub(*from).Seconds()
+}
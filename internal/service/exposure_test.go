@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExposureService_AnalyzeTrack(t *testing.T) {
+	base := time.Date(2026, 1, 18, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		ts := base.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+
+	// Трек заходит в зону на 10-й минуте и выходит после 20-й
+	walk := []entity.TrackPoint{
+		{Lat: 0.5, Lon: -0.5, Time: at(0)},
+		{Lat: 0.5, Lon: 0.2, Time: at(10)},
+		{Lat: 0.5, Lon: 0.8, Time: at(20)},
+		{Lat: 0.5, Lon: 1.5, Time: at(30)},
+	}
+
+	// Те же точки в произвольном порядке, как в выгрузке трекера после офлайн-периода
+	shuffled := []entity.TrackPoint{walk[2], walk[0], walk[3], walk[1]}
+
+	// Две точки по разные стороны зоны: внутрь не попала ни одна
+	jump := []entity.TrackPoint{
+		{Lat: 0.5, Lon: -0.5, Time: at(0)},
+		{Lat: 0.5, Lon: 1.5, Time: at(5)},
+	}
+
+	// Зона вплотную к старту трека: шагом позже ее расширили на запад
+	shifted := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{-1, 0}, {1, 0}, {1, 1}, {-1, 1}, {-1, 0}}},
+	}
+
+	version := func(n int, area entity.GeoJsonPolygon, active bool, from time.Time, to *time.Time) *entity.IncidentVersion {
+		return &entity.IncidentVersion{
+			Version: n, Name: "Zone", Area: area, IsActive: active,
+			ValidFrom: from, ValidTo: to,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		versions     []*entity.IncidentVersion
+		points       []entity.TrackPoint
+		wantExposed  bool
+		wantTransit  bool
+		wantDuration float64
+		wantVersion  int
+	}{
+		{
+			name:         "Track inside active zone",
+			versions:     []*entity.IncidentVersion{version(1, area, true, base.Add(-time.Hour), nil)},
+			points:       walk,
+			wantExposed:  true,
+			wantDuration: 600,
+			wantVersion:  1,
+		},
+		{
+			name:         "Shuffled points are ordered by time",
+			versions:     []*entity.IncidentVersion{version(1, area, true, base.Add(-time.Hour), nil)},
+			points:       shuffled,
+			wantExposed:  true,
+			wantDuration: 600,
+			wantVersion:  1,
+		},
+		{
+			name:        "Zone created after track",
+			versions:    []*entity.IncidentVersion{version(1, area, true, base.Add(time.Hour), nil)},
+			points:      walk,
+			wantExposed: false,
+		},
+		{
+			name: "Zone deactivated before track",
+			versions: []*entity.IncidentVersion{
+				version(1, area, true, base.Add(-2*time.Hour), ptrTime(base.Add(-time.Hour))),
+				version(2, area, false, base.Add(-time.Hour), nil),
+			},
+			points:      walk,
+			wantExposed: false,
+		},
+		{
+			name: "Zone deactivated after track",
+			versions: []*entity.IncidentVersion{
+				version(1, area, true, base.Add(-time.Hour), ptrTime(base.Add(time.Hour))),
+				version(2, area, false, base.Add(time.Hour), nil),
+			},
+			points:       walk,
+			wantExposed:  true,
+			wantDuration: 600,
+			wantVersion:  1,
+		},
+		{
+			name: "Zone geometry changed during track",
+			versions: []*entity.IncidentVersion{
+				version(1, area, true, base.Add(-time.Hour), ptrTime(*at(5))),
+				version(2, shifted, true, *at(5), nil),
+			},
+			points:       walk,
+			wantExposed:  true,
+			wantDuration: 600,
+			wantVersion:  2,
+		},
+		{
+			name: "Zone crossed between points",
+			versions: []*entity.IncidentVersion{
+				version(1, area, true, base.Add(-time.Hour), nil),
+			},
+			points:       jump,
+			wantExposed:  true,
+			wantTransit:  true,
+			wantDuration: 300,
+			wantVersion:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incidentID := uuid.New()
+			for _, v := range tt.versions {
+				v.IncidentID = incidentID
+			}
+
+			incidentRepo := mocks.NewIncidentRepo(t)
+			incidentRepo.On("FindVersionsInRange", mock.Anything, mock.Anything, mock.Anything).Return(tt.versions, nil)
+
+			s := NewExposureService(incidentRepo)
+
+			got, err := s.AnalyzeTrack(context.Background(), &entity.Track{Points: tt.points})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExposed, got.IsExposed)
+			assert.Equal(t, len(tt.points), got.PointCount)
+
+			if tt.wantExposed {
+				require.Len(t, got.Exposures, 1)
+				assert.Equal(t, incidentID.String(), got.Exposures[0].IncidentID)
+				assert.Equal(t, tt.wantTransit, got.Exposures[0].Transit)
+				assert.Equal(t, tt.wantDuration, got.Exposures[0].DurationSec)
+				assert.Equal(t, tt.wantVersion, got.Exposures[0].Version)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func TestExposureService_AnalyzeTrack_Invalid(t *testing.T) {
+	s := NewExposureService(mocks.NewIncidentRepo(t))
+
+	tests := []struct {
+		name   string
+		points []entity.TrackPoint
+	}{
+		{name: "Empty track"},
+		{name: "Latitude out of range", points: []entity.TrackPoint{{Lat: 95, Lon: 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.AnalyzeTrack(context.Background(), &entity.Track{Points: tt.points})
+			assert.ErrorIs(t, err, ErrInvalidTrack)
+		})
+	}
+}
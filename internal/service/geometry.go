@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
+)
+
+var ErrInvalidGeometryOperation = errors.New("недопустимая операция над зоной инцидента")
+
+type GeometryService interface {
+	Buffer(ctx context.Context, id string, req *entity.BufferIncidentRequest) (*entity.GeometryOperationResponse, error)
+	Union(ctx context.Context, id string, req *entity.CombineIncidentRequest) (*entity.GeometryOperationResponse, error)
+	Difference(ctx context.Context, id string, req *entity.CombineIncidentRequest) (*entity.GeometryOperationResponse, error)
+	Simplify(ctx context.Context, id string, req *entity.SimplifyIncidentRequest) (*entity.GeometryOperationResponse, error)
+	Validate(ctx context.Context, req *entity.ValidateAreaRequest) (*entity.ValidateAreaResponse, error)
+}
+
+type GeometryServiceImpl struct {
+	repo         postgres.GeometryRepo
+	incidentRepo postgres.IncidentRepo
+	incidents    IncidentService
+}
+
+// NewGeometryService принимает IncidentService, чтобы сохранение результата шло через обычное
+// обновление инцидента: с валидацией, записью версии и событием incident.updated.
+func NewGeometryService(repo postgres.GeometryRepo, incidentRepo postgres.IncidentRepo, incidents IncidentService) GeometryService {
+	return &GeometryServiceImpl{repo: repo, incidentRepo: incidentRepo, incidents: incidents}
+}
+
+func (s *GeometryServiceImpl) Buffer(ctx context.Context, id string, req *entity.BufferIncidentRequest) (*entity.GeometryOperationResponse, error) {
+	incident, err := s.findIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Buffer(ctx, incident.Area, req.DistanceM)
+	if err != nil {
+		return nil, wrapGeometryError("не удалось построить буфер зоны", err)
+	}
+
+	reason := fmt.Sprintf("буфер зоны на %g м", req.DistanceM)
+	return s.finish(ctx, id, entity.GeometryOperationBuffer, result, &req.GeometryCommit, reason)
+}
+
+func (s *GeometryServiceImpl) Union(ctx context.Context, id string, req *entity.CombineIncidentRequest) (*entity.GeometryOperationResponse, error) {
+	incident, err := s.findIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	other, source, err := s.resolveOperand(ctx, incident.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Union(ctx, incident.Area, other)
+	if err != nil {
+		return nil, wrapGeometryError("не удалось объединить зоны", err)
+	}
+
+	return s.finish(ctx, id, entity.GeometryOperationUnion, result, &req.GeometryCommit, "объединение с "+source)
+}
+
+func (s *GeometryServiceImpl) Difference(ctx context.Context, id string, req *entity.CombineIncidentRequest) (*entity.GeometryOperationResponse, error) {
+	incident, err := s.findIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	other, source, err := s.resolveOperand(ctx, incident.ID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Difference(ctx, incident.Area, other)
+	if err != nil {
+		return nil, wrapGeometryError("не удалось вычесть полигон из зоны", err)
+	}
+
+	return s.finish(ctx, id, entity.GeometryOperationDifference, result, &req.GeometryCommit, "вычитание "+source)
+}
+
+func (s *GeometryServiceImpl) Simplify(ctx context.Context, id string, req *entity.SimplifyIncidentRequest) (*entity.GeometryOperationResponse, error) {
+	incident, err := s.findIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.Simplify(ctx, incident.Area, req.ToleranceM)
+	if err != nil {
+		return nil, wrapGeometryError("не удалось упростить зону", err)
+	}
+
+	reason := fmt.Sprintf("упрощение зоны с допуском %g м", req.ToleranceM)
+	return s.finish(ctx, id, entity.GeometryOperationSimplify, result, &req.GeometryCommit, reason)
+}
+
+// Validate выполняет топологическую проверку полигона. С repair=true для некорректного полигона
+// возвращается исправленный вариант; если исправление невозможно или дает несколько полигонов,
+// причина передается в RepairError, а не ошибкой запроса.
+func (s *GeometryServiceImpl) Validate(ctx context.Context, req *entity.ValidateAreaRequest) (*entity.ValidateAreaResponse, error) {
+	resp := &entity.ValidateAreaResponse{
+		Issues: validator.PolygonIssues(req.Area),
+	}
+	resp.Valid = len(resp.Issues) == 0
+
+	if !req.Repair || resp.Valid {
+		return resp, nil
+	}
+
+	// Без корректной структуры полигон не разобрать в PostGIS
+	if err := validator.ValidatePolygon(req.Area); err != nil {
+		resp.RepairError = fmt.Sprintf("исправление невозможно: %v", err)
+		return resp, nil
+	}
+
+	repaired, err := s.repo.MakeValid(ctx, req.Area)
+	switch {
+	case errors.Is(err, postgres.ErrGeometryEmpty) || errors.Is(err, postgres.ErrGeometryNotPolygon):
+		resp.RepairError = err.Error()
+	case err != nil:
+		slog.Error("не удалось исправить полигон", "error", err)
+		return nil, fmt.Errorf("не удалось исправить полигон: %w", err)
+	default:
+		resp.Repaired = repaired
+	}
+
+	return resp, nil
+}
+
+func (s *GeometryServiceImpl) findIncident(ctx context.Context, id string) (*entity.Incident, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+	}
+
+	incident, err := s.incidentRepo.FindByID(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось найти инцидент", "id", incidentID, "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
+	}
+
+	return incident, nil
+}
+
+// resolveOperand возвращает второй полигон операции и его описание для причины изменения.
+// Операндом служит либо другой инцидент, либо переданный полигон.
+func (s *GeometryServiceImpl) resolveOperand(ctx context.Context, incidentID uuid.UUID, req *entity.CombineIncidentRequest) (entity.GeoJsonPolygon, string, error) {
+	switch {
+	case req.IncidentID != "" && req.Area != nil:
+		return entity.GeoJsonPolygon{}, "", fmt.Errorf("%w: укажите либо incident_id, либо area", ErrInvalidGeometryOperation)
+	case req.Area != nil:
+		if err := validator.ValidatePolygon(*req.Area); err != nil {
+			slog.Error("некорректный полигон", "error", err)
+			return entity.GeoJsonPolygon{}, "", fmt.Errorf("%w: некорректный полигон: %v", ErrInvalidGeometryOperation, err)
+		}
+		return *req.Area, "полигоном", nil
+	case req.IncidentID == "":
+		return entity.GeoJsonPolygon{}, "", fmt.Errorf("%w: не указан второй полигон (incident_id или area)", ErrInvalidGeometryOperation)
+	}
+
+	otherID, err := uuid.Parse(req.IncidentID)
+	if err != nil {
+		return entity.GeoJsonPolygon{}, "", fmt.Errorf("%w: ошибка парсинга incident_id: %v", ErrInvalidGeometryOperation, err)
+	}
+
+	if otherID == incidentID {
+		return entity.GeoJsonPolygon{}, "", fmt.Errorf("%w: инцидент не может быть операндом для самого себя", ErrInvalidGeometryOperation)
+	}
+
+	other, err := s.incidentRepo.FindByID(ctx, otherID)
+	if err != nil {
+		slog.Error("не удалось найти инцидент-операнд", "id", otherID, "error", err)
+		return entity.GeoJsonPolygon{}, "", fmt.Errorf("не удалось найти инцидент-операнд: %w", err)
+	}
+
+	return other.Area, "зоной инцидента " + other.ID.String(), nil
+}
+
+// finish формирует ответ и при commit=true сохраняет результат как новую зону инцидента.
+// Если причина не указана, в историю записывается описание операции.
+func (s *GeometryServiceImpl) finish(ctx context.Context, id, operation string, result *entity.GeometryResult, commit *entity.GeometryCommit, defaultReason string) (*entity.GeometryOperationResponse, error) {
+	resp := &entity.GeometryOperationResponse{
+		Operation:      operation,
+		GeometryResult: *result,
+		AreaChangeM2:   result.AreaM2 - result.PreviousAreaM2,
+	}
+
+	if !commit.Commit {
+		return resp, nil
+	}
+
+	reason := commit.Reason
+	if reason == "" {
+		reason = defaultReason
+	}
+
+	updated, err := s.incidents.Update(ctx, &entity.UpdateIncidentRequest{
+		Area:   &result.Area,
+		Reason: reason,
+		Actor:  commit.Actor,
+	}, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Committed = true
+	resp.Overlaps = updated.Overlaps
+	return resp, nil
+}
+
+func wrapGeometryError(msg string, err error) error {
+	slog.Error(msg, "error", err)
+	if errors.Is(err, postgres.ErrGeometryEmpty) || errors.Is(err, postgres.ErrGeometryNotPolygon) {
+		return fmt.Errorf("%s: %w: %v", msg, ErrInvalidGeometryOperation, err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
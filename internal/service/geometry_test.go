@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeometryService_Buffer(t *testing.T) {
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+	buffered := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{-0.1, -0.1}, {1.1, -0.1}, {1.1, 1.1}, {-0.1, 1.1}, {-0.1, -0.1}}},
+	}
+
+	tests := []struct {
+		name       string
+		commit     bool
+		reason     string
+		wantReason string
+	}{
+		{name: "Preview"},
+		{name: "Commit with default reason", commit: true, wantReason: "буфер зоны на 300 м"},
+		{name: "Commit with reason", commit: true, reason: "Ветер усилился", wantReason: "Ветер усилился"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incident := &entity.Incident{ID: uuid.New(), Name: "Fire", Area: area, Status: entity.IncidentStatusDraft}
+
+			repo := mocks.NewGeometryRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+
+			incidentRepo.On("FindByID", mock.Anything, incident.ID).Return(incident, nil)
+			repo.On("Buffer", mock.Anything, area, 300.0).Return(&entity.GeometryResult{
+				Area:           buffered,
+				AreaM2:         1500,
+				PreviousAreaM2: 1000,
+			}, nil)
+			if tt.commit {
+				incidentRepo.On("FindOverlaps", mock.Anything, buffered, &incident.ID).Return(nil, nil)
+				incidentRepo.On("Update", mock.Anything, mock.MatchedBy(func(i *entity.Incident) bool {
+					return i.Area.Coordinates[0][0][0] == -0.1 && i.ChangeReason == tt.wantReason && i.ChangedBy == "operator"
+				}), entity.IncidentStatusDraft).Return(nil)
+			}
+
+			incidents := NewIncidentService(incidentRepo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+			s := NewGeometryService(repo, incidentRepo, incidents)
+
+			got, err := s.Buffer(context.Background(), incident.ID.String(), &entity.BufferIncidentRequest{
+				DistanceM: 300,
+				GeometryCommit: entity.GeometryCommit{
+					Commit: tt.commit,
+					Reason: tt.reason,
+					Actor:  "operator",
+				},
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, entity.GeometryOperationBuffer, got.Operation)
+			assert.Equal(t, buffered, got.Area)
+			assert.Equal(t, 500.0, got.AreaChangeM2)
+			assert.Equal(t, tt.commit, got.Committed)
+		})
+	}
+}
+
+func TestGeometryService_InvalidOperation(t *testing.T) {
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+	incident := &entity.Incident{ID: uuid.New(), Name: "Fire", Area: area}
+	other := &entity.Incident{ID: uuid.New(), Name: "Flood", Area: area}
+
+	tests := []struct {
+		name string
+		req  *entity.CombineIncidentRequest
+		mock func(r *mocks.GeometryRepo, ir *mocks.IncidentRepo)
+	}{
+		{
+			name: "Both operands",
+			req:  &entity.CombineIncidentRequest{IncidentID: other.ID.String(), Area: &area},
+		},
+		{
+			name: "No operand",
+			req:  &entity.CombineIncidentRequest{},
+		},
+		{
+			name: "Self operand",
+			req:  &entity.CombineIncidentRequest{IncidentID: incident.ID.String()},
+		},
+		{
+			name: "Result is not a polygon",
+			req:  &entity.CombineIncidentRequest{IncidentID: other.ID.String()},
+			mock: func(r *mocks.GeometryRepo, ir *mocks.IncidentRepo) {
+				ir.On("FindByID", mock.Anything, other.ID).Return(other, nil)
+				r.On("Union", mock.Anything, area, area).Return(nil, postgres.ErrGeometryNotPolygon)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewGeometryRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+
+			incidentRepo.On("FindByID", mock.Anything, incident.ID).Return(incident, nil)
+			if tt.mock != nil {
+				tt.mock(repo, incidentRepo)
+			}
+
+			s := NewGeometryService(repo, incidentRepo, nil)
+
+			_, err := s.Union(context.Background(), incident.ID.String(), tt.req)
+			assert.ErrorIs(t, err, ErrInvalidGeometryOperation)
+		})
+	}
+}
+
+func TestGeometryService_Validate(t *testing.T) {
+	polygon := func(rings ...[][]float64) entity.GeoJsonPolygon {
+		return entity.GeoJsonPolygon{Type: "Polygon", Coordinates: rings}
+	}
+	square := [][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}
+	bowTie := [][]float64{{0, 0}, {1, 1}, {1, 0}, {0, 1}, {0, 0}}
+
+	tests := []struct {
+		name          string
+		area          entity.GeoJsonPolygon
+		repair        error
+		wantCodes     []string
+		wantPoint     []float64
+		wantRepaired  bool
+		wantRepairErr bool
+	}{
+		{
+			name: "Valid with hole",
+			area: polygon(square, [][]float64{{0.5, 0.5}, {0.5, 1.5}, {1.5, 1.5}, {1.5, 0.5}, {0.5, 0.5}}),
+		},
+		{
+			name:          "Bow-tie",
+			area:          polygon(bowTie),
+			repair:        postgres.ErrGeometryNotPolygon,
+			wantCodes:     []string{entity.PolygonIssueSelfIntersection},
+			wantPoint:     []float64{0.5, 0.5},
+			wantRepairErr: true,
+		},
+		{
+			name:         "Duplicate vertex",
+			area:         polygon([][]float64{{0, 0}, {2, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}}),
+			wantCodes:    []string{entity.PolygonIssueDuplicateVertex},
+			wantPoint:    []float64{2, 0},
+			wantRepaired: true,
+		},
+		{
+			name:          "Zero area ring",
+			area:          polygon([][]float64{{0, 0}, {1, 1}, {2, 2}, {0, 0}}),
+			repair:        postgres.ErrGeometryEmpty,
+			wantCodes:     []string{entity.PolygonIssueZeroAreaRing},
+			wantRepairErr: true,
+		},
+		{
+			name:         "Hole outside shell",
+			area:         polygon(square, [][]float64{{5, 5}, {5, 6}, {6, 6}, {6, 5}, {5, 5}}),
+			wantCodes:    []string{entity.PolygonIssueHoleOutsideShell},
+			wantPoint:    []float64{5, 5},
+			wantRepaired: true,
+		},
+		{
+			name:          "Not closed is not repaired",
+			area:          polygon([][]float64{{0, 0}, {2, 0}, {2, 2}, {0, 2}}),
+			wantCodes:     []string{entity.PolygonIssueNotClosed},
+			wantPoint:     []float64{0, 2},
+			wantRepairErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewGeometryRepo(t)
+			if tt.wantRepaired || tt.repair != nil {
+				var result *entity.GeometryResult
+				if tt.repair == nil {
+					result = &entity.GeometryResult{Area: polygon(square)}
+				}
+				repo.On("MakeValid", mock.Anything, tt.area).Return(result, tt.repair)
+			}
+
+			s := NewGeometryService(repo, mocks.NewIncidentRepo(t), nil)
+
+			got, err := s.Validate(context.Background(), &entity.ValidateAreaRequest{Area: tt.area, Repair: true})
+			require.NoError(t, err)
+
+			codes := make([]string, 0, len(got.Issues))
+			for _, issue := range got.Issues {
+				codes = append(codes, issue.Code)
+			}
+			assert.ElementsMatch(t, tt.wantCodes, codes)
+			assert.Equal(t, len(tt.wantCodes) == 0, got.Valid)
+			if tt.wantPoint != nil {
+				assert.Equal(t, tt.wantPoint, got.Issues[0].Point)
+			}
+			assert.Equal(t, tt.wantRepaired, got.Repaired != nil)
+			assert.Equal(t, tt.wantRepairErr, got.RepairError != "")
+		})
+	}
+}
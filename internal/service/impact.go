@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
+)
+
+const (
+	defaultImpactBucketMinutes = 15
+	maxImpactBuckets           = 1000
+	maxImpactUsers             = 1000
+)
+
+type ImpactService interface {
+	Preview(ctx context.Context, req *entity.PreviewIncidentRequest) (*entity.PreviewIncidentResponse, error)
+}
+
+type ImpactServiceImpl struct {
+	locationRepo postgres.LocationRepo
+	incidentRepo postgres.IncidentRepo
+	cfg          *config.Config
+}
+
+func NewImpactService(locationRepo postgres.LocationRepo, incidentRepo postgres.IncidentRepo, cfg *config.Config) ImpactService {
+	return &ImpactServiceImpl{locationRepo: locationRepo, incidentRepo: incidentRepo, cfg: cfg}
+}
+
+// Preview оценивает охват черновика зоны до публикации: пользователей, чьи проверки за окно
+// попали в полигон, их распределение по интервалам и пересечения с активными инцидентами.
+// Ничего не сохраняется и не ставится в очередь.
+func (s *ImpactServiceImpl) Preview(ctx context.Context, req *entity.PreviewIncidentRequest) (*entity.PreviewIncidentResponse, error) {
+	if err := validator.ValidatePolygon(req.Area); err != nil {
+		slog.Error("некорректный полигон", "error", err)
+		return nil, fmt.Errorf("некорректный полигон: %w", err)
+	}
+
+	window := req.WindowMinutes
+	if window <= 0 {
+		window = s.cfg.HTTPServer.StatsWindowMinutes
+	}
+	if window <= 0 {
+		window = 60
+	}
+
+	bucket := req.BucketMinutes
+	if bucket <= 0 {
+		bucket = defaultImpactBucketMinutes
+	}
+	if bucket > window {
+		bucket = window
+	}
+	if window/bucket > maxImpactBuckets {
+		return nil, fmt.Errorf("слишком много интервалов: окно %d мин при шаге %d мин, допустимо не более %d", window, bucket, maxImpactBuckets)
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Duration(window) * time.Minute).Truncate(time.Minute)
+	bucketDuration := time.Duration(bucket) * time.Minute
+
+	users, total, err := s.locationRepo.FindUsersInArea(ctx, req.Area, since, maxImpactUsers)
+	if err != nil {
+		slog.Error("не удалось найти пользователей в зоне", "error", err)
+		return nil, fmt.Errorf("не удалось найти пользователей в зоне: %w", err)
+	}
+
+	counted, err := s.locationRepo.CountChecksInArea(ctx, req.Area, since, bucketDuration)
+	if err != nil {
+		slog.Error("не удалось посчитать проверки в зоне", "error", err)
+		return nil, fmt.Errorf("не удалось посчитать проверки в зоне: %w", err)
+	}
+
+	overlaps, err := s.incidentRepo.FindOverlaps(ctx, req.Area, nil)
+	if err != nil {
+		slog.Error("не удалось найти пересечения с инцидентами", "error", err)
+		return nil, fmt.Errorf("не удалось найти пересечения с инцидентами: %w", err)
+	}
+
+	resp := &entity.PreviewIncidentResponse{
+		WindowMinutes: window,
+		BucketMinutes: bucket,
+		Since:         since,
+		AffectedUsers: total,
+		Users:         users,
+		Buckets:       fillBuckets(counted, since, now, bucketDuration),
+		Overlaps:      overlaps,
+	}
+	if resp.Users == nil {
+		resp.Users = []*entity.AffectedUser{}
+	}
+	if resp.Overlaps == nil {
+		resp.Overlaps = []*entity.IncidentOverlap{}
+	}
+
+	return resp, nil
+}
+
+// fillBuckets дополняет разбивку пустыми интервалами, чтобы каждый интервал [since, until) был
+// в ответе. Последний интервал может быть неполным.
+func fillBuckets(counted []*entity.ImpactBucket, since, until time.Time, step time.Duration) []*entity.ImpactBucket {
+	byStart := make(map[int64]*entity.ImpactBucket, len(counted))
+	for _, b := range counted {
+		byStart[b.Start.Unix()] = b
+	}
+
+	count := int((until.Sub(since) + step - 1) / step)
+
+	buckets := make([]*entity.ImpactBucket, 0, count)
+	for i := 0; i < count; i++ {
+		start := since.Add(time.Duration(i) * step)
+		if b, ok := byStart[start.Unix()]; ok {
+			buckets = append(buckets, b)
+			continue
+		}
+		buckets = append(buckets, &entity.ImpactBucket{Start: start})
+	}
+
+	return buckets
+}
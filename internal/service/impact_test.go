@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpactService_Preview(t *testing.T) {
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+
+	tests := []struct {
+		name        string
+		req         *entity.PreviewIncidentRequest
+		statsWindow int
+		users       []*entity.AffectedUser
+		overlaps    []*entity.IncidentOverlap
+		wantErr     bool
+		wantWindow  int
+		wantBucket  int
+		wantBuckets int
+	}{
+		{
+			name:        "Defaults from stats window",
+			req:         &entity.PreviewIncidentRequest{Area: area},
+			statsWindow: 60,
+			users: []*entity.AffectedUser{
+				{UserID: "user-1", Checks: 3, LastSeenAt: time.Now()},
+			},
+			overlaps: []*entity.IncidentOverlap{
+				{IncidentID: uuid.New(), Name: "Flood", OverlapPercent: 40},
+			},
+			wantWindow:  60,
+			wantBucket:  defaultImpactBucketMinutes,
+			wantBuckets: 5,
+		},
+		{
+			name:        "Bucket larger than window",
+			req:         &entity.PreviewIncidentRequest{Area: area, WindowMinutes: 10, BucketMinutes: 30},
+			wantWindow:  10,
+			wantBucket:  10,
+			wantBuckets: 2,
+		},
+		{
+			name:    "Too many buckets",
+			req:     &entity.PreviewIncidentRequest{Area: area, WindowMinutes: 10080, BucketMinutes: 1},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid polygon",
+			req:     &entity.PreviewIncidentRequest{Area: entity.GeoJsonPolygon{Type: "Point"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+
+			if !tt.wantErr {
+				locationRepo.On("FindUsersInArea", mock.Anything, area, mock.Anything, maxImpactUsers).Return(tt.users, len(tt.users), nil)
+				locationRepo.On("CountChecksInArea", mock.Anything, area, mock.Anything, time.Duration(tt.wantBucket)*time.Minute).Return(nil, nil)
+				incidentRepo.On("FindOverlaps", mock.Anything, area, (*uuid.UUID)(nil)).Return(tt.overlaps, nil)
+			}
+
+			cfg := &config.Config{HTTPServer: config.HTTPServerConfig{StatsWindowMinutes: tt.statsWindow}}
+			s := NewImpactService(locationRepo, incidentRepo, cfg)
+
+			got, err := s.Preview(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWindow, got.WindowMinutes)
+			assert.Equal(t, tt.wantBucket, got.BucketMinutes)
+			assert.Equal(t, len(tt.users), got.AffectedUsers)
+			assert.Len(t, got.Users, len(tt.users))
+			assert.Len(t, got.Overlaps, len(tt.overlaps))
+			// Окно отсчитывается от начала минуты, поэтому последний интервал неполный
+			assert.Len(t, got.Buckets, tt.wantBuckets)
+		})
+	}
+}
@@ -2,55 +2,118 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
 	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/export"
 	"github.com/levinOo/geo-incedent-service/pkg/validator"
 )
 
 type IncidentService interface {
 	Create(ctx context.Context, req *entity.CreateIncidentRequest) (*entity.IncidentResponse, error)
+	Import(ctx context.Context, req *entity.ImportIncidentsRequest) (*entity.ImportIncidentsResponse, error)
 	FindByID(ctx context.Context, id string) (*entity.GetIncidentResponse, error)
+	FindTree(ctx context.Context, id string) (*entity.GetIncidentResponse, error)
 	FindAll(ctx context.Context, limit, offset int) ([]*entity.GetIncidentResponse, error)
+	FindByIDAsOf(ctx context.Context, id string, asOf time.Time) (*entity.GetIncidentResponse, error)
+	FindAllAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]*entity.GetIncidentResponse, error)
+	Export(ctx context.Context, filter entity.IncidentExportFilter, w export.Writer) error
 	Update(ctx context.Context, req *entity.UpdateIncidentRequest, id string) (*entity.IncidentResponse, error)
-	Delete(ctx context.Context, id string) (*entity.IncidentResponse, error)
+	Delete(ctx context.Context, id string, req *entity.DeleteIncidentRequest) (*entity.IncidentResponse, error)
+	History(ctx context.Context, id string, limit, offset int) (*entity.GetIncidentHistoryResponse, error)
+	Diff(ctx context.Context, id string, fromVersion, toVersion int) (*entity.IncidentDiffResponse, error)
 	GetStats(ctx context.Context) (*entity.StatsResponse, error)
+	Submit(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
+	Approve(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
+	Reject(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
+	Resolve(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error)
 }
 
+// Значение INCIDENT_TWO_PERSON_SEVERITY, выключающее правило двух лиц
+const twoPersonRuleOff = "off"
+
+// Значение INCIDENT_POLYGON_VALIDATION, включающее топологическую проверку зоны
+const polygonValidationTopology = "topology"
+
+var (
+	ErrInvalidTransition = errors.New("переход недопустим из текущего статуса инцидента")
+	ErrTwoPersonRule     = errors.New("публикация требует одобрения оператором, не отправлявшим инцидент на согласование")
+	ErrInvalidParent     = errors.New("недопустимый родительский инцидент")
+	ErrIncidentOverlap   = errors.New("зона слишком сильно пересекается с другим активным инцидентом")
+	ErrInvalidIncident   = errors.New("некорректные данные инцидента")
+	ErrInvalidIncidentID = errors.New("некорректный id инцидента")
+	// ErrIncidentNotFound совпадает с ошибкой репозитория: ее оборачивают все пути чтения и изменения инцидента
+	ErrIncidentNotFound = postgres.ErrIncidentNotFound
+	// ErrIncidentVersionNotFound совпадает с ошибкой репозитория для отсутствующей версии
+	ErrIncidentVersionNotFound = postgres.ErrIncidentVersionNotFound
+	ErrInvalidVersion          = errors.New("некорректная пара версий")
+)
+
 type IncidentServiceImpl struct {
-	repo postgres.IncidentRepo
-	cfg  *config.Config
+	repo    postgres.IncidentRepo
+	updates postgres.IncidentUpdateRepo
+	events  events.Publisher
+	cfg     *config.Config
 }
 
-func NewIncidentService(repo postgres.IncidentRepo, cfg *config.Config) IncidentService {
-	return &IncidentServiceImpl{repo: repo, cfg: cfg}
+func NewIncidentService(repo postgres.IncidentRepo, updates postgres.IncidentUpdateRepo, events events.Publisher, cfg *config.Config) IncidentService {
+	return &IncidentServiceImpl{repo: repo, updates: updates, events: events, cfg: cfg}
 }
 
 func (s *IncidentServiceImpl) Create(ctx context.Context, req *entity.CreateIncidentRequest) (*entity.IncidentResponse, error) {
-	if err := validator.ValidatePolygon(req.Area); err != nil {
+	if err := s.validateArea(req.Area); err != nil {
 		slog.Error("ошибка валидации полигона", "error", err.Error())
-		return nil, fmt.Errorf("ошибка валидации полигона: %w", err)
+		return nil, fmt.Errorf("%w: ошибка валидации полигона: %w", ErrInvalidIncident, err)
 	}
 
 	incident := &entity.Incident{
 		Name:        req.Name,
 		Description: req.Description,
 		Area:        req.Area,
-		IsActive:    true,
+		Status:      entity.IncidentStatusDraft,
+		Severity:    req.Severity,
+
+		ChangedBy:    req.Actor,
+		ChangeReason: req.Reason,
 	}
 
-	err := s.repo.Create(ctx, incident)
+	if incident.Severity == "" {
+		incident.Severity = entity.IncidentSeverityModerate
+	}
+	if entity.SeverityRank(incident.Severity) == 0 {
+		slog.Error("неизвестный уровень серьезности", "severity", incident.Severity)
+		return nil, fmt.Errorf("%w: неизвестный уровень серьезности: %s", ErrInvalidIncident, incident.Severity)
+	}
+
+	parentID, err := s.resolveParent(ctx, req.ParentID, nil)
+	if err != nil {
+		return nil, err
+	}
+	incident.ParentID = parentID
+
+	overlaps, err := s.checkOverlaps(ctx, incident.Area, nil, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Create(ctx, incident)
 	if err != nil {
 		slog.Error("не удалось создать инцидент", "error", err.Error())
 		return nil, fmt.Errorf("не удалось создать инцидент: %w", err)
 	}
 
+	s.publish(ctx, entity.EventIncidentCreated, incident)
+
 	return &entity.IncidentResponse{
-		Status: "успешно создан",
+		Status:   "успешно создан",
+		Overlaps: overlaps,
 	}, nil
 }
 
@@ -58,7 +121,7 @@ func (s *IncidentServiceImpl) FindByID(ctx context.Context, id string) (*entity.
 	uuid, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("ошибка парсинга uuid", "error", err.Error())
-		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
 	}
 
 	incident, err := s.repo.FindByID(ctx, uuid)
@@ -67,15 +130,12 @@ func (s *IncidentServiceImpl) FindByID(ctx context.Context, id string) (*entity.
 		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
 	}
 
-	return &entity.GetIncidentResponse{
-		ID:          incident.ID.String(),
-		Name:        incident.Name,
-		Description: incident.Description,
-		Area:        incident.Area,
-		IsActive:    incident.IsActive,
-		CreatedAt:   incident.CreatedAt,
-		UpdatedAt:   incident.UpdatedAt,
-	}, nil
+	resp := incidentToResponse(incident)
+	if err := s.attachLatestUpdates(ctx, nil, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
 }
 
 func (s *IncidentServiceImpl) FindAll(ctx context.Context, limit, offset int) ([]*entity.GetIncidentResponse, error) {
@@ -86,16 +146,96 @@ func (s *IncidentServiceImpl) FindAll(ctx context.Context, limit, offset int) ([
 	}
 
 	var incidentResponses []*entity.GetIncidentResponse
-	for _, incident := range incidents {
-		incidentResponses = append(incidentResponses, &entity.GetIncidentResponse{
-			ID:          incident.ID.String(),
-			Name:        incident.Name,
-			Description: incident.Description,
-			Area:        incident.Area,
-			IsActive:    incident.IsActive,
-			CreatedAt:   incident.CreatedAt,
-			UpdatedAt:   incident.UpdatedAt,
-		})
+	for i := range incidents {
+		incidentResponses = append(incidentResponses, incidentToResponse(&incidents[i]))
+	}
+
+	if err := s.attachLatestUpdates(ctx, nil, incidentResponses...); err != nil {
+		return nil, err
+	}
+
+	return incidentResponses, nil
+}
+
+// FindTree возвращает инцидент со всеми потомками, вложенными в поле Children
+func (s *IncidentServiceImpl) FindTree(ctx context.Context, id string) (*entity.GetIncidentResponse, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
+	}
+
+	incident, err := s.repo.FindByID(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось найти инцидент", "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
+	}
+
+	descendants, err := s.repo.FindDescendants(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось получить дочерние инциденты", "id", id, "error", err)
+		return nil, fmt.Errorf("не удалось получить дочерние инциденты: %w", err)
+	}
+
+	root := incidentToResponse(incident)
+	nodes := []*entity.GetIncidentResponse{root}
+	byID := map[string]*entity.GetIncidentResponse{root.ID: root}
+	for i := range descendants {
+		node := incidentToResponse(&descendants[i])
+		nodes = append(nodes, node)
+		byID[node.ID] = node
+	}
+
+	for _, node := range nodes[1:] {
+		if parent, ok := byID[node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	if err := s.attachLatestUpdates(ctx, nil, nodes...); err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+// FindByIDAsOf возвращает инцидент в том виде, в котором он действовал в момент asOf
+func (s *IncidentServiceImpl) FindByIDAsOf(ctx context.Context, id string, asOf time.Time) (*entity.GetIncidentResponse, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
+	}
+
+	incident, err := s.repo.FindByIDAsOf(ctx, incidentID, asOf)
+	if err != nil {
+		slog.Error("не удалось найти инцидент на момент времени", "as_of", asOf, "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент на момент времени: %w", err)
+	}
+
+	resp := incidentToResponse(incident)
+	if err := s.attachLatestUpdates(ctx, &asOf, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// FindAllAsOf возвращает инциденты в том виде, в котором они действовали в момент asOf
+func (s *IncidentServiceImpl) FindAllAsOf(ctx context.Context, asOf time.Time, limit, offset int) ([]*entity.GetIncidentResponse, error) {
+	incidents, err := s.repo.FindAllAsOf(ctx, asOf, limit, offset)
+	if err != nil {
+		slog.Error("не удалось найти инциденты на момент времени", "as_of", asOf, "error", err)
+		return nil, fmt.Errorf("не удалось найти инциденты на момент времени: %w", err)
+	}
+
+	var incidentResponses []*entity.GetIncidentResponse
+	for i := range incidents {
+		incidentResponses = append(incidentResponses, incidentToResponse(&incidents[i]))
+	}
+
+	if err := s.attachLatestUpdates(ctx, &asOf, incidentResponses...); err != nil {
+		return nil, err
 	}
 
 	return incidentResponses, nil
@@ -105,18 +245,18 @@ func (s *IncidentServiceImpl) Update(ctx context.Context, req *entity.UpdateInci
 	uuid, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("ошибка парсинга uuid", "error", err)
-		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
 	}
 
-	if req.Name == nil && req.Description == nil && req.Area == nil {
+	if req.Name == nil && req.Description == nil && req.Area == nil && req.Severity == nil && req.ParentID == nil {
 		slog.Error("не указаны поля для обновления")
-		return nil, fmt.Errorf("не указаны поля для обновления")
+		return nil, fmt.Errorf("%w: не указаны поля для обновления", ErrInvalidIncident)
 	}
 
 	if req.Area != nil {
-		if err := validator.ValidatePolygon(*req.Area); err != nil {
+		if err := s.validateArea(*req.Area); err != nil {
 			slog.Error("некорректный полигон", "error", err)
-			return nil, fmt.Errorf("некорректный полигон: %w", err)
+			return nil, fmt.Errorf("%w: некорректный полигон: %w", ErrInvalidIncident, err)
 		}
 	}
 
@@ -126,6 +266,18 @@ func (s *IncidentServiceImpl) Update(ctx context.Context, req *entity.UpdateInci
 		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
 	}
 
+	// Изменение отправленного или опубликованного инцидента не действует без согласования:
+	// инцидент возвращается на согласование, а автором отправки становится автор изменения
+	fromStatus := currentIncident.Status
+	switch fromStatus {
+	case entity.IncidentStatusDraft:
+	case entity.IncidentStatusPendingReview, entity.IncidentStatusPublished:
+		currentIncident.Status = entity.IncidentStatusPendingReview
+	default:
+		slog.Error("изменение инцидента недопустимо в текущем статусе", "id", id, "status", fromStatus)
+		return nil, fmt.Errorf("%w: текущий статус %s", ErrInvalidTransition, fromStatus)
+	}
+
 	if req.Name != nil {
 		currentIncident.Name = *req.Name
 	}
@@ -138,33 +290,185 @@ func (s *IncidentServiceImpl) Update(ctx context.Context, req *entity.UpdateInci
 		currentIncident.Area = *req.Area
 	}
 
-	if err := s.repo.Update(ctx, currentIncident); err != nil {
+	if req.Severity != nil {
+		if entity.SeverityRank(*req.Severity) == 0 {
+			slog.Error("неизвестный уровень серьезности", "severity", *req.Severity)
+			return nil, fmt.Errorf("%w: неизвестный уровень серьезности: %s", ErrInvalidIncident, *req.Severity)
+		}
+		currentIncident.Severity = *req.Severity
+	}
+
+	if req.ParentID != nil {
+		parentID, err := s.resolveParent(ctx, *req.ParentID, &currentIncident.ID)
+		if err != nil {
+			return nil, err
+		}
+		currentIncident.ParentID = parentID
+	}
+
+	var overlaps []*entity.IncidentOverlap
+	if req.Area != nil {
+		overlaps, err = s.checkOverlaps(ctx, currentIncident.Area, &currentIncident.ID, currentIncident.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if currentIncident.Status == entity.IncidentStatusPendingReview && req.Actor == "" && s.requiresTwoPersons(currentIncident.Severity) {
+		slog.Error("изменение инцидента на согласовании без автора", "id", id, "severity", currentIncident.Severity)
+		return nil, fmt.Errorf("%w: изменение инцидента с серьезностью %s требует персонального ключа оператора", ErrTwoPersonRule, currentIncident.Severity)
+	}
+
+	currentIncident.ChangedBy = req.Actor
+	currentIncident.ChangeReason = req.Reason
+
+	if err := s.repo.Update(ctx, currentIncident, fromStatus); err != nil {
 		slog.Error("не удалось обновить инцидент", "error", err)
+		if errors.Is(err, postgres.ErrStatusConflict) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTransition, err)
+		}
 		return nil, fmt.Errorf("не удалось обновить инцидент: %w", err)
 	}
 
+	status := "успешно обновлен"
+	if currentIncident.Status == entity.IncidentStatusPendingReview {
+		currentIncident.IsActive = false
+		currentIncident.SubmittedBy = req.Actor
+		status = "обновлен и отправлен на согласование"
+	}
+
+	s.publish(ctx, entity.EventIncidentUpdated, currentIncident)
+	if currentIncident.Status != fromStatus {
+		s.publish(ctx, entity.EventIncidentStatusChanged, currentIncident)
+	}
+
 	return &entity.IncidentResponse{
-		Status: "успешно обновлен",
+		Status:   status,
+		Overlaps: overlaps,
 	}, nil
 }
 
-func (s *IncidentServiceImpl) Delete(ctx context.Context, id string) (*entity.IncidentResponse, error) {
+func (s *IncidentServiceImpl) Delete(ctx context.Context, id string, req *entity.DeleteIncidentRequest) (*entity.IncidentResponse, error) {
 	uuid, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("ошибка парсинга uuid", "error", err)
-		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
 	}
 
-	if err := s.repo.Delete(ctx, uuid); err != nil {
+	children, err := s.repo.Delete(ctx, uuid, req.Actor, req.Reason)
+	if err != nil {
 		slog.Error("не удалось удалить инцидент", "error", err)
 		return nil, fmt.Errorf("не удалось удалить инцидент: %w", err)
 	}
 
+	s.publish(ctx, entity.EventIncidentDeactivated, &entity.Incident{ID: uuid})
+	s.publishCascade(ctx, children)
+
 	return &entity.IncidentResponse{
 		Status: "успешно удален",
 	}, nil
 }
 
+func (s *IncidentServiceImpl) History(ctx context.Context, id string, limit, offset int) (*entity.GetIncidentHistoryResponse, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
+	}
+
+	total, err := s.repo.CountVersions(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось подсчитать версии инцидента", "error", err)
+		return nil, fmt.Errorf("не удалось подсчитать версии инцидента: %w", err)
+	}
+
+	// Версия 1 есть у каждого инцидента, поэтому пустая история означает, что инцидента нет
+	if total == 0 {
+		return nil, ErrIncidentNotFound
+	}
+
+	versions, err := s.repo.FindVersions(ctx, incidentID, limit, offset)
+	if err != nil {
+		slog.Error("не удалось получить историю инцидента", "error", err)
+		return nil, fmt.Errorf("не удалось получить историю инцидента: %w", err)
+	}
+
+	if versions == nil {
+		versions = []*entity.IncidentVersion{}
+	}
+
+	return &entity.GetIncidentHistoryResponse{
+		IncidentID: incidentID.String(),
+		Versions:   versions,
+		Total:      total,
+	}, nil
+}
+
+// Diff сравнивает две версии инцидента. Без toVersion берется последняя версия,
+// без fromVersion — предшествующая toVersion.
+func (s *IncidentServiceImpl) Diff(ctx context.Context, id string, fromVersion, toVersion int) (*entity.IncidentDiffResponse, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
+	}
+
+	to, err := s.repo.FindVersion(ctx, incidentID, toVersion)
+	if err != nil {
+		slog.Error("не удалось найти версию инцидента", "version", toVersion, "error", err)
+		return nil, fmt.Errorf("не удалось найти версию инцидента: %w", err)
+	}
+
+	if fromVersion == 0 {
+		fromVersion = to.Version - 1
+	}
+
+	if fromVersion < 1 || fromVersion == to.Version {
+		slog.Error("некорректная пара версий", "from", fromVersion, "to", to.Version)
+		return nil, fmt.Errorf("%w: %d и %d", ErrInvalidVersion, fromVersion, to.Version)
+	}
+
+	from, err := s.repo.FindVersion(ctx, incidentID, fromVersion)
+	if err != nil {
+		slog.Error("не удалось найти версию инцидента", "version", fromVersion, "error", err)
+		return nil, fmt.Errorf("не удалось найти версию инцидента: %w", err)
+	}
+
+	geometry, err := s.repo.DiffGeometry(ctx, incidentID, from.Version, to.Version)
+	if err != nil {
+		slog.Error("не удалось сравнить геометрию версий", "error", err)
+		return nil, fmt.Errorf("не удалось сравнить геометрию версий: %w", err)
+	}
+
+	changes := []*entity.FieldChange{}
+	if from.Name != to.Name {
+		changes = append(changes, &entity.FieldChange{Field: "name", Old: from.Name, New: to.Name})
+	}
+	if from.Description != to.Description {
+		changes = append(changes, &entity.FieldChange{Field: "description", Old: from.Description, New: to.Description})
+	}
+	if from.IsActive != to.IsActive {
+		changes = append(changes, &entity.FieldChange{Field: "is_active", Old: from.IsActive, New: to.IsActive})
+	}
+	if from.Status != to.Status {
+		changes = append(changes, &entity.FieldChange{Field: "status", Old: from.Status, New: to.Status})
+	}
+	if from.Severity != to.Severity {
+		changes = append(changes, &entity.FieldChange{Field: "severity", Old: from.Severity, New: to.Severity})
+	}
+	if geometry.Changed {
+		changes = append(changes, &entity.FieldChange{Field: "area", Old: from.Area, New: to.Area})
+	}
+
+	return &entity.IncidentDiffResponse{
+		IncidentID:  incidentID.String(),
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Changes:     changes,
+		Geometry:    geometry,
+	}, nil
+}
+
 func (s *IncidentServiceImpl) GetStats(ctx context.Context) (*entity.StatsResponse, error) {
 	stats, err := s.repo.GetStats(ctx, s.cfg.HTTPServer.StatsWindowMinutes)
 	if err != nil {
@@ -176,3 +480,355 @@ func (s *IncidentServiceImpl) GetStats(ctx context.Context) (*entity.StatsRespon
 		WindowMinutes: s.cfg.HTTPServer.StatsWindowMinutes,
 	}, nil
 }
+
+// Submit отправляет черновик на согласование
+func (s *IncidentServiceImpl) Submit(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error) {
+	incident, err := s.findForTransition(ctx, id, entity.IncidentStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+
+	// Без автора отправки правило двух лиц не с чем сравнивать
+	if req.Actor == "" && s.requiresTwoPersons(incident.Severity) {
+		slog.Error("отправка на согласование без автора", "id", id, "severity", incident.Severity)
+		return nil, fmt.Errorf("%w: отправка инцидента с серьезностью %s требует персонального ключа оператора", ErrTwoPersonRule, incident.Severity)
+	}
+
+	if err := s.transition(ctx, incident, entity.IncidentStatusPendingReview, entity.IncidentOperationSubmit, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.IncidentResponse{
+		Status: "отправлен на согласование",
+	}, nil
+}
+
+// Approve публикует инцидент, после чего он учитывается в проверках локаций. Инциденты
+// ниже порога правила двух лиц можно опубликовать прямо из черновика; начиная с порога
+// инцидент должен быть на согласовании, а одобрить его может только другой оператор.
+func (s *IncidentServiceImpl) Approve(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error) {
+	incident, err := s.findForTransition(ctx, id, entity.IncidentStatusDraft, entity.IncidentStatusPendingReview)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.requiresTwoPersons(incident.Severity) {
+		if incident.Status != entity.IncidentStatusPendingReview {
+			slog.Error("инцидент не отправлен на согласование", "id", id, "severity", incident.Severity)
+			return nil, fmt.Errorf("%w: инцидент с серьезностью %s должен быть отправлен на согласование", ErrTwoPersonRule, incident.Severity)
+		}
+		if req.Actor == "" || incident.SubmittedBy == "" || req.Actor == incident.SubmittedBy {
+			slog.Error("нарушено правило двух лиц", "id", id, "actor", req.Actor, "submitted_by", incident.SubmittedBy)
+			return nil, fmt.Errorf("%w: одобряющий и отправивший операторы должны быть известны и различаться (отправил %q)", ErrTwoPersonRule, incident.SubmittedBy)
+		}
+	}
+
+	if err := s.transition(ctx, incident, entity.IncidentStatusPublished, entity.IncidentOperationApprove, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.IncidentResponse{
+		Status: "опубликован",
+	}, nil
+}
+
+// Reject возвращает инцидент с согласования в черновик
+func (s *IncidentServiceImpl) Reject(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error) {
+	incident, err := s.findForTransition(ctx, id, entity.IncidentStatusPendingReview)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(ctx, incident, entity.IncidentStatusDraft, entity.IncidentOperationReject, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.IncidentResponse{
+		Status: "возвращен в черновик",
+	}, nil
+}
+
+// Resolve завершает опубликованный инцидент, после чего он перестает учитываться в проверках
+func (s *IncidentServiceImpl) Resolve(ctx context.Context, id string, req *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error) {
+	incident, err := s.findForTransition(ctx, id, entity.IncidentStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(ctx, incident, entity.IncidentStatusResolved, entity.IncidentOperationResolve, req); err != nil {
+		return nil, err
+	}
+
+	return &entity.IncidentResponse{
+		Status: "завершен",
+	}, nil
+}
+
+// findForTransition загружает инцидент и проверяет, что переход допустим из его текущего статуса
+func (s *IncidentServiceImpl) findForTransition(ctx context.Context, id string, allowed ...string) (*entity.Incident, error) {
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIncidentID, err)
+	}
+
+	incident, err := s.repo.FindByID(ctx, incidentID)
+	if err != nil {
+		slog.Error("не удалось найти инцидент", "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
+	}
+
+	for _, status := range allowed {
+		if incident.Status == status {
+			return incident, nil
+		}
+	}
+
+	slog.Error("недопустимый переход статуса инцидента", "id", id, "status", incident.Status)
+	return nil, fmt.Errorf("%w: текущий статус %s", ErrInvalidTransition, incident.Status)
+}
+
+func (s *IncidentServiceImpl) transition(ctx context.Context, incident *entity.Incident, toStatus, operation string, req *entity.IncidentTransitionRequest) error {
+	children, err := s.repo.UpdateStatus(ctx, incident, toStatus, operation, req.Actor, req.Reason)
+	if err != nil {
+		slog.Error("не удалось сменить статус инцидента", "id", incident.ID, "to", toStatus, "error", err)
+		if errors.Is(err, postgres.ErrStatusConflict) {
+			return fmt.Errorf("%w: %w", ErrInvalidTransition, err)
+		}
+		return fmt.Errorf("не удалось сменить статус инцидента: %w", err)
+	}
+
+	incident.Status = toStatus
+	incident.IsActive = toStatus == entity.IncidentStatusPublished
+	switch toStatus {
+	case entity.IncidentStatusPendingReview:
+		incident.SubmittedBy = req.Actor
+	case entity.IncidentStatusDraft:
+		incident.SubmittedBy = ""
+	}
+
+	s.publish(ctx, entity.EventIncidentStatusChanged, incident)
+	s.publishCascade(ctx, children)
+
+	return nil
+}
+
+// resolveParent проверяет родителя, указанного при создании или обновлении инцидента childID.
+// Пустая строка означает отсутствие родителя. Родитель должен существовать, не быть завершенным
+// и не быть самим инцидентом или его потомком.
+func (s *IncidentServiceImpl) resolveParent(ctx context.Context, raw string, childID *uuid.UUID) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	parentID, err := uuid.Parse(raw)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid родителя", "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidParent, err)
+	}
+
+	if childID != nil && parentID == *childID {
+		return nil, fmt.Errorf("%w: инцидент не может быть родителем самому себе", ErrInvalidParent)
+	}
+
+	parent, err := s.repo.FindByID(ctx, parentID)
+	if err != nil {
+		slog.Error("не удалось найти родительский инцидент", "id", parentID, "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidParent, err)
+	}
+
+	if parent.Status == entity.IncidentStatusResolved {
+		return nil, fmt.Errorf("%w: родительский инцидент завершен", ErrInvalidParent)
+	}
+
+	if childID == nil {
+		return &parentID, nil
+	}
+
+	descendants, err := s.repo.FindDescendants(ctx, *childID)
+	if err != nil {
+		slog.Error("не удалось получить дочерние инциденты", "id", childID, "error", err)
+		return nil, fmt.Errorf("не удалось получить дочерние инциденты: %w", err)
+	}
+
+	for _, d := range descendants {
+		if d.ID == parentID {
+			return nil, fmt.Errorf("%w: родитель %s является потомком инцидента", ErrInvalidParent, parentID)
+		}
+	}
+
+	return &parentID, nil
+}
+
+// validateArea проверяет зону инцидента; при INCIDENT_POLYGON_VALIDATION=topology дополнительно
+// проверяется топология, а ошибка содержит *validator.TopologyError с местами проблем
+func (s *IncidentServiceImpl) validateArea(area entity.GeoJsonPolygon) error {
+	if err := validator.ValidatePolygon(area); err != nil {
+		return err
+	}
+
+	if s.cfg.Incident.PolygonValidation == polygonValidationTopology {
+		return validator.ValidatePolygonTopology(area)
+	}
+
+	return nil
+}
+
+// checkOverlaps ищет активные инциденты, с которыми пересекается зона инцидента selfID
+// (nil при создании). Пересечения с предками и потомками по иерархии ожидаемы и не учитываются,
+// касание границ тоже. При INCIDENT_OVERLAP_MAX_PERCENT > 0 возвращает ErrIncidentOverlap, если
+// пересечение превышает этот процент площади любой из двух зон.
+func (s *IncidentServiceImpl) checkOverlaps(ctx context.Context, area entity.GeoJsonPolygon, selfID, parentID *uuid.UUID) ([]*entity.IncidentOverlap, error) {
+	found, err := s.repo.FindOverlaps(ctx, area, selfID)
+	if err != nil {
+		slog.Error("не удалось найти пересечения с инцидентами", "error", err)
+		return nil, fmt.Errorf("не удалось найти пересечения с инцидентами: %w", err)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	related, err := s.hierarchyOf(ctx, selfID, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.cfg.Incident.OverlapMaxPercent
+
+	var overlaps []*entity.IncidentOverlap
+	for _, o := range found {
+		if o.OverlapAreaM2 <= 0 || related[o.IncidentID] {
+			continue
+		}
+
+		if limit > 0 && max(o.OverlapPercent, o.IncidentPercent) > limit {
+			slog.Error("пересечение с инцидентом превышает порог", "incident_id", o.IncidentID, "limit", limit)
+			return nil, fmt.Errorf("%w: %s (%s) — %.1f%% зоны, %.1f%% зоны инцидента при пороге %g%%",
+				ErrIncidentOverlap, o.Name, o.IncidentID, o.OverlapPercent, o.IncidentPercent, limit)
+		}
+
+		overlaps = append(overlaps, o)
+	}
+
+	return overlaps, nil
+}
+
+// hierarchyOf возвращает предков (начиная с parentID) и потомков инцидента selfID
+func (s *IncidentServiceImpl) hierarchyOf(ctx context.Context, selfID, parentID *uuid.UUID) (map[uuid.UUID]bool, error) {
+	related := make(map[uuid.UUID]bool)
+
+	for id := parentID; id != nil && !related[*id]; {
+		related[*id] = true
+
+		ancestor, err := s.repo.FindByID(ctx, *id)
+		if err != nil {
+			slog.Error("не удалось найти родительский инцидент", "id", *id, "error", err)
+			return nil, fmt.Errorf("не удалось найти родительский инцидент: %w", err)
+		}
+		id = ancestor.ParentID
+	}
+
+	if selfID == nil {
+		return related, nil
+	}
+
+	descendants, err := s.repo.FindDescendants(ctx, *selfID)
+	if err != nil {
+		slog.Error("не удалось получить дочерние инциденты", "id", selfID, "error", err)
+		return nil, fmt.Errorf("не удалось получить дочерние инциденты: %w", err)
+	}
+
+	for _, d := range descendants {
+		related[d.ID] = true
+	}
+
+	return related, nil
+}
+
+// requiresTwoPersons сообщает, нужна ли для публикации инцидента с такой серьезностью
+// проверка вторым оператором
+func (s *IncidentServiceImpl) requiresTwoPersons(severity string) bool {
+	threshold := s.cfg.Incident.TwoPersonSeverity
+	if threshold == twoPersonRuleOff {
+		return false
+	}
+	if entity.SeverityRank(threshold) == 0 {
+		threshold = entity.IncidentSeveritySevere
+	}
+
+	return entity.SeverityRank(severity) >= entity.SeverityRank(threshold)
+}
+
+// attachLatestUpdates дополняет ответы последним сообщением о ходе инцидента. С asOf берется
+// последнее сообщение, опубликованное не позже этого момента.
+func (s *IncidentServiceImpl) attachLatestUpdates(ctx context.Context, asOf *time.Time, responses ...*entity.GetIncidentResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(responses))
+	for _, resp := range responses {
+		id, err := uuid.Parse(resp.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка парсинга uuid: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	latest, err := s.updates.FindLatest(ctx, ids, asOf)
+	if err != nil {
+		slog.Error("не удалось получить последние сообщения по инцидентам", "error", err)
+		return fmt.Errorf("не удалось получить последние сообщения по инцидентам: %w", err)
+	}
+
+	for i, resp := range responses {
+		resp.LatestUpdate = latest[ids[i]]
+	}
+
+	return nil
+}
+
+func incidentToResponse(incident *entity.Incident) *entity.GetIncidentResponse {
+	resp := &entity.GetIncidentResponse{
+		ID:          incident.ID.String(),
+		Name:        incident.Name,
+		Description: incident.Description,
+		Area:        incident.Area,
+		IsActive:    incident.IsActive,
+		Status:      incident.Status,
+		Severity:    incident.Severity,
+		SubmittedBy: incident.SubmittedBy,
+		ExternalID:  incident.ExternalID,
+		CreatedAt:   incident.CreatedAt,
+		UpdatedAt:   incident.UpdatedAt,
+		Version:     incident.Version,
+	}
+
+	if incident.ParentID != nil {
+		resp.ParentID = incident.ParentID.String()
+	}
+
+	return resp
+}
+
+// publishCascade сообщает о деактивации потомков, завершенных вместе с родителем
+func (s *IncidentServiceImpl) publishCascade(ctx context.Context, children []uuid.UUID) {
+	for _, id := range children {
+		s.publish(ctx, entity.EventIncidentDeactivated, &entity.Incident{ID: id})
+	}
+}
+
+// publish отправляет событие об изменении инцидента в ленту оператора. Ошибка публикации
+// не влияет на результат операции.
+func (s *IncidentServiceImpl) publish(ctx context.Context, eventType string, incident *entity.Incident) {
+	id := incident.ID
+	event := &entity.Event{Type: eventType, IncidentID: &id}
+
+	if eventType != entity.EventIncidentDeactivated {
+		event.Data = incidentToResponse(incident)
+	}
+
+	if err := s.events.Publish(ctx, event); err != nil {
+		slog.Error("не удалось опубликовать событие инцидента", "type", eventType, "error", err)
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/pkg/export"
+)
+
+// Export выгружает инциденты по фильтру в w по мере чтения из базы. Begin вызывается перед
+// первым инцидентом, поэтому ошибка запроса до начала выгрузки не оставляет в w частичных данных.
+func (s *IncidentServiceImpl) Export(ctx context.Context, filter entity.IncidentExportFilter, w export.Writer) error {
+	started := false
+	err := s.repo.StreamAll(ctx, filter, func(incident *entity.Incident) error {
+		if !started {
+			if err := w.Begin(); err != nil {
+				return err
+			}
+			started = true
+		}
+		return w.Write(incident)
+	})
+	if err != nil {
+		slog.Error("не удалось выгрузить инциденты", "error", err)
+		return fmt.Errorf("не удалось выгрузить инциденты: %w", err)
+	}
+
+	if !started {
+		if err := w.Begin(); err != nil {
+			return fmt.Errorf("не удалось выгрузить инциденты: %w", err)
+		}
+	}
+
+	if err := w.End(); err != nil {
+		return fmt.Errorf("не удалось выгрузить инциденты: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentService_Export(t *testing.T) {
+	parentID := uuid.New()
+	incidents := []*entity.Incident{
+		{
+			ID:   uuid.New(),
+			Name: "Наводнение <юг>",
+			Area: entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{
+				{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+				{{0.2, 0.2}, {0.4, 0.2}, {0.4, 0.4}, {0.2, 0.2}},
+			}},
+			Status:    entity.IncidentStatusPublished,
+			Severity:  entity.IncidentSeveritySevere,
+			IsActive:  true,
+			CreatedAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+			UpdatedAt: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:          uuid.New(),
+			Name:        "Подтопление, квартал 5",
+			Description: "Ул. \"Речная\"",
+			Area: entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{
+				{{30.5, 59.9}, {30.6, 59.9}, {30.6, 60}, {30.5, 59.9}},
+			}},
+			Status:     entity.IncidentStatusDraft,
+			Severity:   entity.IncidentSeverityMinor,
+			ParentID:   &parentID,
+			ExternalID: "ext-2",
+		},
+	}
+
+	run := func(t *testing.T, format string, filter entity.IncidentExportFilter, streamErr error) (*bytes.Buffer, error) {
+		repo := mocks.NewIncidentRepo(t)
+		repo.On("StreamAll", mock.Anything, filter, mock.Anything).
+			Return(func(_ context.Context, _ entity.IncidentExportFilter, fn func(*entity.Incident) error) error {
+				if streamErr != nil {
+					return streamErr
+				}
+				for _, i := range incidents {
+					if err := fn(i); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+
+		var buf bytes.Buffer
+		w, err := export.NewWriter(format, &buf)
+		require.NoError(t, err)
+
+		return &buf, s.Export(context.Background(), filter, w)
+	}
+
+	t.Run("GeoJSON", func(t *testing.T) {
+		buf, err := run(t, export.FormatGeoJSON, entity.IncidentExportFilter{}, nil)
+		require.NoError(t, err)
+
+		var fc struct {
+			Type     string `json:"type"`
+			Features []struct {
+				ID         string                `json:"id"`
+				Geometry   entity.GeoJsonPolygon `json:"geometry"`
+				Properties map[string]any        `json:"properties"`
+			} `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &fc))
+
+		assert.Equal(t, "FeatureCollection", fc.Type)
+		require.Len(t, fc.Features, 2)
+		assert.Equal(t, incidents[0].ID.String(), fc.Features[0].ID)
+		assert.Equal(t, incidents[0].Area, fc.Features[0].Geometry)
+		assert.Equal(t, "severe", fc.Features[0].Properties["severity"])
+		assert.Nil(t, fc.Features[0].Properties["parent_id"])
+		assert.Equal(t, parentID.String(), fc.Features[1].Properties["parent_id"])
+		assert.Equal(t, "ext-2", fc.Features[1].Properties["external_id"])
+	})
+
+	t.Run("KML", func(t *testing.T) {
+		asOf := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+		buf, err := run(t, export.FormatKML, entity.IncidentExportFilter{Limit: 2, AsOf: &asOf}, nil)
+		require.NoError(t, err)
+
+		var doc struct {
+			Placemarks []struct {
+				Name    string `xml:"name"`
+				Polygon struct {
+					Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+					Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+				} `xml:"Polygon"`
+			} `xml:"Document>Placemark"`
+		}
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+		require.Len(t, doc.Placemarks, 2)
+		assert.Equal(t, "Наводнение <юг>", doc.Placemarks[0].Name)
+		assert.Equal(t, "0,0 1,0 1,1 0,1 0,0", doc.Placemarks[0].Polygon.Outer)
+		assert.Equal(t, []string{"0.2,0.2 0.4,0.2 0.4,0.4 0.2,0.2"}, doc.Placemarks[0].Polygon.Inner)
+		assert.Equal(t, "30.5,59.9 30.6,59.9 30.6,60 30.5,59.9", doc.Placemarks[1].Polygon.Outer)
+	})
+
+	t.Run("CSV with WKT", func(t *testing.T) {
+		buf, err := run(t, export.FormatCSV, entity.IncidentExportFilter{Offset: 5}, nil)
+		require.NoError(t, err)
+
+		records, err := csv.NewReader(buf).ReadAll()
+		require.NoError(t, err)
+
+		require.Len(t, records, 3)
+		assert.Equal(t, "id", records[0][0])
+		assert.Equal(t, "wkt", records[0][len(records[0])-1])
+		assert.Equal(t, "POLYGON ((0 0, 1 0, 1 1, 0 1, 0 0), (0.2 0.2, 0.4 0.2, 0.4 0.4, 0.2 0.2))", records[1][len(records[1])-1])
+		assert.Equal(t, "Подтопление, квартал 5", records[2][1])
+		assert.Equal(t, "Ул. \"Речная\"", records[2][2])
+	})
+
+	t.Run("Error before the first row writes nothing", func(t *testing.T) {
+		buf, err := run(t, export.FormatGeoJSON, entity.IncidentExportFilter{}, errors.New("connection refused"))
+		require.Error(t, err)
+		assert.Zero(t, buf.Len())
+	})
+}
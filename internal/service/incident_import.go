@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
+)
+
+// maxImportFeatures ограничивает размер одного импорта, чтобы транзакция не держала блокировки слишком долго
+const maxImportFeatures = 5000
+
+// Import загружает зоны из FeatureCollection. Каждый объект проверяется полностью, и если хотя бы
+// один некорректен, ничего не сохраняется, а отчет содержит ошибки по объектам. Корректный набор
+// сохраняется одной транзакцией с upsert по external_id. Новые инциденты создаются черновиками,
+// а изменение инцидента на согласовании или опубликованного возвращает его на согласование —
+// так же, как правка через Update. Завершенные инциденты импорт не изменяет. Зоны проверяются
+// на пересечения с активными инцидентами по правилам INCIDENT_OVERLAP_MAX_PERCENT.
+// При DryRun объекты только проверяются, а отчет дополняется топологическими проблемами полигонов
+// с их местами — независимо от режима INCIDENT_POLYGON_VALIDATION.
+func (s *IncidentServiceImpl) Import(ctx context.Context, req *entity.ImportIncidentsRequest) (*entity.ImportIncidentsResponse, error) {
+	features := req.Collection.Features
+	if len(features) > maxImportFeatures {
+		return nil, fmt.Errorf("слишком много объектов для импорта: %d, допустимо не более %d", len(features), maxImportFeatures)
+	}
+
+	mapping := mergeImportMapping(req.Mapping)
+
+	resp := &entity.ImportIncidentsResponse{
+		DryRun:   req.DryRun,
+		Total:    len(features),
+		Features: make([]entity.ImportFeatureResult, len(features)),
+	}
+	incidents := make([]*entity.Incident, len(features))
+	seen := make(map[string]int)
+
+	for idx, f := range features {
+		incident, errs := s.featureToIncident(f, mapping)
+		incident.ChangedBy = req.Actor
+		incident.ChangeReason = req.Reason
+
+		if incident.ExternalID != "" {
+			if first, ok := seen[incident.ExternalID]; ok {
+				errs = append(errs, fmt.Sprintf("external_id %q повторяет объект %d", incident.ExternalID, first))
+			} else {
+				seen[incident.ExternalID] = idx
+			}
+		}
+
+		incidents[idx] = incident
+		resp.Features[idx] = entity.ImportFeatureResult{
+			Index:      idx,
+			ExternalID: incident.ExternalID,
+			Name:       incident.Name,
+			Action:     entity.ImportActionValid,
+			Errors:     errs,
+		}
+		if len(errs) > 0 {
+			resp.Features[idx].Action = entity.ImportActionFailed
+			resp.Failed++
+		}
+		if req.DryRun && incident.Area.Type != "" {
+			resp.Features[idx].Issues = validator.PolygonIssues(incident.Area)
+		}
+	}
+
+	existing, err := s.checkImportTargets(ctx, req.Actor, incidents, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	if resp.Failed > 0 {
+		slog.Error("импорт отклонен: найдены некорректные объекты", "failed", resp.Failed, "total", resp.Total)
+		return resp, nil
+	}
+
+	created, err := s.repo.UpsertMany(ctx, incidents)
+	if err != nil {
+		var itemErr *postgres.BatchItemError
+		if errors.As(err, &itemErr) {
+			slog.Error("импорт отменен: ошибка сохранения объекта", "index", itemErr.Index, "error", itemErr.Err)
+			resp.Features[itemErr.Index].Action = entity.ImportActionFailed
+			msg := itemErr.Err.Error()
+			if errors.Is(itemErr.Err, postgres.ErrStatusConflict) {
+				msg = "инцидент завершен или его статус изменился во время импорта"
+			}
+			resp.Features[itemErr.Index].Errors = []string{msg}
+			resp.Failed = 1
+			return resp, nil
+		}
+		slog.Error("не удалось импортировать инциденты", "error", err)
+		return nil, fmt.Errorf("не удалось импортировать инциденты: %w", err)
+	}
+
+	resp.Committed = true
+	for idx, incident := range incidents {
+		result := &resp.Features[idx]
+		result.ID = incident.ID.String()
+
+		eventType := entity.EventIncidentUpdated
+		if created[idx] {
+			result.Action = entity.ImportActionCreated
+			resp.Created++
+			eventType = entity.EventIncidentCreated
+		} else {
+			result.Action = entity.ImportActionUpdated
+			resp.Updated++
+		}
+
+		s.publish(ctx, eventType, incident)
+		if prev, ok := existing[incident.ExternalID]; ok && prev.Status != incident.Status {
+			s.publish(ctx, entity.EventIncidentStatusChanged, incident)
+		}
+	}
+
+	return resp, nil
+}
+
+// checkImportTargets сверяет корректные объекты с существующими инцидентами по external_id и
+// проверяет пересечения их зон. Найденные ошибки дописываются в отчет. Возвращает существующие
+// инциденты по external_id.
+func (s *IncidentServiceImpl) checkImportTargets(ctx context.Context, actor string, incidents []*entity.Incident, resp *entity.ImportIncidentsResponse) (map[string]*entity.Incident, error) {
+	var externalIDs []string
+	for idx, incident := range incidents {
+		if incident.ExternalID != "" && len(resp.Features[idx].Errors) == 0 {
+			externalIDs = append(externalIDs, incident.ExternalID)
+		}
+	}
+
+	existing := make(map[string]*entity.Incident)
+	if len(externalIDs) > 0 {
+		found, err := s.repo.FindByExternalIDs(ctx, externalIDs)
+		if err != nil {
+			slog.Error("не удалось найти инциденты по external_id", "error", err)
+			return nil, fmt.Errorf("не удалось найти инциденты по external_id: %w", err)
+		}
+		existing = found
+	}
+
+	for idx, incident := range incidents {
+		result := &resp.Features[idx]
+		if len(result.Errors) > 0 {
+			continue
+		}
+
+		var errs []string
+		var selfID, parentID *uuid.UUID
+
+		if prev, ok := existing[incident.ExternalID]; ok {
+			selfID, parentID = &prev.ID, prev.ParentID
+
+			severity := incident.Severity
+			if severity == "" {
+				severity = prev.Severity
+			}
+
+			switch prev.Status {
+			case entity.IncidentStatusResolved:
+				errs = append(errs, fmt.Sprintf("инцидент %s завершен и не может быть изменен импортом", prev.ID))
+			case entity.IncidentStatusPendingReview, entity.IncidentStatusPublished:
+				if actor == "" && s.requiresTwoPersons(severity) {
+					errs = append(errs, fmt.Sprintf("изменение инцидента %s с серьезностью %s требует персонального ключа оператора", prev.ID, severity))
+				}
+			}
+		}
+
+		if len(errs) == 0 {
+			overlaps, err := s.checkOverlaps(ctx, incident.Area, selfID, parentID)
+			switch {
+			case errors.Is(err, ErrIncidentOverlap):
+				errs = append(errs, err.Error())
+			case err != nil:
+				return nil, err
+			}
+			result.Overlaps = overlaps
+		}
+
+		if len(errs) > 0 {
+			result.Errors = errs
+			result.Action = entity.ImportActionFailed
+			resp.Failed++
+		}
+	}
+
+	return existing, nil
+}
+
+// featureToIncident переносит свойства объекта в инцидент и возвращает все найденные ошибки
+func (s *IncidentServiceImpl) featureToIncident(f entity.GeoJsonFeature, mapping entity.ImportMapping) (*entity.Incident, []string) {
+	var errs []string
+
+	incident := &entity.Incident{
+		Name:        propertyString(f.Properties[mapping.Name]),
+		Description: propertyString(f.Properties[mapping.Description]),
+		Severity:    propertyString(f.Properties[mapping.Severity]),
+		ExternalID:  propertyString(f.Properties[mapping.ExternalID]),
+	}
+	if incident.ExternalID == "" {
+		incident.ExternalID = propertyString(f.ID)
+	}
+
+	if f.Type != "Feature" {
+		errs = append(errs, fmt.Sprintf("неверный тип объекта %q, ожидается Feature", f.Type))
+	}
+
+	switch {
+	case incident.Name == "":
+		errs = append(errs, fmt.Sprintf("не задано свойство %q (название)", mapping.Name))
+	case len([]rune(incident.Name)) > 255:
+		errs = append(errs, "название длиннее 255 символов")
+	}
+	if len([]rune(incident.Description)) > 1000 {
+		errs = append(errs, "описание длиннее 1000 символов")
+	}
+	if incident.Severity != "" && entity.SeverityRank(incident.Severity) == 0 {
+		errs = append(errs, fmt.Sprintf("неизвестный уровень серьезности: %s", incident.Severity))
+	}
+	if len(incident.ExternalID) > 255 {
+		errs = append(errs, "external_id длиннее 255 символов")
+	}
+
+	if len(f.Geometry) == 0 || string(f.Geometry) == "null" {
+		errs = append(errs, "у объекта нет геометрии")
+		return incident, errs
+	}
+
+	if err := json.Unmarshal(f.Geometry, &incident.Area); err != nil {
+		errs = append(errs, fmt.Sprintf("геометрия не является полигоном: %v", err))
+		return incident, errs
+	}
+
+	if err := s.validateArea(incident.Area); err != nil {
+		errs = append(errs, fmt.Sprintf("некорректный полигон: %v", err))
+	}
+
+	return incident, errs
+}
+
+func mergeImportMapping(m entity.ImportMapping) entity.ImportMapping {
+	def := entity.DefaultImportMapping
+	if m.Name == "" {
+		m.Name = def.Name
+	}
+	if m.Description == "" {
+		m.Description = def.Description
+	}
+	if m.Severity == "" {
+		m.Severity = def.Severity
+	}
+	if m.ExternalID == "" {
+		m.ExternalID = def.ExternalID
+	}
+	return m
+}
+
+// propertyString приводит значение свойства GeoJSON к строке: числа записываются без экспоненты,
+// отсутствующее значение дает пустую строку
+func propertyString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
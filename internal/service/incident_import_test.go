@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/geoimport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentService_Import(t *testing.T) {
+	square := json.RawMessage(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]]}`)
+	multi := json.RawMessage(`{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]]]}`)
+	open := json.RawMessage(`{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 1]]]}`)
+
+	feature := func(id any, geometry json.RawMessage, props map[string]any) entity.GeoJsonFeature {
+		return entity.GeoJsonFeature{Type: "Feature", ID: id, Geometry: geometry, Properties: props}
+	}
+
+	tests := []struct {
+		name        string
+		features    []entity.GeoJsonFeature
+		mapping     entity.ImportMapping
+		existing    map[string]*entity.Incident
+		overlaps    []*entity.IncidentOverlap
+		upsert      func(incidents []*entity.Incident) ([]bool, error)
+		wantCommit  bool
+		wantActions []string
+		wantIDs     []string
+	}{
+		{
+			name: "Created and updated",
+			features: []entity.GeoJsonFeature{
+				feature(nil, square, map[string]any{"name": "Зона 1", "external_id": "z-1", "severity": "severe"}),
+				feature(42.0, square, map[string]any{"name": "Зона 2"}),
+			},
+			upsert: func([]*entity.Incident) ([]bool, error) {
+				return []bool{true, false}, nil
+			},
+			wantCommit:  true,
+			wantActions: []string{entity.ImportActionCreated, entity.ImportActionUpdated},
+			wantIDs:     []string{"z-1", "42"},
+		},
+		{
+			name: "Custom mapping",
+			features: []entity.GeoJsonFeature{
+				feature(nil, square, map[string]any{"ZONE_NAME": "Зона 1", "ZONE_ID": 7.0}),
+			},
+			mapping: entity.ImportMapping{Name: "ZONE_NAME", ExternalID: "ZONE_ID"},
+			upsert: func([]*entity.Incident) ([]bool, error) {
+				return []bool{true}, nil
+			},
+			wantCommit:  true,
+			wantActions: []string{entity.ImportActionCreated},
+			wantIDs:     []string{"7"},
+		},
+		{
+			name: "Invalid features reject the whole import",
+			features: []entity.GeoJsonFeature{
+				feature("a", square, map[string]any{"name": "Зона 1"}),
+				feature("b", multi, map[string]any{"name": "Зона 2"}),
+				feature("c", open, map[string]any{"name": "Зона 3"}),
+				feature("d", square, map[string]any{}),
+				feature("a", square, map[string]any{"name": "Дубль"}),
+				feature("e", square, map[string]any{"name": "Зона 5", "severity": "huge"}),
+			},
+			wantActions: []string{
+				entity.ImportActionValid,
+				entity.ImportActionFailed,
+				entity.ImportActionFailed,
+				entity.ImportActionFailed,
+				entity.ImportActionFailed,
+				entity.ImportActionFailed,
+			},
+			wantIDs: []string{"a", "b", "c", "d", "a", "e"},
+		},
+		{
+			name: "Update of a published incident goes back to review",
+			features: []entity.GeoJsonFeature{
+				feature("z-1", square, map[string]any{"name": "Зона 1", "severity": "severe"}),
+			},
+			existing: map[string]*entity.Incident{
+				"z-1": {ID: uuid.New(), ExternalID: "z-1", Status: entity.IncidentStatusPublished, Severity: entity.IncidentSeverityModerate},
+			},
+			upsert: func(incidents []*entity.Incident) ([]bool, error) {
+				incidents[0].Status = entity.IncidentStatusPendingReview
+				return []bool{false}, nil
+			},
+			wantCommit:  true,
+			wantActions: []string{entity.ImportActionUpdated},
+			wantIDs:     []string{"z-1"},
+		},
+		{
+			name: "Resolved incident is not changed",
+			features: []entity.GeoJsonFeature{
+				feature("z-1", square, map[string]any{"name": "Зона 1"}),
+				feature("z-2", square, map[string]any{"name": "Зона 2"}),
+			},
+			existing: map[string]*entity.Incident{
+				"z-1": {ID: uuid.New(), ExternalID: "z-1", Status: entity.IncidentStatusResolved},
+			},
+			wantActions: []string{entity.ImportActionFailed, entity.ImportActionValid},
+			wantIDs:     []string{"z-1", "z-2"},
+		},
+		{
+			name: "Overlap above the limit rejects the feature",
+			features: []entity.GeoJsonFeature{
+				feature("z-1", square, map[string]any{"name": "Зона 1"}),
+			},
+			overlaps: []*entity.IncidentOverlap{
+				{IncidentID: uuid.New(), Name: "Пожар", OverlapAreaM2: 1000, OverlapPercent: 60, IncidentPercent: 10},
+			},
+			wantActions: []string{entity.ImportActionFailed},
+			wantIDs:     []string{"z-1"},
+		},
+		{
+			name: "Database error is attributed to the feature",
+			features: []entity.GeoJsonFeature{
+				feature("a", square, map[string]any{"name": "Зона 1"}),
+				feature("b", square, map[string]any{"name": "Зона 2"}),
+			},
+			upsert: func([]*entity.Incident) ([]bool, error) {
+				return nil, &postgres.BatchItemError{Index: 1, Err: errors.New("constraint violation")}
+			},
+			wantActions: []string{entity.ImportActionValid, entity.ImportActionFailed},
+			wantIDs:     []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByExternalIDs", mock.Anything, mock.Anything).Return(tt.existing, nil).Maybe()
+			repo.On("FindOverlaps", mock.Anything, mock.Anything, mock.Anything).Return(tt.overlaps, nil).Maybe()
+			if tt.upsert != nil {
+				repo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(incidents []*entity.Incident) bool {
+					return len(incidents) == len(tt.features) && incidents[0].ChangedBy == "operator"
+				})).Return(func(_ context.Context, incidents []*entity.Incident) ([]bool, error) {
+					for _, i := range incidents {
+						i.ID = uuid.New()
+					}
+					return tt.upsert(incidents)
+				})
+			}
+
+			cfg := &config.Config{Incident: config.IncidentConfig{OverlapMaxPercent: 50}}
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
+
+			got, err := s.Import(context.Background(), &entity.ImportIncidentsRequest{
+				Collection: entity.GeoJsonFeatureCollection{Type: "FeatureCollection", Features: tt.features},
+				Mapping:    tt.mapping,
+				Actor:      "operator",
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantCommit, got.Committed)
+			require.Len(t, got.Features, len(tt.features))
+			for i, f := range got.Features {
+				assert.Equal(t, tt.wantActions[i], f.Action, "feature %d", i)
+				assert.Equal(t, tt.wantIDs[i], f.ExternalID, "feature %d", i)
+				assert.Equal(t, f.Action == entity.ImportActionFailed, len(f.Errors) > 0, "feature %d", i)
+				assert.Equal(t, tt.wantCommit, f.ID != "", "feature %d", i)
+			}
+		})
+	}
+}
+
+func TestIncidentService_ImportDryRun(t *testing.T) {
+	kml := `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2"><Document><Folder>
+  <Placemark id="z-1">
+    <name>Зона 1</name>
+    <ExtendedData><Data name="severity"><value>severe</value></Data></ExtendedData>
+    <Polygon><outerBoundaryIs><LinearRing>
+      <coordinates>0,0,0 1,0,0 1,1,0 0,1,0 0,0,0</coordinates>
+    </LinearRing></outerBoundaryIs></Polygon>
+  </Placemark>
+  <Placemark id="z-2">
+    <name>Бабочка</name>
+    <Polygon><outerBoundaryIs><LinearRing>
+      <coordinates>0,0 1,1 1,0 0,1 0,0</coordinates>
+    </LinearRing></outerBoundaryIs></Polygon>
+  </Placemark>
+  <Placemark id="z-3">
+    <name>Точка</name>
+    <Point><coordinates>0,0</coordinates></Point>
+  </Placemark>
+</Folder></Document></kml>`
+
+	features, err := geoimport.ParseKML([]byte(kml))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		validation   string
+		wantActions  []string
+		wantIssueFor []bool
+	}{
+		{
+			name:         "Basic validation reports topology issues without failing",
+			wantActions:  []string{entity.ImportActionValid, entity.ImportActionValid, entity.ImportActionFailed},
+			wantIssueFor: []bool{false, true, true},
+		},
+		{
+			name:         "Topology validation fails the feature",
+			validation:   polygonValidationTopology,
+			wantActions:  []string{entity.ImportActionValid, entity.ImportActionFailed, entity.ImportActionFailed},
+			wantIssueFor: []bool{false, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// UpsertMany не ожидается: пробный импорт только проверяет объекты
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByExternalIDs", mock.Anything, mock.Anything).Return(map[string]*entity.Incident{}, nil)
+			repo.On("FindOverlaps", mock.Anything, mock.Anything, (*uuid.UUID)(nil)).Return(nil, nil)
+			cfg := &config.Config{Incident: config.IncidentConfig{PolygonValidation: tt.validation}}
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
+
+			got, err := s.Import(context.Background(), &entity.ImportIncidentsRequest{
+				Collection: entity.GeoJsonFeatureCollection{Type: "FeatureCollection", Features: features},
+				DryRun:     true,
+			})
+			require.NoError(t, err)
+
+			assert.True(t, got.DryRun)
+			assert.False(t, got.Committed)
+			require.Len(t, got.Features, 3)
+			for i, f := range got.Features {
+				assert.Equal(t, tt.wantActions[i], f.Action, "feature %d", i)
+				assert.Equal(t, tt.wantIssueFor[i], len(f.Issues) > 0, "feature %d", i)
+				assert.Empty(t, f.ID, "feature %d", i)
+			}
+
+			assert.Equal(t, "z-1", got.Features[0].ExternalID)
+			assert.Equal(t, entity.PolygonIssueSelfIntersection, got.Features[1].Issues[0].Code)
+		})
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
 	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/levinOo/geo-incedent-service/pkg/validator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIncidentService_Create(t *testing.T) {
@@ -43,6 +47,7 @@ func TestIncidentService_Create(t *testing.T) {
 				req: validReq,
 			},
 			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindOverlaps", mock.Anything, validReq.Area, (*uuid.UUID)(nil)).Return(nil, nil)
 				r.On("Create", mock.Anything, mock.MatchedBy(func(i *entity.Incident) bool {
 					return i.Name == validReq.Name && i.Description == validReq.Description
 				})).Return(nil)
@@ -75,6 +80,7 @@ func TestIncidentService_Create(t *testing.T) {
 				req: validReq,
 			},
 			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindOverlaps", mock.Anything, validReq.Area, (*uuid.UUID)(nil)).Return(nil, nil)
 				r.On("Create", mock.Anything, mock.Anything).Return(errors.New("db error"))
 			},
 			wantErr: true,
@@ -86,7 +92,7 @@ func TestIncidentService_Create(t *testing.T) {
 			repo := mocks.NewIncidentRepo(t)
 			tt.mock(repo)
 
-			s := NewIncidentService(repo, &config.Config{})
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
 			got, err := s.Create(tt.args.ctx, tt.args.req)
 
 			if tt.wantErr {
@@ -110,41 +116,45 @@ func TestIncidentService_FindByID(t *testing.T) {
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	latest := &entity.IncidentUpdate{ID: uuid.New(), IncidentID: id, Message: "Пожар локализован на 40%"}
 
 	tests := []struct {
 		name    string
 		id      string
-		mock    func(r *mocks.IncidentRepo)
+		mock    func(r *mocks.IncidentRepo, u *mocks.IncidentUpdateRepo)
 		want    *entity.GetIncidentResponse
 		wantErr bool
 	}{
 		{
 			name: "Success",
 			id:   id.String(),
-			mock: func(r *mocks.IncidentRepo) {
+			mock: func(r *mocks.IncidentRepo, u *mocks.IncidentUpdateRepo) {
 				r.On("FindByID", mock.Anything, id).Return(testIncident, nil)
+				u.On("FindLatest", mock.Anything, []uuid.UUID{id}, (*time.Time)(nil)).
+					Return(map[uuid.UUID]*entity.IncidentUpdate{id: latest}, nil)
 			},
 			want: &entity.GetIncidentResponse{
-				ID:          id.String(),
-				Name:        testIncident.Name,
-				Description: testIncident.Description,
-				Area:        testIncident.Area,
-				IsActive:    testIncident.IsActive,
-				CreatedAt:   testIncident.CreatedAt,
-				UpdatedAt:   testIncident.UpdatedAt,
+				ID:           id.String(),
+				Name:         testIncident.Name,
+				Description:  testIncident.Description,
+				Area:         testIncident.Area,
+				IsActive:     testIncident.IsActive,
+				CreatedAt:    testIncident.CreatedAt,
+				UpdatedAt:    testIncident.UpdatedAt,
+				LatestUpdate: latest,
 			},
 			wantErr: false,
 		},
 		{
 			name:    "Invalid UUID",
 			id:      "invalid",
-			mock:    func(r *mocks.IncidentRepo) {},
+			mock:    func(r *mocks.IncidentRepo, u *mocks.IncidentUpdateRepo) {},
 			wantErr: true,
 		},
 		{
 			name: "Not Found",
 			id:   id.String(),
-			mock: func(r *mocks.IncidentRepo) {
+			mock: func(r *mocks.IncidentRepo, u *mocks.IncidentUpdateRepo) {
 				r.On("FindByID", mock.Anything, id).Return(nil, errors.New("not found"))
 			},
 			wantErr: true,
@@ -154,9 +164,10 @@ func TestIncidentService_FindByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := mocks.NewIncidentRepo(t)
-			tt.mock(repo)
+			updates := mocks.NewIncidentUpdateRepo(t)
+			tt.mock(repo, updates)
 
-			s := NewIncidentService(repo, &config.Config{})
+			s := NewIncidentService(repo, updates, events.NewBus(newTestRedis(t).Client), &config.Config{})
 			got, err := s.FindByID(context.Background(), tt.id)
 
 			if tt.wantErr {
@@ -175,7 +186,7 @@ func TestIncidentService_Update(t *testing.T) {
 		ID:          id,
 		Name:        "Old Name",
 		Description: "Old Desc",
-		IsActive:    true,
+		Status:      entity.IncidentStatusDraft,
 	}
 	newName := "New Name"
 
@@ -197,7 +208,7 @@ func TestIncidentService_Update(t *testing.T) {
 				r.On("FindByID", mock.Anything, id).Return(existing, nil)
 				r.On("Update", mock.Anything, mock.MatchedBy(func(i *entity.Incident) bool {
 					return i.Name == newName && i.Description == "Old Desc"
-				})).Return(nil)
+				}), entity.IncidentStatusDraft).Return(nil)
 			},
 			want:    &entity.IncidentResponse{Status: "успешно обновлен"},
 			wantErr: false,
@@ -225,7 +236,7 @@ func TestIncidentService_Update(t *testing.T) {
 			repo := mocks.NewIncidentRepo(t)
 			tt.mock(repo)
 
-			s := NewIncidentService(repo, &config.Config{})
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
 			got, err := s.Update(context.Background(), tt.req, tt.id)
 
 			if tt.wantErr {
@@ -238,6 +249,83 @@ func TestIncidentService_Update(t *testing.T) {
 	}
 }
 
+func TestIncidentService_UpdateStatus(t *testing.T) {
+	id := uuid.New()
+	newName := "New Name"
+
+	tests := []struct {
+		name       string
+		status     string
+		severity   string
+		actor      string
+		repoErr    error
+		wantStatus string
+		wantErr    error
+	}{
+		{
+			name:       "Draft stays draft",
+			status:     entity.IncidentStatusDraft,
+			severity:   entity.IncidentSeveritySevere,
+			wantStatus: entity.IncidentStatusDraft,
+		},
+		{
+			name:       "Published edit goes back to review",
+			status:     entity.IncidentStatusPublished,
+			severity:   entity.IncidentSeveritySevere,
+			actor:      "alice",
+			wantStatus: entity.IncidentStatusPendingReview,
+		},
+		{
+			name:       "Minor published edit without operator",
+			status:     entity.IncidentStatusPublished,
+			severity:   entity.IncidentSeverityMinor,
+			wantStatus: entity.IncidentStatusPendingReview,
+		},
+		{
+			name:     "Severe published edit without operator",
+			status:   entity.IncidentStatusPublished,
+			severity: entity.IncidentSeveritySevere,
+			wantErr:  ErrTwoPersonRule,
+		},
+		{
+			name:     "Resolved incident",
+			status:   entity.IncidentStatusResolved,
+			severity: entity.IncidentSeverityMinor,
+			actor:    "alice",
+			wantErr:  ErrInvalidTransition,
+		},
+		{
+			name:       "Concurrent status change",
+			status:     entity.IncidentStatusDraft,
+			severity:   entity.IncidentSeverityMinor,
+			repoErr:    postgres.ErrStatusConflict,
+			wantStatus: entity.IncidentStatusDraft,
+			wantErr:    ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByID", mock.Anything, id).Return(&entity.Incident{ID: id, Name: "Old Name", Status: tt.status, Severity: tt.severity}, nil)
+			if tt.wantStatus != "" {
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(i *entity.Incident) bool {
+					return i.Status == tt.wantStatus && i.ChangedBy == tt.actor
+				}), tt.status).Return(tt.repoErr)
+			}
+
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+			_, err := s.Update(context.Background(), &entity.UpdateIncidentRequest{Name: &newName, Actor: tt.actor}, id.String())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestIncidentService_GetStats(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -280,7 +368,7 @@ func TestIncidentService_GetStats(t *testing.T) {
 			cfg := &config.Config{
 				HTTPServer: config.HTTPServerConfig{StatsWindowMinutes: tt.settings},
 			}
-			s := NewIncidentService(repo, cfg)
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
 			got, err := s.GetStats(context.Background())
 
 			if tt.wantErr {
@@ -292,3 +380,551 @@ func TestIncidentService_GetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestIncidentService_Events(t *testing.T) {
+	id := uuid.New()
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+	}
+
+	tests := []struct {
+		name     string
+		mock     func(r *mocks.IncidentRepo)
+		call     func(s IncidentService) error
+		wantType string
+	}{
+		{
+			name: "Create",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindOverlaps", mock.Anything, area, (*uuid.UUID)(nil)).Return(nil, nil)
+				r.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					args.Get(1).(*entity.Incident).ID = id
+				}).Return(nil)
+			},
+			call: func(s IncidentService) error {
+				_, err := s.Create(context.Background(), &entity.CreateIncidentRequest{Name: "Fire", Area: area})
+				return err
+			},
+			wantType: entity.EventIncidentCreated,
+		},
+		{
+			name: "Update",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindByID", mock.Anything, id).Return(&entity.Incident{ID: id, Name: "Fire", Area: area, Status: entity.IncidentStatusDraft}, nil)
+				r.On("Update", mock.Anything, mock.Anything, entity.IncidentStatusDraft).Return(nil)
+			},
+			call: func(s IncidentService) error {
+				name := "Flood"
+				_, err := s.Update(context.Background(), &entity.UpdateIncidentRequest{Name: &name}, id.String())
+				return err
+			},
+			wantType: entity.EventIncidentUpdated,
+		},
+		{
+			name: "Delete",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("Delete", mock.Anything, id, "", "").Return(nil, nil)
+			},
+			call: func(s IncidentService) error {
+				_, err := s.Delete(context.Background(), id.String(), &entity.DeleteIncidentRequest{})
+				return err
+			},
+			wantType: entity.EventIncidentDeactivated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			tt.mock(repo)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			rdb := newTestRedis(t)
+			bus := events.NewBus(rdb.Client)
+			sub := bus.Subscribe(events.Filter{IncidentID: &id})
+			defer bus.Unsubscribe(sub)
+			go bus.Run(ctx)
+
+			// Дожидаемся подписки на канал, иначе событие уйдет до ее появления
+			require.Eventually(t, func() bool {
+				n, err := rdb.Client.PubSubNumSub(ctx, "events").Result()
+				return err == nil && n["events"] > 0
+			}, time.Second, 10*time.Millisecond)
+
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), bus, &config.Config{})
+			require.NoError(t, tt.call(s))
+
+			select {
+			case msg := <-sub.C:
+				assert.Equal(t, tt.wantType, msg.Event.Type)
+				assert.Equal(t, id, *msg.Event.IncidentID)
+			case <-time.After(time.Second):
+				t.Fatal("событие не получено")
+			}
+		})
+	}
+}
+
+func TestIncidentService_Diff(t *testing.T) {
+	id := uuid.New()
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+	}
+
+	v1 := &entity.IncidentVersion{Version: 1, Name: "Fire", Area: area, IsActive: true}
+	v2 := &entity.IncidentVersion{Version: 2, Name: "Big fire", Area: area, IsActive: true}
+	v3 := &entity.IncidentVersion{Version: 3, Name: "Big fire", Area: area, IsActive: false}
+
+	tests := []struct {
+		name        string
+		from, to    int
+		mock        func(r *mocks.IncidentRepo)
+		wantFrom    int
+		wantTo      int
+		wantChanges []string
+		wantErr     error
+	}{
+		{
+			name: "Latest against previous",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindVersion", mock.Anything, id, 0).Return(v3, nil)
+				r.On("FindVersion", mock.Anything, id, 2).Return(v2, nil)
+				r.On("DiffGeometry", mock.Anything, id, 2, 3).Return(&entity.GeometryDiff{}, nil)
+			},
+			wantFrom:    2,
+			wantTo:      3,
+			wantChanges: []string{"is_active"},
+		},
+		{
+			name: "Explicit versions with geometry change",
+			from: 1,
+			to:   2,
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindVersion", mock.Anything, id, 2).Return(v2, nil)
+				r.On("FindVersion", mock.Anything, id, 1).Return(v1, nil)
+				r.On("DiffGeometry", mock.Anything, id, 1, 2).Return(&entity.GeometryDiff{Changed: true}, nil)
+			},
+			wantFrom:    1,
+			wantTo:      2,
+			wantChanges: []string{"name", "area"},
+		},
+		{
+			name: "Single version",
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindVersion", mock.Anything, id, 0).Return(v1, nil)
+			},
+			wantErr: ErrInvalidVersion,
+		},
+		{
+			name: "Unknown version",
+			to:   7,
+			mock: func(r *mocks.IncidentRepo) {
+				r.On("FindVersion", mock.Anything, id, 7).Return(nil, postgres.ErrIncidentVersionNotFound)
+			},
+			wantErr: ErrIncidentVersionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			tt.mock(repo)
+
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+			got, err := s.Diff(context.Background(), id.String(), tt.from, tt.to)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, got.FromVersion)
+			assert.Equal(t, tt.wantTo, got.ToVersion)
+
+			var fields []string
+			for _, c := range got.Changes {
+				fields = append(fields, c.Field)
+			}
+			assert.Equal(t, tt.wantChanges, fields)
+		})
+	}
+}
+
+func TestIncidentService_History(t *testing.T) {
+	id := uuid.New()
+
+	t.Run("Total counts all versions, not the page", func(t *testing.T) {
+		repo := mocks.NewIncidentRepo(t)
+		repo.On("CountVersions", mock.Anything, id).Return(5, nil)
+		repo.On("FindVersions", mock.Anything, id, 2, 0).Return([]*entity.IncidentVersion{{Version: 5}, {Version: 4}}, nil)
+
+		s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+		got, err := s.History(context.Background(), id.String(), 2, 0)
+
+		require.NoError(t, err)
+		assert.Len(t, got.Versions, 2)
+		assert.Equal(t, 5, got.Total)
+	})
+
+	t.Run("Unknown incident", func(t *testing.T) {
+		repo := mocks.NewIncidentRepo(t)
+		repo.On("CountVersions", mock.Anything, id).Return(0, nil)
+
+		s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+		_, err := s.History(context.Background(), id.String(), 10, 0)
+
+		assert.ErrorIs(t, err, ErrIncidentNotFound)
+	})
+
+	t.Run("Malformed id", func(t *testing.T) {
+		s := NewIncidentService(mocks.NewIncidentRepo(t), mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+		_, err := s.History(context.Background(), "not-a-uuid", 10, 0)
+
+		assert.ErrorIs(t, err, ErrInvalidIncidentID)
+	})
+}
+
+func TestIncidentService_Workflow(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name      string
+		action    string
+		incident  *entity.Incident
+		threshold string
+		actor     string
+		toStatus  string
+		repoErr   error
+		wantErr   error
+	}{
+		{
+			name:     "Submit draft",
+			action:   "submit",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeveritySevere},
+			actor:    "alice",
+			toStatus: entity.IncidentStatusPendingReview,
+		},
+		{
+			name:     "Approve minor draft directly",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeverityMinor},
+			actor:    "alice",
+			toStatus: entity.IncidentStatusPublished,
+		},
+		{
+			name:     "Approve severe draft without review",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeveritySevere},
+			actor:    "alice",
+			wantErr:  ErrTwoPersonRule,
+		},
+		{
+			name:     "Approve own submission",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPendingReview, Severity: entity.IncidentSeverityExtreme, SubmittedBy: "alice"},
+			actor:    "alice",
+			wantErr:  ErrTwoPersonRule,
+		},
+		{
+			name:     "Submit severe draft without operator",
+			action:   "submit",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeveritySevere},
+			wantErr:  ErrTwoPersonRule,
+		},
+		{
+			name:     "Approve submission without known submitter",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPendingReview, Severity: entity.IncidentSeveritySevere},
+			actor:    "bob",
+			wantErr:  ErrTwoPersonRule,
+		},
+		{
+			name:     "Approve by second operator",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPendingReview, Severity: entity.IncidentSeveritySevere, SubmittedBy: "alice"},
+			actor:    "bob",
+			toStatus: entity.IncidentStatusPublished,
+		},
+		{
+			name:      "Two-person rule disabled",
+			action:    "approve",
+			incident:  &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeverityExtreme},
+			threshold: twoPersonRuleOff,
+			toStatus:  entity.IncidentStatusPublished,
+		},
+		{
+			name:     "Reject pending review",
+			action:   "reject",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPendingReview, Severity: entity.IncidentSeveritySevere, SubmittedBy: "alice"},
+			actor:    "bob",
+			toStatus: entity.IncidentStatusDraft,
+		},
+		{
+			name:     "Resolve draft",
+			action:   "resolve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusDraft, Severity: entity.IncidentSeverityMinor},
+			wantErr:  ErrInvalidTransition,
+		},
+		{
+			name:     "Concurrent status change",
+			action:   "resolve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPublished, Severity: entity.IncidentSeverityMinor},
+			toStatus: entity.IncidentStatusResolved,
+			repoErr:  postgres.ErrStatusConflict,
+			wantErr:  ErrInvalidTransition,
+		},
+		{
+			// Пока инцидент ждал согласования, серьезность подняли до severe: одобрение тем же
+			// оператором по правилу для minor отклоняется условием UPDATE
+			name:     "Severity raised during review",
+			action:   "approve",
+			incident: &entity.Incident{ID: id, Status: entity.IncidentStatusPendingReview, Severity: entity.IncidentSeverityMinor, SubmittedBy: "alice"},
+			actor:    "alice",
+			toStatus: entity.IncidentStatusPublished,
+			repoErr:  postgres.ErrStatusConflict,
+			wantErr:  ErrInvalidTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByID", mock.Anything, id).Return(tt.incident, nil)
+			if tt.toStatus != "" {
+				repo.On("UpdateStatus", mock.Anything, tt.incident, tt.toStatus, mock.Anything, tt.actor, "").Return(nil, tt.repoErr)
+			}
+
+			cfg := &config.Config{Incident: config.IncidentConfig{TwoPersonSeverity: tt.threshold}}
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
+
+			transitions := map[string]func(context.Context, string, *entity.IncidentTransitionRequest) (*entity.IncidentResponse, error){
+				"submit":  s.Submit,
+				"approve": s.Approve,
+				"reject":  s.Reject,
+				"resolve": s.Resolve,
+			}
+			_, err := transitions[tt.action](context.Background(), id.String(), &entity.IncidentTransitionRequest{Actor: tt.actor})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.toStatus, tt.incident.Status)
+			assert.Equal(t, tt.toStatus == entity.IncidentStatusPublished, tt.incident.IsActive)
+		})
+	}
+}
+
+func TestIncidentService_UpdateParent(t *testing.T) {
+	id, parentID, childID := uuid.New(), uuid.New(), uuid.New()
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}},
+	}
+	oldParent := uuid.New()
+
+	tests := []struct {
+		name       string
+		parentID   string
+		parent     *entity.Incident
+		children   []entity.Incident
+		wantErr    error
+		wantParent *uuid.UUID
+	}{
+		{
+			name:       "Attach to parent",
+			parentID:   parentID.String(),
+			parent:     &entity.Incident{ID: parentID, Status: entity.IncidentStatusPublished},
+			wantParent: &parentID,
+		},
+		{
+			name:     "Detach",
+			parentID: "",
+		},
+		{
+			name:     "Self as parent",
+			parentID: id.String(),
+			wantErr:  ErrInvalidParent,
+		},
+		{
+			name:     "Resolved parent",
+			parentID: parentID.String(),
+			parent:   &entity.Incident{ID: parentID, Status: entity.IncidentStatusResolved},
+			wantErr:  ErrInvalidParent,
+		},
+		{
+			name:     "Descendant as parent",
+			parentID: childID.String(),
+			parent:   &entity.Incident{ID: childID, Status: entity.IncidentStatusDraft},
+			children: []entity.Incident{{ID: childID}},
+			wantErr:  ErrInvalidParent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incident := &entity.Incident{ID: id, Name: "Fire", Area: area, ParentID: &oldParent, Status: entity.IncidentStatusDraft}
+
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByID", mock.Anything, id).Return(incident, nil)
+			if tt.parent != nil {
+				repo.On("FindByID", mock.Anything, tt.parent.ID).Return(tt.parent, nil)
+			}
+			if tt.parent != nil && tt.parent.Status != entity.IncidentStatusResolved {
+				repo.On("FindDescendants", mock.Anything, id).Return(tt.children, nil)
+			}
+			if tt.wantErr == nil {
+				repo.On("Update", mock.Anything, mock.Anything, entity.IncidentStatusDraft).Return(nil)
+			}
+
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), &config.Config{})
+			_, err := s.Update(context.Background(), &entity.UpdateIncidentRequest{ParentID: &tt.parentID}, id.String())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantParent, incident.ParentID)
+		})
+	}
+}
+
+func TestIncidentService_FindTree(t *testing.T) {
+	rootID, childID, grandchildID := uuid.New(), uuid.New(), uuid.New()
+
+	repo := mocks.NewIncidentRepo(t)
+	repo.On("FindByID", mock.Anything, rootID).Return(&entity.Incident{ID: rootID, Name: "Wildfire"}, nil)
+	repo.On("FindDescendants", mock.Anything, rootID).Return([]entity.Incident{
+		{ID: childID, Name: "North", ParentID: &rootID},
+		{ID: grandchildID, Name: "North-East", ParentID: &childID},
+	}, nil)
+
+	updates := mocks.NewIncidentUpdateRepo(t)
+	updates.On("FindLatest", mock.Anything, []uuid.UUID{rootID, childID, grandchildID}, (*time.Time)(nil)).
+		Return(map[uuid.UUID]*entity.IncidentUpdate{}, nil)
+
+	s := NewIncidentService(repo, updates, events.NewBus(newTestRedis(t).Client), &config.Config{})
+
+	got, err := s.FindTree(context.Background(), rootID.String())
+	require.NoError(t, err)
+
+	require.Len(t, got.Children, 1)
+	assert.Equal(t, "North", got.Children[0].Name)
+	assert.Equal(t, rootID.String(), got.Children[0].ParentID)
+	require.Len(t, got.Children[0].Children, 1)
+	assert.Equal(t, "North-East", got.Children[0].Children[0].Name)
+}
+
+func TestIncidentService_Overlaps(t *testing.T) {
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+	parentID, otherID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name         string
+		limit        float64
+		overlaps     []*entity.IncidentOverlap
+		wantErr      error
+		wantOverlaps int
+	}{
+		{
+			name:         "Warning only",
+			overlaps:     []*entity.IncidentOverlap{{IncidentID: otherID, OverlapAreaM2: 100, OverlapPercent: 90, IncidentPercent: 90}},
+			wantOverlaps: 1,
+		},
+		{
+			name:     "Touching borders ignored",
+			limit:    50,
+			overlaps: []*entity.IncidentOverlap{{IncidentID: otherID}},
+		},
+		{
+			name:         "Below limit",
+			limit:        50,
+			overlaps:     []*entity.IncidentOverlap{{IncidentID: otherID, OverlapAreaM2: 100, OverlapPercent: 10, IncidentPercent: 40}},
+			wantOverlaps: 1,
+		},
+		{
+			name:     "Strict rejects",
+			limit:    50,
+			overlaps: []*entity.IncidentOverlap{{IncidentID: otherID, OverlapAreaM2: 100, OverlapPercent: 10, IncidentPercent: 80}},
+			wantErr:  ErrIncidentOverlap,
+		},
+		{
+			name:     "Parent is not a duplicate",
+			limit:    50,
+			overlaps: []*entity.IncidentOverlap{{IncidentID: parentID, OverlapAreaM2: 100, OverlapPercent: 100, IncidentPercent: 20}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			repo.On("FindByID", mock.Anything, parentID).Return(&entity.Incident{ID: parentID, Status: entity.IncidentStatusPublished}, nil)
+			repo.On("FindOverlaps", mock.Anything, area, (*uuid.UUID)(nil)).Return(tt.overlaps, nil)
+			if tt.wantErr == nil {
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			cfg := &config.Config{Incident: config.IncidentConfig{OverlapMaxPercent: tt.limit}}
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
+
+			got, err := s.Create(context.Background(), &entity.CreateIncidentRequest{Name: "Fire", Area: area, ParentID: parentID.String()})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got.Overlaps, tt.wantOverlaps)
+		})
+	}
+}
+
+func TestIncidentService_TopologyValidation(t *testing.T) {
+	bowTie := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 1}, {1, 0}, {0, 1}, {0, 0}}},
+	}
+
+	tests := []struct {
+		name    string
+		mode    string
+		wantErr bool
+	}{
+		{name: "Basic accepts bow-tie", mode: ""},
+		{name: "Topology rejects bow-tie", mode: polygonValidationTopology, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewIncidentRepo(t)
+			if !tt.wantErr {
+				repo.On("FindOverlaps", mock.Anything, bowTie, (*uuid.UUID)(nil)).Return(nil, nil)
+				repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			}
+
+			cfg := &config.Config{Incident: config.IncidentConfig{PolygonValidation: tt.mode}}
+			s := NewIncidentService(repo, mocks.NewIncidentUpdateRepo(t), events.NewBus(newTestRedis(t).Client), cfg)
+
+			_, err := s.Create(context.Background(), &entity.CreateIncidentRequest{Name: "Fire", Area: bowTie})
+
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			var topologyErr *validator.TopologyError
+			require.ErrorAs(t, err, &topologyErr)
+			assert.Equal(t, entity.PolygonIssueSelfIntersection, topologyErr.Issues[0].Code)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/queue"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+)
+
+const (
+	defaultUpdateNotifyWindow = time.Hour
+	maxUpdateRecipients       = 1000
+)
+
+var ErrIncidentUpdateNotFound = errors.New("сообщение по инциденту не найдено")
+
+type IncidentUpdateService interface {
+	Create(ctx context.Context, incidentID string, req *entity.CreateIncidentUpdateRequest) (*entity.CreateIncidentUpdateResponse, error)
+	FindByID(ctx context.Context, incidentID, id string) (*entity.IncidentUpdate, error)
+	FindAll(ctx context.Context, incidentID string, limit, offset int) (*entity.GetIncidentUpdatesResponse, error)
+	Update(ctx context.Context, incidentID, id string, req *entity.UpdateIncidentUpdateRequest) (*entity.IncidentUpdate, error)
+	Delete(ctx context.Context, incidentID, id string) (*entity.IncidentResponse, error)
+}
+
+type IncidentUpdateServiceImpl struct {
+	repo         postgres.IncidentUpdateRepo
+	incidentRepo postgres.IncidentRepo
+	locationRepo postgres.LocationRepo
+	queue        queue.Queue
+	cfg          *config.Config
+}
+
+func NewIncidentUpdateService(repo postgres.IncidentUpdateRepo, incidentRepo postgres.IncidentRepo, locationRepo postgres.LocationRepo, redis *db.Redis, cfg *config.Config) IncidentUpdateService {
+	return &IncidentUpdateServiceImpl{
+		repo:         repo,
+		incidentRepo: incidentRepo,
+		locationRepo: locationRepo,
+		queue:        *queue.NewQueue(redis.Client),
+		cfg:          cfg,
+	}
+}
+
+// Create публикует сообщение о ходе инцидента. С notify сообщение отправляется вебхуком
+// пользователям, проверявшим локацию внутри зоны за INCIDENT_UPDATE_NOTIFY_WINDOW.
+// Пользователей уведомляют только по опубликованным инцидентам.
+func (s *IncidentUpdateServiceImpl) Create(ctx context.Context, incidentID string, req *entity.CreateIncidentUpdateRequest) (*entity.CreateIncidentUpdateResponse, error) {
+	id, err := uuid.Parse(incidentID)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+	}
+
+	incident, err := s.incidentRepo.FindByID(ctx, id)
+	if err != nil {
+		slog.Error("не удалось найти инцидент", "error", err)
+		return nil, fmt.Errorf("не удалось найти инцидент: %w", err)
+	}
+
+	update := &entity.IncidentUpdate{
+		IncidentID: id,
+		Message:    req.Message,
+		Author:     req.Actor,
+	}
+
+	if err := s.repo.Create(ctx, update); err != nil {
+		slog.Error("не удалось создать сообщение по инциденту", "error", err)
+		return nil, fmt.Errorf("не удалось создать сообщение по инциденту: %w", err)
+	}
+
+	resp := &entity.CreateIncidentUpdateResponse{Update: update}
+
+	if !req.Notify {
+		return resp, nil
+	}
+	if incident.Status != entity.IncidentStatusPublished {
+		slog.Warn("инцидент не опубликован, пользователи не уведомляются", "id", id, "status", incident.Status)
+		return resp, nil
+	}
+
+	resp.Notified, resp.Truncated = s.notify(ctx, incident, update)
+
+	return resp, nil
+}
+
+// notify ставит в очередь вебхук с сообщением каждому пользователю, недавно бывшему в зоне.
+// Ошибки не прерывают создание сообщения: оно уже сохранено.
+func (s *IncidentUpdateServiceImpl) notify(ctx context.Context, incident *entity.Incident, update *entity.IncidentUpdate) (int, bool) {
+	window := s.cfg.Incident.UpdateNotifyWindow
+	if window <= 0 {
+		window = defaultUpdateNotifyWindow
+	}
+
+	users, total, err := s.locationRepo.FindUsersInArea(ctx, incident.Area, time.Now().Add(-window), maxUpdateRecipients)
+	if err != nil {
+		slog.Error("не удалось найти пользователей в зоне", "id", incident.ID, "error", err)
+		return 0, false
+	}
+
+	updateID := update.ID
+	notified := 0
+	for _, user := range users {
+		task := &entity.WebhookTask{
+			ID:         uuid.New(),
+			Name:       incident.Name,
+			UserID:     user.UserID,
+			IncidentID: incident.ID,
+			CreatedAt:  time.Now(),
+			UpdateID:   &updateID,
+			Message:    update.Message,
+		}
+
+		if err := s.queue.Enqueue(ctx, task); err != nil {
+			slog.Error("ошибка добавления вебхука в очередь", "user_id", user.UserID, "error", err)
+			continue
+		}
+		notified++
+	}
+
+	return notified, total > len(users)
+}
+
+func (s *IncidentUpdateServiceImpl) FindByID(ctx context.Context, incidentID, id string) (*entity.IncidentUpdate, error) {
+	incUUID, updateUUID, err := parseUpdateIDs(incidentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := s.repo.FindByID(ctx, incUUID, updateUUID)
+	if err != nil {
+		slog.Error("не удалось найти сообщение по инциденту", "id", id, "error", err)
+		return nil, wrapUpdateNotFound("не удалось найти сообщение по инциденту", err)
+	}
+
+	return update, nil
+}
+
+func (s *IncidentUpdateServiceImpl) FindAll(ctx context.Context, incidentID string, limit, offset int) (*entity.GetIncidentUpdatesResponse, error) {
+	id, err := uuid.Parse(incidentID)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+	}
+
+	updates, err := s.repo.FindByIncident(ctx, id, limit, offset)
+	if err != nil {
+		slog.Error("не удалось найти сообщения по инциденту", "error", err)
+		return nil, fmt.Errorf("не удалось найти сообщения по инциденту: %w", err)
+	}
+
+	if updates == nil {
+		updates = []*entity.IncidentUpdate{}
+	}
+
+	return &entity.GetIncidentUpdatesResponse{Updates: updates}, nil
+}
+
+// Update исправляет текст сообщения. Повторно вебхуки не отправляются.
+func (s *IncidentUpdateServiceImpl) Update(ctx context.Context, incidentID, id string, req *entity.UpdateIncidentUpdateRequest) (*entity.IncidentUpdate, error) {
+	incUUID, updateUUID, err := parseUpdateIDs(incidentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := s.repo.FindByID(ctx, incUUID, updateUUID)
+	if err != nil {
+		slog.Error("не удалось найти сообщение по инциденту", "id", id, "error", err)
+		return nil, wrapUpdateNotFound("не удалось найти сообщение по инциденту", err)
+	}
+
+	update.Message = req.Message
+
+	if err := s.repo.Update(ctx, update); err != nil {
+		slog.Error("не удалось обновить сообщение по инциденту", "id", id, "error", err)
+		return nil, wrapUpdateNotFound("не удалось обновить сообщение по инциденту", err)
+	}
+
+	return update, nil
+}
+
+func (s *IncidentUpdateServiceImpl) Delete(ctx context.Context, incidentID, id string) (*entity.IncidentResponse, error) {
+	incUUID, updateUUID, err := parseUpdateIDs(incidentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Delete(ctx, incUUID, updateUUID); err != nil {
+		slog.Error("не удалось удалить сообщение по инциденту", "id", id, "error", err)
+		return nil, wrapUpdateNotFound("не удалось удалить сообщение по инциденту", err)
+	}
+
+	return &entity.IncidentResponse{
+		Status: "успешно удален",
+	}, nil
+}
+
+func parseUpdateIDs(incidentID, id string) (uuid.UUID, uuid.UUID, error) {
+	incUUID, err := uuid.Parse(incidentID)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+	}
+
+	updateUUID, err := uuid.Parse(id)
+	if err != nil {
+		slog.Error("ошибка парсинга uuid", "error", err)
+		return uuid.Nil, uuid.Nil, fmt.Errorf("ошибка парсинга uuid: %w", err)
+	}
+
+	return incUUID, updateUUID, nil
+}
+
+// wrapUpdateNotFound заменяет ошибку репозитория об отсутствии сообщения на ErrIncidentUpdateNotFound,
+// чтобы транспортный слой мог ответить 404
+func wrapUpdateNotFound(msg string, err error) error {
+	if errors.Is(err, postgres.ErrIncidentUpdateNotFound) {
+		return fmt.Errorf("%s: %w", msg, ErrIncidentUpdateNotFound)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
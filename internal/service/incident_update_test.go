@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentUpdateService_Create(t *testing.T) {
+	area := entity.GeoJsonPolygon{
+		Type:        "Polygon",
+		Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+	}
+
+	tests := []struct {
+		name          string
+		status        string
+		notify        bool
+		users         []*entity.AffectedUser
+		total         int
+		wantNotified  int
+		wantTruncated bool
+	}{
+		{
+			name:   "Without notify",
+			status: entity.IncidentStatusPublished,
+		},
+		{
+			name:         "Notify users in zone",
+			status:       entity.IncidentStatusPublished,
+			notify:       true,
+			users:        []*entity.AffectedUser{{UserID: "user-1"}, {UserID: "user-2"}},
+			total:        2,
+			wantNotified: 2,
+		},
+		{
+			name:          "Notify truncated",
+			status:        entity.IncidentStatusPublished,
+			notify:        true,
+			users:         []*entity.AffectedUser{{UserID: "user-1"}},
+			total:         5,
+			wantNotified:  1,
+			wantTruncated: true,
+		},
+		{
+			name:   "Draft is not notified",
+			status: entity.IncidentStatusDraft,
+			notify: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incident := &entity.Incident{ID: uuid.New(), Name: "Fire", Area: area, Status: tt.status}
+
+			repo := mocks.NewIncidentUpdateRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			locationRepo := mocks.NewLocationRepo(t)
+
+			incidentRepo.On("FindByID", mock.Anything, incident.ID).Return(incident, nil)
+			repo.On("Create", mock.Anything, mock.MatchedBy(func(u *entity.IncidentUpdate) bool {
+				return u.IncidentID == incident.ID && u.Message == "Пожар локализован" && u.Author == "operator"
+			})).Run(func(args mock.Arguments) {
+				args.Get(1).(*entity.IncidentUpdate).ID = uuid.New()
+			}).Return(nil)
+			if tt.users != nil {
+				locationRepo.On("FindUsersInArea", mock.Anything, area, mock.Anything, maxUpdateRecipients).Return(tt.users, tt.total, nil)
+			}
+
+			rdb := newTestRedis(t)
+			s := NewIncidentUpdateService(repo, incidentRepo, locationRepo, rdb, &config.Config{})
+
+			got, err := s.Create(context.Background(), incident.ID.String(), &entity.CreateIncidentUpdateRequest{
+				Message: "Пожар локализован",
+				Notify:  tt.notify,
+				Actor:   "operator",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNotified, got.Notified)
+			assert.Equal(t, tt.wantTruncated, got.Truncated)
+
+			pending, err := rdb.Client.LLen(context.Background(), "webhook:pending").Result()
+			require.NoError(t, err)
+			assert.Equal(t, int64(tt.wantNotified), pending)
+		})
+	}
+}
+
+func TestIncidentUpdateService_NotFound(t *testing.T) {
+	incidentID, id := uuid.New(), uuid.New()
+
+	repo := mocks.NewIncidentUpdateRepo(t)
+	repo.On("Delete", mock.Anything, incidentID, id).Return(postgres.ErrIncidentUpdateNotFound)
+	repo.On("FindByID", mock.Anything, incidentID, id).Return(nil, postgres.ErrIncidentUpdateNotFound)
+
+	s := NewIncidentUpdateService(repo, mocks.NewIncidentRepo(t), mocks.NewLocationRepo(t), newTestRedis(t), &config.Config{})
+
+	_, err := s.Delete(context.Background(), incidentID.String(), id.String())
+	assert.ErrorIs(t, err, ErrIncidentUpdateNotFound)
+
+	_, err = s.Update(context.Background(), incidentID.String(), id.String(), &entity.UpdateIncidentUpdateRequest{Message: "x"})
+	assert.ErrorIs(t, err, ErrIncidentUpdateNotFound)
+}
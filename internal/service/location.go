@@ -3,13 +3,18 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/db"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
 	"github.com/levinOo/geo-incedent-service/internal/queue"
 	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
 	"github.com/levinOo/geo-incedent-service/pkg/validator"
@@ -18,32 +23,74 @@ import (
 const (
 	cacheKey       = "incidents:active"
 	contextTimeout = 2 * time.Second
+
+	// Доля круга точности внутри зоны по умолчанию, начиная с которой пользователь считается внутри
+	defaultInsideOverlap = 0.9
+)
+
+// Политики обработки неуверенных совпадений (possibly_inside)
+const (
+	uncertainPolicyNotify = "notify"
+	uncertainPolicyRecord = "record"
+	uncertainPolicyIgnore = "ignore"
+)
+
+// Максимальное число изменений в отчете пересчета по умолчанию
+const defaultRecheckLimit = 10000
+
+// Насколько в прошлое по умолчанию может указывать время фиксации координат, переданное трекером
+const defaultMaxBackdate = 24 * time.Hour
+
+const (
+	spoofPolicyExclude         = "exclude"
+	flagReasonImpossibleTravel = "impossible_travel"
+)
+
+var (
+	ErrInvalidCheckID       = errors.New("некорректный id проверки")
+	ErrFlaggedCheckNotFound = errors.New("помеченная проверка не найдена")
+	ErrInvalidLocation      = errors.New("некорректные координаты")
+	ErrNoLocationChecks     = errors.New("у пользователя нет проверок локации")
 )
 
 type LocationService interface {
 	CheckLocation(ctx context.Context, req *entity.CheckLocationRequest) (*entity.CheckLocationResponse, error)
+	CheckRoute(ctx context.Context, req *entity.CheckRouteRequest) (*entity.CheckRouteResponse, error)
+	FindFlaggedChecks(ctx context.Context, reviewStatus string, limit, offset int) (*entity.GetFlaggedChecksResponse, error)
+	ReviewFlaggedCheck(ctx context.Context, id string, req *entity.ReviewLocationCheckRequest) error
+	ReplayLocation(ctx context.Context, req *entity.ReplayLocationRequest) (*entity.ReplayLocationResponse, error)
+	Recheck(ctx context.Context, req *entity.RecheckRequest) (*entity.RecheckReport, error)
 }
 
 type LocationServiceImpl struct {
 	repo         postgres.LocationRepo
 	incidentRepo postgres.IncidentRepo
 	queue        queue.Queue
+	events       events.Publisher
 	redis        *db.Redis
+	cfg          *config.Config
 }
 
-func NewLocationService(repo postgres.LocationRepo, incidentRepo postgres.IncidentRepo, redis *db.Redis) LocationService {
+func NewLocationService(repo postgres.LocationRepo, incidentRepo postgres.IncidentRepo, events events.Publisher, redis *db.Redis, cfg *config.Config) LocationService {
 	return &LocationServiceImpl{
 		repo:         repo,
 		incidentRepo: incidentRepo,
 		queue:        *queue.NewQueue(redis.Client),
+		events:       events,
 		redis:        redis,
+		cfg:          cfg,
 	}
 }
 
 func (s *LocationServiceImpl) CheckLocation(ctx context.Context, req *entity.CheckLocationRequest) (*entity.CheckLocationResponse, error) {
 	if err := validator.ValidateLocation(req.UserLocation); err != nil {
 		slog.Error("ошибка валидации локации", "error", err)
-		return nil, fmt.Errorf("ошибка валидации локации: %w", err)
+		return nil, fmt.Errorf("%w: ошибка валидации локации: %w", ErrInvalidLocation, err)
+	}
+
+	createdAt, err := s.checkTime(req)
+	if err != nil {
+		return nil, err
 	}
 
 	var incidents []entity.Incident
@@ -67,21 +114,14 @@ func (s *LocationServiceImpl) CheckLocation(ctx context.Context, req *entity.Che
 		}
 	}
 
-	var matchedIncidents []*entity.LocationCheckIncident
-	slog.Debug("Проверка инцидентов", "count", len(incidents))
-	for _, inc := range incidents {
-		slog.Debug("Проверка инцидента", "name", inc.Name, "area", inc.Area)
-		if inc.Area.Contains(req.UserLocation.Lat, req.UserLocation.Lon) {
-			slog.Info("Инцидент найден", "name", inc.Name)
-			matchedIncidents = append(matchedIncidents, &entity.LocationCheckIncident{
-				ID:          inc.ID,
-				Name:        inc.Name,
-				Description: inc.Description,
-			})
-		}
-	}
+	matchedIncidents, status := s.matchIncidents(incidents, req.UserLocation)
+
+	policy := s.uncertainPolicy()
+	isDanger := status == entity.LocationStatusInside ||
+		(status == entity.LocationStatusPossiblyInside && policy != uncertainPolicyIgnore)
+	notify := status == entity.LocationStatusInside ||
+		(status == entity.LocationStatusPossiblyInside && policy == uncertainPolicyNotify)
 
-	isDanger := len(matchedIncidents) > 0
 	var incidentID *uuid.UUID
 	if isDanger {
 		id := matchedIncidents[0].ID
@@ -93,7 +133,34 @@ func (s *LocationServiceImpl) CheckLocation(ctx context.Context, req *entity.Che
 		UserLocation: req.UserLocation,
 		IsDanger:     isDanger,
 		IncidentID:   incidentID,
-		CreatedAt:    time.Now(),
+		MatchStatus:  status,
+		CreatedAt:    createdAt,
+	}
+
+	// Соседние проверки нужно получить до сохранения текущей. Точка, доставленная с опозданием,
+	// встает между ними, поэтому сравнивается и с предыдущей, и со следующей по времени проверкой.
+	var prev, next *entity.LocationCheck
+	if req.CheckTrajectory || s.cfg.Location.SpoofMaxSpeedKmh > 0 {
+		prev, next, err = s.repo.FindAdjacentLocationChecks(ctx, req.UserID, check.CreatedAt)
+		if err != nil {
+			slog.Error("не удалось получить соседние проверки", "error", err)
+		}
+	}
+
+	s.detectSpoofing(prev, check)
+	if !check.IsFlagged {
+		s.detectSpoofing(next, check)
+	}
+
+	// Отрезок от или до подозрительной точки не описывает реальный путь пользователя
+	var transit *entity.TransitEvent
+	if req.CheckTrajectory && !check.IsFlagged {
+		if prev != nil && !prev.IsFlagged {
+			transit = s.checkTrajectory(prev, check, incidents)
+		}
+		if transit == nil && next != nil && !next.IsFlagged {
+			transit = s.checkTrajectory(check, next, incidents)
+		}
 	}
 
 	if err := s.repo.SaveLocationCheck(ctx, check); err != nil {
@@ -101,7 +168,7 @@ func (s *LocationServiceImpl) CheckLocation(ctx context.Context, req *entity.Che
 		return nil, fmt.Errorf("ошибка сохранения проверки локации: %w", err)
 	}
 
-	if isDanger && len(matchedIncidents) > 0 {
+	if notify && !check.IsExcluded && len(matchedIncidents) > 0 {
 		task := &entity.WebhookTask{
 			ID:         uuid.New(),
 			Name:       matchedIncidents[0].Name,
@@ -118,8 +185,385 @@ func (s *LocationServiceImpl) CheckLocation(ctx context.Context, req *entity.Che
 		}
 	}
 
+	if isDanger {
+		s.publishDanger(ctx, check, matchedIncidents, policy)
+	}
+
 	return &entity.CheckLocationResponse{
 		IsDanger:  isDanger,
+		Status:    status,
 		Incidents: matchedIncidents,
+		Transit:   transit,
 	}, nil
 }
+
+// checkTime возвращает время проверки: время фиксации координат из запроса или текущее. Время из
+// будущего заменяется текущим, а время старше LOCATION_MAX_BACKDATE (в том числе нулевое)
+// отклоняется с ErrInvalidLocation.
+func (s *LocationServiceImpl) checkTime(req *entity.CheckLocationRequest) (time.Time, error) {
+	now := time.Now()
+	if req.Timestamp == nil || req.Timestamp.After(now) {
+		return now, nil
+	}
+
+	maxBackdate := s.cfg.Location.MaxBackdate
+	if maxBackdate <= 0 {
+		maxBackdate = defaultMaxBackdate
+	}
+
+	if req.Timestamp.Before(now.Add(-maxBackdate)) {
+		slog.Error("время фиксации координат слишком старое", "user_id", req.UserID, "timestamp", *req.Timestamp)
+		return time.Time{}, fmt.Errorf("%w: время фиксации %s старше допустимых %s", ErrInvalidLocation,
+			req.Timestamp.Format(time.RFC3339), maxBackdate)
+	}
+
+	return *req.Timestamp, nil
+}
+
+// publishDanger отправляет в ленту оператора событие location.danger по каждой зоне,
+// совпадение с которой считается опасным при текущей политике
+func (s *LocationServiceImpl) publishDanger(ctx context.Context, check *entity.LocationCheck, incidents []*entity.LocationCheckIncident, policy string) {
+	for _, inc := range incidents {
+		if inc.Status == entity.LocationStatusPossiblyInside && policy == uncertainPolicyIgnore {
+			continue
+		}
+
+		id := inc.ID
+		event := &entity.Event{
+			Type:       entity.EventLocationDanger,
+			IncidentID: &id,
+			UserID:     check.UserID,
+			Data: &entity.DangerEventData{
+				Status:       inc.Status,
+				UserLocation: check.UserLocation,
+				IsFlagged:    check.IsFlagged,
+			},
+			CreatedAt: check.CreatedAt,
+		}
+
+		if err := s.events.Publish(ctx, event); err != nil {
+			slog.Error("не удалось опубликовать событие опасности", "user_id", check.UserID, "error", err)
+		}
+	}
+}
+
+// matchIncidents сопоставляет точку с активными инцидентами по правилам matchStatus.
+// Возвращает совпавшие зоны (сначала те, в которых пользователь находится наверняка) и итоговый
+// статус: inside, если хотя бы одна зона дала inside, иначе possibly_inside или outside.
+func (s *LocationServiceImpl) matchIncidents(incidents []entity.Incident, location entity.UserLocation) ([]*entity.LocationCheckIncident, string) {
+	var matchedIncidents []*entity.LocationCheckIncident
+	status := entity.LocationStatusOutside
+	slog.Debug("Проверка инцидентов", "count", len(incidents))
+	for _, inc := range incidents {
+		if !inc.IsActive {
+			continue
+		}
+		slog.Debug("Проверка инцидента", "name", inc.Name, "area", inc.Area)
+
+		incidentStatus := s.matchStatus(&inc.Area, location)
+		if incidentStatus == entity.LocationStatusOutside {
+			continue
+		}
+
+		slog.Info("Инцидент найден", "name", inc.Name, "status", incidentStatus)
+		matchedIncidents = append(matchedIncidents, &entity.LocationCheckIncident{
+			ID:          inc.ID,
+			Name:        inc.Name,
+			Description: inc.Description,
+			Status:      incidentStatus,
+		})
+
+		if incidentStatus == entity.LocationStatusInside || status == entity.LocationStatusOutside {
+			status = incidentStatus
+		}
+	}
+
+	// Зоны, в которых пользователь находится наверняка, идут первыми
+	sort.SliceStable(matchedIncidents, func(i, j int) bool {
+		return matchedIncidents[i].Status == entity.LocationStatusInside &&
+			matchedIncidents[j].Status != entity.LocationStatusInside
+	})
+
+	return matchedIncidents, status
+}
+
+func (s *LocationServiceImpl) uncertainPolicy() string {
+	policy := s.cfg.Location.UncertainPolicy
+	if policy != uncertainPolicyRecord && policy != uncertainPolicyIgnore {
+		policy = uncertainPolicyNotify
+	}
+	return policy
+}
+
+// ReplayLocation отвечает на вопрос «был ли пользователь в опасной зоне в момент at» по зонам
+// в том виде, в котором они действовали тогда. Если координаты не переданы, берется последняя
+// достоверная проверка пользователя не позже at. Результат никуда не сохраняется, вебхуки
+// и события не отправляются.
+func (s *LocationServiceImpl) ReplayLocation(ctx context.Context, req *entity.ReplayLocationRequest) (*entity.ReplayLocationResponse, error) {
+	resp := &entity.ReplayLocationResponse{
+		AsOf:   req.At,
+		UserID: req.UserID,
+	}
+
+	switch {
+	case req.UserLocation != nil:
+		resp.UserLocation = *req.UserLocation
+	case req.UserID != "":
+		check, err := s.repo.FindLocationCheckAt(ctx, req.UserID, req.At)
+		if err != nil {
+			slog.Error("не удалось получить проверку пользователя на момент времени", "error", err)
+			return nil, fmt.Errorf("не удалось получить проверку пользователя на момент времени: %w", err)
+		}
+		if check == nil {
+			return nil, fmt.Errorf("%w: %s до %s", ErrNoLocationChecks, req.UserID, req.At.Format(time.RFC3339))
+		}
+		resp.UserLocation = check.UserLocation
+		resp.LocationTime = &check.CreatedAt
+	default:
+		return nil, fmt.Errorf("%w: нужно указать user_location или user_id", ErrInvalidLocation)
+	}
+
+	if err := validator.ValidateLocation(resp.UserLocation); err != nil {
+		slog.Error("ошибка валидации локации", "error", err)
+		return nil, fmt.Errorf("%w: ошибка валидации локации: %w", ErrInvalidLocation, err)
+	}
+
+	incidents, err := s.incidentRepo.FindAllAsOf(ctx, req.At, 1000, 0)
+	if err != nil {
+		slog.Error("не удалось получить инциденты на момент времени", "error", err)
+		return nil, fmt.Errorf("ошибка получения инцидентов на момент времени: %w", err)
+	}
+
+	matchedIncidents, status := s.matchIncidents(incidents, resp.UserLocation)
+
+	resp.Status = status
+	resp.Incidents = matchedIncidents
+	resp.IsDanger = status == entity.LocationStatusInside ||
+		(status == entity.LocationStatusPossiblyInside && s.uncertainPolicy() != uncertainPolicyIgnore)
+
+	return resp, nil
+}
+
+// Recheck пересчитывает сохраненные проверки за период по текущей или исторической геометрии
+// зон, например после исправления ошибочно нарисованного полигона. Возвращает отчет об
+// изменившихся проверках; при req.Apply они обновляются, и статистика начинает учитывать новые значения.
+func (s *LocationServiceImpl) Recheck(ctx context.Context, req *entity.RecheckRequest) (*entity.RecheckReport, error) {
+	if !req.From.Before(req.To) {
+		return nil, fmt.Errorf("начало периода должно быть раньше конца")
+	}
+
+	if req.Geometry == "" {
+		req.Geometry = entity.RecheckGeometryCurrent
+	}
+	if req.Limit <= 0 {
+		req.Limit = defaultRecheckLimit
+	}
+
+	changes, scanned, skipped, err := s.repo.RecheckLocationChecks(ctx, req)
+	if err != nil {
+		slog.Error("не удалось пересчитать проверки", "error", err)
+		return nil, fmt.Errorf("не удалось пересчитать проверки: %w", err)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.Before(changes[j].CreatedAt)
+	})
+
+	report := &entity.RecheckReport{
+		From:      req.From,
+		To:        req.To,
+		Geometry:  req.Geometry,
+		Applied:   req.Apply,
+		Scanned:   scanned,
+		Skipped:   skipped,
+		Changed:   len(changes),
+		Truncated: len(changes) == req.Limit,
+		Changes:   changes,
+	}
+	if report.Changes == nil {
+		report.Changes = []*entity.RecheckChange{}
+	}
+
+	for _, ch := range changes {
+		switch {
+		case ch.NewIsDanger && !ch.OldIsDanger:
+			report.BecameDanger++
+		case !ch.NewIsDanger && ch.OldIsDanger:
+			report.BecameSafe++
+		}
+	}
+
+	slog.Info("Пересчет проверок завершен",
+		"from", req.From,
+		"to", req.To,
+		"geometry", req.Geometry,
+		"applied", req.Apply,
+		"scanned", scanned,
+		"skipped", skipped,
+		"changed", len(changes),
+	)
+
+	return report, nil
+}
+
+// matchStatus определяет положение пользователя относительно зоны с учетом точности GPS.
+// Без точности используется обычная проверка попадания точки в полигон.
+func (s *LocationServiceImpl) matchStatus(area *entity.GeoJsonPolygon, location entity.UserLocation) string {
+	if location.AccuracyM == nil || *location.AccuracyM == 0 {
+		if area.Contains(location.Lat, location.Lon) {
+			return entity.LocationStatusInside
+		}
+		return entity.LocationStatusOutside
+	}
+
+	threshold := s.cfg.Location.InsideOverlap
+	if threshold <= 0 || threshold > 1 {
+		threshold = defaultInsideOverlap
+	}
+
+	overlap := area.OverlapRatio(location.Lat, location.Lon, *location.AccuracyM)
+	switch {
+	case overlap >= threshold:
+		return entity.LocationStatusInside
+	case overlap > 0:
+		return entity.LocationStatusPossiblyInside
+	default:
+		return entity.LocationStatusOutside
+	}
+}
+
+func (s *LocationServiceImpl) CheckRoute(ctx context.Context, req *entity.CheckRouteRequest) (*entity.CheckRouteResponse, error) {
+	if err := validator.ValidateLineString(req.Route); err != nil {
+		slog.Error("ошибка валидации маршрута", "error", err)
+		return nil, fmt.Errorf("%w: ошибка валидации маршрута: %w", ErrInvalidLocation, err)
+	}
+
+	incidents, err := s.repo.CheckRoute(ctx, req.Route)
+	if err != nil {
+		slog.Error("не удалось проверить маршрут", "error", err)
+		return nil, fmt.Errorf("ошибка проверки маршрута: %w", err)
+	}
+
+	return &entity.CheckRouteResponse{
+		IsSafe:    len(incidents) == 0,
+		Incidents: incidents,
+	}, nil
+}
+
+// checkTrajectory проверяет отрезок от предыдущей проверки пользователя до текущей
+// и возвращает зоны, которые были пересечены между двумя точками
+func (s *LocationServiceImpl) checkTrajectory(prev, check *entity.LocationCheck, incidents []entity.Incident) *entity.TransitEvent {
+	if prev == nil {
+		return nil
+	}
+
+	maxGap := s.cfg.Location.TrajectoryMaxGap
+	if maxGap > 0 && check.CreatedAt.Sub(prev.CreatedAt) > maxGap {
+		slog.Debug("предыдущая проверка слишком старая для анализа траектории", "user_id", check.UserID, "prev", prev.CreatedAt)
+		return nil
+	}
+
+	from, to := prev.UserLocation, check.UserLocation
+
+	var crossed []*entity.LocationCheckIncident
+	for _, inc := range incidents {
+		if !inc.IsActive {
+			continue
+		}
+
+		if inc.Area.Contains(from.Lat, from.Lon) || inc.Area.Contains(to.Lat, to.Lon) {
+			continue
+		}
+
+		if inc.Area.IntersectsSegment(from.Lat, from.Lon, to.Lat, to.Lon) {
+			slog.Info("Пересечение зоны между проверками", "name", inc.Name, "user_id", check.UserID)
+			crossed = append(crossed, &entity.LocationCheckIncident{
+				ID:          inc.ID,
+				Name:        inc.Name,
+				Description: inc.Description,
+			})
+		}
+	}
+
+	if len(crossed) == 0 {
+		return nil
+	}
+
+	return &entity.TransitEvent{
+		From:      from,
+		To:        to,
+		FromTime:  prev.CreatedAt,
+		ToTime:    check.CreatedAt,
+		Incidents: crossed,
+	}
+}
+
+// detectSpoofing помечает проверку как подозрительную, если скорость перемещения
+// между ней и соседней проверкой (предыдущей или следующей по времени) превышает физически правдоподобную
+func (s *LocationServiceImpl) detectSpoofing(other, check *entity.LocationCheck) {
+	maxSpeed := s.cfg.Location.SpoofMaxSpeedKmh
+	if maxSpeed <= 0 || other == nil {
+		return
+	}
+
+	elapsed := check.CreatedAt.Sub(other.CreatedAt).Abs()
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
+	speed := other.UserLocation.DistanceTo(check.UserLocation) / 1000 / elapsed.Hours()
+	if speed <= maxSpeed {
+		return
+	}
+
+	slog.Warn("подозрение на подмену координат", "user_id", check.UserID, "speed_kmh", speed)
+
+	check.IsFlagged = true
+	check.FlagReason = flagReasonImpossibleTravel
+	check.ImpliedSpeedKmh = &speed
+	check.IsExcluded = s.cfg.Location.SpoofPolicy == spoofPolicyExclude
+}
+
+func (s *LocationServiceImpl) FindFlaggedChecks(ctx context.Context, reviewStatus string, limit, offset int) (*entity.GetFlaggedChecksResponse, error) {
+	switch reviewStatus {
+	case "", entity.ReviewStatusPending, entity.ReviewStatusConfirmed, entity.ReviewStatusDismissed:
+	default:
+		slog.Error("некорректный статус проверки", "status", reviewStatus)
+		return nil, fmt.Errorf("некорректный статус проверки: %s", reviewStatus)
+	}
+
+	checks, err := s.repo.FindFlaggedChecks(ctx, reviewStatus, limit, offset)
+	if err != nil {
+		slog.Error("не удалось получить помеченные проверки", "error", err)
+		return nil, fmt.Errorf("не удалось получить помеченные проверки: %w", err)
+	}
+
+	return &entity.GetFlaggedChecksResponse{
+		Checks: checks,
+		Total:  len(checks),
+	}, nil
+}
+
+func (s *LocationServiceImpl) ReviewFlaggedCheck(ctx context.Context, id string, req *entity.ReviewLocationCheckRequest) error {
+	checkID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		slog.Error("ошибка парсинга id проверки", "error", err)
+		return fmt.Errorf("%w: %s", ErrInvalidCheckID, id)
+	}
+
+	// Подтвержденная подмена исключается из статистики, отклоненная пометка возвращает проверку в учет
+	reviewStatus, excluded := entity.ReviewStatusDismissed, false
+	if req.Decision == "confirm" {
+		reviewStatus, excluded = entity.ReviewStatusConfirmed, true
+	}
+
+	if err := s.repo.ReviewLocationCheck(ctx, checkID, reviewStatus, req.Reviewer, excluded); err != nil {
+		if errors.Is(err, postgres.ErrFlaggedCheckNotFound) {
+			return fmt.Errorf("%w: %d", ErrFlaggedCheckNotFound, checkID)
+		}
+		slog.Error("не удалось сохранить решение по проверке", "error", err)
+		return fmt.Errorf("не удалось сохранить решение по проверке: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,740 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/levinOo/geo-incedent-service/config"
+	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
+	"github.com/levinOo/geo-incedent-service/internal/repo/postgres"
+	"github.com/levinOo/geo-incedent-service/internal/service/mocks"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedis(t *testing.T) *db.Redis {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	return &db.Redis{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+}
+
+func TestLocationService_CheckLocation_Trajectory(t *testing.T) {
+	userID := uuid.NewString()
+	latePoint := time.Now().Add(-2 * time.Minute)
+
+	// Узкая зона между двумя точками: x от 10 до 10.01
+	narrow := entity.Incident{
+		ID:       uuid.New(),
+		Name:     "Narrow",
+		IsActive: true,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{10, 0}, {10.01, 0}, {10.01, 10}, {10, 10}, {10, 0}}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		trajectory  bool
+		maxGap      time.Duration
+		timestamp   *time.Time
+		prev        *entity.LocationCheck
+		next        *entity.LocationCheck
+		wantTransit bool
+	}{
+		{
+			name:       "Crossed between fixes",
+			trajectory: true,
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 5, Lon: 9},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			wantTransit: true,
+		},
+		{
+			name:       "Not crossed",
+			trajectory: true,
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 5, Lon: 10.5},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			wantTransit: false,
+		},
+		{
+			name:        "No previous check",
+			trajectory:  true,
+			prev:        nil,
+			wantTransit: false,
+		},
+		{
+			name:       "Late point crossed before newer check",
+			trajectory: true,
+			timestamp:  &latePoint,
+			next: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 5, Lon: 9},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			wantTransit: true,
+		},
+		{
+			name:       "Previous check too old",
+			trajectory: true,
+			maxGap:     10 * time.Minute,
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 5, Lon: 9},
+				CreatedAt:    time.Now().Add(-time.Hour),
+			},
+			wantTransit: false,
+		},
+		{
+			name:        "Trajectory disabled",
+			trajectory:  false,
+			wantTransit: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+
+			incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{narrow}, nil)
+			if tt.trajectory {
+				locationRepo.On("FindAdjacentLocationChecks", mock.Anything, userID, mock.Anything).Return(tt.prev, tt.next, nil)
+			}
+			locationRepo.On("SaveLocationCheck", mock.Anything, mock.MatchedBy(func(c *entity.LocationCheck) bool {
+				return !c.IsDanger
+			})).Return(nil)
+
+			cfg := &config.Config{Location: config.LocationConfig{TrajectoryMaxGap: tt.maxGap}}
+			rdb := newTestRedis(t)
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, cfg)
+
+			got, err := s.CheckLocation(context.Background(), &entity.CheckLocationRequest{
+				UserID:          userID,
+				UserLocation:    entity.UserLocation{Lat: 5, Lon: 11},
+				CheckTrajectory: tt.trajectory,
+				Timestamp:       tt.timestamp,
+			})
+
+			require.NoError(t, err)
+			assert.False(t, got.IsDanger)
+
+			if tt.wantTransit {
+				require.NotNil(t, got.Transit)
+				require.Len(t, got.Transit.Incidents, 1)
+				assert.Equal(t, narrow.ID, got.Transit.Incidents[0].ID)
+			} else {
+				assert.Nil(t, got.Transit)
+			}
+		})
+	}
+}
+
+func TestLocationService_CheckRoute(t *testing.T) {
+	route := entity.GeoJsonLineString{
+		Type:        "LineString",
+		Coordinates: [][]float64{{9, 5}, {11, 5}},
+	}
+
+	tests := []struct {
+		name     string
+		route    entity.GeoJsonLineString
+		mock     func(r *mocks.LocationRepo)
+		wantSafe bool
+		wantErr  bool
+	}{
+		{
+			name:  "Route crosses incident",
+			route: route,
+			mock: func(r *mocks.LocationRepo) {
+				r.On("CheckRoute", mock.Anything, route).Return([]*entity.RouteIncident{
+					{
+						ID:         uuid.New(),
+						Name:       "Zone",
+						EntryPoint: &entity.UserLocation{Lat: 5, Lon: 10},
+						ExitPoint:  &entity.UserLocation{Lat: 5, Lon: 10.01},
+						LengthM:    1113,
+					},
+				}, nil)
+			},
+			wantSafe: false,
+		},
+		{
+			name:  "Safe route",
+			route: route,
+			mock: func(r *mocks.LocationRepo) {
+				r.On("CheckRoute", mock.Anything, route).Return(nil, nil)
+			},
+			wantSafe: true,
+		},
+		{
+			name: "Invalid route",
+			route: entity.GeoJsonLineString{
+				Type:        "LineString",
+				Coordinates: [][]float64{{9, 5}},
+			},
+			mock:    func(r *mocks.LocationRepo) {},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			tt.mock(locationRepo)
+
+			rdb := newTestRedis(t)
+			s := NewLocationService(locationRepo, mocks.NewIncidentRepo(t), events.NewBus(rdb.Client), rdb, &config.Config{})
+			got, err := s.CheckRoute(context.Background(), &entity.CheckRouteRequest{Route: tt.route})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSafe, got.IsSafe)
+		})
+	}
+}
+
+func TestLocationService_CheckLocation_Accuracy(t *testing.T) {
+	zone := entity.Incident{
+		ID:       uuid.New(),
+		Name:     "Zone",
+		IsActive: true,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+	}
+
+	accuracy := func(m float64) *float64 { return &m }
+
+	tests := []struct {
+		name        string
+		location    entity.UserLocation
+		policy      string
+		wantStatus  string
+		wantDanger  bool
+		wantWebhook bool
+	}{
+		{
+			name:        "Inside without accuracy",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 0.5},
+			wantStatus:  entity.LocationStatusInside,
+			wantDanger:  true,
+			wantWebhook: true,
+		},
+		{
+			name:        "Inside with small accuracy",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 0.5, AccuracyM: accuracy(100)},
+			wantStatus:  entity.LocationStatusInside,
+			wantDanger:  true,
+			wantWebhook: true,
+		},
+		{
+			name:        "Near boundary, notify policy",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 1.0005, AccuracyM: accuracy(200)},
+			policy:      "notify",
+			wantStatus:  entity.LocationStatusPossiblyInside,
+			wantDanger:  true,
+			wantWebhook: true,
+		},
+		{
+			name:        "Near boundary, record policy",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 1.0005, AccuracyM: accuracy(200)},
+			policy:      "record",
+			wantStatus:  entity.LocationStatusPossiblyInside,
+			wantDanger:  true,
+			wantWebhook: false,
+		},
+		{
+			name:        "Near boundary, ignore policy",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 1.0005, AccuracyM: accuracy(200)},
+			policy:      "ignore",
+			wantStatus:  entity.LocationStatusPossiblyInside,
+			wantDanger:  false,
+			wantWebhook: false,
+		},
+		{
+			name:        "Outside accuracy circle",
+			location:    entity.UserLocation{Lat: 0.5, Lon: 1.01, AccuracyM: accuracy(200)},
+			wantStatus:  entity.LocationStatusOutside,
+			wantDanger:  false,
+			wantWebhook: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			rdb := newTestRedis(t)
+
+			incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{zone}, nil)
+			// Точность и статус сохраняются: пересчет по ним отличает точечные проверки
+			locationRepo.On("SaveLocationCheck", mock.Anything, mock.MatchedBy(func(c *entity.LocationCheck) bool {
+				return c.IsDanger == tt.wantDanger && c.MatchStatus == tt.wantStatus &&
+					c.UserLocation.AccuracyM == tt.location.AccuracyM
+			})).Return(nil)
+
+			cfg := &config.Config{Location: config.LocationConfig{UncertainPolicy: tt.policy}}
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, cfg)
+
+			got, err := s.CheckLocation(context.Background(), &entity.CheckLocationRequest{
+				UserID:       uuid.NewString(),
+				UserLocation: tt.location,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantDanger, got.IsDanger)
+
+			pending, err := rdb.Client.LLen(context.Background(), "webhook:pending").Result()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWebhook, pending > 0)
+		})
+	}
+}
+
+func TestLocationService_CheckLocation_Spoofing(t *testing.T) {
+	userID := uuid.NewString()
+	zone := entity.Incident{
+		ID:       uuid.New(),
+		Name:     "Zone",
+		IsActive: true,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		prev        *entity.LocationCheck
+		policy      string
+		wantFlagged bool
+		wantWebhook bool
+	}{
+		{
+			name: "Plausible speed",
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.5, Lon: 0.45},
+				CreatedAt:    time.Now().Add(-10 * time.Minute),
+			},
+			policy:      "exclude",
+			wantFlagged: false,
+			wantWebhook: true,
+		},
+		{
+			name: "Impossible travel, exclude policy",
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 40, Lon: 40},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			policy:      "exclude",
+			wantFlagged: true,
+			wantWebhook: false,
+		},
+		{
+			name: "Impossible travel, flag policy",
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 40, Lon: 40},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			policy:      "flag",
+			wantFlagged: true,
+			wantWebhook: true,
+		},
+		{
+			name: "Check after a real long move is compared with the flagged arrival point",
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.5, Lon: 0.45},
+				IsFlagged:    true,
+				CreatedAt:    time.Now().Add(-10 * time.Minute),
+			},
+			policy:      "exclude",
+			wantFlagged: false,
+			wantWebhook: true,
+		},
+		{
+			name:        "First check",
+			prev:        nil,
+			policy:      "exclude",
+			wantFlagged: false,
+			wantWebhook: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			rdb := newTestRedis(t)
+
+			incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{zone}, nil)
+			locationRepo.On("FindAdjacentLocationChecks", mock.Anything, userID, mock.Anything).Return(tt.prev, nil, nil)
+			locationRepo.On("SaveLocationCheck", mock.Anything, mock.MatchedBy(func(c *entity.LocationCheck) bool {
+				return c.IsFlagged == tt.wantFlagged && c.IsExcluded == (tt.wantFlagged && tt.policy == "exclude")
+			})).Return(nil)
+
+			cfg := &config.Config{Location: config.LocationConfig{SpoofMaxSpeedKmh: 300, SpoofPolicy: tt.policy}}
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, cfg)
+
+			got, err := s.CheckLocation(context.Background(), &entity.CheckLocationRequest{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.5, Lon: 0.5},
+			})
+
+			require.NoError(t, err)
+			assert.True(t, got.IsDanger)
+
+			pending, err := rdb.Client.LLen(context.Background(), "webhook:pending").Result()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWebhook, pending > 0)
+		})
+	}
+}
+
+func TestLocationService_CheckLocation_Timestamp(t *testing.T) {
+	userID := uuid.NewString()
+	recorded := time.Now().Add(-2 * time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		timestamp   *time.Time
+		prev        *entity.LocationCheck
+		next        *entity.LocationCheck
+		wantTime    func(time.Time) bool
+		wantFlagged bool
+	}{
+		{
+			name:      "Timestamp used as check time",
+			timestamp: &recorded,
+			wantTime:  func(ts time.Time) bool { return ts.Equal(recorded) },
+		},
+		{
+			name:      "Future timestamp replaced with request time",
+			timestamp: &future,
+			wantTime:  func(ts time.Time) bool { return ts.Before(future) },
+		},
+		{
+			name:      "Backdated point is compared with newer check",
+			timestamp: &recorded,
+			next: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 40, Lon: 40},
+				CreatedAt:    time.Now().Add(-time.Minute),
+			},
+			wantTime:    func(ts time.Time) bool { return ts.Equal(recorded) },
+			wantFlagged: true,
+		},
+		{
+			name:      "Plausible late point between older and newer checks",
+			timestamp: &recorded,
+			prev: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.4, Lon: 0.5},
+				CreatedAt:    recorded.Add(-10 * time.Minute),
+			},
+			next: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.6, Lon: 0.5},
+				CreatedAt:    recorded.Add(10 * time.Minute),
+			},
+			wantTime:    func(ts time.Time) bool { return ts.Equal(recorded) },
+			wantFlagged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			rdb := newTestRedis(t)
+
+			incidentRepo.On("FindAll", mock.Anything, 1000, 0).Return([]entity.Incident{}, nil)
+			locationRepo.On("FindAdjacentLocationChecks", mock.Anything, userID, mock.MatchedBy(tt.wantTime)).Return(tt.prev, tt.next, nil)
+			locationRepo.On("SaveLocationCheck", mock.Anything, mock.MatchedBy(func(c *entity.LocationCheck) bool {
+				return tt.wantTime(c.CreatedAt) && c.IsFlagged == tt.wantFlagged
+			})).Return(nil)
+
+			cfg := &config.Config{Location: config.LocationConfig{SpoofMaxSpeedKmh: 300}}
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, cfg)
+
+			_, err := s.CheckLocation(context.Background(), &entity.CheckLocationRequest{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.5, Lon: 0.5},
+				Timestamp:    tt.timestamp,
+			})
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLocationService_CheckLocation_TimestampTooOld(t *testing.T) {
+	userID := uuid.NewString()
+	zero := time.Time{}
+	epoch := time.Unix(0, 0)
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name        string
+		timestamp   *time.Time
+		maxBackdate time.Duration
+	}{
+		{name: "Zero timestamp", timestamp: &zero},
+		{name: "Unix epoch", timestamp: &epoch},
+		{name: "Older than default window", timestamp: &lastWeek},
+		{name: "Older than configured window", timestamp: &twoHoursAgo, maxBackdate: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+			rdb := newTestRedis(t)
+
+			cfg := &config.Config{Location: config.LocationConfig{SpoofMaxSpeedKmh: 300, MaxBackdate: tt.maxBackdate}}
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, cfg)
+
+			_, err := s.CheckLocation(context.Background(), &entity.CheckLocationRequest{
+				UserID:       userID,
+				UserLocation: entity.UserLocation{Lat: 0.5, Lon: 0.5},
+				Timestamp:    tt.timestamp,
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidLocation)
+		})
+	}
+}
+
+func TestLocationService_ReplayLocation(t *testing.T) {
+	at := time.Date(2026, 1, 18, 14, 32, 0, 0, time.UTC)
+	userID := uuid.NewString()
+
+	zone := entity.Incident{
+		ID:       uuid.New(),
+		Name:     "Zone",
+		IsActive: true,
+		Version:  2,
+		Area: entity.GeoJsonPolygon{
+			Type:        "Polygon",
+			Coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}},
+		},
+	}
+	inactive := zone
+	inactive.IsActive = false
+
+	inside := entity.UserLocation{Lat: 0.5, Lon: 0.5}
+
+	tests := []struct {
+		name         string
+		req          *entity.ReplayLocationRequest
+		lastCheck    *entity.LocationCheck
+		incidents    []entity.Incident
+		wantErr      error
+		wantDanger   bool
+		wantLocation entity.UserLocation
+	}{
+		{
+			name:         "Explicit location inside zone",
+			req:          &entity.ReplayLocationRequest{UserLocation: &inside, At: at},
+			incidents:    []entity.Incident{zone},
+			wantDanger:   true,
+			wantLocation: inside,
+		},
+		{
+			name: "Location from last check before at",
+			req:  &entity.ReplayLocationRequest{UserID: userID, At: at},
+			lastCheck: &entity.LocationCheck{
+				UserID:       userID,
+				UserLocation: inside,
+				CreatedAt:    at.Add(-2 * time.Minute),
+			},
+			incidents:    []entity.Incident{zone},
+			wantDanger:   true,
+			wantLocation: inside,
+		},
+		{
+			name:         "Zone inactive at that time",
+			req:          &entity.ReplayLocationRequest{UserLocation: &inside, At: at},
+			incidents:    []entity.Incident{inactive},
+			wantDanger:   false,
+			wantLocation: inside,
+		},
+		{
+			name:    "No checks before at",
+			req:     &entity.ReplayLocationRequest{UserID: userID, At: at},
+			wantErr: ErrNoLocationChecks,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			incidentRepo := mocks.NewIncidentRepo(t)
+
+			if tt.req.UserLocation == nil {
+				locationRepo.On("FindLocationCheckAt", mock.Anything, userID, at).Return(tt.lastCheck, nil)
+			}
+			if tt.wantErr == nil {
+				incidentRepo.On("FindAllAsOf", mock.Anything, at, 1000, 0).Return(tt.incidents, nil)
+			}
+
+			rdb := newTestRedis(t)
+			s := NewLocationService(locationRepo, incidentRepo, events.NewBus(rdb.Client), rdb, &config.Config{})
+
+			got, err := s.ReplayLocation(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDanger, got.IsDanger)
+			assert.Equal(t, tt.wantLocation, got.UserLocation)
+			assert.Equal(t, at, got.AsOf)
+			if tt.lastCheck != nil {
+				require.NotNil(t, got.LocationTime)
+				assert.Equal(t, tt.lastCheck.CreatedAt, *got.LocationTime)
+			}
+		})
+	}
+}
+
+func TestLocationService_Recheck(t *testing.T) {
+	from := time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	oldZone, newZone := uuid.New(), uuid.New()
+
+	changes := []*entity.RecheckChange{
+		{CheckID: 2, CreatedAt: from.Add(2 * time.Hour), OldIsDanger: true, OldIncidentID: &oldZone},
+		{CheckID: 1, CreatedAt: from.Add(time.Hour), NewIsDanger: true, NewIncidentID: &newZone},
+		{CheckID: 3, CreatedAt: from.Add(3 * time.Hour), OldIsDanger: true, OldIncidentID: &oldZone, NewIsDanger: true, NewIncidentID: &newZone},
+	}
+
+	tests := []struct {
+		name          string
+		req           *entity.RecheckRequest
+		changes       []*entity.RecheckChange
+		wantErr       bool
+		wantGeometry  string
+		wantLimit     int
+		wantDanger    int
+		wantSafe      int
+		wantTruncated bool
+	}{
+		{
+			name:         "Defaults applied",
+			req:          &entity.RecheckRequest{From: from, To: to},
+			changes:      changes,
+			wantGeometry: entity.RecheckGeometryCurrent,
+			wantLimit:    defaultRecheckLimit,
+			wantDanger:   1,
+			wantSafe:     1,
+		},
+		{
+			name:          "Historical geometry with limit",
+			req:           &entity.RecheckRequest{From: from, To: to, Geometry: entity.RecheckGeometryHistorical, Limit: 3, Apply: true},
+			changes:       changes,
+			wantGeometry:  entity.RecheckGeometryHistorical,
+			wantLimit:     3,
+			wantDanger:    1,
+			wantSafe:      1,
+			wantTruncated: true,
+		},
+		{
+			name:    "Empty period",
+			req:     &entity.RecheckRequest{From: to, To: from},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			if !tt.wantErr {
+				locationRepo.On("RecheckLocationChecks", mock.Anything, mock.MatchedBy(func(req *entity.RecheckRequest) bool {
+					return req.Geometry == tt.wantGeometry && req.Limit == tt.wantLimit
+				})).Return(append([]*entity.RecheckChange(nil), tt.changes...), 100, 7, nil)
+			}
+
+			rdb := newTestRedis(t)
+			s := NewLocationService(locationRepo, mocks.NewIncidentRepo(t), events.NewBus(rdb.Client), rdb, &config.Config{})
+
+			got, err := s.Recheck(context.Background(), tt.req)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 100, got.Scanned)
+			assert.Equal(t, 7, got.Skipped)
+			assert.Equal(t, len(tt.changes), got.Changed)
+			assert.Equal(t, tt.wantDanger, got.BecameDanger)
+			assert.Equal(t, tt.wantSafe, got.BecameSafe)
+			assert.Equal(t, tt.wantTruncated, got.Truncated)
+			assert.Equal(t, tt.req.Apply, got.Applied)
+			require.Len(t, got.Changes, len(tt.changes))
+			assert.Equal(t, int64(1), got.Changes[0].CheckID)
+		})
+	}
+}
+
+func TestLocationService_ReviewFlaggedCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Confirmed", id: "42"},
+		{name: "Malformed id", id: "abc", wantErr: ErrInvalidCheckID},
+		{name: "Unknown check", id: "42", repoErr: postgres.ErrFlaggedCheckNotFound, wantErr: ErrFlaggedCheckNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locationRepo := mocks.NewLocationRepo(t)
+			if tt.id == "42" {
+				locationRepo.On("ReviewLocationCheck", mock.Anything, int64(42), entity.ReviewStatusConfirmed, "operator", true).Return(tt.repoErr)
+			}
+
+			rdb := newTestRedis(t)
+			s := NewLocationService(locationRepo, mocks.NewIncidentRepo(t), events.NewBus(rdb.Client), rdb, &config.Config{})
+
+			err := s.ReviewFlaggedCheck(context.Background(), tt.id, &entity.ReviewLocationCheckRequest{Decision: "confirm", Reviewer: "operator"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/levinOo/geo-incedent-service/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CapRepo is an autogenerated mock type for the CapRepo type
+type CapRepo struct {
+	mock.Mock
+}
+
+// ClaimMessage provides a mock function with given fields: ctx, m
+func (_m *CapRepo) ClaimMessage(ctx context.Context, m *entity.CapMessage) (bool, error) {
+	ret := _m.Called(ctx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimMessage")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CapMessage) (bool, error)); ok {
+		return rf(ctx, m)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CapMessage) bool); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.CapMessage) error); ok {
+		r1 = rf(ctx, m)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMessage provides a mock function with given fields: ctx, sender, identifier
+func (_m *CapRepo) FindMessage(ctx context.Context, sender string, identifier string) (*entity.CapMessage, error) {
+	ret := _m.Called(ctx, sender, identifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMessage")
+	}
+
+	var r0 *entity.CapMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.CapMessage, error)); ok {
+		return rf(ctx, sender, identifier)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.CapMessage); ok {
+		r0 = rf(ctx, sender, identifier)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CapMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, sender, identifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseMessage provides a mock function with given fields: ctx, sender, identifier
+func (_m *CapRepo) ReleaseMessage(ctx context.Context, sender string, identifier string) error {
+	ret := _m.Called(ctx, sender, identifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, sender, identifier)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveMessage provides a mock function with given fields: ctx, m
+func (_m *CapRepo) SaveMessage(ctx context.Context, m *entity.CapMessage) error {
+	ret := _m.Called(ctx, m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMessage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.CapMessage) error); ok {
+		r0 = rf(ctx, m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCapRepo creates a new instance of CapRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCapRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CapRepo {
+	mock := &CapRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,179 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/levinOo/geo-incedent-service/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeometryRepo is an autogenerated mock type for the GeometryRepo type
+type GeometryRepo struct {
+	mock.Mock
+}
+
+// Buffer provides a mock function with given fields: ctx, area, distanceM
+func (_m *GeometryRepo) Buffer(ctx context.Context, area entity.GeoJsonPolygon, distanceM float64) (*entity.GeometryResult, error) {
+	ret := _m.Called(ctx, area, distanceM)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Buffer")
+	}
+
+	var r0 *entity.GeometryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, float64) (*entity.GeometryResult, error)); ok {
+		return rf(ctx, area, distanceM)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, float64) *entity.GeometryResult); ok {
+		r0 = rf(ctx, area, distanceM)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, float64) error); ok {
+		r1 = rf(ctx, area, distanceM)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Difference provides a mock function with given fields: ctx, area, other
+func (_m *GeometryRepo) Difference(ctx context.Context, area entity.GeoJsonPolygon, other entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	ret := _m.Called(ctx, area, other)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Difference")
+	}
+
+	var r0 *entity.GeometryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) (*entity.GeometryResult, error)); ok {
+		return rf(ctx, area, other)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) *entity.GeometryResult); ok {
+		r0 = rf(ctx, area, other)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) error); ok {
+		r1 = rf(ctx, area, other)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MakeValid provides a mock function with given fields: ctx, area
+func (_m *GeometryRepo) MakeValid(ctx context.Context, area entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	ret := _m.Called(ctx, area)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MakeValid")
+	}
+
+	var r0 *entity.GeometryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon) (*entity.GeometryResult, error)); ok {
+		return rf(ctx, area)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon) *entity.GeometryResult); ok {
+		r0 = rf(ctx, area)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon) error); ok {
+		r1 = rf(ctx, area)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Simplify provides a mock function with given fields: ctx, area, toleranceM
+func (_m *GeometryRepo) Simplify(ctx context.Context, area entity.GeoJsonPolygon, toleranceM float64) (*entity.GeometryResult, error) {
+	ret := _m.Called(ctx, area, toleranceM)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Simplify")
+	}
+
+	var r0 *entity.GeometryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, float64) (*entity.GeometryResult, error)); ok {
+		return rf(ctx, area, toleranceM)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, float64) *entity.GeometryResult); ok {
+		r0 = rf(ctx, area, toleranceM)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, float64) error); ok {
+		r1 = rf(ctx, area, toleranceM)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Union provides a mock function with given fields: ctx, area, other
+func (_m *GeometryRepo) Union(ctx context.Context, area entity.GeoJsonPolygon, other entity.GeoJsonPolygon) (*entity.GeometryResult, error) {
+	ret := _m.Called(ctx, area, other)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Union")
+	}
+
+	var r0 *entity.GeometryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) (*entity.GeometryResult, error)); ok {
+		return rf(ctx, area, other)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) *entity.GeometryResult); ok {
+		r0 = rf(ctx, area, other)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, entity.GeoJsonPolygon) error); ok {
+		r1 = rf(ctx, area, other)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewGeometryRepo creates a new instance of GeometryRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeometryRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeometryRepo {
+	mock := &GeometryRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
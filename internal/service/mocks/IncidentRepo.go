@@ -8,6 +8,8 @@ import (
 	entity "github.com/levinOo/geo-incedent-service/internal/entity"
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,6 +18,34 @@ type IncidentRepo struct {
 	mock.Mock
 }
 
+// CountVersions provides a mock function with given fields: ctx, id
+func (_m *IncidentRepo) CountVersions(ctx context.Context, id uuid.UUID) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountVersions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, i
 func (_m *IncidentRepo) Create(ctx context.Context, i *entity.Incident) error {
 	ret := _m.Called(ctx, i)
@@ -34,22 +64,64 @@ func (_m *IncidentRepo) Create(ctx context.Context, i *entity.Incident) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *IncidentRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, changedBy, reason
+func (_m *IncidentRepo) Delete(ctx context.Context, id uuid.UUID, changedBy string, reason string) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, id, changedBy, reason)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) ([]uuid.UUID, error)); ok {
+		return rf(ctx, id, changedBy, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) []uuid.UUID); ok {
+		r0 = rf(ctx, id, changedBy, reason)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r1 = rf(ctx, id, changedBy, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiffGeometry provides a mock function with given fields: ctx, id, fromVersion, toVersion
+func (_m *IncidentRepo) DiffGeometry(ctx context.Context, id uuid.UUID, fromVersion int, toVersion int) (*entity.GeometryDiff, error) {
+	ret := _m.Called(ctx, id, fromVersion, toVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DiffGeometry")
+	}
+
+	var r0 *entity.GeometryDiff
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) (*entity.GeometryDiff, error)); ok {
+		return rf(ctx, id, fromVersion, toVersion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) *entity.GeometryDiff); ok {
+		r0 = rf(ctx, id, fromVersion, toVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GeometryDiff)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r1 = rf(ctx, id, fromVersion, toVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // FindAll provides a mock function with given fields: ctx, limit, offset
@@ -82,6 +154,66 @@ func (_m *IncidentRepo) FindAll(ctx context.Context, limit int, offset int) ([]e
 	return r0, r1
 }
 
+// FindAllAsOf provides a mock function with given fields: ctx, asOf, limit, offset
+func (_m *IncidentRepo) FindAllAsOf(ctx context.Context, asOf time.Time, limit int, offset int) ([]entity.Incident, error) {
+	ret := _m.Called(ctx, asOf, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAllAsOf")
+	}
+
+	var r0 []entity.Incident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int, int) ([]entity.Incident, error)); ok {
+		return rf(ctx, asOf, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int, int) []entity.Incident); ok {
+		r0 = rf(ctx, asOf, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Incident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int, int) error); ok {
+		r1 = rf(ctx, asOf, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByExternalIDs provides a mock function with given fields: ctx, externalIDs
+func (_m *IncidentRepo) FindByExternalIDs(ctx context.Context, externalIDs []string) (map[string]*entity.Incident, error) {
+	ret := _m.Called(ctx, externalIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByExternalIDs")
+	}
+
+	var r0 map[string]*entity.Incident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]*entity.Incident, error)); ok {
+		return rf(ctx, externalIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]*entity.Incident); ok {
+		r0 = rf(ctx, externalIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*entity.Incident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, externalIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByID provides a mock function with given fields: ctx, id
 func (_m *IncidentRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Incident, error) {
 	ret := _m.Called(ctx, id)
@@ -112,6 +244,216 @@ func (_m *IncidentRepo) FindByID(ctx context.Context, id uuid.UUID) (*entity.Inc
 	return r0, r1
 }
 
+// FindByIDAsOf provides a mock function with given fields: ctx, id, asOf
+func (_m *IncidentRepo) FindByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*entity.Incident, error) {
+	ret := _m.Called(ctx, id, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIDAsOf")
+	}
+
+	var r0 *entity.Incident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (*entity.Incident, error)); ok {
+		return rf(ctx, id, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) *entity.Incident); ok {
+		r0 = rf(ctx, id, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Incident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDescendants provides a mock function with given fields: ctx, id
+func (_m *IncidentRepo) FindDescendants(ctx context.Context, id uuid.UUID) ([]entity.Incident, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDescendants")
+	}
+
+	var r0 []entity.Incident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entity.Incident, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entity.Incident); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Incident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindOverlaps provides a mock function with given fields: ctx, area, excludeID
+func (_m *IncidentRepo) FindOverlaps(ctx context.Context, area entity.GeoJsonPolygon, excludeID *uuid.UUID) ([]*entity.IncidentOverlap, error) {
+	ret := _m.Called(ctx, area, excludeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOverlaps")
+	}
+
+	var r0 []*entity.IncidentOverlap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, *uuid.UUID) ([]*entity.IncidentOverlap, error)); ok {
+		return rf(ctx, area, excludeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, *uuid.UUID) []*entity.IncidentOverlap); ok {
+		r0 = rf(ctx, area, excludeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.IncidentOverlap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, *uuid.UUID) error); ok {
+		r1 = rf(ctx, area, excludeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPublished provides a mock function with given fields: ctx
+func (_m *IncidentRepo) FindPublished(ctx context.Context) ([]*entity.Incident, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPublished")
+	}
+
+	var r0 []*entity.Incident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.Incident, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.Incident); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Incident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVersion provides a mock function with given fields: ctx, id, version
+func (_m *IncidentRepo) FindVersion(ctx context.Context, id uuid.UUID, version int) (*entity.IncidentVersion, error) {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVersion")
+	}
+
+	var r0 *entity.IncidentVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) (*entity.IncidentVersion, error)); ok {
+		return rf(ctx, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) *entity.IncidentVersion); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IncidentVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVersions provides a mock function with given fields: ctx, id, limit, offset
+func (_m *IncidentRepo) FindVersions(ctx context.Context, id uuid.UUID, limit int, offset int) ([]*entity.IncidentVersion, error) {
+	ret := _m.Called(ctx, id, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVersions")
+	}
+
+	var r0 []*entity.IncidentVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*entity.IncidentVersion, error)); ok {
+		return rf(ctx, id, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*entity.IncidentVersion); ok {
+		r0 = rf(ctx, id, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.IncidentVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r1 = rf(ctx, id, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindVersionsInRange provides a mock function with given fields: ctx, from, to
+func (_m *IncidentRepo) FindVersionsInRange(ctx context.Context, from time.Time, to time.Time) ([]*entity.IncidentVersion, error) {
+	ret := _m.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindVersionsInRange")
+	}
+
+	var r0 []*entity.IncidentVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) ([]*entity.IncidentVersion, error)); ok {
+		return rf(ctx, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) []*entity.IncidentVersion); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.IncidentVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStats provides a mock function with given fields: ctx, minutes
 func (_m *IncidentRepo) GetStats(ctx context.Context, minutes int) ([]*entity.IncidentStats, error) {
 	ret := _m.Called(ctx, minutes)
@@ -142,6 +484,34 @@ func (_m *IncidentRepo) GetStats(ctx context.Context, minutes int) ([]*entity.In
 	return r0, r1
 }
 
+// LastModified provides a mock function with given fields: ctx
+func (_m *IncidentRepo) LastModified(ctx context.Context) (time.Time, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastModified")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (time.Time, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) time.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Ping provides a mock function with given fields: ctx
 func (_m *IncidentRepo) Ping(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -160,17 +530,35 @@ func (_m *IncidentRepo) Ping(ctx context.Context) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: ctx, i
-func (_m *IncidentRepo) Update(ctx context.Context, i *entity.Incident) error {
-	ret := _m.Called(ctx, i)
+// StreamAll provides a mock function with given fields: ctx, filter, fn
+func (_m *IncidentRepo) StreamAll(ctx context.Context, filter entity.IncidentExportFilter, fn func(*entity.Incident) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.IncidentExportFilter, func(*entity.Incident) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, i, fromStatus
+func (_m *IncidentRepo) Update(ctx context.Context, i *entity.Incident, fromStatus string) error {
+	ret := _m.Called(ctx, i, fromStatus)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Incident) error); ok {
-		r0 = rf(ctx, i)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Incident, string) error); ok {
+		r0 = rf(ctx, i, fromStatus)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -178,6 +566,66 @@ func (_m *IncidentRepo) Update(ctx context.Context, i *entity.Incident) error {
 	return r0
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, current, toStatus, operation, changedBy, reason
+func (_m *IncidentRepo) UpdateStatus(ctx context.Context, current *entity.Incident, toStatus string, operation string, changedBy string, reason string) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, current, toStatus, operation, changedBy, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Incident, string, string, string, string) ([]uuid.UUID, error)); ok {
+		return rf(ctx, current, toStatus, operation, changedBy, reason)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Incident, string, string, string, string) []uuid.UUID); ok {
+		r0 = rf(ctx, current, toStatus, operation, changedBy, reason)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.Incident, string, string, string, string) error); ok {
+		r1 = rf(ctx, current, toStatus, operation, changedBy, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertMany provides a mock function with given fields: ctx, incidents
+func (_m *IncidentRepo) UpsertMany(ctx context.Context, incidents []*entity.Incident) ([]bool, error) {
+	ret := _m.Called(ctx, incidents)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertMany")
+	}
+
+	var r0 []bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.Incident) ([]bool, error)); ok {
+		return rf(ctx, incidents)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*entity.Incident) []bool); ok {
+		r0 = rf(ctx, incidents)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*entity.Incident) error); ok {
+		r1 = rf(ctx, incidents)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIncidentRepo creates a new instance of IncidentRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIncidentRepo(t interface {
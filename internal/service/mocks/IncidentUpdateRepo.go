@@ -0,0 +1,177 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/levinOo/geo-incedent-service/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// IncidentUpdateRepo is an autogenerated mock type for the IncidentUpdateRepo type
+type IncidentUpdateRepo struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, u
+func (_m *IncidentUpdateRepo) Create(ctx context.Context, u *entity.IncidentUpdate) error {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.IncidentUpdate) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, incidentID, id
+func (_m *IncidentUpdateRepo) Delete(ctx context.Context, incidentID uuid.UUID, id uuid.UUID) error {
+	ret := _m.Called(ctx, incidentID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, incidentID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByID provides a mock function with given fields: ctx, incidentID, id
+func (_m *IncidentUpdateRepo) FindByID(ctx context.Context, incidentID uuid.UUID, id uuid.UUID) (*entity.IncidentUpdate, error) {
+	ret := _m.Called(ctx, incidentID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByID")
+	}
+
+	var r0 *entity.IncidentUpdate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*entity.IncidentUpdate, error)); ok {
+		return rf(ctx, incidentID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *entity.IncidentUpdate); ok {
+		r0 = rf(ctx, incidentID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.IncidentUpdate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, incidentID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByIncident provides a mock function with given fields: ctx, incidentID, limit, offset
+func (_m *IncidentUpdateRepo) FindByIncident(ctx context.Context, incidentID uuid.UUID, limit int, offset int) ([]*entity.IncidentUpdate, error) {
+	ret := _m.Called(ctx, incidentID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIncident")
+	}
+
+	var r0 []*entity.IncidentUpdate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) ([]*entity.IncidentUpdate, error)); ok {
+		return rf(ctx, incidentID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int, int) []*entity.IncidentUpdate); ok {
+		r0 = rf(ctx, incidentID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.IncidentUpdate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int, int) error); ok {
+		r1 = rf(ctx, incidentID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatest provides a mock function with given fields: ctx, incidentIDs, asOf
+func (_m *IncidentUpdateRepo) FindLatest(ctx context.Context, incidentIDs []uuid.UUID, asOf *time.Time) (map[uuid.UUID]*entity.IncidentUpdate, error) {
+	ret := _m.Called(ctx, incidentIDs, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatest")
+	}
+
+	var r0 map[uuid.UUID]*entity.IncidentUpdate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, *time.Time) (map[uuid.UUID]*entity.IncidentUpdate, error)); ok {
+		return rf(ctx, incidentIDs, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID, *time.Time) map[uuid.UUID]*entity.IncidentUpdate); ok {
+		r0 = rf(ctx, incidentIDs, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]*entity.IncidentUpdate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID, *time.Time) error); ok {
+		r1 = rf(ctx, incidentIDs, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, u
+func (_m *IncidentUpdateRepo) Update(ctx context.Context, u *entity.IncidentUpdate) error {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.IncidentUpdate) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIncidentUpdateRepo creates a new instance of IncidentUpdateRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIncidentUpdateRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IncidentUpdateRepo {
+	mock := &IncidentUpdateRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
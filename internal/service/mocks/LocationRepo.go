@@ -0,0 +1,337 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/levinOo/geo-incedent-service/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LocationRepo is an autogenerated mock type for the LocationRepo type
+type LocationRepo struct {
+	mock.Mock
+}
+
+// CheckLocation provides a mock function with given fields: ctx, location
+func (_m *LocationRepo) CheckLocation(ctx context.Context, location entity.UserLocation) ([]*entity.LocationCheckIncident, error) {
+	ret := _m.Called(ctx, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckLocation")
+	}
+
+	var r0 []*entity.LocationCheckIncident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserLocation) ([]*entity.LocationCheckIncident, error)); ok {
+		return rf(ctx, location)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserLocation) []*entity.LocationCheckIncident); ok {
+		r0 = rf(ctx, location)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.LocationCheckIncident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserLocation) error); ok {
+		r1 = rf(ctx, location)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckRoute provides a mock function with given fields: ctx, route
+func (_m *LocationRepo) CheckRoute(ctx context.Context, route entity.GeoJsonLineString) ([]*entity.RouteIncident, error) {
+	ret := _m.Called(ctx, route)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckRoute")
+	}
+
+	var r0 []*entity.RouteIncident
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonLineString) ([]*entity.RouteIncident, error)); ok {
+		return rf(ctx, route)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonLineString) []*entity.RouteIncident); ok {
+		r0 = rf(ctx, route)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RouteIncident)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonLineString) error); ok {
+		r1 = rf(ctx, route)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountChecksInArea provides a mock function with given fields: ctx, area, since, bucket
+func (_m *LocationRepo) CountChecksInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, bucket time.Duration) ([]*entity.ImpactBucket, error) {
+	ret := _m.Called(ctx, area, since, bucket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountChecksInArea")
+	}
+
+	var r0 []*entity.ImpactBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, time.Time, time.Duration) ([]*entity.ImpactBucket, error)); ok {
+		return rf(ctx, area, since, bucket)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, time.Time, time.Duration) []*entity.ImpactBucket); ok {
+		r0 = rf(ctx, area, since, bucket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ImpactBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, area, since, bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAdjacentLocationChecks provides a mock function with given fields: ctx, userID, at
+func (_m *LocationRepo) FindAdjacentLocationChecks(ctx context.Context, userID string, at time.Time) (*entity.LocationCheck, *entity.LocationCheck, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAdjacentLocationChecks")
+	}
+
+	var r0 *entity.LocationCheck
+	var r1 *entity.LocationCheck
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.LocationCheck, *entity.LocationCheck, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.LocationCheck); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LocationCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) *entity.LocationCheck); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entity.LocationCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Time) error); ok {
+		r2 = rf(ctx, userID, at)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindFlaggedChecks provides a mock function with given fields: ctx, reviewStatus, limit, offset
+func (_m *LocationRepo) FindFlaggedChecks(ctx context.Context, reviewStatus string, limit int, offset int) ([]*entity.FlaggedLocationCheck, error) {
+	ret := _m.Called(ctx, reviewStatus, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindFlaggedChecks")
+	}
+
+	var r0 []*entity.FlaggedLocationCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*entity.FlaggedLocationCheck, error)); ok {
+		return rf(ctx, reviewStatus, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*entity.FlaggedLocationCheck); ok {
+		r0 = rf(ctx, reviewStatus, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.FlaggedLocationCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, reviewStatus, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLocationCheckAt provides a mock function with given fields: ctx, userID, at
+func (_m *LocationRepo) FindLocationCheckAt(ctx context.Context, userID string, at time.Time) (*entity.LocationCheck, error) {
+	ret := _m.Called(ctx, userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLocationCheckAt")
+	}
+
+	var r0 *entity.LocationCheck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.LocationCheck, error)); ok {
+		return rf(ctx, userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.LocationCheck); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LocationCheck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUsersInArea provides a mock function with given fields: ctx, area, since, limit
+func (_m *LocationRepo) FindUsersInArea(ctx context.Context, area entity.GeoJsonPolygon, since time.Time, limit int) ([]*entity.AffectedUser, int, error) {
+	ret := _m.Called(ctx, area, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUsersInArea")
+	}
+
+	var r0 []*entity.AffectedUser
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, time.Time, int) ([]*entity.AffectedUser, int, error)); ok {
+		return rf(ctx, area, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GeoJsonPolygon, time.Time, int) []*entity.AffectedUser); ok {
+		r0 = rf(ctx, area, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.AffectedUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GeoJsonPolygon, time.Time, int) int); ok {
+		r1 = rf(ctx, area, since, limit)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.GeoJsonPolygon, time.Time, int) error); ok {
+		r2 = rf(ctx, area, since, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RecheckLocationChecks provides a mock function with given fields: ctx, req
+func (_m *LocationRepo) RecheckLocationChecks(ctx context.Context, req *entity.RecheckRequest) ([]*entity.RecheckChange, int, int, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecheckLocationChecks")
+	}
+
+	var r0 []*entity.RecheckChange
+	var r1 int
+	var r2 int
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RecheckRequest) ([]*entity.RecheckChange, int, int, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RecheckRequest) []*entity.RecheckChange); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RecheckChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entity.RecheckRequest) int); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entity.RecheckRequest) int); ok {
+		r2 = rf(ctx, req)
+	} else {
+		r2 = ret.Get(2).(int)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, *entity.RecheckRequest) error); ok {
+		r3 = rf(ctx, req)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// ReviewLocationCheck provides a mock function with given fields: ctx, id, reviewStatus, reviewer, excluded
+func (_m *LocationRepo) ReviewLocationCheck(ctx context.Context, id int64, reviewStatus string, reviewer string, excluded bool) error {
+	ret := _m.Called(ctx, id, reviewStatus, reviewer, excluded)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewLocationCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, bool) error); ok {
+		r0 = rf(ctx, id, reviewStatus, reviewer, excluded)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveLocationCheck provides a mock function with given fields: ctx, location
+func (_m *LocationRepo) SaveLocationCheck(ctx context.Context, location *entity.LocationCheck) error {
+	ret := _m.Called(ctx, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveLocationCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.LocationCheck) error); ok {
+		r0 = rf(ctx, location)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLocationRepo creates a new instance of LocationRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocationRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LocationRepo {
+	mock := &LocationRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,19 +3,38 @@ package service
 import (
 	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/db"
+	"github.com/levinOo/geo-incedent-service/internal/events"
 	"github.com/levinOo/geo-incedent-service/internal/repo"
 )
 
 type Service struct {
 	Incident IncidentService
+	Updates  IncidentUpdateService
+	Geometry GeometryService
 	Location LocationService
 	Health   HealthService
+	Device   DeviceService
+	Exposure ExposureService
+	Impact   ImpactService
+	Cap      CapService
+	Events   *events.Bus
 }
 
 func NewService(repo *repo.Repo, cfg *config.Config, redis *db.Redis) *Service {
+	bus := events.NewBus(redis.Client)
+
+	incident := NewIncidentService(repo.IncidentRepo, repo.IncidentUpdateRepo, bus, cfg)
+
 	return &Service{
-		Incident: NewIncidentService(repo.IncidentRepo, cfg),
-		Location: NewLocationService(repo.LocationRepo, repo.IncidentRepo, redis),
+		Incident: incident,
+		Updates:  NewIncidentUpdateService(repo.IncidentUpdateRepo, repo.IncidentRepo, repo.LocationRepo, redis, cfg),
+		Geometry: NewGeometryService(repo.GeometryRepo, repo.IncidentRepo, incident),
+		Location: NewLocationService(repo.LocationRepo, repo.IncidentRepo, bus, redis, cfg),
 		Health:   NewHealthService(repo.HealthRepo, redis),
+		Device:   NewDeviceService(repo.DeviceRepo, redis, cfg),
+		Exposure: NewExposureService(repo.IncidentRepo),
+		Impact:   NewImpactService(repo.LocationRepo, repo.IncidentRepo, cfg),
+		Cap:      NewCapService(repo.CapRepo, repo.IncidentRepo, repo.GeometryRepo, incident, cfg),
+		Events:   bus,
 	}
 }
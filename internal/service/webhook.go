@@ -37,6 +37,8 @@ func (s *WebhookSender) Send(ctx context.Context, task *entity.WebhookTask, webh
 		IncidentID: task.IncidentID,
 		UserID:     task.UserID,
 		Timestamp:  time.Now().UTC(),
+		UpdateID:   task.UpdateID,
+		Message:    task.Message,
 	}
 
 	data, err := json.Marshal(payload)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/levinOo/geo-incedent-service/config"
 	"github.com/levinOo/geo-incedent-service/internal/entity"
+	"github.com/levinOo/geo-incedent-service/internal/events"
 	"github.com/levinOo/geo-incedent-service/internal/queue"
 	"github.com/levinOo/geo-incedent-service/internal/service"
 )
@@ -13,13 +14,15 @@ import (
 type Worker struct {
 	queue  *queue.Queue
 	sender *service.WebhookSender
+	events events.Publisher
 	config config.Worker
 }
 
-func NewWorker(queue *queue.Queue, sender *service.WebhookSender, config *config.Config) *Worker {
+func NewWorker(queue *queue.Queue, sender *service.WebhookSender, events events.Publisher, config *config.Config) *Worker {
 	return &Worker{
 		queue:  queue,
 		sender: sender,
+		events: events,
 		config: config.Worker,
 	}
 }
@@ -65,9 +68,12 @@ func (w *Worker) processTask(ctx context.Context, task *entity.WebhookTask) {
 		if err := w.queue.MoveToDLQ(ctx, task); err != nil {
 			slog.Error("не удалось перенести в DLQ", "error", err)
 		}
+		w.publish(ctx, entity.EventWebhookDLQ, task, err)
 		return
 	}
 
+	w.publish(ctx, entity.EventWebhookFailed, task, err)
+
 	slog.Warn("не удалось отправить вебхук, повторная попытка", "id", task.ID, "count", task.RetryCount, "error", err)
 
 	if err := w.queue.Update(ctx, task); err != nil {
@@ -78,3 +84,21 @@ func (w *Worker) processTask(ctx context.Context, task *entity.WebhookTask) {
 		slog.Error("не удалось вернуть задачу в очередь", "error", err)
 	}
 }
+
+func (w *Worker) publish(ctx context.Context, eventType string, task *entity.WebhookTask, sendErr error) {
+	incidentID := task.IncidentID
+	event := &entity.Event{
+		Type:       eventType,
+		IncidentID: &incidentID,
+		UserID:     task.UserID,
+		Data: &entity.WebhookEventData{
+			TaskID:     task.ID,
+			RetryCount: task.RetryCount,
+			Error:      sendErr.Error(),
+		},
+	}
+
+	if err := w.events.Publish(ctx, event); err != nil {
+		slog.Error("не удалось опубликовать событие вебхука", "id", task.ID, "error", err)
+	}
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE location_checks
+    ADD COLUMN IF NOT EXISTS is_flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS flag_reason TEXT,
+    ADD COLUMN IF NOT EXISTS implied_speed_kmh DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS is_excluded BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS review_status VARCHAR(16),
+    ADD COLUMN IF NOT EXISTS reviewed_by TEXT,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_location_checks_user_id_created_at ON location_checks (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_location_checks_flagged ON location_checks (created_at DESC) WHERE is_flagged = TRUE;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_location_checks_flagged;
+DROP INDEX IF EXISTS idx_location_checks_user_id_created_at;
+
+ALTER TABLE location_checks
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS review_status,
+    DROP COLUMN IF EXISTS is_excluded,
+    DROP COLUMN IF EXISTS implied_speed_kmh,
+    DROP COLUMN IF EXISTS flag_reason,
+    DROP COLUMN IF EXISTS is_flagged;
@@ -0,0 +1,45 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS incident_versions (
+    id BIGSERIAL PRIMARY KEY,
+    incident_id UUID NOT NULL REFERENCES incidents(id),
+    version INT NOT NULL,
+    operation VARCHAR(16) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    area GEOGRAPHY(POLYGON, 4326) NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    changed_by VARCHAR(255),
+    change_reason TEXT,
+    valid_from TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    valid_to TIMESTAMPTZ,
+    UNIQUE (incident_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_versions_validity ON incident_versions (incident_id, valid_from, valid_to);
+
+-- Исходное состояние существующих инцидентов. Для деактивированных инцидентов момент
+-- деактивации неизвестен, используется время последнего обновления.
+INSERT INTO incident_versions (
+    incident_id, version, operation, name, description, area, is_active,
+    changed_by, change_reason, valid_from, valid_to
+)
+SELECT
+    i.id, 1, 'create', i.name, i.description, i.area, TRUE,
+    'migration', 'состояние на момент включения истории', i.created_at,
+    CASE WHEN i.is_active = FALSE THEN i.updated_at END
+FROM incidents i
+WHERE NOT EXISTS (SELECT 1 FROM incident_versions v WHERE v.incident_id = i.id);
+
+INSERT INTO incident_versions (
+    incident_id, version, operation, name, description, area, is_active,
+    changed_by, change_reason, valid_from
+)
+SELECT
+    i.id, 2, 'delete', i.name, i.description, i.area, FALSE,
+    'migration', 'состояние на момент включения истории', i.updated_at
+FROM incidents i
+WHERE i.is_active = FALSE
+AND NOT EXISTS (SELECT 1 FROM incident_versions v WHERE v.incident_id = i.id AND v.version = 2);
+
+-- +goose Down
+DROP TABLE IF EXISTS incident_versions;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- Существующие инциденты уже действуют: активные считаются опубликованными,
+-- деактивированные — завершенными. Новые инциденты создаются черновиками.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'published';
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS severity VARCHAR(16) NOT NULL DEFAULT 'moderate';
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS submitted_by VARCHAR(255);
+
+UPDATE incidents SET status = 'resolved' WHERE is_active = FALSE;
+
+ALTER TABLE incidents ALTER COLUMN status SET DEFAULT 'draft';
+
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_status_check;
+ALTER TABLE incidents ADD CONSTRAINT incidents_status_check
+    CHECK (status IN ('draft', 'pending_review', 'published', 'resolved'));
+
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_severity_check;
+ALTER TABLE incidents ADD CONSTRAINT incidents_severity_check
+    CHECK (severity IN ('minor', 'moderate', 'severe', 'extreme'));
+
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents (status);
+
+ALTER TABLE incident_versions ADD COLUMN IF NOT EXISTS status VARCHAR(32);
+ALTER TABLE incident_versions ADD COLUMN IF NOT EXISTS severity VARCHAR(16);
+
+UPDATE incident_versions
+SET
+    status = CASE WHEN is_active THEN 'published' ELSE 'resolved' END,
+    severity = 'moderate'
+WHERE status IS NULL;
+
+ALTER TABLE incident_versions ALTER COLUMN status SET NOT NULL;
+ALTER TABLE incident_versions ALTER COLUMN severity SET NOT NULL;
+
+-- +goose Down
+ALTER TABLE incident_versions DROP COLUMN IF EXISTS severity;
+ALTER TABLE incident_versions DROP COLUMN IF EXISTS status;
+
+DROP INDEX IF EXISTS idx_incidents_status;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_severity_check;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_status_check;
+ALTER TABLE incidents DROP COLUMN IF EXISTS submitted_by;
+ALTER TABLE incidents DROP COLUMN IF EXISTS severity;
+ALTER TABLE incidents DROP COLUMN IF EXISTS status;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS incident_updates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    incident_id UUID NOT NULL REFERENCES incidents (id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    author VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_updates_incident_created
+    ON incident_updates (incident_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS incident_updates;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Родительский инцидент объединяет зоны одного события (например, очаги крупного пожара).
+-- При удалении родителя дочерние инциденты становятся самостоятельными.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES incidents (id) ON DELETE SET NULL;
+
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_parent_check;
+ALTER TABLE incidents ADD CONSTRAINT incidents_parent_check CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_parent_id ON incidents (parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE incident_versions ADD COLUMN IF NOT EXISTS parent_id UUID;
+
+-- +goose Down
+ALTER TABLE incident_versions DROP COLUMN IF EXISTS parent_id;
+
+DROP INDEX IF EXISTS idx_incidents_parent_id;
+ALTER TABLE incidents DROP CONSTRAINT IF EXISTS incidents_parent_check;
+ALTER TABLE incidents DROP COLUMN IF EXISTS parent_id;
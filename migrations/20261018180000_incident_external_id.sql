@@ -0,0 +1,10 @@
+-- +goose Up
+-- Идентификатор зоны во внешней системе (например, у ведомства-партнера) для повторного импорта:
+-- инцидент с тем же external_id обновляется, а не создается заново.
+ALTER TABLE incidents ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_external_id ON incidents (external_id) WHERE external_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_incidents_external_id;
+ALTER TABLE incidents DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Принятые сообщения CAP (Common Alerting Protocol). Каждое сообщение связано с инцидентом, который
+-- оно создало или изменило: по ним Update и Cancel находят инцидент через references, а повторная
+-- доставка того же сообщения не обрабатывается дважды.
+CREATE TABLE IF NOT EXISTS cap_messages (
+    sender VARCHAR(255) NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    sent TIMESTAMPTZ NOT NULL,
+    msg_type VARCHAR(16) NOT NULL,
+    incident_id UUID NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    event TEXT,
+    urgency VARCHAR(16),
+    severity VARCHAR(16),
+    certainty VARCHAR(16),
+    onset TIMESTAMPTZ,
+    expires TIMESTAMPTZ,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (sender, identifier)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cap_messages_incident ON cap_messages (incident_id, sent DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_cap_messages_incident;
+DROP TABLE IF EXISTS cap_messages;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Токен трекера OsmAnd/Traccar: трекеры не умеют подписывать запросы и передают только id,
+-- поэтому вместо публичного ID устройства используется секретный токен. Хранится его SHA-256.
+ALTER TABLE devices ADD COLUMN IF NOT EXISTS tracker_token_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_tracker_token_hash ON devices (tracker_token_hash) WHERE tracker_token_hash IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_devices_tracker_token_hash;
+ALTER TABLE devices DROP COLUMN IF EXISTS tracker_token_hash;
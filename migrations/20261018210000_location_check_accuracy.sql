@@ -0,0 +1,10 @@
+-- +goose Up
+-- Точность координат и статус совпадения проверки (inside, possibly_inside, outside). Пересчет
+-- сверяет с зоной только точку, поэтому проверки с точностью нужно отличать от точечных.
+-- У проверок, сохраненных до миграции, обе колонки пустые: они пересчитываются как точечные.
+ALTER TABLE location_checks ADD COLUMN IF NOT EXISTS accuracy_m DOUBLE PRECISION;
+ALTER TABLE location_checks ADD COLUMN IF NOT EXISTS match_status TEXT;
+
+-- +goose Down
+ALTER TABLE location_checks DROP COLUMN IF EXISTS match_status;
+ALTER TABLE location_checks DROP COLUMN IF EXISTS accuracy_m;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Сообщение CAP занимается записью без инцидента до того, как оно применено: одновременные доставки
+-- одного сообщения не создают два инцидента. incident_id заполняется после применения.
+ALTER TABLE cap_messages ALTER COLUMN incident_id DROP NOT NULL;
+
+-- +goose Down
+DELETE FROM cap_messages WHERE incident_id IS NULL;
+ALTER TABLE cap_messages ALTER COLUMN incident_id SET NOT NULL;
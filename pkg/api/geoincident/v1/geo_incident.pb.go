@@ -0,0 +1,1620 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: geoincident/v1/geo_incident.proto
+
+package geoincidentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lon           float64                `protobuf:"fixed64,1,opt,name=lon,proto3" json:"lon,omitempty"`
+	Lat           float64                `protobuf:"fixed64,2,opt,name=lat,proto3" json:"lat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Position) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *Position) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+type LinearRing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinearRing) Reset() {
+	*x = LinearRing{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinearRing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinearRing) ProtoMessage() {}
+
+func (x *LinearRing) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinearRing.ProtoReflect.Descriptor instead.
+func (*LinearRing) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LinearRing) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+// Polygon — GeoJSON Polygon: первое кольцо внешнее, остальные — отверстия.
+type Polygon struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rings         []*LinearRing          `protobuf:"bytes,1,rep,name=rings,proto3" json:"rings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Polygon) Reset() {
+	*x = Polygon{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Polygon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Polygon) ProtoMessage() {}
+
+func (x *Polygon) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Polygon.ProtoReflect.Descriptor instead.
+func (*Polygon) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Polygon) GetRings() []*LinearRing {
+	if x != nil {
+		return x.Rings
+	}
+	return nil
+}
+
+type Incident struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Area        *Polygon               `protobuf:"bytes,4,opt,name=area,proto3" json:"area,omitempty"`
+	IsActive    bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status      string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`
+	Severity    string                 `protobuf:"bytes,9,opt,name=severity,proto3" json:"severity,omitempty"`
+	SubmittedBy string                 `protobuf:"bytes,10,opt,name=submitted_by,json=submittedBy,proto3" json:"submitted_by,omitempty"`
+	ParentId    string                 `protobuf:"bytes,11,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Заполняется только для GetIncident с include_tree.
+	Children      []*Incident `protobuf:"bytes,12,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Incident) Reset() {
+	*x = Incident{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Incident) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Incident) ProtoMessage() {}
+
+func (x *Incident) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Incident.ProtoReflect.Descriptor instead.
+func (*Incident) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Incident) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Incident) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Incident) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Incident) GetArea() *Polygon {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *Incident) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Incident) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Incident) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Incident) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Incident) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Incident) GetSubmittedBy() string {
+	if x != nil {
+		return x.SubmittedBy
+	}
+	return ""
+}
+
+func (x *Incident) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Incident) GetChildren() []*Incident {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type CreateIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Area          *Polygon               `protobuf:"bytes,3,opt,name=area,proto3" json:"area,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	ParentId      string                 `protobuf:"bytes,6,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateIncidentRequest) Reset() {
+	*x = CreateIncidentRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateIncidentRequest) ProtoMessage() {}
+
+func (x *CreateIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateIncidentRequest.ProtoReflect.Descriptor instead.
+func (*CreateIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateIncidentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetArea() *Polygon {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *CreateIncidentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *CreateIncidentRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type GetIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeTree   bool                   `protobuf:"varint,2,opt,name=include_tree,json=includeTree,proto3" json:"include_tree,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetIncidentRequest) Reset() {
+	*x = GetIncidentRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIncidentRequest) ProtoMessage() {}
+
+func (x *GetIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIncidentRequest.ProtoReflect.Descriptor instead.
+func (*GetIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetIncidentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetIncidentRequest) GetIncludeTree() bool {
+	if x != nil {
+		return x.IncludeTree
+	}
+	return false
+}
+
+type ListIncidentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncidentsRequest) Reset() {
+	*x = ListIncidentsRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsRequest) ProtoMessage() {}
+
+func (x *ListIncidentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsRequest.ProtoReflect.Descriptor instead.
+func (*ListIncidentsRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListIncidentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListIncidentsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListIncidentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incidents     []*Incident            `protobuf:"bytes,1,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListIncidentsResponse) Reset() {
+	*x = ListIncidentsResponse{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListIncidentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListIncidentsResponse) ProtoMessage() {}
+
+func (x *ListIncidentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListIncidentsResponse.ProtoReflect.Descriptor instead.
+func (*ListIncidentsResponse) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListIncidentsResponse) GetIncidents() []*Incident {
+	if x != nil {
+		return x.Incidents
+	}
+	return nil
+}
+
+type UpdateIncidentRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Description *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Area        *Polygon               `protobuf:"bytes,4,opt,name=area,proto3" json:"area,omitempty"`
+	Reason      string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Severity    *string                `protobuf:"bytes,6,opt,name=severity,proto3,oneof" json:"severity,omitempty"`
+	// Пустая строка отвязывает инцидент от родителя.
+	ParentId      *string `protobuf:"bytes,7,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIncidentRequest) Reset() {
+	*x = UpdateIncidentRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIncidentRequest) ProtoMessage() {}
+
+func (x *UpdateIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIncidentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateIncidentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetArea() *Polygon {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *UpdateIncidentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetSeverity() string {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return ""
+}
+
+func (x *UpdateIncidentRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+type DeleteIncidentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteIncidentRequest) Reset() {
+	*x = DeleteIncidentRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteIncidentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteIncidentRequest) ProtoMessage() {}
+
+func (x *DeleteIncidentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteIncidentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteIncidentRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteIncidentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteIncidentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type IncidentTransitionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncidentTransitionRequest) Reset() {
+	*x = IncidentTransitionRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncidentTransitionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncidentTransitionRequest) ProtoMessage() {}
+
+func (x *IncidentTransitionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncidentTransitionRequest.ProtoReflect.Descriptor instead.
+func (*IncidentTransitionRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IncidentTransitionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IncidentTransitionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{12}
+}
+
+type IncidentStats struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	IncidentId string                 `protobuf:"bytes,1,opt,name=incident_id,json=incidentId,proto3" json:"incident_id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Пользователи зоны и всех ее активных потомков, каждый учитывается один раз.
+	UserCount     int32  `protobuf:"varint,3,opt,name=user_count,json=userCount,proto3" json:"user_count,omitempty"`
+	ParentId      string `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	OwnUserCount  int32  `protobuf:"varint,5,opt,name=own_user_count,json=ownUserCount,proto3" json:"own_user_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncidentStats) Reset() {
+	*x = IncidentStats{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncidentStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncidentStats) ProtoMessage() {}
+
+func (x *IncidentStats) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncidentStats.ProtoReflect.Descriptor instead.
+func (*IncidentStats) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IncidentStats) GetIncidentId() string {
+	if x != nil {
+		return x.IncidentId
+	}
+	return ""
+}
+
+func (x *IncidentStats) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *IncidentStats) GetUserCount() int32 {
+	if x != nil {
+		return x.UserCount
+	}
+	return 0
+}
+
+func (x *IncidentStats) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *IncidentStats) GetOwnUserCount() int32 {
+	if x != nil {
+		return x.OwnUserCount
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*IncidentStats       `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	WindowMinutes int32                  `protobuf:"varint,2,opt,name=window_minutes,json=windowMinutes,proto3" json:"window_minutes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetStatsResponse) GetStats() []*IncidentStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetWindowMinutes() int32 {
+	if x != nil {
+		return x.WindowMinutes
+	}
+	return 0
+}
+
+type UserLocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lon           float64                `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"`
+	AccuracyM     *float64               `protobuf:"fixed64,3,opt,name=accuracy_m,json=accuracyM,proto3,oneof" json:"accuracy_m,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserLocation) Reset() {
+	*x = UserLocation{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserLocation) ProtoMessage() {}
+
+func (x *UserLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserLocation.ProtoReflect.Descriptor instead.
+func (*UserLocation) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserLocation) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *UserLocation) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+func (x *UserLocation) GetAccuracyM() float64 {
+	if x != nil && x.AccuracyM != nil {
+		return *x.AccuracyM
+	}
+	return 0
+}
+
+type CheckLocationRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserLocation    *UserLocation          `protobuf:"bytes,2,opt,name=user_location,json=userLocation,proto3" json:"user_location,omitempty"`
+	CheckTrajectory bool                   `protobuf:"varint,3,opt,name=check_trajectory,json=checkTrajectory,proto3" json:"check_trajectory,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CheckLocationRequest) Reset() {
+	*x = CheckLocationRequest{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckLocationRequest) ProtoMessage() {}
+
+func (x *CheckLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckLocationRequest.ProtoReflect.Descriptor instead.
+func (*CheckLocationRequest) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CheckLocationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckLocationRequest) GetUserLocation() *UserLocation {
+	if x != nil {
+		return x.UserLocation
+	}
+	return nil
+}
+
+func (x *CheckLocationRequest) GetCheckTrajectory() bool {
+	if x != nil {
+		return x.CheckTrajectory
+	}
+	return false
+}
+
+type LocationIncident struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationIncident) Reset() {
+	*x = LocationIncident{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationIncident) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationIncident) ProtoMessage() {}
+
+func (x *LocationIncident) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationIncident.ProtoReflect.Descriptor instead.
+func (*LocationIncident) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LocationIncident) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LocationIncident) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LocationIncident) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LocationIncident) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type TransitEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *UserLocation          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            *UserLocation          `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	FromTime      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from_time,json=fromTime,proto3" json:"from_time,omitempty"`
+	ToTime        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to_time,json=toTime,proto3" json:"to_time,omitempty"`
+	Incidents     []*LocationIncident    `protobuf:"bytes,5,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitEvent) Reset() {
+	*x = TransitEvent{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitEvent) ProtoMessage() {}
+
+func (x *TransitEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitEvent.ProtoReflect.Descriptor instead.
+func (*TransitEvent) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TransitEvent) GetFrom() *UserLocation {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *TransitEvent) GetTo() *UserLocation {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *TransitEvent) GetFromTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FromTime
+	}
+	return nil
+}
+
+func (x *TransitEvent) GetToTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ToTime
+	}
+	return nil
+}
+
+func (x *TransitEvent) GetIncidents() []*LocationIncident {
+	if x != nil {
+		return x.Incidents
+	}
+	return nil
+}
+
+type CheckLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsDanger      bool                   `protobuf:"varint,1,opt,name=is_danger,json=isDanger,proto3" json:"is_danger,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Incidents     []*LocationIncident    `protobuf:"bytes,3,rep,name=incidents,proto3" json:"incidents,omitempty"`
+	Transit       *TransitEvent          `protobuf:"bytes,4,opt,name=transit,proto3" json:"transit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckLocationResponse) Reset() {
+	*x = CheckLocationResponse{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckLocationResponse) ProtoMessage() {}
+
+func (x *CheckLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckLocationResponse.ProtoReflect.Descriptor instead.
+func (*CheckLocationResponse) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CheckLocationResponse) GetIsDanger() bool {
+	if x != nil {
+		return x.IsDanger
+	}
+	return false
+}
+
+func (x *CheckLocationResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CheckLocationResponse) GetIncidents() []*LocationIncident {
+	if x != nil {
+		return x.Incidents
+	}
+	return nil
+}
+
+func (x *CheckLocationResponse) GetTransit() *TransitEvent {
+	if x != nil {
+		return x.Transit
+	}
+	return nil
+}
+
+type PositionUpdate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserLocation    *UserLocation          `protobuf:"bytes,2,opt,name=user_location,json=userLocation,proto3" json:"user_location,omitempty"`
+	CheckTrajectory bool                   `protobuf:"varint,3,opt,name=check_trajectory,json=checkTrajectory,proto3" json:"check_trajectory,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PositionUpdate) Reset() {
+	*x = PositionUpdate{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionUpdate) ProtoMessage() {}
+
+func (x *PositionUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionUpdate.ProtoReflect.Descriptor instead.
+func (*PositionUpdate) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PositionUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PositionUpdate) GetUserLocation() *UserLocation {
+	if x != nil {
+		return x.UserLocation
+	}
+	return nil
+}
+
+func (x *PositionUpdate) GetCheckTrajectory() bool {
+	if x != nil {
+		return x.CheckTrajectory
+	}
+	return false
+}
+
+type LocationState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	State         *CheckLocationResponse `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationState) Reset() {
+	*x = LocationState{}
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationState) ProtoMessage() {}
+
+func (x *LocationState) ProtoReflect() protoreflect.Message {
+	mi := &file_geoincident_v1_geo_incident_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationState.ProtoReflect.Descriptor instead.
+func (*LocationState) Descriptor() ([]byte, []int) {
+	return file_geoincident_v1_geo_incident_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LocationState) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *LocationState) GetState() *CheckLocationResponse {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+var File_geoincident_v1_geo_incident_proto protoreflect.FileDescriptor
+
+const file_geoincident_v1_geo_incident_proto_rawDesc = "" +
+	"\n" +
+	"!geoincident/v1/geo_incident.proto\x12\x0egeoincident.v1\x1a\x1fgoogle/protobuf/timestamp.proto\".\n" +
+	"\bPosition\x12\x10\n" +
+	"\x03lon\x18\x01 \x01(\x01R\x03lon\x12\x10\n" +
+	"\x03lat\x18\x02 \x01(\x01R\x03lat\"D\n" +
+	"\n" +
+	"LinearRing\x126\n" +
+	"\tpositions\x18\x01 \x03(\v2\x18.geoincident.v1.PositionR\tpositions\";\n" +
+	"\aPolygon\x120\n" +
+	"\x05rings\x18\x01 \x03(\v2\x1a.geoincident.v1.LinearRingR\x05rings\"\xba\x03\n" +
+	"\bIncident\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12+\n" +
+	"\x04area\x18\x04 \x01(\v2\x17.geoincident.v1.PolygonR\x04area\x12\x1b\n" +
+	"\tis_active\x18\x05 \x01(\bR\bisActive\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x1a\n" +
+	"\bseverity\x18\t \x01(\tR\bseverity\x12!\n" +
+	"\fsubmitted_by\x18\n" +
+	" \x01(\tR\vsubmittedBy\x12\x1b\n" +
+	"\tparent_id\x18\v \x01(\tR\bparentId\x124\n" +
+	"\bchildren\x18\f \x03(\v2\x18.geoincident.v1.IncidentR\bchildren\"(\n" +
+	"\x0eStatusResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\xcb\x01\n" +
+	"\x15CreateIncidentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12+\n" +
+	"\x04area\x18\x03 \x01(\v2\x17.geoincident.v1.PolygonR\x04area\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1a\n" +
+	"\bseverity\x18\x05 \x01(\tR\bseverity\x12\x1b\n" +
+	"\tparent_id\x18\x06 \x01(\tR\bparentId\"G\n" +
+	"\x12GetIncidentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\finclude_tree\x18\x02 \x01(\bR\vincludeTree\"D\n" +
+	"\x14ListIncidentsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"O\n" +
+	"\x15ListIncidentsResponse\x126\n" +
+	"\tincidents\x18\x01 \x03(\v2\x18.geoincident.v1.IncidentR\tincidents\"\xa3\x02\n" +
+	"\x15UpdateIncidentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x12+\n" +
+	"\x04area\x18\x04 \x01(\v2\x17.geoincident.v1.PolygonR\x04area\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1f\n" +
+	"\bseverity\x18\x06 \x01(\tH\x02R\bseverity\x88\x01\x01\x12 \n" +
+	"\tparent_id\x18\a \x01(\tH\x03R\bparentId\x88\x01\x01B\a\n" +
+	"\x05_nameB\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_severityB\f\n" +
+	"\n" +
+	"_parent_id\"?\n" +
+	"\x15DeleteIncidentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"C\n" +
+	"\x19IncidentTransitionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x11\n" +
+	"\x0fGetStatsRequest\"\xa6\x01\n" +
+	"\rIncidentStats\x12\x1f\n" +
+	"\vincident_id\x18\x01 \x01(\tR\n" +
+	"incidentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"user_count\x18\x03 \x01(\x05R\tuserCount\x12\x1b\n" +
+	"\tparent_id\x18\x04 \x01(\tR\bparentId\x12$\n" +
+	"\x0eown_user_count\x18\x05 \x01(\x05R\fownUserCount\"n\n" +
+	"\x10GetStatsResponse\x123\n" +
+	"\x05stats\x18\x01 \x03(\v2\x1d.geoincident.v1.IncidentStatsR\x05stats\x12%\n" +
+	"\x0ewindow_minutes\x18\x02 \x01(\x05R\rwindowMinutes\"e\n" +
+	"\fUserLocation\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lon\x18\x02 \x01(\x01R\x03lon\x12\"\n" +
+	"\n" +
+	"accuracy_m\x18\x03 \x01(\x01H\x00R\taccuracyM\x88\x01\x01B\r\n" +
+	"\v_accuracy_m\"\x9d\x01\n" +
+	"\x14CheckLocationRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12A\n" +
+	"\ruser_location\x18\x02 \x01(\v2\x1c.geoincident.v1.UserLocationR\fuserLocation\x12)\n" +
+	"\x10check_trajectory\x18\x03 \x01(\bR\x0fcheckTrajectory\"p\n" +
+	"\x10LocationIncident\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"\x9c\x02\n" +
+	"\fTransitEvent\x120\n" +
+	"\x04from\x18\x01 \x01(\v2\x1c.geoincident.v1.UserLocationR\x04from\x12,\n" +
+	"\x02to\x18\x02 \x01(\v2\x1c.geoincident.v1.UserLocationR\x02to\x127\n" +
+	"\tfrom_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bfromTime\x123\n" +
+	"\ato_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06toTime\x12>\n" +
+	"\tincidents\x18\x05 \x03(\v2 .geoincident.v1.LocationIncidentR\tincidents\"\xc4\x01\n" +
+	"\x15CheckLocationResponse\x12\x1b\n" +
+	"\tis_danger\x18\x01 \x01(\bR\bisDanger\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12>\n" +
+	"\tincidents\x18\x03 \x03(\v2 .geoincident.v1.LocationIncidentR\tincidents\x126\n" +
+	"\atransit\x18\x04 \x01(\v2\x1c.geoincident.v1.TransitEventR\atransit\"\x97\x01\n" +
+	"\x0ePositionUpdate\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12A\n" +
+	"\ruser_location\x18\x02 \x01(\v2\x1c.geoincident.v1.UserLocationR\fuserLocation\x12)\n" +
+	"\x10check_trajectory\x18\x03 \x01(\bR\x0fcheckTrajectory\"e\n" +
+	"\rLocationState\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12;\n" +
+	"\x05state\x18\x02 \x01(\v2%.geoincident.v1.CheckLocationResponseR\x05state2\x8c\a\n" +
+	"\x0fIncidentService\x12W\n" +
+	"\x0eCreateIncident\x12%.geoincident.v1.CreateIncidentRequest\x1a\x1e.geoincident.v1.StatusResponse\x12K\n" +
+	"\vGetIncident\x12\".geoincident.v1.GetIncidentRequest\x1a\x18.geoincident.v1.Incident\x12\\\n" +
+	"\rListIncidents\x12$.geoincident.v1.ListIncidentsRequest\x1a%.geoincident.v1.ListIncidentsResponse\x12W\n" +
+	"\x0eUpdateIncident\x12%.geoincident.v1.UpdateIncidentRequest\x1a\x1e.geoincident.v1.StatusResponse\x12W\n" +
+	"\x0eDeleteIncident\x12%.geoincident.v1.DeleteIncidentRequest\x1a\x1e.geoincident.v1.StatusResponse\x12M\n" +
+	"\bGetStats\x12\x1f.geoincident.v1.GetStatsRequest\x1a .geoincident.v1.GetStatsResponse\x12[\n" +
+	"\x0eSubmitIncident\x12).geoincident.v1.IncidentTransitionRequest\x1a\x1e.geoincident.v1.StatusResponse\x12\\\n" +
+	"\x0fApproveIncident\x12).geoincident.v1.IncidentTransitionRequest\x1a\x1e.geoincident.v1.StatusResponse\x12[\n" +
+	"\x0eRejectIncident\x12).geoincident.v1.IncidentTransitionRequest\x1a\x1e.geoincident.v1.StatusResponse\x12\\\n" +
+	"\x0fResolveIncident\x12).geoincident.v1.IncidentTransitionRequest\x1a\x1e.geoincident.v1.StatusResponse2\xc5\x01\n" +
+	"\x0fLocationService\x12\\\n" +
+	"\rCheckLocation\x12$.geoincident.v1.CheckLocationRequest\x1a%.geoincident.v1.CheckLocationResponse\x12T\n" +
+	"\x0fStreamPositions\x12\x1e.geoincident.v1.PositionUpdate\x1a\x1d.geoincident.v1.LocationState(\x010\x01BNZLgithub.com/levinOo/geo-incedent-service/pkg/api/geoincident/v1;geoincidentv1b\x06proto3"
+
+var (
+	file_geoincident_v1_geo_incident_proto_rawDescOnce sync.Once
+	file_geoincident_v1_geo_incident_proto_rawDescData []byte
+)
+
+func file_geoincident_v1_geo_incident_proto_rawDescGZIP() []byte {
+	file_geoincident_v1_geo_incident_proto_rawDescOnce.Do(func() {
+		file_geoincident_v1_geo_incident_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_geoincident_v1_geo_incident_proto_rawDesc), len(file_geoincident_v1_geo_incident_proto_rawDesc)))
+	})
+	return file_geoincident_v1_geo_incident_proto_rawDescData
+}
+
+var file_geoincident_v1_geo_incident_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_geoincident_v1_geo_incident_proto_goTypes = []any{
+	(*Position)(nil),                  // 0: geoincident.v1.Position
+	(*LinearRing)(nil),                // 1: geoincident.v1.LinearRing
+	(*Polygon)(nil),                   // 2: geoincident.v1.Polygon
+	(*Incident)(nil),                  // 3: geoincident.v1.Incident
+	(*StatusResponse)(nil),            // 4: geoincident.v1.StatusResponse
+	(*CreateIncidentRequest)(nil),     // 5: geoincident.v1.CreateIncidentRequest
+	(*GetIncidentRequest)(nil),        // 6: geoincident.v1.GetIncidentRequest
+	(*ListIncidentsRequest)(nil),      // 7: geoincident.v1.ListIncidentsRequest
+	(*ListIncidentsResponse)(nil),     // 8: geoincident.v1.ListIncidentsResponse
+	(*UpdateIncidentRequest)(nil),     // 9: geoincident.v1.UpdateIncidentRequest
+	(*DeleteIncidentRequest)(nil),     // 10: geoincident.v1.DeleteIncidentRequest
+	(*IncidentTransitionRequest)(nil), // 11: geoincident.v1.IncidentTransitionRequest
+	(*GetStatsRequest)(nil),           // 12: geoincident.v1.GetStatsRequest
+	(*IncidentStats)(nil),             // 13: geoincident.v1.IncidentStats
+	(*GetStatsResponse)(nil),          // 14: geoincident.v1.GetStatsResponse
+	(*UserLocation)(nil),              // 15: geoincident.v1.UserLocation
+	(*CheckLocationRequest)(nil),      // 16: geoincident.v1.CheckLocationRequest
+	(*LocationIncident)(nil),          // 17: geoincident.v1.LocationIncident
+	(*TransitEvent)(nil),              // 18: geoincident.v1.TransitEvent
+	(*CheckLocationResponse)(nil),     // 19: geoincident.v1.CheckLocationResponse
+	(*PositionUpdate)(nil),            // 20: geoincident.v1.PositionUpdate
+	(*LocationState)(nil),             // 21: geoincident.v1.LocationState
+	(*timestamppb.Timestamp)(nil),     // 22: google.protobuf.Timestamp
+}
+var file_geoincident_v1_geo_incident_proto_depIdxs = []int32{
+	0,  // 0: geoincident.v1.LinearRing.positions:type_name -> geoincident.v1.Position
+	1,  // 1: geoincident.v1.Polygon.rings:type_name -> geoincident.v1.LinearRing
+	2,  // 2: geoincident.v1.Incident.area:type_name -> geoincident.v1.Polygon
+	22, // 3: geoincident.v1.Incident.created_at:type_name -> google.protobuf.Timestamp
+	22, // 4: geoincident.v1.Incident.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 5: geoincident.v1.Incident.children:type_name -> geoincident.v1.Incident
+	2,  // 6: geoincident.v1.CreateIncidentRequest.area:type_name -> geoincident.v1.Polygon
+	3,  // 7: geoincident.v1.ListIncidentsResponse.incidents:type_name -> geoincident.v1.Incident
+	2,  // 8: geoincident.v1.UpdateIncidentRequest.area:type_name -> geoincident.v1.Polygon
+	13, // 9: geoincident.v1.GetStatsResponse.stats:type_name -> geoincident.v1.IncidentStats
+	15, // 10: geoincident.v1.CheckLocationRequest.user_location:type_name -> geoincident.v1.UserLocation
+	15, // 11: geoincident.v1.TransitEvent.from:type_name -> geoincident.v1.UserLocation
+	15, // 12: geoincident.v1.TransitEvent.to:type_name -> geoincident.v1.UserLocation
+	22, // 13: geoincident.v1.TransitEvent.from_time:type_name -> google.protobuf.Timestamp
+	22, // 14: geoincident.v1.TransitEvent.to_time:type_name -> google.protobuf.Timestamp
+	17, // 15: geoincident.v1.TransitEvent.incidents:type_name -> geoincident.v1.LocationIncident
+	17, // 16: geoincident.v1.CheckLocationResponse.incidents:type_name -> geoincident.v1.LocationIncident
+	18, // 17: geoincident.v1.CheckLocationResponse.transit:type_name -> geoincident.v1.TransitEvent
+	15, // 18: geoincident.v1.PositionUpdate.user_location:type_name -> geoincident.v1.UserLocation
+	19, // 19: geoincident.v1.LocationState.state:type_name -> geoincident.v1.CheckLocationResponse
+	5,  // 20: geoincident.v1.IncidentService.CreateIncident:input_type -> geoincident.v1.CreateIncidentRequest
+	6,  // 21: geoincident.v1.IncidentService.GetIncident:input_type -> geoincident.v1.GetIncidentRequest
+	7,  // 22: geoincident.v1.IncidentService.ListIncidents:input_type -> geoincident.v1.ListIncidentsRequest
+	9,  // 23: geoincident.v1.IncidentService.UpdateIncident:input_type -> geoincident.v1.UpdateIncidentRequest
+	10, // 24: geoincident.v1.IncidentService.DeleteIncident:input_type -> geoincident.v1.DeleteIncidentRequest
+	12, // 25: geoincident.v1.IncidentService.GetStats:input_type -> geoincident.v1.GetStatsRequest
+	11, // 26: geoincident.v1.IncidentService.SubmitIncident:input_type -> geoincident.v1.IncidentTransitionRequest
+	11, // 27: geoincident.v1.IncidentService.ApproveIncident:input_type -> geoincident.v1.IncidentTransitionRequest
+	11, // 28: geoincident.v1.IncidentService.RejectIncident:input_type -> geoincident.v1.IncidentTransitionRequest
+	11, // 29: geoincident.v1.IncidentService.ResolveIncident:input_type -> geoincident.v1.IncidentTransitionRequest
+	16, // 30: geoincident.v1.LocationService.CheckLocation:input_type -> geoincident.v1.CheckLocationRequest
+	20, // 31: geoincident.v1.LocationService.StreamPositions:input_type -> geoincident.v1.PositionUpdate
+	4,  // 32: geoincident.v1.IncidentService.CreateIncident:output_type -> geoincident.v1.StatusResponse
+	3,  // 33: geoincident.v1.IncidentService.GetIncident:output_type -> geoincident.v1.Incident
+	8,  // 34: geoincident.v1.IncidentService.ListIncidents:output_type -> geoincident.v1.ListIncidentsResponse
+	4,  // 35: geoincident.v1.IncidentService.UpdateIncident:output_type -> geoincident.v1.StatusResponse
+	4,  // 36: geoincident.v1.IncidentService.DeleteIncident:output_type -> geoincident.v1.StatusResponse
+	14, // 37: geoincident.v1.IncidentService.GetStats:output_type -> geoincident.v1.GetStatsResponse
+	4,  // 38: geoincident.v1.IncidentService.SubmitIncident:output_type -> geoincident.v1.StatusResponse
+	4,  // 39: geoincident.v1.IncidentService.ApproveIncident:output_type -> geoincident.v1.StatusResponse
+	4,  // 40: geoincident.v1.IncidentService.RejectIncident:output_type -> geoincident.v1.StatusResponse
+	4,  // 41: geoincident.v1.IncidentService.ResolveIncident:output_type -> geoincident.v1.StatusResponse
+	19, // 42: geoincident.v1.LocationService.CheckLocation:output_type -> geoincident.v1.CheckLocationResponse
+	21, // 43: geoincident.v1.LocationService.StreamPositions:output_type -> geoincident.v1.LocationState
+	32, // [32:44] is the sub-list for method output_type
+	20, // [20:32] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_geoincident_v1_geo_incident_proto_init() }
+func file_geoincident_v1_geo_incident_proto_init() {
+	if File_geoincident_v1_geo_incident_proto != nil {
+		return
+	}
+	file_geoincident_v1_geo_incident_proto_msgTypes[9].OneofWrappers = []any{}
+	file_geoincident_v1_geo_incident_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_geoincident_v1_geo_incident_proto_rawDesc), len(file_geoincident_v1_geo_incident_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_geoincident_v1_geo_incident_proto_goTypes,
+		DependencyIndexes: file_geoincident_v1_geo_incident_proto_depIdxs,
+		MessageInfos:      file_geoincident_v1_geo_incident_proto_msgTypes,
+	}.Build()
+	File_geoincident_v1_geo_incident_proto = out.File
+	file_geoincident_v1_geo_incident_proto_goTypes = nil
+	file_geoincident_v1_geo_incident_proto_depIdxs = nil
+}
@@ -0,0 +1,616 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: geoincident/v1/geo_incident.proto
+
+package geoincidentv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IncidentService_CreateIncident_FullMethodName  = "/geoincident.v1.IncidentService/CreateIncident"
+	IncidentService_GetIncident_FullMethodName     = "/geoincident.v1.IncidentService/GetIncident"
+	IncidentService_ListIncidents_FullMethodName   = "/geoincident.v1.IncidentService/ListIncidents"
+	IncidentService_UpdateIncident_FullMethodName  = "/geoincident.v1.IncidentService/UpdateIncident"
+	IncidentService_DeleteIncident_FullMethodName  = "/geoincident.v1.IncidentService/DeleteIncident"
+	IncidentService_GetStats_FullMethodName        = "/geoincident.v1.IncidentService/GetStats"
+	IncidentService_SubmitIncident_FullMethodName  = "/geoincident.v1.IncidentService/SubmitIncident"
+	IncidentService_ApproveIncident_FullMethodName = "/geoincident.v1.IncidentService/ApproveIncident"
+	IncidentService_RejectIncident_FullMethodName  = "/geoincident.v1.IncidentService/RejectIncident"
+	IncidentService_ResolveIncident_FullMethodName = "/geoincident.v1.IncidentService/ResolveIncident"
+)
+
+// IncidentServiceClient is the client API for IncidentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IncidentService — управление инцидентами (гео-зонами опасности).
+// Все методы требуют API-ключ в метаданных x-api-key. Автором изменения для истории версий
+// считается оператор, чей персональный ключ передан в x-api-key.
+type IncidentServiceClient interface {
+	CreateIncident(ctx context.Context, in *CreateIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	GetIncident(ctx context.Context, in *GetIncidentRequest, opts ...grpc.CallOption) (*Incident, error)
+	ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error)
+	UpdateIncident(ctx context.Context, in *UpdateIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	DeleteIncident(ctx context.Context, in *DeleteIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// Процесс согласования: draft -> pending_review -> published -> resolved.
+	SubmitIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	ApproveIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	RejectIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	ResolveIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+}
+
+type incidentServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIncidentServiceClient(cc grpc.ClientConnInterface) IncidentServiceClient {
+	return &incidentServiceClient{cc}
+}
+
+func (c *incidentServiceClient) CreateIncident(ctx context.Context, in *CreateIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_CreateIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) GetIncident(ctx context.Context, in *GetIncidentRequest, opts ...grpc.CallOption) (*Incident, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Incident)
+	err := c.cc.Invoke(ctx, IncidentService_GetIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIncidentsResponse)
+	err := c.cc.Invoke(ctx, IncidentService_ListIncidents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) UpdateIncident(ctx context.Context, in *UpdateIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_UpdateIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) DeleteIncident(ctx context.Context, in *DeleteIncidentRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_DeleteIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, IncidentService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) SubmitIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_SubmitIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) ApproveIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_ApproveIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) RejectIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_RejectIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *incidentServiceClient) ResolveIncident(ctx context.Context, in *IncidentTransitionRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, IncidentService_ResolveIncident_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IncidentServiceServer is the server API for IncidentService service.
+// All implementations must embed UnimplementedIncidentServiceServer
+// for forward compatibility.
+//
+// IncidentService — управление инцидентами (гео-зонами опасности).
+// Все методы требуют API-ключ в метаданных x-api-key. Автором изменения для истории версий
+// считается оператор, чей персональный ключ передан в x-api-key.
+type IncidentServiceServer interface {
+	CreateIncident(context.Context, *CreateIncidentRequest) (*StatusResponse, error)
+	GetIncident(context.Context, *GetIncidentRequest) (*Incident, error)
+	ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error)
+	UpdateIncident(context.Context, *UpdateIncidentRequest) (*StatusResponse, error)
+	DeleteIncident(context.Context, *DeleteIncidentRequest) (*StatusResponse, error)
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// Процесс согласования: draft -> pending_review -> published -> resolved.
+	SubmitIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error)
+	ApproveIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error)
+	RejectIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error)
+	ResolveIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error)
+	mustEmbedUnimplementedIncidentServiceServer()
+}
+
+// UnimplementedIncidentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIncidentServiceServer struct{}
+
+func (UnimplementedIncidentServiceServer) CreateIncident(context.Context, *CreateIncidentRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) GetIncident(context.Context, *GetIncidentRequest) (*Incident, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncidents not implemented")
+}
+func (UnimplementedIncidentServiceServer) UpdateIncident(context.Context, *UpdateIncidentRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) DeleteIncident(context.Context, *DeleteIncidentRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedIncidentServiceServer) SubmitIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) ApproveIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) RejectIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) ResolveIncident(context.Context, *IncidentTransitionRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveIncident not implemented")
+}
+func (UnimplementedIncidentServiceServer) mustEmbedUnimplementedIncidentServiceServer() {}
+func (UnimplementedIncidentServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeIncidentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IncidentServiceServer will
+// result in compilation errors.
+type UnsafeIncidentServiceServer interface {
+	mustEmbedUnimplementedIncidentServiceServer()
+}
+
+func RegisterIncidentServiceServer(s grpc.ServiceRegistrar, srv IncidentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIncidentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IncidentService_ServiceDesc, srv)
+}
+
+func _IncidentService_CreateIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).CreateIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_CreateIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).CreateIncident(ctx, req.(*CreateIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_GetIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).GetIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_GetIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).GetIncident(ctx, req.(*GetIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_ListIncidents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncidentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).ListIncidents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_ListIncidents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).ListIncidents(ctx, req.(*ListIncidentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_UpdateIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).UpdateIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_UpdateIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).UpdateIncident(ctx, req.(*UpdateIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_DeleteIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteIncidentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).DeleteIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_DeleteIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).DeleteIncident(ctx, req.(*DeleteIncidentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_SubmitIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).SubmitIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_SubmitIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).SubmitIncident(ctx, req.(*IncidentTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_ApproveIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).ApproveIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_ApproveIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).ApproveIncident(ctx, req.(*IncidentTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_RejectIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).RejectIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_RejectIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).RejectIncident(ctx, req.(*IncidentTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IncidentService_ResolveIncident_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncidentTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IncidentServiceServer).ResolveIncident(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IncidentService_ResolveIncident_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IncidentServiceServer).ResolveIncident(ctx, req.(*IncidentTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IncidentService_ServiceDesc is the grpc.ServiceDesc for IncidentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IncidentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "geoincident.v1.IncidentService",
+	HandlerType: (*IncidentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateIncident",
+			Handler:    _IncidentService_CreateIncident_Handler,
+		},
+		{
+			MethodName: "GetIncident",
+			Handler:    _IncidentService_GetIncident_Handler,
+		},
+		{
+			MethodName: "ListIncidents",
+			Handler:    _IncidentService_ListIncidents_Handler,
+		},
+		{
+			MethodName: "UpdateIncident",
+			Handler:    _IncidentService_UpdateIncident_Handler,
+		},
+		{
+			MethodName: "DeleteIncident",
+			Handler:    _IncidentService_DeleteIncident_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _IncidentService_GetStats_Handler,
+		},
+		{
+			MethodName: "SubmitIncident",
+			Handler:    _IncidentService_SubmitIncident_Handler,
+		},
+		{
+			MethodName: "ApproveIncident",
+			Handler:    _IncidentService_ApproveIncident_Handler,
+		},
+		{
+			MethodName: "RejectIncident",
+			Handler:    _IncidentService_RejectIncident_Handler,
+		},
+		{
+			MethodName: "ResolveIncident",
+			Handler:    _IncidentService_ResolveIncident_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "geoincident/v1/geo_incident.proto",
+}
+
+const (
+	LocationService_CheckLocation_FullMethodName   = "/geoincident.v1.LocationService/CheckLocation"
+	LocationService_StreamPositions_FullMethodName = "/geoincident.v1.LocationService/StreamPositions"
+)
+
+// LocationServiceClient is the client API for LocationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LocationService — проверка положения пользователей относительно активных инцидентов.
+type LocationServiceClient interface {
+	CheckLocation(ctx context.Context, in *CheckLocationRequest, opts ...grpc.CallOption) (*CheckLocationResponse, error)
+	// StreamPositions принимает поток координат пользователей и возвращает состояние
+	// опасности только при его изменении для конкретного пользователя.
+	StreamPositions(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PositionUpdate, LocationState], error)
+}
+
+type locationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLocationServiceClient(cc grpc.ClientConnInterface) LocationServiceClient {
+	return &locationServiceClient{cc}
+}
+
+func (c *locationServiceClient) CheckLocation(ctx context.Context, in *CheckLocationRequest, opts ...grpc.CallOption) (*CheckLocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckLocationResponse)
+	err := c.cc.Invoke(ctx, LocationService_CheckLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) StreamPositions(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[PositionUpdate, LocationState], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LocationService_ServiceDesc.Streams[0], LocationService_StreamPositions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PositionUpdate, LocationState]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LocationService_StreamPositionsClient = grpc.BidiStreamingClient[PositionUpdate, LocationState]
+
+// LocationServiceServer is the server API for LocationService service.
+// All implementations must embed UnimplementedLocationServiceServer
+// for forward compatibility.
+//
+// LocationService — проверка положения пользователей относительно активных инцидентов.
+type LocationServiceServer interface {
+	CheckLocation(context.Context, *CheckLocationRequest) (*CheckLocationResponse, error)
+	// StreamPositions принимает поток координат пользователей и возвращает состояние
+	// опасности только при его изменении для конкретного пользователя.
+	StreamPositions(grpc.BidiStreamingServer[PositionUpdate, LocationState]) error
+	mustEmbedUnimplementedLocationServiceServer()
+}
+
+// UnimplementedLocationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLocationServiceServer struct{}
+
+func (UnimplementedLocationServiceServer) CheckLocation(context.Context, *CheckLocationRequest) (*CheckLocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckLocation not implemented")
+}
+func (UnimplementedLocationServiceServer) StreamPositions(grpc.BidiStreamingServer[PositionUpdate, LocationState]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPositions not implemented")
+}
+func (UnimplementedLocationServiceServer) mustEmbedUnimplementedLocationServiceServer() {}
+func (UnimplementedLocationServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeLocationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LocationServiceServer will
+// result in compilation errors.
+type UnsafeLocationServiceServer interface {
+	mustEmbedUnimplementedLocationServiceServer()
+}
+
+func RegisterLocationServiceServer(s grpc.ServiceRegistrar, srv LocationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLocationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LocationService_ServiceDesc, srv)
+}
+
+func _LocationService_CheckLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).CheckLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_CheckLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).CheckLocation(ctx, req.(*CheckLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_StreamPositions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LocationServiceServer).StreamPositions(&grpc.GenericServerStream[PositionUpdate, LocationState]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LocationService_StreamPositionsServer = grpc.BidiStreamingServer[PositionUpdate, LocationState]
+
+// LocationService_ServiceDesc is the grpc.ServiceDesc for LocationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LocationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "geoincident.v1.LocationService",
+	HandlerType: (*LocationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckLocation",
+			Handler:    _LocationService_CheckLocation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPositions",
+			Handler:       _LocationService_StreamPositions_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "geoincident/v1/geo_incident.proto",
+}
@@ -0,0 +1,241 @@
+// Package cap разбирает сообщения Common Alerting Protocol (CAP 1.2, OASIS) и переводит
+// их зоны polygon и circle в полигоны GeoJSON.
+package cap
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// Namespace — пространство имен CAP 1.2
+const Namespace = "urn:oasis:names:tc:emergency:cap:1.2"
+
+const (
+	StatusActual = "Actual"
+
+	MsgTypeAlert  = "Alert"
+	MsgTypeUpdate = "Update"
+	MsgTypeCancel = "Cancel"
+	MsgTypeAck    = "Ack"
+	MsgTypeError  = "Error"
+)
+
+const (
+	// circleSegments — число вершин многоугольника, которым заменяется circle
+	circleSegments = 64
+	earthRadiusKm  = 6371.0088
+)
+
+// Alert — сообщение CAP. Поля перечислены в порядке схемы CAP 1.2, поэтому структура годится
+// и для разбора, и для формирования документа.
+type Alert struct {
+	XMLName    xml.Name `xml:"alert"`
+	Xmlns      string   `xml:"xmlns,attr,omitempty"`
+	Identifier string   `xml:"identifier"`
+	Sender     string   `xml:"sender"`
+	Sent       string   `xml:"sent"`
+	Status     string   `xml:"status"`
+	MsgType    string   `xml:"msgType"`
+	Source     string   `xml:"source,omitempty"`
+	Scope      string   `xml:"scope"`
+	Note       string   `xml:"note,omitempty"`
+	References string   `xml:"references,omitempty"`
+	Infos      []Info   `xml:"info"`
+}
+
+type Info struct {
+	Language    string   `xml:"language,omitempty"`
+	Categories  []string `xml:"category"`
+	Event       string   `xml:"event"`
+	Urgency     string   `xml:"urgency"`
+	Severity    string   `xml:"severity"`
+	Certainty   string   `xml:"certainty"`
+	Effective   string   `xml:"effective,omitempty"`
+	Onset       string   `xml:"onset,omitempty"`
+	Expires     string   `xml:"expires,omitempty"`
+	SenderName  string   `xml:"senderName,omitempty"`
+	Headline    string   `xml:"headline,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Instruction string   `xml:"instruction,omitempty"`
+	Web         string   `xml:"web,omitempty"`
+	Areas       []Area   `xml:"area"`
+}
+
+type Area struct {
+	AreaDesc string   `xml:"areaDesc"`
+	Polygons []string `xml:"polygon"`
+	Circles  []string `xml:"circle"`
+}
+
+// Reference — ссылка на предыдущее сообщение из элемента references
+type Reference struct {
+	Sender     string
+	Identifier string
+	Sent       string
+}
+
+// Parse разбирает сообщение CAP и проверяет обязательные элементы alert
+func Parse(data []byte) (*Alert, error) {
+	var a Alert
+	if err := xml.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("некорректный CAP: %w", err)
+	}
+
+	var missing []string
+	for _, field := range []struct{ name, value string }{
+		{"identifier", a.Identifier},
+		{"sender", a.Sender},
+		{"sent", a.Sent},
+		{"status", a.Status},
+		{"msgType", a.MsgType},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("некорректный CAP: нет обязательных элементов %s", strings.Join(missing, ", "))
+	}
+
+	if _, err := ParseTime(a.Sent); err != nil {
+		return nil, fmt.Errorf("некорректный CAP: sent: %w", err)
+	}
+
+	return &a, nil
+}
+
+// ParseTime разбирает время CAP (формат dateTime с обязательным смещением, как в RFC 3339)
+func ParseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339, strings.TrimSpace(s))
+}
+
+// ParseReferences разбирает references: записи "sender,identifier,sent", разделенные пробелами
+func (a *Alert) ParseReferences() ([]Reference, error) {
+	var refs []Reference
+	for _, item := range strings.Fields(a.References) {
+		parts := strings.Split(item, ",")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("некорректная ссылка в references: %q", item)
+		}
+		refs = append(refs, Reference{Sender: parts[0], Identifier: parts[1], Sent: parts[2]})
+	}
+	return refs, nil
+}
+
+// Shapes возвращает полигоны всех зон area: polygon как есть, circle — вписанным многоугольником
+func (i *Info) Shapes() ([]entity.GeoJsonPolygon, error) {
+	var shapes []entity.GeoJsonPolygon
+	for _, area := range i.Areas {
+		for _, p := range area.Polygons {
+			polygon, err := ParsePolygon(p)
+			if err != nil {
+				return nil, fmt.Errorf("area %q: %w", area.AreaDesc, err)
+			}
+			shapes = append(shapes, polygon)
+		}
+		for _, c := range area.Circles {
+			polygon, err := ParseCircle(c)
+			if err != nil {
+				return nil, fmt.Errorf("area %q: %w", area.AreaDesc, err)
+			}
+			shapes = append(shapes, polygon)
+		}
+	}
+	return shapes, nil
+}
+
+// ParsePolygon разбирает polygon CAP: пары "широта,долгота", разделенные пробелами.
+// Незамкнутое кольцо замыкается.
+func ParsePolygon(s string) (entity.GeoJsonPolygon, error) {
+	var ring [][]float64
+	for _, pair := range strings.Fields(s) {
+		lat, lon, err := parsePoint(pair)
+		if err != nil {
+			return entity.GeoJsonPolygon{}, fmt.Errorf("polygon: %w", err)
+		}
+		ring = append(ring, []float64{lon, lat})
+	}
+
+	if len(ring) > 0 {
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			ring = append(ring, []float64{first[0], first[1]})
+		}
+	}
+	if len(ring) < 4 {
+		return entity.GeoJsonPolygon{}, errors.New("polygon: нужно не менее четырех точек")
+	}
+
+	return entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{ring}}, nil
+}
+
+// ParseCircle разбирает circle CAP ("широта,долгота радиус_км") и возвращает вписанный
+// многоугольник из circleSegments вершин, обходящий центр против часовой стрелки
+func ParseCircle(s string) (entity.GeoJsonPolygon, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return entity.GeoJsonPolygon{}, fmt.Errorf("circle: ожидается \"широта,долгота радиус\", получено %q", s)
+	}
+
+	lat, lon, err := parsePoint(fields[0])
+	if err != nil {
+		return entity.GeoJsonPolygon{}, fmt.Errorf("circle: %w", err)
+	}
+	radiusKm, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || radiusKm <= 0 {
+		return entity.GeoJsonPolygon{}, fmt.Errorf("circle: некорректный радиус %q", fields[1])
+	}
+
+	phi1 := lat * math.Pi / 180
+	lambda1 := lon * math.Pi / 180
+	delta := radiusKm / earthRadiusKm
+
+	ring := make([][]float64, 0, circleSegments+1)
+	for i := range circleSegments {
+		// Азимут убывает, чтобы обход в координатах долгота-широта шел против часовой стрелки
+		theta := -2 * math.Pi * float64(i) / circleSegments
+		phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+		lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1), math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+		ring = append(ring, []float64{lambda2 * 180 / math.Pi, phi2 * 180 / math.Pi})
+	}
+	ring = append(ring, []float64{ring[0][0], ring[0][1]})
+
+	return entity.GeoJsonPolygon{Type: "Polygon", Coordinates: [][][]float64{ring}}, nil
+}
+
+// Severity переводит серьезность CAP в уровень инцидента; Unknown дает пустую строку
+func Severity(capSeverity string) string {
+	switch capSeverity {
+	case "Extreme":
+		return entity.IncidentSeverityExtreme
+	case "Severe":
+		return entity.IncidentSeveritySevere
+	case "Moderate":
+		return entity.IncidentSeverityModerate
+	case "Minor":
+		return entity.IncidentSeverityMinor
+	default:
+		return ""
+	}
+}
+
+func parsePoint(pair string) (lat, lon float64, err error) {
+	parts := strings.Split(pair, ",")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("некорректная точка %q, ожидается \"широта,долгота\"", pair)
+	}
+	if lat, err = strconv.ParseFloat(parts[0], 64); err != nil {
+		return 0, 0, fmt.Errorf("некорректная точка %q", pair)
+	}
+	if lon, err = strconv.ParseFloat(parts[1], 64); err != nil {
+		return 0, 0, fmt.Errorf("некорректная точка %q", pair)
+	}
+	return lat, lon, nil
+}
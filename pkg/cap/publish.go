@@ -0,0 +1,128 @@
+package cap
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+const (
+	ContentType     = "application/cap+xml"
+	AtomContentType = "application/atom+xml"
+	AtomNamespace   = "http://www.w3.org/2005/Atom"
+)
+
+// Feed — лента Atom, каждая запись которой содержит сообщение CAP (профиль CAP-over-Atom)
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  Author   `xml:"author"`
+	Links   []Link   `xml:"link"`
+	Entries []Entry  `xml:"entry"`
+}
+
+type Author struct {
+	Name string `xml:"name"`
+}
+
+type Link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type Entry struct {
+	ID      string  `xml:"id"`
+	Title   string  `xml:"title"`
+	Updated string  `xml:"updated"`
+	Summary string  `xml:"summary,omitempty"`
+	Links   []Link  `xml:"link"`
+	Content Content `xml:"content"`
+}
+
+// Content встраивает сообщение CAP в запись как XML с MIME-типом application/cap+xml
+type Content struct {
+	Type  string `xml:"type,attr"`
+	Alert *Alert `xml:"alert"`
+}
+
+// FromIncident формирует сообщение CAP об опубликованном инциденте. Каждое состояние инцидента
+// получает свой identifier из id и updated_at. CAP не описывает отверстия в полигонах, поэтому
+// в polygon попадает только внешний контур зоны.
+func FromIncident(incident *entity.Incident, sender, web string) *Alert {
+	var polygons []string
+	if len(incident.Area.Coordinates) > 0 {
+		polygons = []string{FormatPolygon(incident.Area.Coordinates[0])}
+	}
+
+	return &Alert{
+		Xmlns:      Namespace,
+		Identifier: Identifier(incident),
+		Sender:     sender,
+		Sent:       FormatTime(incident.UpdatedAt),
+		Status:     StatusActual,
+		MsgType:    MsgTypeAlert,
+		Scope:      "Public",
+		Infos: []Info{{
+			Language:    "ru-RU",
+			Categories:  []string{"Safety"},
+			Event:       incident.Name,
+			Urgency:     "Unknown",
+			Severity:    SeverityFor(incident.Severity),
+			Certainty:   "Observed",
+			SenderName:  sender,
+			Headline:    incident.Name,
+			Description: incident.Description,
+			Web:         web,
+			Areas: []Area{{
+				AreaDesc: incident.Name,
+				Polygons: polygons,
+			}},
+		}},
+	}
+}
+
+// Identifier возвращает identifier сообщения CAP для текущего состояния инцидента
+func Identifier(incident *entity.Incident) string {
+	return incident.ID.String() + "-" + strconv.FormatInt(incident.UpdatedAt.UnixMicro(), 10)
+}
+
+// FormatTime форматирует время для CAP: смещение всегда числовое, обозначение Z не допускается
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05-07:00")
+}
+
+// FormatPolygon записывает кольцо GeoJSON (долгота, широта) в формате polygon CAP
+func FormatPolygon(ring [][]float64) string {
+	pairs := make([]string, 0, len(ring))
+	for _, coord := range ring {
+		pairs = append(pairs, formatFloat(coord[1])+","+formatFloat(coord[0]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// SeverityFor переводит уровень инцидента в серьезность CAP; пустой уровень дает Unknown
+func SeverityFor(severity string) string {
+	switch severity {
+	case entity.IncidentSeverityExtreme:
+		return "Extreme"
+	case entity.IncidentSeveritySevere:
+		return "Severe"
+	case entity.IncidentSeverityModerate:
+		return "Moderate"
+	case entity.IncidentSeverityMinor:
+		return "Minor"
+	default:
+		return "Unknown"
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,277 @@
+// Package export записывает инциденты в форматы ГИС потоком, по одному объекту,
+// не накапливая весь набор в памяти.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+const (
+	FormatGeoJSON = "geojson"
+	FormatKML     = "kml"
+	FormatCSV     = "csv"
+)
+
+// Writer записывает инциденты в выбранном формате. Begin вызывается один раз перед первым
+// инцидентом, End — после последнего; между ними Write вызывается для каждого инцидента.
+type Writer interface {
+	Begin() error
+	Write(incident *entity.Incident) error
+	End() error
+	ContentType() string
+	Extension() string
+}
+
+// NewWriter возвращает Writer для формата geojson, kml или csv
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatGeoJSON:
+		return &geoJSONWriter{w: w}, nil
+	case FormatKML:
+		return &kmlWriter{w: w}, nil
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат экспорта: %s", format)
+	}
+}
+
+// FormatForMediaType возвращает формат экспорта для MIME-типа из заголовка Accept
+func FormatForMediaType(mediaType string) (string, bool) {
+	switch mediaType {
+	case "application/geo+json", "application/json":
+		return FormatGeoJSON, true
+	case "application/vnd.google-earth.kml+xml", "application/xml", "text/xml":
+		return FormatKML, true
+	case "text/csv":
+		return FormatCSV, true
+	default:
+		return "", false
+	}
+}
+
+type geoJSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+type geoJSONFeature struct {
+	Type       string                `json:"type"`
+	ID         string                `json:"id"`
+	Geometry   entity.GeoJsonPolygon `json:"geometry"`
+	Properties map[string]any        `json:"properties"`
+}
+
+func (g *geoJSONWriter) Begin() error {
+	_, err := io.WriteString(g.w, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (g *geoJSONWriter) Write(incident *entity.Incident) error {
+	properties := make(map[string]any)
+	for _, field := range attributes(incident) {
+		properties[field.name] = field.value
+	}
+
+	data, err := json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		ID:         incident.ID.String(),
+		Geometry:   incident.Area,
+		Properties: properties,
+	})
+	if err != nil {
+		return err
+	}
+
+	if g.count > 0 {
+		if _, err := io.WriteString(g.w, ","); err != nil {
+			return err
+		}
+	}
+	g.count++
+
+	_, err = g.w.Write(data)
+	return err
+}
+
+func (g *geoJSONWriter) End() error {
+	_, err := io.WriteString(g.w, "]}\n")
+	return err
+}
+
+func (g *geoJSONWriter) ContentType() string { return "application/geo+json" }
+func (g *geoJSONWriter) Extension() string   { return "geojson" }
+
+type kmlWriter struct {
+	w io.Writer
+}
+
+func (k *kmlWriter) Begin() error {
+	_, err := io.WriteString(k.w, xml.Header+
+		`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Geo Incident Service</name>`+"\n")
+	return err
+}
+
+func (k *kmlWriter) Write(incident *entity.Incident) error {
+	var b strings.Builder
+
+	b.WriteString(`<Placemark id="`)
+	xml.EscapeText(&b, []byte(incident.ID.String()))
+	b.WriteString(`"><name>`)
+	xml.EscapeText(&b, []byte(incident.Name))
+	b.WriteString(`</name>`)
+	if incident.Description != "" {
+		b.WriteString(`<description>`)
+		xml.EscapeText(&b, []byte(incident.Description))
+		b.WriteString(`</description>`)
+	}
+
+	b.WriteString(`<ExtendedData>`)
+	for _, field := range attributes(incident) {
+		b.WriteString(`<Data name="` + field.name + `"><value>`)
+		xml.EscapeText(&b, []byte(field.String()))
+		b.WriteString(`</value></Data>`)
+	}
+	b.WriteString(`</ExtendedData><Polygon>`)
+
+	for i, ring := range incident.Area.Coordinates {
+		boundary := "innerBoundaryIs"
+		if i == 0 {
+			boundary = "outerBoundaryIs"
+		}
+		b.WriteString(`<` + boundary + `><LinearRing><coordinates>`)
+		for j, coord := range ring {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(formatFloat(coord[0]) + "," + formatFloat(coord[1]))
+		}
+		b.WriteString(`</coordinates></LinearRing></` + boundary + `>`)
+	}
+	b.WriteString("</Polygon></Placemark>\n")
+
+	_, err := io.WriteString(k.w, b.String())
+	return err
+}
+
+func (k *kmlWriter) End() error {
+	_, err := io.WriteString(k.w, "</Document></kml>\n")
+	return err
+}
+
+func (k *kmlWriter) ContentType() string { return "application/vnd.google-earth.kml+xml" }
+func (k *kmlWriter) Extension() string   { return "kml" }
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Begin() error {
+	header := []string{"id"}
+	for _, field := range attributes(&entity.Incident{}) {
+		header = append(header, field.name)
+	}
+	return c.w.Write(append(header, "wkt"))
+}
+
+func (c *csvWriter) Write(incident *entity.Incident) error {
+	record := []string{incident.ID.String()}
+	for _, field := range attributes(incident) {
+		record = append(record, field.String())
+	}
+	if err := c.w.Write(append(record, WKT(incident.Area))); err != nil {
+		return err
+	}
+
+	// csv.Writer буферизует вывод; сбрасываем после каждой строки, чтобы данные уходили клиенту сразу
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) ContentType() string { return "text/csv; charset=utf-8" }
+func (c *csvWriter) Extension() string   { return "csv" }
+
+// WKT возвращает полигон в формате Well-Known Text
+func WKT(area entity.GeoJsonPolygon) string {
+	var b strings.Builder
+
+	b.WriteString("POLYGON (")
+	for i, ring := range area.Coordinates {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j, coord := range ring {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(formatFloat(coord[0]) + " " + formatFloat(coord[1]))
+		}
+		b.WriteByte(')')
+	}
+	b.WriteByte(')')
+
+	return b.String()
+}
+
+type attribute struct {
+	name  string
+	value any
+}
+
+func (a attribute) String() string {
+	switch v := a.value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// attributes возвращает атрибуты инцидента в едином для всех форматов порядке
+func attributes(incident *entity.Incident) []attribute {
+	var parentID any
+	if incident.ParentID != nil {
+		parentID = incident.ParentID.String()
+	}
+
+	var version any
+	if incident.Version > 0 {
+		version = incident.Version
+	}
+
+	return []attribute{
+		{"name", incident.Name},
+		{"description", incident.Description},
+		{"status", incident.Status},
+		{"severity", incident.Severity},
+		{"is_active", incident.IsActive},
+		{"parent_id", parentID},
+		{"external_id", incident.ExternalID},
+		{"created_at", incident.CreatedAt},
+		{"updated_at", incident.UpdatedAt},
+		{"version", version},
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
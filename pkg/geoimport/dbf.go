@@ -0,0 +1,177 @@
+package geoimport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const dbfFieldDescriptorSize = 32
+
+type dbfTable struct {
+	records []dbfRecord
+}
+
+type dbfRecord struct {
+	deleted    bool
+	properties map[string]any
+}
+
+type dbfField struct {
+	name     string
+	kind     byte
+	offset   int
+	length   int
+	decimals int
+}
+
+// parseDBF разбирает таблицу атрибутов dBASE. Кодировка берется из .cpg, затем из байта
+// языкового драйвера; по умолчанию строки считаются UTF-8.
+func parseDBF(data []byte, cpg string) (*dbfTable, error) {
+	if len(data) < 32 {
+		return nil, errors.New("некорректный файл .dbf: неверный заголовок")
+	}
+
+	numRecords := int(binary.LittleEndian.Uint32(data[4:8]))
+	headerLen := int(binary.LittleEndian.Uint16(data[8:10]))
+	recordLen := int(binary.LittleEndian.Uint16(data[10:12]))
+	if headerLen > len(data) || recordLen < 1 {
+		return nil, errors.New("некорректный файл .dbf: неверный заголовок")
+	}
+	// Число записей из заголовка сверяется с размером файла до выделения памяти под них
+	if numRecords > (len(data)-headerLen)/recordLen {
+		return nil, fmt.Errorf("некорректный файл .dbf: %d записей по %d байт не помещаются в файл", numRecords, recordLen)
+	}
+
+	decoder, err := dbfDecoder(cpg, data[29])
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []dbfField
+	offset := 1 // первый байт записи — признак удаления
+	for at := 32; at+dbfFieldDescriptorSize <= headerLen && data[at] != 0x0D; at += dbfFieldDescriptorSize {
+		desc := data[at : at+dbfFieldDescriptorSize]
+		name := string(desc[:11])
+		if i := strings.IndexByte(name, 0); i >= 0 {
+			name = name[:i]
+		}
+
+		f := dbfField{
+			name:     strings.TrimSpace(decode(decoder, []byte(name))),
+			kind:     desc[11],
+			offset:   offset,
+			length:   int(desc[16]),
+			decimals: int(desc[17]),
+		}
+		offset += f.length
+		fields = append(fields, f)
+	}
+	if offset > recordLen {
+		return nil, errors.New("некорректный файл .dbf: поля не помещаются в запись")
+	}
+
+	table := &dbfTable{records: make([]dbfRecord, 0, numRecords)}
+	for i := range numRecords {
+		start := headerLen + i*recordLen
+		if start+recordLen > len(data) {
+			return nil, fmt.Errorf("некорректный файл .dbf: обрезанная запись %d", i+1)
+		}
+		raw := data[start : start+recordLen]
+
+		record := dbfRecord{deleted: raw[0] == '*', properties: make(map[string]any, len(fields))}
+		for _, f := range fields {
+			value := raw[f.offset : f.offset+f.length]
+			record.properties[f.name] = f.value(decode(decoder, value))
+		}
+		table.records = append(table.records, record)
+	}
+
+	return table, nil
+}
+
+// value приводит значение поля к типу свойства GeoJSON: числа — float64, логические — bool,
+// даты — строка YYYY-MM-DD; пустое значение дает nil
+func (f *dbfField) value(raw string) any {
+	s := strings.TrimSpace(raw)
+
+	switch f.kind {
+	case 'N', 'F':
+		if s == "" || strings.HasPrefix(s, "*") {
+			return nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return s
+		}
+		return v
+	case 'L':
+		switch strings.ToUpper(s) {
+		case "T", "Y":
+			return true
+		case "F", "N":
+			return false
+		}
+		return nil
+	case 'D':
+		if len(s) == 8 {
+			return s[0:4] + "-" + s[4:6] + "-" + s[6:8]
+		}
+	}
+
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// dbfDecoder выбирает кодировку: nil означает UTF-8
+func dbfDecoder(cpg string, ldid byte) (*encoding.Decoder, error) {
+	if cpg = strings.TrimSpace(cpg); cpg != "" {
+		switch strings.NewReplacer("ANSI ", "", "CP", "", "WINDOWS-", "", "-", "").Replace(strings.ToUpper(cpg)) {
+		case "UTF8", "65001":
+			return nil, nil
+		case "1251":
+			return charmap.Windows1251.NewDecoder(), nil
+		case "1252":
+			return charmap.Windows1252.NewDecoder(), nil
+		case "866", "OEM 866":
+			return charmap.CodePage866.NewDecoder(), nil
+		case "KOI8R":
+			return charmap.KOI8R.NewDecoder(), nil
+		default:
+			return nil, fmt.Errorf("неподдерживаемая кодировка .dbf: %s", cpg)
+		}
+	}
+
+	switch ldid {
+	case 0xC9:
+		return charmap.Windows1251.NewDecoder(), nil
+	case 0x26, 0x65:
+		return charmap.CodePage866.NewDecoder(), nil
+	case 0x03, 0x57:
+		return charmap.Windows1252.NewDecoder(), nil
+	}
+	return nil, nil
+}
+
+func decode(decoder *encoding.Decoder, raw []byte) string {
+	if decoder == nil {
+		if utf8.Valid(raw) {
+			return string(raw)
+		}
+		return strings.ToValidUTF8(string(raw), "�")
+	}
+
+	s, err := decoder.Bytes(raw)
+	if err != nil {
+		return string(raw)
+	}
+	return string(s)
+}
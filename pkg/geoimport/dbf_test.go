@@ -0,0 +1,145 @@
+package geoimport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+type testDBFField struct {
+	name   string
+	kind   byte
+	length int
+}
+
+// buildDBF собирает таблицу dBASE III. Значения записей дополняются пробелами до длины поля,
+// запись с префиксом "*" помечается удаленной.
+func buildDBF(ldid byte, fields []testDBFField, records [][]string) []byte {
+	headerLen := 32 + 32*len(fields) + 1
+	recordLen := 1
+	for _, f := range fields {
+		recordLen += f.length
+	}
+
+	var buf bytes.Buffer
+	header := make([]byte, 32)
+	header[0] = 0x03
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(records)))
+	binary.LittleEndian.PutUint16(header[8:10], uint16(headerLen))
+	binary.LittleEndian.PutUint16(header[10:12], uint16(recordLen))
+	header[29] = ldid
+	buf.Write(header)
+
+	for _, f := range fields {
+		desc := make([]byte, 32)
+		copy(desc, f.name)
+		desc[11] = f.kind
+		desc[16] = byte(f.length)
+		buf.Write(desc)
+	}
+	buf.WriteByte(0x0D)
+
+	for _, values := range records {
+		flag := byte(' ')
+		if len(values) > 0 && values[0] == "*" {
+			flag, values = '*', values[1:]
+		}
+		buf.WriteByte(flag)
+		for i, f := range fields {
+			value := make([]byte, f.length)
+			for j := range value {
+				value[j] = ' '
+			}
+			copy(value, values[i])
+			buf.Write(value)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseDBF(t *testing.T) {
+	fields := []testDBFField{
+		{name: "NAME", kind: 'C', length: 32},
+		{name: "LEVEL", kind: 'N', length: 5},
+		{name: "ACTIVE", kind: 'L', length: 1},
+		{name: "SINCE", kind: 'D', length: 8},
+	}
+	data := buildDBF(0, fields, [][]string{
+		{"Пойма", "2.5", "T", "20261018"},
+		{"*", "Старая зона", "", "F", ""},
+	})
+
+	table, err := parseDBF(data, "UTF-8")
+	require.NoError(t, err)
+	require.Len(t, table.records, 2)
+
+	assert.False(t, table.records[0].deleted)
+	assert.Equal(t, map[string]any{"NAME": "Пойма", "LEVEL": 2.5, "ACTIVE": true, "SINCE": "2026-10-18"}, table.records[0].properties)
+
+	assert.True(t, table.records[1].deleted)
+	assert.Equal(t, map[string]any{"NAME": "Старая зона", "LEVEL": nil, "ACTIVE": false, "SINCE": nil}, table.records[1].properties)
+}
+
+func TestParseDBF_Encoding(t *testing.T) {
+	name, err := charmap.Windows1251.NewEncoder().String("Пойма")
+	require.NoError(t, err)
+
+	data := buildDBF(0xC9, []testDBFField{{name: "NAME", kind: 'C', length: 10}}, [][]string{{name}})
+
+	tests := []struct {
+		name string
+		cpg  string
+	}{
+		{name: "Code page from .cpg", cpg: "1251"},
+		{name: "Code page from language driver", cpg: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := parseDBF(data, tt.cpg)
+			require.NoError(t, err)
+			assert.Equal(t, "Пойма", table.records[0].properties["NAME"])
+		})
+	}
+
+	_, err = parseDBF(data, "EBCDIC")
+	assert.Error(t, err)
+}
+
+func TestParseDBF_InvalidHeader(t *testing.T) {
+	valid := buildDBF(0, []testDBFField{{name: "NAME", kind: 'C', length: 10}}, [][]string{{"Пойма"}})
+
+	hugeCount := bytes.Clone(valid)
+	binary.LittleEndian.PutUint32(hugeCount[4:8], 0xFFFFFFFF)
+
+	truncated := valid[:len(valid)-3]
+
+	zeroRecordLen := bytes.Clone(valid)
+	binary.LittleEndian.PutUint16(zeroRecordLen[10:12], 0)
+
+	longHeader := bytes.Clone(valid)
+	binary.LittleEndian.PutUint16(longHeader[8:10], uint16(len(valid)+1))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Too short", data: valid[:16]},
+		{name: "Record count exceeds file size", data: hugeCount},
+		{name: "Truncated record", data: truncated},
+		{name: "Zero record length", data: zeroRecordLen},
+		{name: "Header longer than file", data: longHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDBF(tt.data, "")
+			assert.Error(t, err)
+		})
+	}
+}
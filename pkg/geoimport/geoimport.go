@@ -0,0 +1,116 @@
+// Package geoimport разбирает файлы зон в форматах KML и Shapefile (ZIP-архив) и приводит их
+// к GeoJSON FeatureCollection в EPSG:4326 для общего пути импорта инцидентов.
+package geoimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+var ErrUnknownFormat = errors.New("неизвестный формат файла: ожидается GeoJSON, KML или Shapefile в ZIP-архиве")
+
+// zipSignature — сигнатура локального заголовка ZIP
+var zipSignature = []byte("PK\x03\x04")
+
+// DetectFormat определяет формат файла по Content-Type, а если он не задан — по содержимому
+func DetectFormat(contentType string, data []byte) string {
+	switch ct := strings.ToLower(contentType); {
+	case strings.Contains(ct, "zip"):
+		return entity.ImportFormatShapefile
+	case strings.Contains(ct, "kml"), strings.Contains(ct, "xml"):
+		return entity.ImportFormatKML
+	case strings.Contains(ct, "json"):
+		return entity.ImportFormatGeoJSON
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, zipSignature):
+		return entity.ImportFormatShapefile
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return entity.ImportFormatKML
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return entity.ImportFormatGeoJSON
+	}
+
+	return ""
+}
+
+// Parse разбирает файл KML или ZIP-архив Shapefile. GeoJSON разбирается вызывающей стороной
+// вместе с проверкой тела запроса.
+func Parse(format string, data []byte) (*entity.GeoJsonFeatureCollection, error) {
+	var (
+		features []entity.GeoJsonFeature
+		err      error
+	)
+
+	switch format {
+	case entity.ImportFormatKML:
+		features, err = ParseKML(data)
+	case entity.ImportFormatShapefile:
+		features, err = ParseShapefileZip(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(features) == 0 {
+		return nil, errors.New("в файле нет объектов")
+	}
+
+	return &entity.GeoJsonFeatureCollection{Type: "FeatureCollection", Features: features}, nil
+}
+
+// polygon — кольца полигона, первое внешнее
+type polygon [][][]float64
+
+// geometryJSON возвращает геометрию GeoJSON: один полигон как Polygon, несколько как MultiPolygon.
+// MultiPolygon не подходит для зоны инцидента и отклоняется проверкой импорта с понятной ошибкой.
+func geometryJSON(polygons []polygon) json.RawMessage {
+	var geometry any
+	switch len(polygons) {
+	case 0:
+		return nil
+	case 1:
+		geometry = map[string]any{"type": "Polygon", "coordinates": polygons[0]}
+	default:
+		geometry = map[string]any{"type": "MultiPolygon", "coordinates": polygons}
+	}
+
+	data, _ := json.Marshal(geometry)
+	return data
+}
+
+// signedArea возвращает ориентированную площадь кольца: положительную для обхода против часовой стрелки
+func signedArea(ring [][]float64) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+func reverseRing(ring [][]float64) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+func pointInRing(ring [][]float64, p []float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		if ((yi > p[1]) != (yj > p[1])) && (p[0] < (xj-xi)*(p[1]-yi)/(yj-yi)+xi) {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,205 @@
+package geoimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+type kmlPlacemark struct {
+	ID           string `xml:"id,attr"`
+	Name         string `xml:"name"`
+	Description  string `xml:"description"`
+	ExtendedData struct {
+		Data []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value"`
+		} `xml:"Data"`
+		SchemaData []struct {
+			SimpleData []struct {
+				Name  string `xml:"name,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"SimpleData"`
+		} `xml:"SchemaData"`
+	} `xml:"ExtendedData"`
+	kmlGeometry
+}
+
+type kmlGeometry struct {
+	Polygons      []kmlPolygon  `xml:"Polygon"`
+	MultiGeometry []kmlGeometry `xml:"MultiGeometry"`
+	Points        []struct{}    `xml:"Point"`
+	LineStrings   []struct{}    `xml:"LineString"`
+	LinearRings   []struct{}    `xml:"LinearRing"`
+}
+
+type kmlPolygon struct {
+	Outer string   `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	Inner []string `xml:"innerBoundaryIs>LinearRing>coordinates"`
+}
+
+// ParseKML возвращает объекты из всех Placemark файла, включая вложенные в Document и Folder.
+// Свойства берутся из name, description и ExtendedData (Data и SchemaData), id объекта — из
+// атрибута id. Placemark без полигона получает геометрию с типом исходной фигуры, чтобы проверка
+// импорта сообщила, что это не полигон.
+func ParseKML(data []byte) ([]entity.GeoJsonFeature, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var (
+		features []entity.GeoJsonFeature
+		isKML    bool
+	)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("некорректный KML: %w", err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "kml":
+			isKML = true
+		case "Placemark":
+			var pm kmlPlacemark
+			if err := dec.DecodeElement(&pm, &start); err != nil {
+				return nil, fmt.Errorf("некорректный KML: %w", err)
+			}
+
+			feature, err := placemarkToFeature(&pm)
+			if err != nil {
+				return nil, fmt.Errorf("объект %d (%q): %w", len(features), pm.Name, err)
+			}
+			features = append(features, feature)
+		}
+	}
+
+	if !isKML {
+		return nil, errors.New("некорректный KML: нет корневого элемента kml")
+	}
+
+	return features, nil
+}
+
+func placemarkToFeature(pm *kmlPlacemark) (entity.GeoJsonFeature, error) {
+	properties := make(map[string]any)
+	for _, d := range pm.ExtendedData.Data {
+		properties[d.Name] = strings.TrimSpace(d.Value)
+	}
+	for _, sd := range pm.ExtendedData.SchemaData {
+		for _, d := range sd.SimpleData {
+			properties[d.Name] = strings.TrimSpace(d.Value)
+		}
+	}
+	if name := strings.TrimSpace(pm.Name); name != "" {
+		properties["name"] = name
+	}
+	if description := strings.TrimSpace(pm.Description); description != "" {
+		properties["description"] = description
+	}
+
+	feature := entity.GeoJsonFeature{Type: "Feature", Properties: properties}
+	if pm.ID != "" {
+		feature.ID = pm.ID
+	}
+
+	polygons, err := pm.kmlGeometry.polygons()
+	if err != nil {
+		return feature, err
+	}
+
+	if len(polygons) > 0 {
+		feature.Geometry = geometryJSON(polygons)
+	} else if kind := pm.kmlGeometry.kind(); kind != "" {
+		feature.Geometry, _ = json.Marshal(map[string]string{"type": kind})
+	}
+
+	return feature, nil
+}
+
+// polygons собирает полигоны геометрии, включая вложенные MultiGeometry
+func (g *kmlGeometry) polygons() ([]polygon, error) {
+	var polygons []polygon
+
+	for _, p := range g.Polygons {
+		outer, err := parseKMLCoordinates(p.Outer)
+		if err != nil {
+			return nil, err
+		}
+
+		rings := polygon{outer}
+		for _, inner := range p.Inner {
+			ring, err := parseKMLCoordinates(inner)
+			if err != nil {
+				return nil, err
+			}
+			rings = append(rings, ring)
+		}
+		polygons = append(polygons, rings)
+	}
+
+	for i := range g.MultiGeometry {
+		nested, err := g.MultiGeometry[i].polygons()
+		if err != nil {
+			return nil, err
+		}
+		polygons = append(polygons, nested...)
+	}
+
+	return polygons, nil
+}
+
+// kind возвращает тип GeoJSON для неполигональной геометрии
+func (g *kmlGeometry) kind() string {
+	switch {
+	case len(g.Points) > 0:
+		return "Point"
+	case len(g.LineStrings) > 0, len(g.LinearRings) > 0:
+		return "LineString"
+	}
+	for i := range g.MultiGeometry {
+		if kind := g.MultiGeometry[i].kind(); kind != "" {
+			return kind
+		}
+	}
+	return ""
+}
+
+// parseKMLCoordinates разбирает список кортежей "lon,lat[,alt]", разделенных пробелами
+func parseKMLCoordinates(s string) ([][]float64, error) {
+	fields := strings.Fields(s)
+	ring := make([][]float64, 0, len(fields))
+
+	for _, tuple := range fields {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("некорректные координаты %q", tuple)
+		}
+
+		lon, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("некорректные координаты %q", tuple)
+		}
+		lat, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("некорректные координаты %q", tuple)
+		}
+
+		ring = append(ring, []float64{lon, lat})
+	}
+
+	return ring, nil
+}
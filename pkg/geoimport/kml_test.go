@@ -0,0 +1,93 @@
+package geoimport
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKML(t *testing.T) {
+	kml := `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2"><Document><Folder>
+  <Placemark id="z-1">
+    <name> Пойма </name>
+    <description>Подтопление</description>
+    <ExtendedData>
+      <Data name="severity"><value>severe</value></Data>
+      <SchemaData schemaUrl="#zones"><SimpleData name="ZONE_ID">42</SimpleData></SchemaData>
+    </ExtendedData>
+    <Polygon>
+      <outerBoundaryIs><LinearRing><coordinates>0,0,0 1,0,0 1,1,0 0,1,0 0,0,0</coordinates></LinearRing></outerBoundaryIs>
+      <innerBoundaryIs><LinearRing><coordinates>0.2,0.2 0.4,0.2 0.4,0.4 0.2,0.2</coordinates></LinearRing></innerBoundaryIs>
+    </Polygon>
+  </Placemark>
+  <Placemark>
+    <name>Два острова</name>
+    <MultiGeometry>
+      <Polygon><outerBoundaryIs><LinearRing><coordinates>0,0 1,0 1,1 0,0</coordinates></LinearRing></outerBoundaryIs></Polygon>
+      <Polygon><outerBoundaryIs><LinearRing><coordinates>5,5 6,5 6,6 5,5</coordinates></LinearRing></outerBoundaryIs></Polygon>
+    </MultiGeometry>
+  </Placemark>
+  <Placemark>
+    <name>Пост</name>
+    <Point><coordinates>37.6,55.7</coordinates></Point>
+  </Placemark>
+</Folder></Document></kml>`
+
+	features, err := ParseKML([]byte(kml))
+	require.NoError(t, err)
+	require.Len(t, features, 3)
+
+	first := features[0]
+	assert.Equal(t, "z-1", first.ID)
+	assert.Equal(t, map[string]any{
+		"name":        "Пойма",
+		"description": "Подтопление",
+		"severity":    "severe",
+		"ZONE_ID":     "42",
+	}, first.Properties)
+
+	var polygon struct {
+		Type        string        `json:"type"`
+		Coordinates [][][]float64 `json:"coordinates"`
+	}
+	require.NoError(t, json.Unmarshal(first.Geometry, &polygon))
+	assert.Equal(t, "Polygon", polygon.Type)
+	require.Len(t, polygon.Coordinates, 2)
+	assert.Equal(t, []float64{1, 1}, polygon.Coordinates[0][2])
+
+	assert.Nil(t, features[1].ID)
+	assert.Contains(t, string(features[1].Geometry), `"MultiPolygon"`)
+	assert.JSONEq(t, `{"type": "Point"}`, string(features[2].Geometry))
+}
+
+func TestParseKML_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		kml  string
+	}{
+		{
+			name: "Not KML",
+			kml:  `<gpx><trk/></gpx>`,
+		},
+		{
+			name: "Broken XML",
+			kml:  `<kml><Placemark><name>Зона</Placemark></kml>`,
+		},
+		{
+			name: "Bad coordinates",
+			kml: `<kml><Placemark><Polygon><outerBoundaryIs><LinearRing>
+				<coordinates>0,0 1;0 1,1 0,0</coordinates>
+			</LinearRing></outerBoundaryIs></Polygon></Placemark></kml>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKML([]byte(tt.kml))
+			assert.Error(t, err)
+		})
+	}
+}
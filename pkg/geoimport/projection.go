@@ -0,0 +1,485 @@
+package geoimport
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	wgs84SemiMajor     = 6378137.0
+	wgs84InvFlattening = 298.257223563
+	arcSecondToRadian  = math.Pi / (180 * 3600)
+	degreeToRadian     = math.Pi / 180
+)
+
+// knownDatumShifts — параметры пересчета в WGS 84 (TOWGS84, Position Vector) для датумов,
+// которые встречаются в ESRI .prj без TOWGS84. Pulkovo 1942 — ГОСТ Р 51794-2008.
+var knownDatumShifts = map[string][7]float64{
+	"pulkovo_1942": {23.57, -140.95, -79.8, 0, -0.35, -0.79, -0.22},
+}
+
+// Projection пересчитывает координаты из системы, описанной в .prj (WKT), в EPSG:4326.
+// Поддерживаются географические системы, Transverse Mercator (UTM, Гаусс-Крюгер, МСК),
+// Mercator и Web Mercator; датум отличный от WGS 84 пересчитывается сдвигом Гельмерта.
+type Projection struct {
+	name    string
+	inverse func(x, y float64) (lam, phi float64)
+	datum   *datumShift
+}
+
+type ellipsoid struct {
+	a  float64
+	e2 float64
+}
+
+type datumShift struct {
+	source ellipsoid
+	// tx, ty, tz в метрах, rx, ry, rz в радианах, s — безразмерный масштаб
+	tx, ty, tz, rx, ry, rz, s float64
+}
+
+// ParsePRJ разбирает описание системы координат в формате WKT (содержимое .prj)
+func ParsePRJ(wkt string) (*Projection, error) {
+	root, err := parseWKT(wkt)
+	if err != nil {
+		return nil, fmt.Errorf("некорректный .prj: %w", err)
+	}
+
+	switch root.name {
+	case "GEOGCS":
+		geog, err := parseGeogCS(root)
+		if err != nil {
+			return nil, err
+		}
+		return &Projection{
+			name:  root.str(0),
+			datum: geog.datum,
+			inverse: func(x, y float64) (float64, float64) {
+				return x*geog.angularUnit + geog.primeMeridian, y * geog.angularUnit
+			},
+		}, nil
+	case "PROJCS":
+		return parseProjCS(root)
+	default:
+		return nil, fmt.Errorf("неподдерживаемая система координат %s: ожидается GEOGCS или PROJCS", root.name)
+	}
+}
+
+// ToWGS84 возвращает долготу и широту в градусах EPSG:4326
+func (p *Projection) ToWGS84(x, y float64) (lon, lat float64) {
+	lam, phi := p.inverse(x, y)
+	if p.datum != nil {
+		lam, phi = p.datum.apply(lam, phi)
+	}
+	return lam / degreeToRadian, phi / degreeToRadian
+}
+
+func (p *Projection) String() string {
+	return p.name
+}
+
+type geogCS struct {
+	ellipsoid     ellipsoid
+	datum         *datumShift
+	angularUnit   float64
+	primeMeridian float64
+}
+
+func parseGeogCS(n *wktNode) (*geogCS, error) {
+	datum := n.child("DATUM")
+	if datum == nil {
+		return nil, fmt.Errorf("в GEOGCS %q нет DATUM", n.str(0))
+	}
+	spheroid := datum.child("SPHEROID")
+	if spheroid == nil {
+		return nil, fmt.Errorf("в DATUM %q нет SPHEROID", datum.str(0))
+	}
+
+	a, invF := spheroid.num(1), spheroid.num(2)
+	if a <= 0 {
+		return nil, fmt.Errorf("некорректный SPHEROID %q", spheroid.str(0))
+	}
+	e2 := 0.0
+	if invF != 0 {
+		f := 1 / invF
+		e2 = 2*f - f*f
+	}
+
+	g := &geogCS{
+		ellipsoid:   ellipsoid{a: a, e2: e2},
+		angularUnit: degreeToRadian,
+	}
+	if unit := n.child("UNIT"); unit != nil && unit.num(1) > 0 {
+		g.angularUnit = unit.num(1)
+	}
+	if primem := n.child("PRIMEM"); primem != nil {
+		g.primeMeridian = primem.num(1) * g.angularUnit
+	}
+
+	var params []float64
+	if towgs84 := datum.child("TOWGS84"); towgs84 != nil {
+		for i := range 7 {
+			params = append(params, towgs84.num(i))
+		}
+	} else if !isWGS84Compatible(a, invF) {
+		shift, ok := knownDatumShifts[normalizeName(strings.TrimPrefix(datum.str(0), "D_"))]
+		if !ok {
+			return nil, fmt.Errorf("для датума %q не задан TOWGS84: пересчет в EPSG:4326 невозможен", datum.str(0))
+		}
+		params = shift[:]
+	}
+
+	if len(params) == 7 && !allZero(params) {
+		g.datum = &datumShift{
+			source: g.ellipsoid,
+			tx:     params[0], ty: params[1], tz: params[2],
+			rx: params[3] * arcSecondToRadian, ry: params[4] * arcSecondToRadian, rz: params[5] * arcSecondToRadian,
+			s: params[6] * 1e-6,
+		}
+	}
+
+	return g, nil
+}
+
+// isWGS84Compatible считает датум совпадающим с WGS 84, если совпадает эллипсоид (WGS 84 или
+// GRS 80 — ETRS89, NAD83, ГСК-2011 отличаются от WGS 84 менее чем на метр). Сфера радиуса
+// 6378137 — вспомогательная сфера Web Mercator, координаты на ней считаются координатами WGS 84.
+func isWGS84Compatible(a, invF float64) bool {
+	return a == wgs84SemiMajor && (invF == 0 || math.Abs(invF-wgs84InvFlattening) < 1e-3)
+}
+
+func parseProjCS(n *wktNode) (*Projection, error) {
+	geogNode := n.child("GEOGCS")
+	if geogNode == nil {
+		return nil, fmt.Errorf("в PROJCS %q нет GEOGCS", n.str(0))
+	}
+	geog, err := parseGeogCS(geogNode)
+	if err != nil {
+		return nil, err
+	}
+
+	projection := n.child("PROJECTION")
+	if projection == nil {
+		return nil, fmt.Errorf("в PROJCS %q нет PROJECTION", n.str(0))
+	}
+
+	params := make(map[string]float64)
+	for _, p := range n.children("PARAMETER") {
+		params[normalizeName(p.str(0))] = p.num(1)
+	}
+	param := func(def float64, names ...string) float64 {
+		for _, name := range names {
+			if v, ok := params[name]; ok {
+				return v
+			}
+		}
+		return def
+	}
+
+	linearUnit := 1.0
+	if unit := n.child("UNIT"); unit != nil && unit.num(1) > 0 {
+		linearUnit = unit.num(1)
+	}
+
+	falseEasting := param(0, "false_easting")
+	falseNorthing := param(0, "false_northing")
+	lon0 := param(0, "central_meridian", "longitude_of_origin", "longitude_of_center")*geog.angularUnit + geog.primeMeridian
+	lat0 := param(0, "latitude_of_origin", "latitude_of_center") * geog.angularUnit
+	k0 := param(1, "scale_factor")
+	ell := geog.ellipsoid
+
+	var inverse func(x, y float64) (float64, float64)
+
+	switch name := normalizeName(projection.str(0)); {
+	case strings.Contains(name, "pseudo_mercator"), strings.Contains(name, "auxiliary_sphere"),
+		strings.Contains(normalizeName(n.str(0)), "web_mercator"), strings.Contains(n.String(), "+nadgrids=@null"):
+		inverse = sphericalMercatorInverse(ell.a, lon0)
+	case name == "transverse_mercator", name == "gauss_kruger":
+		inverse = transverseMercatorInverse(ell, k0, lon0, lat0)
+	case name == "mercator", name == "mercator_1sp":
+		inverse = mercatorInverse(ell, k0, lon0)
+	case name == "mercator_2sp":
+		lat1 := param(0, "standard_parallel_1") * geog.angularUnit
+		sin := math.Sin(lat1)
+		inverse = mercatorInverse(ell, math.Cos(lat1)/math.Sqrt(1-ell.e2*sin*sin), lon0)
+	default:
+		return nil, fmt.Errorf("неподдерживаемая проекция %q: поддерживаются Transverse Mercator и Mercator", projection.str(0))
+	}
+
+	return &Projection{
+		name:  n.str(0),
+		datum: geog.datum,
+		inverse: func(x, y float64) (float64, float64) {
+			return inverse(x*linearUnit-falseEasting*linearUnit, y*linearUnit-falseNorthing*linearUnit)
+		},
+	}, nil
+}
+
+// transverseMercatorInverse — обратная поперечная проекция Меркатора на эллипсоиде (Snyder, 1987).
+// Точность лучше метра в пределах нескольких градусов от осевого меридиана.
+func transverseMercatorInverse(ell ellipsoid, k0, lon0, lat0 float64) func(x, y float64) (float64, float64) {
+	a, e2 := ell.a, ell.e2
+	e4, e6 := e2*e2, e2*e2*e2
+	ep2 := e2 / (1 - e2)
+
+	meridianArc := func(phi float64) float64 {
+		return a * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+			(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+			(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+			(35*e6/3072)*math.Sin(6*phi))
+	}
+	m0 := meridianArc(lat0)
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+
+	return func(x, y float64) (float64, float64) {
+		m := m0 + y/k0
+		mu := m / (a * (1 - e2/4 - 3*e4/64 - 5*e6/256))
+
+		phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+			(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+			(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+			(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+		sin1, cos1, tan1 := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+		c1 := ep2 * cos1 * cos1
+		t1 := tan1 * tan1
+		n1 := a / math.Sqrt(1-e2*sin1*sin1)
+		r1 := a * (1 - e2) / math.Pow(1-e2*sin1*sin1, 1.5)
+		d := x / (n1 * k0)
+
+		phi := phi1 - (n1*tan1/r1)*(d*d/2-
+			(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+			(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+		lam := lon0 + (d-
+			(1+2*t1+c1)*math.Pow(d, 3)/6+
+			(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120)/cos1
+
+		return lam, phi
+	}
+}
+
+// mercatorInverse — обратная проекция Меркатора на эллипсоиде
+func mercatorInverse(ell ellipsoid, k0, lon0 float64) func(x, y float64) (float64, float64) {
+	e := math.Sqrt(ell.e2)
+
+	return func(x, y float64) (float64, float64) {
+		t := math.Exp(-y / (ell.a * k0))
+		phi := math.Pi/2 - 2*math.Atan(t)
+		for range 10 {
+			es := e * math.Sin(phi)
+			phi = math.Pi/2 - 2*math.Atan(t*math.Pow((1-es)/(1+es), e/2))
+		}
+		return lon0 + x/(ell.a*k0), phi
+	}
+}
+
+// sphericalMercatorInverse — обратная проекция Web Mercator (EPSG:3857): сфера радиуса большой полуоси
+func sphericalMercatorInverse(r, lon0 float64) func(x, y float64) (float64, float64) {
+	return func(x, y float64) (float64, float64) {
+		return lon0 + x/r, math.Pi/2 - 2*math.Atan(math.Exp(-y/r))
+	}
+}
+
+// apply пересчитывает геодезические координаты из исходного датума в WGS 84 через
+// геоцентрические координаты и семипараметрическое преобразование Гельмерта
+func (d *datumShift) apply(lam, phi float64) (float64, float64) {
+	a, e2 := d.source.a, d.source.e2
+	sinPhi := math.Sin(phi)
+	n := a / math.Sqrt(1-e2*sinPhi*sinPhi)
+
+	x := n * math.Cos(phi) * math.Cos(lam)
+	y := n * math.Cos(phi) * math.Sin(lam)
+	z := n * (1 - e2) * sinPhi
+
+	scale := 1 + d.s
+	x2 := d.tx + scale*(x-d.rz*y+d.ry*z)
+	y2 := d.ty + scale*(d.rz*x+y-d.rx*z)
+	z2 := d.tz + scale*(-d.ry*x+d.rx*y+z)
+
+	f := 1 / wgs84InvFlattening
+	we2 := 2*f - f*f
+	p := math.Hypot(x2, y2)
+	phi2 := math.Atan2(z2, p*(1-we2))
+	for range 5 {
+		sin := math.Sin(phi2)
+		n2 := wgs84SemiMajor / math.Sqrt(1-we2*sin*sin)
+		h := p/math.Cos(phi2) - n2
+		phi2 = math.Atan2(z2, p*(1-we2*n2/(n2+h)))
+	}
+
+	return math.Atan2(y2, x2), phi2
+}
+
+func allZero(values []float64) bool {
+	for _, v := range values {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeName приводит имя из WKT к виду "transverse_mercator"
+func normalizeName(s string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_"))
+}
+
+// wktNode — узел WKT вида NAME["строка", 1.0, CHILD[...]]
+type wktNode struct {
+	name string
+	args []any
+}
+
+func (n *wktNode) child(name string) *wktNode {
+	for _, arg := range n.args {
+		if c, ok := arg.(*wktNode); ok && strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *wktNode) children(name string) []*wktNode {
+	var nodes []*wktNode
+	for _, arg := range n.args {
+		if c, ok := arg.(*wktNode); ok && strings.EqualFold(c.name, name) {
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+func (n *wktNode) str(i int) string {
+	if i < len(n.args) {
+		if s, ok := n.args[i].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+func (n *wktNode) num(i int) float64 {
+	if i < len(n.args) {
+		if f, ok := n.args[i].(float64); ok {
+			return f
+		}
+	}
+	return 0
+}
+
+func (n *wktNode) String() string {
+	parts := make([]string, 0, len(n.args))
+	for _, arg := range n.args {
+		parts = append(parts, fmt.Sprint(arg))
+	}
+	return n.name + "[" + strings.Join(parts, ",") + "]"
+}
+
+func parseWKT(s string) (*wktNode, error) {
+	p := &wktParser{s: s}
+	node, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, fmt.Errorf("лишние символы после позиции %d", p.pos)
+	}
+	return node, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) node() (*wktNode, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && (p.s[p.pos] == '_' || unicode.IsLetter(rune(p.s[p.pos])) || unicode.IsDigit(rune(p.s[p.pos]))) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, fmt.Errorf("ожидается имя узла в позиции %d", p.pos)
+	}
+	node := &wktNode{name: strings.ToUpper(p.s[start:p.pos])}
+
+	p.skipSpace()
+	if p.pos >= len(p.s) || (p.s[p.pos] != '[' && p.s[p.pos] != '(') {
+		return nil, fmt.Errorf("ожидается [ после %s", node.name)
+	}
+	closing := byte(']')
+	if p.s[p.pos] == '(' {
+		closing = ')'
+	}
+	p.pos++
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("незакрытый узел %s", node.name)
+		}
+
+		switch c := p.s[p.pos]; {
+		case c == '"':
+			end := strings.IndexByte(p.s[p.pos+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("незакрытая строка в позиции %d", p.pos)
+			}
+			node.args = append(node.args, p.s[p.pos+1:p.pos+1+end])
+			p.pos += end + 2
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			start := p.pos
+			for p.pos < len(p.s) && strings.IndexByte("+-.eE0123456789", p.s[p.pos]) >= 0 {
+				p.pos++
+			}
+			f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+			if err != nil {
+				return nil, fmt.Errorf("некорректное число %q", p.s[start:p.pos])
+			}
+			node.args = append(node.args, f)
+		default:
+			// Вложенный узел или перечисление без скобок (например, AXIS["X",EAST])
+			save := p.pos
+			child, err := p.node()
+			if err != nil {
+				p.pos = save
+				start := p.pos
+				for p.pos < len(p.s) && (p.s[p.pos] == '_' || unicode.IsLetter(rune(p.s[p.pos]))) {
+					p.pos++
+				}
+				if start == p.pos {
+					return nil, fmt.Errorf("неожиданный символ %q в позиции %d", p.s[p.pos], p.pos)
+				}
+				node.args = append(node.args, p.s[start:p.pos])
+			} else {
+				node.args = append(node.args, child)
+			}
+		}
+
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("незакрытый узел %s", node.name)
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case closing:
+			p.pos++
+			return node, nil
+		default:
+			return nil, fmt.Errorf("неожиданный символ %q в позиции %d", p.s[p.pos], p.pos)
+		}
+	}
+}
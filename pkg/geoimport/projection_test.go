@@ -0,0 +1,103 @@
+package geoimport
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	wgs84GeogCS   = `GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+	pulkovoGeogCS = `GEOGCS["GCS_Pulkovo_1942",DATUM["D_Pulkovo_1942",SPHEROID["Krasovsky_1940",6378245.0,298.3]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`
+)
+
+func TestParsePRJ(t *testing.T) {
+	tests := []struct {
+		name  string
+		wkt   string
+		x, y  float64
+		lon   float64
+		lat   float64
+		delta float64
+	}{
+		{
+			name: "WGS 84 geographic",
+			wkt:  wgs84GeogCS,
+			x:    37.6, y: 55.7,
+			lon: 37.6, lat: 55.7,
+			delta: 1e-12,
+		},
+		{
+			name: "UTM zone 37N on the central meridian",
+			wkt: `PROJCS["WGS_1984_UTM_Zone_37N",` + wgs84GeogCS + `,PROJECTION["Transverse_Mercator"],` +
+				`PARAMETER["False_Easting",500000.0],PARAMETER["False_Northing",0.0],PARAMETER["Central_Meridian",39.0],` +
+				`PARAMETER["Scale_Factor",0.9996],PARAMETER["Latitude_Of_Origin",0.0],UNIT["Meter",1.0]]`,
+			// Длина дуги меридиана WGS 84 до 45° — 4984944.38 м, с масштабом 0.9996
+			x: 500000, y: 4982950.40,
+			lon: 39, lat: 45,
+			delta: 1e-5,
+		},
+		{
+			name: "Web Mercator",
+			wkt: `PROJCS["WGS_1984_Web_Mercator_Auxiliary_Sphere",` + wgs84GeogCS + `,PROJECTION["Mercator_Auxiliary_Sphere"],` +
+				`PARAMETER["False_Easting",0.0],PARAMETER["False_Northing",0.0],PARAMETER["Central_Meridian",0.0],` +
+				`PARAMETER["Standard_Parallel_1",0.0],PARAMETER["Auxiliary_Sphere_Type",0.0],UNIT["Meter",1.0]]`,
+			x: 20037508.342789244, y: wgs84SemiMajor * math.Log(math.Tan(math.Pi/4+math.Pi/8)),
+			lon: 180, lat: 45,
+			delta: 1e-9,
+		},
+		{
+			name: "Pulkovo 1942 without TOWGS84 uses the known shift",
+			wkt:  pulkovoGeogCS,
+			x:    37.6, y: 55.7,
+			// Сдвиг Пулково 1942 — WGS 84 под Москвой: около 135 м к западу и 20 м к северу
+			lon: 37.5979, lat: 55.7002,
+			delta: 1e-4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePRJ(tt.wkt)
+			require.NoError(t, err)
+
+			lon, lat := p.ToWGS84(tt.x, tt.y)
+			assert.InDelta(t, tt.lon, lon, tt.delta)
+			assert.InDelta(t, tt.lat, lat, tt.delta)
+		})
+	}
+}
+
+func TestParsePRJ_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		wkt  string
+	}{
+		{
+			name: "Malformed WKT",
+			wkt:  `GEOGCS["GCS_WGS_1984",DATUM[`,
+		},
+		{
+			name: "Unknown datum without TOWGS84",
+			wkt:  `GEOGCS["GCS_Tokyo",DATUM["D_Tokyo",SPHEROID["Bessel_1841",6377397.155,299.1528128]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]]`,
+		},
+		{
+			name: "Unsupported projection",
+			wkt: `PROJCS["Lambert",` + wgs84GeogCS + `,PROJECTION["Lambert_Conformal_Conic"],` +
+				`PARAMETER["Central_Meridian",0.0],UNIT["Meter",1.0]]`,
+		},
+		{
+			name: "Unsupported root",
+			wkt:  `GEOCCS["WGS 84"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePRJ(tt.wkt)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package geoimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+const (
+	// maxShapefileArchiveBytes ограничивает размер самого архива
+	maxShapefileArchiveBytes = 64 << 20
+	// maxShapefileMemberBytes ограничивает распакованный размер файла архива
+	maxShapefileMemberBytes = 256 << 20
+	// maxShapefileUnpackedBytes ограничивает суммарный распакованный размер файлов слоя
+	maxShapefileUnpackedBytes = 512 << 20
+
+	shpFileCode   = 9994
+	shpHeaderSize = 100
+
+	shapeNull     = 0
+	shapePolygon  = 5
+	shapePolygonZ = 15
+	shapePolygonM = 25
+)
+
+var shapeTypeNames = map[int32]string{
+	1: "Point", 3: "PolyLine", 8: "MultiPoint",
+	11: "PointZ", 13: "PolyLineZ", 18: "MultiPointZ",
+	21: "PointM", 23: "PolyLineM", 28: "MultiPointM",
+	31: "MultiPatch",
+}
+
+// ParseShapefileZip разбирает ZIP-архив с одним слоем Shapefile: .shp и .dbf обязательны,
+// .prj задает систему координат (без него координаты считаются EPSG:4326), .cpg — кодировку
+// атрибутов. Свойства объектов берутся из атрибутов .dbf. Удаленные в .dbf записи пропускаются.
+func ParseShapefileZip(data []byte) ([]entity.GeoJsonFeature, error) {
+	if len(data) > maxShapefileArchiveBytes {
+		return nil, fmt.Errorf("ZIP-архив больше %d МБ", maxShapefileArchiveBytes>>20)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("некорректный ZIP-архив: %w", err)
+	}
+
+	members := make(map[string]*zip.File)
+	var layers []string
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(path.Base(f.Name), "._") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Name))
+		base := strings.TrimSuffix(f.Name, path.Ext(f.Name))
+		members[strings.ToLower(base)+ext] = f
+		if ext == ".shp" {
+			layers = append(layers, base)
+		}
+	}
+
+	switch len(layers) {
+	case 0:
+		return nil, errors.New("в архиве нет файла .shp")
+	case 1:
+	default:
+		return nil, fmt.Errorf("в архиве несколько слоев (%s), ожидается один", strings.Join(layers, ", "))
+	}
+
+	base := strings.ToLower(layers[0])
+	unpacked := 0
+	read := func(ext string, required bool) ([]byte, error) {
+		f, ok := members[base+ext]
+		if !ok {
+			if required {
+				return nil, fmt.Errorf("в архиве нет файла %s%s", layers[0], ext)
+			}
+			return nil, nil
+		}
+
+		member, err := readZipMember(f, min(maxShapefileMemberBytes, maxShapefileUnpackedBytes-unpacked))
+		if err != nil {
+			return nil, err
+		}
+		unpacked += len(member)
+		return member, nil
+	}
+
+	shp, err := read(".shp", true)
+	if err != nil {
+		return nil, err
+	}
+	dbf, err := read(".dbf", true)
+	if err != nil {
+		return nil, err
+	}
+	prj, err := read(".prj", false)
+	if err != nil {
+		return nil, err
+	}
+	cpg, err := read(".cpg", false)
+	if err != nil {
+		return nil, err
+	}
+
+	var projection *Projection
+	if len(bytes.TrimSpace(prj)) > 0 {
+		projection, err = ParsePRJ(string(prj))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shapes, err := parseShp(shp)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := parseDBF(dbf, string(cpg))
+	if err != nil {
+		return nil, err
+	}
+	if len(table.records) != len(shapes) {
+		return nil, fmt.Errorf("число записей .dbf (%d) не совпадает с числом фигур .shp (%d)", len(table.records), len(shapes))
+	}
+
+	features := make([]entity.GeoJsonFeature, 0, len(shapes))
+	for i, shape := range shapes {
+		record := table.records[i]
+		if record.deleted {
+			continue
+		}
+
+		var polygons []polygon
+		if shape != nil {
+			polygons = assemblePolygons(shape, projection)
+		}
+
+		features = append(features, entity.GeoJsonFeature{
+			Type:       "Feature",
+			Geometry:   geometryJSON(polygons),
+			Properties: record.properties,
+		})
+	}
+
+	return features, nil
+}
+
+// readZipMember распаковывает файл архива не больше limit байт. Размер из заголовка ZIP
+// проверяется заранее, но ему не доверяется: чтение тоже обрезается по limit.
+func readZipMember(f *zip.File, limit int) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, memberTooLarge(f.Name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось распаковать %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, int64(limit)+1))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось распаковать %s: %w", f.Name, err)
+	}
+	if len(data) > limit {
+		return nil, memberTooLarge(f.Name)
+	}
+
+	return data, nil
+}
+
+func memberTooLarge(name string) error {
+	return fmt.Errorf("файл %s слишком большой: файл архива не должен превышать %d МБ, а все файлы слоя — %d МБ",
+		name, maxShapefileMemberBytes>>20, maxShapefileUnpackedBytes>>20)
+}
+
+// parseShp возвращает кольца каждой фигуры в исходной системе координат; nil — пустая фигура
+func parseShp(data []byte) ([][][][]float64, error) {
+	if len(data) < shpHeaderSize || binary.BigEndian.Uint32(data[0:4]) != shpFileCode {
+		return nil, errors.New("некорректный файл .shp: неверный заголовок")
+	}
+
+	layerType := int32(binary.LittleEndian.Uint32(data[32:36]))
+	if layerType != shapeNull && layerType != shapePolygon && layerType != shapePolygonZ && layerType != shapePolygonM {
+		return nil, fmt.Errorf("слой содержит фигуры %s, ожидаются полигоны", shapeTypeName(layerType))
+	}
+
+	var shapes [][][][]float64
+	for offset := shpHeaderSize; offset < len(data); {
+		if offset+8 > len(data) {
+			return nil, fmt.Errorf("некорректный файл .shp: обрезанная запись %d", len(shapes)+1)
+		}
+		length := int(binary.BigEndian.Uint32(data[offset+4:offset+8])) * 2
+		content := offset + 8
+		if length < 4 || content+length > len(data) {
+			return nil, fmt.Errorf("некорректный файл .shp: обрезанная запись %d", len(shapes)+1)
+		}
+
+		rings, err := parseShpPolygon(data[content : content+length])
+		if err != nil {
+			return nil, fmt.Errorf("запись .shp %d: %w", len(shapes)+1, err)
+		}
+		shapes = append(shapes, rings)
+
+		offset = content + length
+	}
+
+	return shapes, nil
+}
+
+func parseShpPolygon(rec []byte) ([][][]float64, error) {
+	shapeType := int32(binary.LittleEndian.Uint32(rec[0:4]))
+	switch shapeType {
+	case shapeNull:
+		return nil, nil
+	case shapePolygon, shapePolygonZ, shapePolygonM:
+	default:
+		return nil, fmt.Errorf("фигура %s, ожидается полигон", shapeTypeName(shapeType))
+	}
+
+	if len(rec) < 44 {
+		return nil, errors.New("обрезанный полигон")
+	}
+	numParts := int(binary.LittleEndian.Uint32(rec[36:40]))
+	numPoints := int(binary.LittleEndian.Uint32(rec[40:44]))
+	pointsAt := 44 + 4*numParts
+	if numParts <= 0 || numPoints < 0 || pointsAt+16*numPoints > len(rec) {
+		return nil, errors.New("обрезанный полигон")
+	}
+
+	starts := make([]int, numParts+1)
+	for i := range numParts {
+		starts[i] = int(binary.LittleEndian.Uint32(rec[44+4*i:]))
+	}
+	starts[numParts] = numPoints
+
+	rings := make([][][]float64, 0, numParts)
+	for i := range numParts {
+		from, to := starts[i], starts[i+1]
+		if from < 0 || from > to || to > numPoints {
+			return nil, errors.New("некорректные индексы частей полигона")
+		}
+
+		ring := make([][]float64, 0, to-from)
+		for j := from; j < to; j++ {
+			at := pointsAt + 16*j
+			ring = append(ring, []float64{
+				math.Float64frombits(binary.LittleEndian.Uint64(rec[at:])),
+				math.Float64frombits(binary.LittleEndian.Uint64(rec[at+8:])),
+			})
+		}
+		rings = append(rings, ring)
+	}
+
+	return rings, nil
+}
+
+// assemblePolygons группирует кольца в полигоны: в Shapefile внешние кольца обходятся по часовой
+// стрелке, дыры — против, и дыра относится к внешнему кольцу, в котором лежит. Ориентация
+// определяется в исходных координатах, затем кольца пересчитываются в EPSG:4326 и приводятся
+// к порядку RFC 7946 (внешнее кольцо против часовой стрелки).
+func assemblePolygons(rings [][][]float64, projection *Projection) []polygon {
+	var (
+		polygons []polygon
+		holes    [][][]float64
+	)
+	for _, ring := range rings {
+		if signedArea(ring) < 0 {
+			polygons = append(polygons, polygon{ring})
+		} else {
+			holes = append(holes, ring)
+		}
+	}
+
+	for _, hole := range holes {
+		owner := -1
+		for i, p := range polygons {
+			if len(hole) > 0 && pointInRing(p[0], hole[0]) {
+				owner = i
+				break
+			}
+		}
+		// Кольцо вне всех внешних колец (или файл с неверной ориентацией) становится отдельным полигоном
+		if owner < 0 {
+			polygons = append(polygons, polygon{hole})
+			continue
+		}
+		polygons[owner] = append(polygons[owner], hole)
+	}
+
+	for _, p := range polygons {
+		for i, ring := range p {
+			if projection != nil {
+				for _, coord := range ring {
+					coord[0], coord[1] = projection.ToWGS84(coord[0], coord[1])
+				}
+			}
+			if (i == 0) != (signedArea(ring) > 0) {
+				reverseRing(ring)
+			}
+		}
+	}
+
+	return polygons
+}
+
+func shapeTypeName(t int32) string {
+	if name, ok := shapeTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("типа %d", t)
+}
@@ -0,0 +1,197 @@
+package geoimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildShp собирает файл .shp с полигонами: каждая фигура — список колец, nil — пустая фигура
+func buildShp(shapeType int32, shapes [][][][]float64) []byte {
+	var records bytes.Buffer
+	for i, rings := range shapes {
+		var content bytes.Buffer
+		if rings == nil {
+			binary.Write(&content, binary.LittleEndian, int32(shapeNull))
+		} else {
+			numPoints := 0
+			for _, ring := range rings {
+				numPoints += len(ring)
+			}
+
+			binary.Write(&content, binary.LittleEndian, shapeType)
+			content.Write(make([]byte, 32)) // bbox не читается
+			binary.Write(&content, binary.LittleEndian, int32(len(rings)))
+			binary.Write(&content, binary.LittleEndian, int32(numPoints))
+			start := 0
+			for _, ring := range rings {
+				binary.Write(&content, binary.LittleEndian, int32(start))
+				start += len(ring)
+			}
+			for _, ring := range rings {
+				for _, p := range ring {
+					binary.Write(&content, binary.LittleEndian, p[0])
+					binary.Write(&content, binary.LittleEndian, p[1])
+				}
+			}
+		}
+
+		binary.Write(&records, binary.BigEndian, int32(i+1))
+		binary.Write(&records, binary.BigEndian, int32(content.Len()/2))
+		records.Write(content.Bytes())
+	}
+
+	header := make([]byte, shpHeaderSize)
+	binary.BigEndian.PutUint32(header[0:4], shpFileCode)
+	binary.BigEndian.PutUint32(header[24:28], uint32((shpHeaderSize+records.Len())/2))
+	binary.LittleEndian.PutUint32(header[28:32], 1000)
+	binary.LittleEndian.PutUint32(header[32:36], uint32(shapeType))
+
+	return append(header, records.Bytes()...)
+}
+
+func buildZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, data := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestParseShapefileZip(t *testing.T) {
+	// Внешнее кольцо по часовой стрелке, дыра — против, как требует спецификация Shapefile
+	outer := [][]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	hole := [][]float64{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}
+	second := [][]float64{{20, 0}, {20, 5}, {25, 5}, {25, 0}, {20, 0}}
+
+	shp := buildShp(shapePolygon, [][][][]float64{
+		{outer, hole},
+		{outer, second},
+		nil,
+		{outer},
+	})
+	dbf := buildDBF(0, []testDBFField{{name: "name", kind: 'C', length: 32}}, [][]string{
+		{"Зона с дырой"},
+		{"Две части"},
+		{"Без геометрии"},
+		{"*", "Удаленная"},
+	})
+
+	features, err := ParseShapefileZip(buildZip(t, map[string][]byte{
+		"layer/ZONES.SHP": shp,
+		"layer/zones.dbf": dbf,
+		"layer/zones.cpg": []byte("UTF-8"),
+	}))
+	require.NoError(t, err)
+	require.Len(t, features, 3)
+
+	var first struct {
+		Type        string        `json:"type"`
+		Coordinates [][][]float64 `json:"coordinates"`
+	}
+	require.NoError(t, json.Unmarshal(features[0].Geometry, &first))
+	assert.Equal(t, "Polygon", first.Type)
+	assert.Equal(t, "Зона с дырой", features[0].Properties["name"])
+	require.Len(t, first.Coordinates, 2)
+	assert.Greater(t, signedArea(first.Coordinates[0]), 0.0, "внешнее кольцо против часовой стрелки")
+	assert.Less(t, signedArea(first.Coordinates[1]), 0.0, "дыра по часовой стрелке")
+
+	assert.Contains(t, string(features[1].Geometry), `"MultiPolygon"`)
+	assert.Nil(t, features[2].Geometry)
+	assert.Equal(t, "Без геометрии", features[2].Properties["name"])
+}
+
+func TestParseShapefileZip_Projection(t *testing.T) {
+	const prj = `PROJCS["WGS_1984_UTM_Zone_37N",GEOGCS["GCS_WGS_1984",DATUM["D_WGS_1984",SPHEROID["WGS_1984",6378137.0,298.257223563]],PRIMEM["Greenwich",0.0],UNIT["Degree",0.0174532925199433]],PROJECTION["Transverse_Mercator"],PARAMETER["False_Easting",500000.0],PARAMETER["False_Northing",0.0],PARAMETER["Central_Meridian",39.0],PARAMETER["Scale_Factor",0.9996],PARAMETER["Latitude_Of_Origin",0.0],UNIT["Meter",1.0]]`
+
+	ring := [][]float64{{500000, 0}, {500000, 1000}, {501000, 1000}, {501000, 0}, {500000, 0}}
+	features, err := ParseShapefileZip(buildZip(t, map[string][]byte{
+		"zones.shp": buildShp(shapePolygon, [][][][]float64{{ring}}),
+		"zones.dbf": buildDBF(0, []testDBFField{{name: "name", kind: 'C', length: 10}}, [][]string{{"UTM"}}),
+		"zones.prj": []byte(prj),
+	}))
+	require.NoError(t, err)
+	require.Len(t, features, 1)
+
+	var geometry struct {
+		Coordinates [][][]float64 `json:"coordinates"`
+	}
+	require.NoError(t, json.Unmarshal(features[0].Geometry, &geometry))
+	assert.InDelta(t, 39.0, geometry.Coordinates[0][0][0], 1e-9)
+	assert.InDelta(t, 0.0, geometry.Coordinates[0][0][1], 1e-9)
+	for _, p := range geometry.Coordinates[0] {
+		assert.InDelta(t, 39.0, p[0], 0.01)
+		assert.InDelta(t, 0.0, p[1], 0.01)
+	}
+}
+
+func TestParseShapefileZip_Invalid(t *testing.T) {
+	square := [][][][]float64{{{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}}}
+	shp := buildShp(shapePolygon, square)
+	dbf := buildDBF(0, []testDBFField{{name: "name", kind: 'C', length: 10}}, [][]string{{"Зона"}})
+
+	points := bytes.Clone(shp)
+	binary.LittleEndian.PutUint32(points[32:36], 1)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Not a ZIP", data: []byte("not a zip")},
+		{name: "No .shp", data: buildZip(t, map[string][]byte{"zones.dbf": dbf})},
+		{name: "No .dbf", data: buildZip(t, map[string][]byte{"zones.shp": shp})},
+		{name: "Several layers", data: buildZip(t, map[string][]byte{
+			"a.shp": shp, "a.dbf": dbf, "b.shp": shp, "b.dbf": dbf,
+		})},
+		{name: "Point layer", data: buildZip(t, map[string][]byte{"zones.shp": points, "zones.dbf": dbf})},
+		{name: "Record count mismatch", data: buildZip(t, map[string][]byte{
+			"zones.shp": buildShp(shapePolygon, append(square, square...)), "zones.dbf": dbf,
+		})},
+		{name: "Truncated .shp", data: buildZip(t, map[string][]byte{"zones.shp": shp[:len(shp)-8], "zones.dbf": dbf})},
+		{name: "Archive too large", data: make([]byte, maxShapefileArchiveBytes+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseShapefileZip(tt.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReadZipMember_Limit(t *testing.T) {
+	// Сжатый архив небольшой, но распаковывается больше лимита
+	archive := buildZip(t, map[string][]byte{"zones.dbf": make([]byte, 4096)})
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	_, err = readZipMember(r.File[0], 1024)
+	assert.ErrorContains(t, err, "слишком большой")
+
+	data, err := readZipMember(r.File[0], 4096)
+	require.NoError(t, err)
+	assert.Len(t, data, 4096)
+}
+
+func TestParseShpPolygon_InvalidParts(t *testing.T) {
+	shp := buildShp(shapePolygon, [][][][]float64{{{{0, 0}, {0, 1}, {1, 1}, {0, 0}}}})
+	rec := bytes.Clone(shp[shpHeaderSize+8:])
+
+	binary.LittleEndian.PutUint32(rec[36:40], math.MaxInt32)
+	_, err := parseShpPolygon(rec)
+	assert.Error(t, err)
+}
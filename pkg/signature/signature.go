@@ -0,0 +1,42 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Заголовки подписанного запроса устройства
+const (
+	HeaderDeviceID  = "X-Device-ID"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// Sign вычисляет HMAC-SHA256 подпись запроса в hex. Подписывается строка
+// METHOD\nPATH\nTIMESTAMP\nNONCE\nSHA256(BODY), где PATH — путь запроса вместе с query-строкой,
+// а timestamp — unix-время в секундах.
+func Sign(secret, method, path, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+
+	canonical := strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify сравнивает подпись с ожидаемой за постоянное время
+func Verify(secret, method, path, timestamp, nonce string, body []byte, sig string) bool {
+	expected := Sign(secret, method, path, timestamp, nonce, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(sig)))
+}
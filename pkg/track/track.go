@@ -0,0 +1,284 @@
+package track
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+// Unix-время в миллисекундах заведомо больше этого значения (2001 год в мс)
+const unixMillisThreshold = 1e12
+
+var ErrUnknownFormat = errors.New("неизвестный формат трека: ожидается GPX или GeoJSON")
+
+// UnixTime переводит unix-время в секундах или миллисекундах в time.Time
+func UnixTime(v float64) time.Time {
+	if v > unixMillisThreshold {
+		return time.UnixMilli(int64(v))
+	}
+	return time.Unix(int64(v), 0)
+}
+
+// DetectFormat определяет формат трека по Content-Type, а если он не задан — по содержимому
+func DetectFormat(contentType string, data []byte) string {
+	switch ct := strings.ToLower(contentType); {
+	case strings.Contains(ct, "gpx"), strings.Contains(ct, "xml"):
+		return entity.TrackFormatGPX
+	case strings.Contains(ct, "json"):
+		return entity.TrackFormatGeoJSON
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return entity.TrackFormatGPX
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return entity.TrackFormatGeoJSON
+	}
+
+	return ""
+}
+
+// Parse разбирает трек в формате GPX или GeoJSON
+func Parse(format string, data []byte) (*entity.Track, error) {
+	var (
+		t   *entity.Track
+		err error
+	)
+
+	switch format {
+	case entity.TrackFormatGPX:
+		t, err = ParseGPX(data)
+	case entity.TrackFormatGeoJSON:
+		t, err = ParseGeoJSON(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+type gpxFile struct {
+	XMLName xml.Name `xml:"gpx"`
+	Tracks  []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Name   string     `xml:"name"`
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time"`
+}
+
+// ParseGPX разбирает треки (trk) и маршруты (rte) GPX 1.0/1.1. Все сегменты объединяются в один трек.
+func ParseGPX(data []byte) (*entity.Track, error) {
+	var doc gpxFile
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("ошибка разбора GPX: %w", err)
+	}
+
+	t := &entity.Track{}
+	add := func(points []gpxPoint) error {
+		for _, p := range points {
+			point := entity.TrackPoint{Lat: p.Lat, Lon: p.Lon}
+			if p.Time != "" {
+				ts, err := time.Parse(time.RFC3339, strings.TrimSpace(p.Time))
+				if err != nil {
+					return fmt.Errorf("ошибка разбора времени точки GPX: %w", err)
+				}
+				point.Time = &ts
+			}
+			t.Points = append(t.Points, point)
+		}
+		return nil
+	}
+
+	for _, trk := range doc.Tracks {
+		if t.Name == "" {
+			t.Name = trk.Name
+		}
+		for _, seg := range trk.Segments {
+			if err := add(seg.Points); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, rte := range doc.Routes {
+		if t.Name == "" {
+			t.Name = rte.Name
+		}
+		if err := add(rte.Points); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+type geoJSONObject struct {
+	Type        string           `json:"type"`
+	Coordinates json.RawMessage  `json:"coordinates"`
+	Geometry    *geoJSONObject   `json:"geometry"`
+	Properties  map[string]any   `json:"properties"`
+	Features    []*geoJSONObject `json:"features"`
+}
+
+// ParseGeoJSON разбирает LineString/MultiLineString (время точек — в properties.coordTimes или
+// properties.times, как в выгрузках togeojson и Strava) и коллекции Point с properties.time
+func ParseGeoJSON(data []byte) (*entity.Track, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("ошибка разбора GeoJSON: %w", err)
+	}
+
+	t := &entity.Track{}
+	if err := appendGeoJSON(t, &obj, nil); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func appendGeoJSON(t *entity.Track, obj *geoJSONObject, props map[string]any) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, f := range obj.Features {
+			if err := appendGeoJSON(t, f, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil
+		}
+		if t.Name == "" {
+			if name, ok := obj.Properties["name"].(string); ok {
+				t.Name = name
+			}
+		}
+		return appendGeoJSON(t, obj.Geometry, obj.Properties)
+
+	case "Point":
+		var coords []float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil || len(coords) < 2 {
+			return errors.New("некорректные координаты Point")
+		}
+
+		point := entity.TrackPoint{Lon: coords[0], Lat: coords[1]}
+		for _, key := range []string{"time", "timestamp"} {
+			if value, ok := props[key]; ok {
+				ts, err := parseGeoJSONTime(value)
+				if err != nil {
+					return err
+				}
+				point.Time = ts
+				break
+			}
+		}
+		t.Points = append(t.Points, point)
+		return nil
+
+	case "LineString":
+		var coords [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return errors.New("некорректные координаты LineString")
+		}
+		return appendLine(t, coords, lineTimes(props, -1))
+
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &lines); err != nil {
+			return errors.New("некорректные координаты MultiLineString")
+		}
+		for i, coords := range lines {
+			if err := appendLine(t, coords, lineTimes(props, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("неподдерживаемый тип GeoJSON: %s", obj.Type)
+}
+
+func appendLine(t *entity.Track, coords [][]float64, times []any) error {
+	if times != nil && len(times) != len(coords) {
+		return errors.New("количество меток времени не совпадает с количеством точек")
+	}
+
+	for i, c := range coords {
+		if len(c) < 2 {
+			return errors.New("некорректные координаты точки трека")
+		}
+
+		point := entity.TrackPoint{Lon: c[0], Lat: c[1]}
+		if times != nil {
+			ts, err := parseGeoJSONTime(times[i])
+			if err != nil {
+				return err
+			}
+			point.Time = ts
+		}
+		t.Points = append(t.Points, point)
+	}
+
+	return nil
+}
+
+// lineTimes возвращает метки времени линии; для MultiLineString (line >= 0) — метки ее части
+func lineTimes(props map[string]any, line int) []any {
+	for _, key := range []string{"coordTimes", "times"} {
+		times, ok := props[key].([]any)
+		if !ok {
+			continue
+		}
+		if line < 0 {
+			return times
+		}
+		if line < len(times) {
+			if part, ok := times[line].([]any); ok {
+				return part
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseGeoJSONTime(value any) (*time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора времени точки: %w", err)
+		}
+		return &ts, nil
+	case float64:
+		ts := UnixTime(v)
+		return &ts, nil
+	case nil:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("некорректное время точки: %v", value)
+}
@@ -0,0 +1,263 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/levinOo/geo-incedent-service/internal/entity"
+)
+
+const (
+	// MaxPolygonVertices — предел числа вершин полигона в топологической проверке
+	MaxPolygonVertices = 10000
+	// maxTopologyIssues ограничивает размер отчета для сильно поврежденных полигонов
+	maxTopologyIssues = 100
+	// zeroAreaEpsilon — площадь кольца в квадратных градусах, ниже которой кольцо считается вырожденным
+	zeroAreaEpsilon = 1e-14
+)
+
+// TopologyError возвращается ValidatePolygonTopology, если у полигона найдены проблемы
+type TopologyError struct {
+	Issues []entity.PolygonIssue
+}
+
+func (e *TopologyError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.Message)
+	}
+	return fmt.Sprintf("invalid polygon topology (%d issues): %s", len(e.Issues), strings.Join(messages, "; "))
+}
+
+// ValidatePolygonTopology дополняет ValidatePolygon проверками, после которых PostGIS и
+// GeoJsonPolygon.Contains дают согласованные результаты: повторяющиеся вершины, вырожденные
+// кольца, самопересечения, пересечения колец, дыры вне внешнего кольца и превышение
+// MaxPolygonVertices. Возвращает *TopologyError со списком проблем и их местами.
+func ValidatePolygonTopology(area entity.GeoJsonPolygon) error {
+	issues := PolygonIssues(area)
+	if len(issues) > 0 {
+		return &TopologyError{Issues: issues}
+	}
+	return nil
+}
+
+// PolygonIssues возвращает все найденные проблемы полигона (не более maxTopologyIssues).
+// Если структура полигона нарушена, пространственные проверки не выполняются.
+func PolygonIssues(area entity.GeoJsonPolygon) []entity.PolygonIssue {
+	r := &issueReport{}
+
+	if area.Type != "Polygon" {
+		r.add(entity.PolygonIssueInvalidType, -1, -1, nil, "invalid GeoJsonPolygon type %q", area.Type)
+	}
+	if len(area.Coordinates) == 0 {
+		r.add(entity.PolygonIssueTooFewPoints, -1, -1, nil, "area must contain at least 1 ring")
+	}
+
+	vertices := 0
+	for ri, ring := range area.Coordinates {
+		vertices += len(ring)
+		r.checkStructure(ri, ring)
+	}
+
+	if vertices > MaxPolygonVertices {
+		r.add(entity.PolygonIssueTooManyVertices, -1, -1, nil, "polygon has %d vertices, at most %d allowed", vertices, MaxPolygonVertices)
+	}
+
+	if len(r.issues) > 0 {
+		return r.issues
+	}
+
+	for ri, ring := range area.Coordinates {
+		r.checkRing(ri, ring)
+	}
+
+	for ri := 1; ri < len(area.Coordinates); ri++ {
+		for rj := ri + 1; rj < len(area.Coordinates); rj++ {
+			r.checkRingPair(ri, area.Coordinates[ri], rj, area.Coordinates[rj])
+		}
+		r.checkRingPair(0, area.Coordinates[0], ri, area.Coordinates[ri])
+
+		hole := area.Coordinates[ri]
+		if !pointInRing(area.Coordinates[0], hole[0]) && !r.has(entity.PolygonIssueRingIntersection, ri) {
+			r.add(entity.PolygonIssueHoleOutsideShell, ri, 0, hole[0], "hole %d lies outside the exterior ring", ri)
+		}
+	}
+
+	return r.issues
+}
+
+type issueReport struct {
+	issues []entity.PolygonIssue
+}
+
+func (r *issueReport) add(code string, ring, vertex int, point []float64, format string, args ...any) {
+	if len(r.issues) >= maxTopologyIssues {
+		return
+	}
+
+	var location []float64
+	if point != nil {
+		location = []float64{point[0], point[1]}
+	}
+
+	r.issues = append(r.issues, entity.PolygonIssue{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Ring:    ring,
+		Vertex:  vertex,
+		Point:   location,
+	})
+}
+
+func (r *issueReport) has(code string, ring int) bool {
+	for _, issue := range r.issues {
+		if issue.Code == code && issue.Ring == ring {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *issueReport) checkStructure(ri int, ring [][]float64) {
+	valid := true
+	for vi, coord := range ring {
+		if len(coord) < 2 {
+			r.add(entity.PolygonIssueInvalidCoordinate, ri, vi, nil, "vertex %d of ring %d has %d coordinates", vi, ri, len(coord))
+			valid = false
+			continue
+		}
+		if coord[1] < -90 || coord[1] > 90 || coord[0] < -180 || coord[0] > 180 {
+			r.add(entity.PolygonIssueInvalidCoordinate, ri, vi, coord, "vertex %d of ring %d is out of range: %v", vi, ri, coord)
+			valid = false
+		}
+	}
+
+	if len(ring) < 4 {
+		r.add(entity.PolygonIssueTooFewPoints, ri, -1, nil, "ring %d must contain at least 4 coordinates, got %d", ri, len(ring))
+		return
+	}
+
+	if !valid {
+		return
+	}
+
+	first, last := ring[0], ring[len(ring)-1]
+	if first[0] != last[0] || first[1] != last[1] {
+		r.add(entity.PolygonIssueNotClosed, ri, len(ring)-1, last, "ring %d is not closed: first %v != last %v", ri, first, last)
+	}
+}
+
+// checkRing ищет в одном кольце повторяющиеся соседние вершины, нулевую площадь и
+// пересечения несмежных отрезков. Повторы исключаются из дальнейших проверок, чтобы
+// отрезок нулевой длины не давал ложных самопересечений.
+func (r *issueReport) checkRing(ri int, ring [][]float64) {
+	points := [][]float64{ring[0]}
+	index := []int{0}
+	for vi := 1; vi < len(ring); vi++ {
+		if samePoint(ring[vi], ring[vi-1]) {
+			r.add(entity.PolygonIssueDuplicateVertex, ri, vi, ring[vi], "vertex %d of ring %d duplicates the previous one", vi, ri)
+			continue
+		}
+		points = append(points, ring[vi])
+		index = append(index, vi)
+	}
+
+	if len(points) < 4 || fanArea(points) < zeroAreaEpsilon {
+		r.add(entity.PolygonIssueZeroAreaRing, ri, -1, nil, "ring %d has zero area", ri)
+		return
+	}
+
+	segments := len(points) - 1
+	for i := 0; i < segments; i++ {
+		for j := i + 2; j < segments; j++ {
+			// Первый и последний отрезки смежны через замыкающую вершину
+			if i == 0 && j == segments-1 {
+				continue
+			}
+			if point, ok := segmentIntersection(points[i], points[i+1], points[j], points[j+1]); ok {
+				r.add(entity.PolygonIssueSelfIntersection, ri, index[i], point,
+					"segments starting at vertices %d and %d of ring %d intersect at %v", index[i], index[j], ri, point)
+			}
+		}
+	}
+}
+
+// checkRingPair ищет пересечения отрезков двух разных колец
+func (r *issueReport) checkRingPair(ri int, a [][]float64, rj int, b [][]float64) {
+	for i := 0; i+1 < len(a); i++ {
+		for j := 0; j+1 < len(b); j++ {
+			if point, ok := segmentIntersection(a[i], a[i+1], b[j], b[j+1]); ok {
+				r.add(entity.PolygonIssueRingIntersection, rj, j, point, "segment %d of ring %d intersects segment %d of ring %d at %v", j, rj, i, ri, point)
+				return
+			}
+		}
+	}
+}
+
+func samePoint(a, b []float64) bool {
+	return a[0] == b[0] && a[1] == b[1]
+}
+
+// fanArea возвращает сумму модулей площадей треугольников веера из первой вершины кольца.
+// В отличие от ориентированной площади она не обнуляется у симметричной «бабочки»
+// и равна нулю, только если все вершины лежат на одной прямой.
+func fanArea(ring [][]float64) float64 {
+	area := 0.0
+	for i := 1; i+1 < len(ring); i++ {
+		area += math.Abs(orientation(ring[0], ring[i], ring[i+1]))
+	}
+	return area / 2
+}
+
+// segmentIntersection возвращает точку пересечения отрезков ab и cd, включая касание
+// концом и наложение коллинеарных отрезков
+func segmentIntersection(a, b, c, d []float64) ([]float64, bool) {
+	d1 := orientation(c, d, a)
+	d2 := orientation(c, d, b)
+	d3 := orientation(a, b, c)
+	d4 := orientation(a, b, d)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		t := d1 / (d1 - d2)
+		return []float64{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}, true
+	}
+
+	switch {
+	case d1 == 0 && onSegment(c, d, a):
+		return a, true
+	case d2 == 0 && onSegment(c, d, b):
+		return b, true
+	case d3 == 0 && onSegment(a, b, c):
+		return c, true
+	case d4 == 0 && onSegment(a, b, d):
+		return d, true
+	}
+
+	return nil, false
+}
+
+// orientation возвращает знак векторного произведения (b - a) x (c - a)
+func orientation(a, b, c []float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// onSegment проверяет, лежит ли коллинеарная точка c внутри отрезка ab
+func onSegment(a, b, c []float64) bool {
+	return c[0] >= min(a[0], b[0]) && c[0] <= max(a[0], b[0]) &&
+		c[1] >= min(a[1], b[1]) && c[1] <= max(a[1], b[1])
+}
+
+func pointInRing(ring [][]float64, p []float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		if ((yi > p[1]) != (yj > p[1])) && (p[0] < (xj-xi)*(p[1]-yi)/(yj-yi)+xi) {
+			inside = !inside
+		}
+	}
+	return inside
+}
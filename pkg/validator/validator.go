@@ -43,6 +43,54 @@ func ValidatePolygon(area entity.GeoJsonPolygon) error {
 	return nil
 }
 
+func ValidateLineString(route entity.GeoJsonLineString) error {
+	if route.Type != "LineString" {
+		return errors.New("invalid GeoJsonLineString type")
+	}
+
+	if len(route.Coordinates) < 2 {
+		return errors.New("route must contain at least 2 coordinates")
+	}
+
+	for _, coord := range route.Coordinates {
+		if len(coord) < 2 {
+			return fmt.Errorf("invalid coordinate: %v", coord)
+		}
+		lat, lon := coord[1], coord[0]
+		if lat < -90 || lat > 90 {
+			return fmt.Errorf("invalid latitude: %f", lat)
+		}
+		if lon < -180 || lon > 180 {
+			return fmt.Errorf("invalid longitude: %f", lon)
+		}
+	}
+
+	return nil
+}
+
+const maxTrackPoints = 100000
+
+func ValidateTrack(track *entity.Track) error {
+	if len(track.Points) == 0 {
+		return errors.New("track must contain at least 1 point")
+	}
+
+	if len(track.Points) > maxTrackPoints {
+		return fmt.Errorf("track must contain at most %d points", maxTrackPoints)
+	}
+
+	for _, p := range track.Points {
+		if p.Lat < -90 || p.Lat > 90 {
+			return fmt.Errorf("invalid latitude: %f", p.Lat)
+		}
+		if p.Lon < -180 || p.Lon > 180 {
+			return fmt.Errorf("invalid longitude: %f", p.Lon)
+		}
+	}
+
+	return nil
+}
+
 func ValidateLocation(location entity.UserLocation) error {
 	if location.Lat < -90 || location.Lat > 90 {
 		return fmt.Errorf("invalid latitude: %f", location.Lat)
@@ -50,5 +98,8 @@ func ValidateLocation(location entity.UserLocation) error {
 	if location.Lon < -180 || location.Lon > 180 {
 		return fmt.Errorf("invalid longitude: %f", location.Lon)
 	}
+	if location.AccuracyM != nil && *location.AccuracyM < 0 {
+		return fmt.Errorf("invalid accuracy: %f", *location.AccuracyM)
+	}
 	return nil
 }
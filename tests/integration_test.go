@@ -68,7 +68,7 @@ func TestIntegration_FullFlow(t *testing.T) {
 
 	repository := repo.NewRepo(pool)
 	svc := service.NewService(repository, cfg, redisClient)
-	router := myHttp.NewRouter(&cfg.HTTPServer, svc)
+	router := myHttp.NewRouter(&cfg.HTTPServer, svc, myHttp.NewStreamHub())
 
 	incidentName := fmt.Sprintf("Test Incident %d", time.Now().UnixNano())
 	createReq := entity.CreateIncidentRequest{
@@ -95,6 +95,17 @@ func TestIntegration_FullFlow(t *testing.T) {
 	router.ServeHTTP(w, req)
 	require.Equal(t, http.StatusCreated, w.Code, "Не удалось создать инцидент: %s", w.Body.String())
 
+	// Новый инцидент создается черновиком и не учитывается в проверках до публикации
+	var incidentID string
+	err = pool.QueryRow(ctx, "SELECT id FROM incidents WHERE name = $1", incidentName).Scan(&incidentID)
+	require.NoError(t, err)
+
+	req = httptest.NewRequest("POST", "/api/v1/incidents/"+incidentID+"/approve", nil)
+	req.Header.Set("X-API-Key", "test-api-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "Не удалось опубликовать инцидент: %s", w.Body.String())
+
 	checkReq := entity.CheckLocationRequest{
 		UserID: uuid.NewString(),
 		UserLocation: entity.UserLocation{